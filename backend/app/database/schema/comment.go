@@ -0,0 +1,97 @@
+package schema
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CommentAnchorType target yang dikomentari di dalam document
+type CommentAnchorType string
+
+const (
+	CommentAnchorNode  CommentAnchorType = "node"  // node mermaid
+	CommentAnchorEdge  CommentAnchorType = "edge"  // edge mermaid antara dua node
+	CommentAnchorLines CommentAnchorType = "lines" // range baris markdown
+)
+
+// CommentStatus status thread komentar
+type CommentStatus string
+
+const (
+	CommentStatusOpen     CommentStatus = "open"
+	CommentStatusResolved CommentStatus = "resolved"
+)
+
+// CommentThread thread komentar yang di-anchor ke node/edge mermaid atau
+// range baris markdown. VersionNumber versi saat thread dibuat, AnchorVersion
+// versi dari anchor yang tersimpan. Posisi di versi terbaru dan Outdated
+// dihitung saat thread dibaca, tidak disimpan.
+type CommentThread struct {
+	ID            uint64            `gorm:"primaryKey" json:"id"`
+	DocumentID    uint64            `gorm:"column:document_id;type:bigint;not null;index:idx_thread_document_status" json:"document_id"`
+	AuthorID      uint64            `gorm:"column:author_id;type:bigint;not null" json:"author_id"`
+	VersionNumber int               `gorm:"column:version_number;type:integer;not null" json:"version_number"`
+	AnchorType    CommentAnchorType `gorm:"column:anchor_type;type:varchar(20);not null" json:"anchor_type"`
+	NodeID        string            `gorm:"column:node_id;type:varchar(255);not null;default:''" json:"node_id"`
+	EdgeFrom      string            `gorm:"column:edge_from;type:varchar(255);not null;default:''" json:"edge_from"`
+	EdgeTo        string            `gorm:"column:edge_to;type:varchar(255);not null;default:''" json:"edge_to"`
+	LineStart     int               `gorm:"column:line_start;type:integer;not null;default:0" json:"line_start"`
+	LineEnd       int               `gorm:"column:line_end;type:integer;not null;default:0" json:"line_end"`
+	AnchorVersion int               `gorm:"column:anchor_version;type:integer;not null" json:"anchor_version"`
+	Outdated      bool              `gorm:"-" json:"outdated"` // target sudah tidak ada di versi terbaru
+	Status        CommentStatus     `gorm:"column:status;type:varchar(20);not null;default:'open';index:idx_thread_document_status" json:"status"`
+	ResolvedBy    *uint64           `gorm:"column:resolved_by;type:bigint" json:"resolved_by"`
+	ResolvedAt    *time.Time        `gorm:"column:resolved_at;type:timestamp" json:"resolved_at"`
+	CreatedAt     time.Time         `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time         `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	DeletedAt     gorm.DeletedAt    `gorm:"column:deleted_at;index" json:"deleted_at"`
+
+	// Relations
+	Document *Document `gorm:"foreignKey:DocumentID;references:ID;OnDelete:CASCADE" json:"-"`
+	Author   *User     `gorm:"foreignKey:AuthorID;references:ID;OnDelete:CASCADE" json:"-"`
+	Comments []Comment `gorm:"foreignKey:ThreadID;references:ID;OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for CommentThread
+func (CommentThread) TableName() string {
+	return "comment_threads"
+}
+
+// Comment satu komentar di dalam thread, komentar pertama adalah pembuka thread
+type Comment struct {
+	ID        uint64         `gorm:"primaryKey" json:"id"`
+	ThreadID  uint64         `gorm:"column:thread_id;type:bigint;not null;index" json:"thread_id"`
+	AuthorID  uint64         `gorm:"column:author_id;type:bigint;not null" json:"author_id"`
+	Body      string         `gorm:"column:body;type:text;not null" json:"body"`
+	CreatedAt time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at"`
+
+	// Relations
+	Thread   *CommentThread   `gorm:"foreignKey:ThreadID;references:ID;OnDelete:CASCADE" json:"-"`
+	Author   *User            `gorm:"foreignKey:AuthorID;references:ID;OnDelete:CASCADE" json:"-"`
+	Mentions []CommentMention `gorm:"foreignKey:CommentID;references:ID;OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for Comment
+func (Comment) TableName() string {
+	return "comments"
+}
+
+// CommentMention user workspace yang di-@mention di sebuah komentar
+type CommentMention struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	CommentID uint64    `gorm:"column:comment_id;type:bigint;not null;index:idx_mention_comment_user,unique" json:"comment_id"`
+	UserID    uint64    `gorm:"column:user_id;type:bigint;not null;index:idx_mention_comment_user,unique;index:idx_mention_user" json:"user_id"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+
+	// Relations
+	Comment *Comment `gorm:"foreignKey:CommentID;references:ID;OnDelete:CASCADE" json:"-"`
+	User    *User    `gorm:"foreignKey:UserID;references:ID;OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for CommentMention
+func (CommentMention) TableName() string {
+	return "comment_mentions"
+}
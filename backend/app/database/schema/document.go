@@ -17,7 +17,7 @@ const (
 // Document represents a diagram or markdown file
 type Document struct {
 	ID          uint64         `gorm:"primaryKey" json:"id"`
-	WorkspaceID uint64         `gorm:"column:workspace_id;type:bigint;not null;index:idx_document_workspace;index:idx_document_workspace_created" json:"workspace_id"`
+	WorkspaceID uint64         `gorm:"column:workspace_id;type:bigint;not null;index:idx_document_workspace;index:idx_document_workspace_created;index:idx_document_workspace_slug,unique" json:"workspace_id"`
 	Title       string         `gorm:"column:title;type:varchar(255);not null" json:"title"`
 	Type        DocumentType   `gorm:"column:type;type:varchar(50);not null;default:'mermaid'" json:"type"`
 	Slug        string         `gorm:"column:slug;type:varchar(255);not null;index:idx_document_workspace_slug,unique" json:"slug"`
@@ -0,0 +1,42 @@
+package schema
+
+import "time"
+
+// ExportStatus status job export PDF
+type ExportStatus string
+
+const (
+	ExportStatusPending   ExportStatus = "pending"
+	ExportStatusRunning   ExportStatus = "running"
+	ExportStatusCompleted ExportStatus = "completed"
+	ExportStatusFailed    ExportStatus = "failed"
+)
+
+// ExportJob job export PDF yang dikerjakan di background. DocumentID nil berarti
+// export seluruh workspace. FilePath key file hasil di storage, hanya boleh
+// diunduh lewat API oleh user yang meminta export.
+type ExportJob struct {
+	ID          uint64       `gorm:"primaryKey" json:"id"`
+	WorkspaceID uint64       `gorm:"column:workspace_id;type:bigint;not null;index" json:"workspace_id"`
+	DocumentID  *uint64      `gorm:"column:document_id;type:bigint" json:"document_id"`
+	RequestedBy uint64       `gorm:"column:requested_by;type:bigint;not null;index" json:"requested_by"`
+	Status      ExportStatus `gorm:"column:status;type:varchar(20);not null;default:'pending';index" json:"status"`
+	FilePath    string       `gorm:"column:file_path;type:varchar(255);not null;default:''" json:"-"`
+	FileName    string       `gorm:"column:file_name;type:varchar(255);not null;default:''" json:"file_name"`
+	FileSize    int64        `gorm:"column:file_size;type:bigint;not null;default:0" json:"file_size"`
+	Error       string       `gorm:"column:error;type:varchar(500);not null;default:''" json:"error"`
+	StartedAt   *time.Time   `gorm:"column:started_at;type:timestamp" json:"started_at"`
+	CompletedAt *time.Time   `gorm:"column:completed_at;type:timestamp" json:"completed_at"`
+	CreatedAt   time.Time    `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time    `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	// Relations
+	Workspace *Workspace `gorm:"foreignKey:WorkspaceID;references:ID;OnDelete:CASCADE" json:"-"`
+	Document  *Document  `gorm:"foreignKey:DocumentID;references:ID;OnDelete:CASCADE" json:"-"`
+	User      *User      `gorm:"foreignKey:RequestedBy;references:ID;OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for ExportJob
+func (ExportJob) TableName() string {
+	return "export_jobs"
+}
@@ -0,0 +1,26 @@
+package schema
+
+import (
+	"time"
+)
+
+// ExternalIdentity menghubungkan user dengan akun di identity provider OIDC.
+// Satu user bisa punya identity dari beberapa provider.
+type ExternalIdentity struct {
+	ID          uint64     `gorm:"primaryKey" json:"id"`
+	UserID      uint64     `gorm:"column:user_id;type:bigint;not null;index" json:"user_id"`
+	Provider    string     `gorm:"column:provider;type:varchar(50);not null;index:idx_identity_provider_subject,unique" json:"provider"` // nama di [[sso.providers]]
+	Subject     string     `gorm:"column:subject;type:varchar(255);not null;index:idx_identity_provider_subject,unique" json:"subject"`  // claim sub dari id_token
+	Email       string     `gorm:"column:email;type:varchar(255)" json:"email"`
+	LastLoginAt *time.Time `gorm:"column:last_login_at;type:timestamp" json:"last_login_at"`
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID;references:ID;OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for ExternalIdentity
+func (ExternalIdentity) TableName() string {
+	return "external_identities"
+}
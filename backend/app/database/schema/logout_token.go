@@ -0,0 +1,18 @@
+package schema
+
+import "time"
+
+// LogoutToken jti logout token back-channel yang sudah diproses. Disimpan sampai
+// logout token kedaluwarsa sehingga token yang sama tidak bisa diputar ulang.
+type LogoutToken struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	Provider  string    `gorm:"column:provider;type:varchar(50);not null;uniqueIndex:idx_logout_token_jti" json:"provider"`
+	JTI       string    `gorm:"column:jti;type:varchar(255);not null;uniqueIndex:idx_logout_token_jti" json:"jti"`
+	ExpiresAt time.Time `gorm:"column:expires_at;type:timestamp;not null;index" json:"expires_at"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+// TableName specifies the table name for LogoutToken
+func (LogoutToken) TableName() string {
+	return "logout_tokens"
+}
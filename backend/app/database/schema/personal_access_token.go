@@ -0,0 +1,56 @@
+package schema
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TokenScope represents what a personal access token is allowed to do
+type TokenScope string
+
+const (
+	TokenScopeRead  TokenScope = "read"  // hanya request GET/HEAD/OPTIONS
+	TokenScopeWrite TokenScope = "write" // semua request
+)
+
+// PersonalAccessToken represents a user-managed API token for CLI and CI clients.
+// Only the SHA-256 hash of the token is stored.
+type PersonalAccessToken struct {
+	ID          uint64         `gorm:"primaryKey" json:"id"`
+	UserID      uint64         `gorm:"column:user_id;type:bigint;not null;index" json:"user_id"`
+	Name        string         `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	TokenHash   string         `gorm:"column:token_hash;type:varchar(64);not null;uniqueIndex" json:"-"`
+	TokenPrefix string         `gorm:"column:token_prefix;type:varchar(20);not null" json:"token_prefix"` // awal token untuk identifikasi di UI
+	Scope       TokenScope     `gorm:"column:scope;type:varchar(20);not null;default:'read'" json:"scope"`
+	ExpiresAt   *time.Time     `gorm:"column:expires_at;type:timestamp" json:"expires_at"`
+	LastUsedAt  *time.Time     `gorm:"column:last_used_at;type:timestamp" json:"last_used_at"`
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at"`
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID;references:ID;OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for PersonalAccessToken
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+// Expired reports whether the token has passed its expiry
+func (t *PersonalAccessToken) Expired() bool {
+	return t.ExpiresAt != nil && !t.ExpiresAt.After(time.Now())
+}
+
+// Allows reports whether the token scope permits the given HTTP method
+func (t *PersonalAccessToken) Allows(method string) bool {
+	if t.Scope == TokenScopeWrite {
+		return true
+	}
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package schema
+
+import (
+	"time"
+)
+
+// RecoveryCode code cadangan 2FA sekali pakai, hanya hash yang disimpan
+type RecoveryCode struct {
+	ID        uint64     `gorm:"primaryKey" json:"id"`
+	UserID    uint64     `gorm:"column:user_id;type:bigint;not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"column:code_hash;type:varchar(64);not null;uniqueIndex" json:"-"` // sha256 dari code yang sudah dinormalisasi
+	UsedAt    *time.Time `gorm:"column:used_at;type:timestamp" json:"used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID;references:ID;OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for RecoveryCode
+func (RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
@@ -19,6 +19,7 @@ type SharedAccess struct {
 	ID          uint64         `gorm:"primaryKey" json:"id"`
 	DocumentID  uint64         `gorm:"column:document_id;type:bigint;not null;index:idx_shared_document;index:idx_shared_document_user,unique" json:"document_id"`
 	UserID      *uint64        `gorm:"column:user_id;type:bigint;index:idx_shared_document_user,unique" json:"user_id"`
+	InviteEmail *string        `gorm:"column:invite_email;type:varchar(255);index" json:"invite_email"` // pending invite, diisi selama user belum pernah login
 	AccessToken string         `gorm:"column:access_token;type:varchar(255);not null;uniqueIndex:idx_access_token;index" json:"access_token"`
 	Permission  Permission     `gorm:"column:permission;type:varchar(50);not null;default:'view'" json:"permission"`
 	ExpiresAt   *time.Time     `gorm:"column:expires_at;type:timestamp" json:"expires_at"`
@@ -6,20 +6,48 @@ import (
 	"git.dev.siap.id/kukuhkkh/app-diagram/utils/helpers"
 )
 
+// UserKind membedakan user manusia dan service account
+type UserKind string
+
+const (
+	UserKindHuman   UserKind = "human"
+	UserKindService UserKind = "service" // bot milik satu workspace, login hanya via personal access token
+)
+
 type User struct {
-	ID        uint64     `gorm:"primary_key;column:id" json:"id"`
-	Name      string     `gorm:"column:name;not null" json:"name"`
-	Password  *string    `gorm:"column:password" json:"-"`
-	Email     string     `gorm:"column:email;unique;not null" json:"email"`
-	LogtoSub  string     `gorm:"type:varchar(255);column:logto_sub;uniqueIndex;not null" json:"logto_sub"`
-	LastLogin *time.Time `gorm:"column:last_login" json:"last_login"`
+	ID             uint64     `gorm:"primary_key;column:id" json:"id"`
+	Name           string     `gorm:"column:name;not null" json:"name"`
+	Password       *string    `gorm:"column:password" json:"-"`
+	Email          string     `gorm:"column:email;unique;not null" json:"email"`
+	Kind           UserKind   `gorm:"type:varchar(20);column:kind;not null;default:'human'" json:"kind"`
+	WorkspaceID    *uint64    `gorm:"column:workspace_id;type:bigint;index" json:"workspace_id"` // workspace pemilik service account
+	Disabled       bool       `gorm:"column:disabled;not null;default:false" json:"disabled"`
+	ScimExternalID *string    `gorm:"column:scim_external_id;type:varchar(255);uniqueIndex" json:"-"` // externalId dari IdP untuk user yang di-provision lewat SCIM
+	LastLogin      *time.Time `gorm:"column:last_login" json:"last_login"`
+
+	// TOTP 2FA untuk akun lokal. Secret terisi saat enrollment dimulai, 2FA
+	// baru aktif setelah code pertama dikonfirmasi (TotpEnabledAt terisi).
+	TotpSecret      *string    `gorm:"column:totp_secret;type:varchar(64)" json:"-"`
+	TotpEnabledAt   *time.Time `gorm:"column:totp_enabled_at" json:"-"`
+	TotpLastCounter int64      `gorm:"column:totp_last_counter;not null;default:0" json:"-"` // time step terakhir yang dipakai, mencegah replay code
 	Base
 }
 
-// ComparePassword compare password
-func (u *User) ComparePassword(password string) bool {
+// IsServiceAccount true jika user adalah service account
+func (u *User) IsServiceAccount() bool {
+	return u.Kind == UserKindService
+}
+
+// TwoFactorEnabled true jika login lokal butuh code TOTP atau recovery code
+func (u *User) TwoFactorEnabled() bool {
+	return u.TotpEnabledAt != nil && u.TotpSecret != nil
+}
+
+// ComparePassword compare password. needsRehash true jika hash tersimpan masih
+// format lama dan perlu di-hash ulang setelah login berhasil.
+func (u *User) ComparePassword(password string) (match bool, needsRehash bool) {
 	if u.Password == nil {
-		return false
+		return false, false
 	}
-	return helpers.ValidateHash(password, *u.Password)
+	return helpers.VerifyPassword(password, *u.Password)
 }
@@ -0,0 +1,45 @@
+package schema
+
+import (
+	"time"
+)
+
+// UserSession session login user yang disimpan di server. Session cookie hanya
+// menyimpan ID baris ini sehingga session bisa dilihat dan dicabut kapan saja.
+// Token dari identity provider disimpan terenkripsi (sso.TokenCipher) dan tidak pernah dikirim ke browser.
+type UserSession struct {
+	ID             uint64     `gorm:"primaryKey" json:"id"`
+	UserID         uint64     `gorm:"column:user_id;type:bigint;not null;index" json:"user_id"`
+	Provider       string     `gorm:"column:provider;type:varchar(50);index:idx_user_session_idp" json:"provider"` // kosong untuk login lokal
+	Subject        string     `gorm:"column:subject;type:varchar(255);index:idx_user_session_idp" json:"-"`        // claim sub dari IdP
+	IdPSessionID   string     `gorm:"column:idp_session_id;type:varchar(255);index" json:"-"`                      // claim sid, untuk back-channel logout
+	AccessToken    string     `gorm:"column:access_token;type:text" json:"-"`
+	RefreshToken   string     `gorm:"column:refresh_token;type:text" json:"-"`
+	IDToken        string     `gorm:"column:id_token;type:text" json:"-"`
+	TokenExpiresAt *time.Time `gorm:"column:token_expires_at;type:timestamp" json:"-"`
+	UserAgent      string     `gorm:"column:user_agent;type:varchar(255)" json:"user_agent"`
+	IPAddress      string     `gorm:"column:ip_address;type:varchar(64)" json:"ip_address"`
+	LastSeenAt     time.Time  `gorm:"column:last_seen_at;type:timestamp" json:"last_seen_at"`
+	ExpiresAt      time.Time  `gorm:"column:expires_at;type:timestamp;index" json:"expires_at"`
+	RevokedAt      *time.Time `gorm:"column:revoked_at;type:timestamp" json:"revoked_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	// Relations
+	User *User `gorm:"foreignKey:UserID;references:ID;OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for UserSession
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
+
+// Active true jika session belum dicabut dan belum kedaluwarsa
+func (s *UserSession) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// TokenExpired true jika access token IdP sudah (hampir) kedaluwarsa dan bisa di-refresh
+func (s *UserSession) TokenExpired(now time.Time, leeway time.Duration) bool {
+	return s.RefreshToken != "" && s.TokenExpiresAt != nil && now.Add(leeway).After(*s.TokenExpiresAt)
+}
@@ -6,16 +6,26 @@ import (
 	"gorm.io/gorm"
 )
 
+// MermaidValidation controls how invalid Mermaid content is handled on save
+type MermaidValidation string
+
+const (
+	MermaidValidationStrict MermaidValidation = "strict" // tolak simpan jika ada error
+	MermaidValidationWarn   MermaidValidation = "warn"   // simpan, diagnostics dikembalikan di response
+	MermaidValidationOff    MermaidValidation = "off"
+)
+
 // Workspace represents a project/workspace grouping for documents
 type Workspace struct {
-	ID          uint64         `gorm:"primaryKey" json:"id"`
-	Name        string         `gorm:"column:name;type:varchar(255);not null;index:idx_workspace_owner_name,unique" json:"name"`
-	Description *string        `gorm:"column:description;type:text" json:"description"`
-	OwnerID     uint64         `gorm:"column:owner_id;type:bigint;not null;index:idx_workspace_owner_name,unique;index:idx_workspace_owner" json:"owner_id"`
-	IsPublic    bool           `gorm:"column:is_public;type:boolean;default:false;index" json:"is_public"`
-	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at"`
+	ID                uint64            `gorm:"primaryKey" json:"id"`
+	Name              string            `gorm:"column:name;type:varchar(255);not null;index:idx_workspace_owner_name,unique" json:"name"`
+	Description       *string           `gorm:"column:description;type:text" json:"description"`
+	OwnerID           uint64            `gorm:"column:owner_id;type:bigint;not null;index:idx_workspace_owner_name,unique;index:idx_workspace_owner" json:"owner_id"`
+	IsPublic          bool              `gorm:"column:is_public;type:boolean;default:false;index" json:"is_public"`
+	MermaidValidation MermaidValidation `gorm:"column:mermaid_validation;type:varchar(20);not null;default:'warn'" json:"mermaid_validation"` // workspace lama tidak langsung menolak save
+	CreatedAt         time.Time         `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time         `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	DeletedAt         gorm.DeletedAt    `gorm:"column:deleted_at;index" json:"deleted_at"`
 
 	// Relations
 	Owner     *User      `gorm:"foreignKey:OwnerID;references:ID" json:"-"`
@@ -0,0 +1,50 @@
+package schema
+
+import (
+	"time"
+)
+
+// WorkspaceRole represents the role of a user inside a workspace
+type WorkspaceRole string
+
+const (
+	WorkspaceRoleOwner  WorkspaceRole = "owner"
+	WorkspaceRoleAdmin  WorkspaceRole = "admin"
+	WorkspaceRoleEditor WorkspaceRole = "editor"
+	WorkspaceRoleViewer WorkspaceRole = "viewer"
+)
+
+var workspaceRoleRank = map[WorkspaceRole]int{
+	WorkspaceRoleViewer: 1,
+	WorkspaceRoleEditor: 2,
+	WorkspaceRoleAdmin:  3,
+	WorkspaceRoleOwner:  4,
+}
+
+// AtLeast reports whether the role is equal to or higher than other.
+// An empty role (not a member) never satisfies any role.
+func (r WorkspaceRole) AtLeast(other WorkspaceRole) bool {
+	rank, ok := workspaceRoleRank[r]
+	return ok && rank >= workspaceRoleRank[other]
+}
+
+// WorkspaceMember represents a user's membership in a workspace.
+// The owner is implied by Workspace.OwnerID and has no member row.
+type WorkspaceMember struct {
+	ID          uint64        `gorm:"primaryKey" json:"id"`
+	WorkspaceID uint64        `gorm:"column:workspace_id;type:bigint;not null;index:idx_member_workspace_user,unique" json:"workspace_id"`
+	UserID      uint64        `gorm:"column:user_id;type:bigint;not null;index:idx_member_workspace_user,unique;index:idx_member_user" json:"user_id"`
+	Role        WorkspaceRole `gorm:"column:role;type:varchar(50);not null;default:'viewer'" json:"role"`
+	InvitedBy   *uint64       `gorm:"column:invited_by;type:bigint" json:"invited_by"`
+	CreatedAt   time.Time     `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time     `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	// Relations
+	Workspace *Workspace `gorm:"foreignKey:WorkspaceID;references:ID;OnDelete:CASCADE" json:"-"`
+	User      *User      `gorm:"foreignKey:UserID;references:ID;OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for WorkspaceMember
+func (WorkspaceMember) TableName() string {
+	return "workspace_members"
+}
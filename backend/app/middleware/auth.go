@@ -1,59 +1,241 @@
 package middleware
 
 import (
+	"errors"
+	"strings"
 	"time"
 
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	token_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/token/repository"
+	user_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/user/repository"
+	session_service "git.dev.siap.id/kukuhkkh/app-diagram/app/module/usersession/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
 	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/helpers"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/session"
 	jwtware "github.com/gofiber/jwt/v2"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
+// Error autentikasi
+var (
+	ErrUnauthorized      = apperr.Unauthorized("unauthorized", "user not authenticated")
+	ErrInvalidToken      = apperr.Unauthorized("invalid_token", "invalid or revoked access token")
+	ErrTokenExpired      = apperr.Unauthorized("token_expired", "access token has expired")
+	ErrAccountDisabled   = apperr.Unauthorized("account_disabled", "account is disabled")
+	ErrInsufficientScope = apperr.Forbidden("insufficient_scope", "access token scope does not allow this request")
+	ErrSessionRequired   = apperr.Forbidden("session_required", "this endpoint requires a browser session")
+)
+
+// key Locals untuk request yang diautentikasi dengan personal access token atau session
+const (
+	localsToken   = "access_token"
+	localsSession = "user_session"
+)
+
+// lastUsedResolution interval minimal update last_used_at agar tidak menulis ke database setiap request
+const lastUsedResolution = time.Minute
+
 // AuthMiddleware holds dependencies for authentication middleware
 type AuthMiddleware struct {
-	cfg   *config.Config
-	store *session.Store
+	cfg      *config.Config
+	store    *session.Store
+	tokens   token_repo.TokenRepository
+	users    user_repo.UserRepository
+	sessions session_service.SessionService
 }
 
 // NewAuthMiddleware creates a new AuthMiddleware instance
-func NewAuthMiddleware(cfg *config.Config, store *session.Store) *AuthMiddleware {
+func NewAuthMiddleware(
+	cfg *config.Config,
+	store *session.Store,
+	tokens token_repo.TokenRepository,
+	users user_repo.UserRepository,
+	sessions session_service.SessionService,
+) *AuthMiddleware {
 	return &AuthMiddleware{
-		cfg:   cfg,
-		store: store,
+		cfg:      cfg,
+		store:    store,
+		tokens:   tokens,
+		users:    users,
+		sessions: sessions,
 	}
 }
 
-// RequireAuth is a middleware for session-based authentication
+// RequireAuth is a middleware for session or personal access token authentication.
+// Token dikirim lewat header "Authorization: Bearer <token>".
 func (am *AuthMiddleware) RequireAuth() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		if am.store == nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Session store not initialized",
-			})
+		if token, ok := bearerToken(c); ok {
+			return am.authenticateToken(c, token, false)
 		}
 
-		sess, err := am.store.Get(c)
-		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Unauthorized",
-			})
+		return am.authenticateSession(c)
+	}
+}
+
+// RequireAuthReadOnly sama seperti RequireAuth untuk endpoint non-GET yang tidak
+// mengubah data, contoh: lint. Token dengan scope read tetap diizinkan.
+func (am *AuthMiddleware) RequireAuthReadOnly() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if token, ok := bearerToken(c); ok {
+			return am.authenticateToken(c, token, true)
 		}
 
-		userID := sess.Get("user_id")
-		if userID == nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Unauthorized",
-			})
+		return am.authenticateSession(c)
+	}
+}
+
+// OptionalAuth autentikasi jika request membawa token atau session, selain itu
+// diteruskan sebagai anonim. Otorisasi diserahkan ke policy, contoh: document publik.
+func (am *AuthMiddleware) OptionalAuth() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if token, ok := bearerToken(c); ok {
+			return am.authenticateToken(c, token, false)
 		}
 
-		// Set user_id in Locals for easy access in controllers
-		c.Locals("user_id", userID)
+		if am.store != nil {
+			sess, err := am.store.Get(c)
+			if err == nil && sess.Get("user_id") != nil {
+				return am.authenticateSession(c)
+			}
+		}
 
 		return c.Next()
 	}
 }
 
+// RequireSession is a middleware for session-based authentication only
+func (am *AuthMiddleware) RequireSession() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := bearerToken(c); ok {
+			return ErrSessionRequired
+		}
+
+		return am.authenticateSession(c)
+	}
+}
+
+// GetUserSession session server-side yang dipakai request, nil jika lewat personal access token
+func GetUserSession(c *fiber.Ctx) *schema.UserSession {
+	userSession, _ := c.Locals(localsSession).(*schema.UserSession)
+	return userSession
+}
+
+// GetAccessToken personal access token yang dipakai request, nil jika lewat session
+func GetAccessToken(c *fiber.Ctx) *schema.PersonalAccessToken {
+	token, _ := c.Locals(localsToken).(*schema.PersonalAccessToken)
+	return token
+}
+
+func (am *AuthMiddleware) authenticateToken(c *fiber.Ctx, plain string, readOnly bool) error {
+	token, err := am.tokens.FindByHash(helpers.Hash([]byte(plain)))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+
+	// user pemilik token yang sudah dihapus membuat token tidak berlaku
+	if token.User == nil {
+		return ErrInvalidToken
+	}
+	if token.User.Disabled {
+		return ErrAccountDisabled
+	}
+
+	if token.Expired() {
+		return ErrTokenExpired
+	}
+
+	if !readOnly && !token.Allows(c.Method()) {
+		return ErrInsufficientScope
+	}
+
+	now := time.Now()
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedResolution {
+		if err := am.tokens.TouchLastUsed(token.ID, now); err != nil {
+			log.Warn().Err(err).Uint64("token_id", token.ID).Msg("failed to update token last_used_at")
+		}
+	}
+
+	c.Locals("user_id", token.UserID)
+	c.Locals(localsToken, token)
+
+	return c.Next()
+}
+
+func (am *AuthMiddleware) authenticateSession(c *fiber.Ctx) error {
+	if am.store == nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Session store not initialized")
+	}
+
+	sess, err := am.store.Get(c)
+	if err != nil {
+		return ErrUnauthorized
+	}
+
+	if sess.Get("user_id") == nil {
+		return ErrUnauthorized
+	}
+
+	// Cookie tanpa session_ref tidak bisa dicabut lewat user_sessions, dianggap tidak valid
+	ref, ok := sess.Get("session_ref").(uint64)
+	if !ok {
+		_ = sess.Destroy()
+		return ErrUnauthorized
+	}
+
+	userSession, err := am.sessions.ValidateSession(ref)
+	if err != nil {
+		if errors.Is(err, session_service.ErrSessionNotFound) {
+			err = ErrUnauthorized
+		}
+		// Session sudah dicabut atau kedaluwarsa, cookie ikut dibersihkan
+		if errors.Is(err, apperr.ErrUnauthorized) {
+			_ = sess.Destroy()
+		}
+		return err
+	}
+
+	// User yang dihapus atau dinonaktifkan membuat session tidak berlaku, sama seperti token
+	user, err := am.users.FindUserByID(userSession.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			_ = sess.Destroy()
+			return ErrUnauthorized
+		}
+		return err
+	}
+	if user.Disabled {
+		_ = sess.Destroy()
+		return ErrAccountDisabled
+	}
+
+	c.Locals(localsSession, userSession)
+
+	// Set user_id in Locals for easy access in controllers
+	c.Locals("user_id", userSession.UserID)
+
+	return c.Next()
+}
+
+// bearerToken ambil token dari header Authorization
+func bearerToken(c *fiber.Ctx) (string, bool) {
+	header := c.Get(fiber.HeaderAuthorization)
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
 // GetUserID helper to get user_id from Locals
 func GetUserID(c *fiber.Ctx) uint64 {
 	id := c.Locals("user_id")
@@ -89,12 +271,10 @@ func (am *AuthMiddleware) Protected() fiber.Handler {
 
 func jwtError(c *fiber.Ctx, err error) error {
 	if err.Error() == "Missing or malformed JWT" {
-		return c.Status(fiber.StatusBadRequest).
-			JSON(fiber.Map{"status": "error", "message": "Missing or malformed JWT", "data": nil})
+		return apperr.BadRequest("malformed_jwt", "Missing or malformed JWT")
 	}
 
-	return c.Status(fiber.StatusUnauthorized).
-		JSON(fiber.Map{"status": "error", "message": "Invalid or expired JWT", "data": nil})
+	return apperr.Unauthorized("invalid_jwt", "Invalid or expired JWT")
 }
 
 type JWTClaims struct {
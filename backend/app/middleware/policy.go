@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// key Locals untuk resource yang sudah dimuat oleh PolicyMiddleware
+const (
+	localsWorkspace = "policy_workspace"
+	localsDocument  = "policy_document"
+	localsShare     = "policy_share"
+)
+
+// PolicyMiddleware memuat resource dari path param dan menolak request lebih awal
+// jika policy tidak mengizinkan aksi
+type PolicyMiddleware struct {
+	policy policy.Policy
+	store  policy.Store
+}
+
+// NewPolicyMiddleware creates a new PolicyMiddleware instance
+func NewPolicyMiddleware(p policy.Policy, store policy.Store) *PolicyMiddleware {
+	return &PolicyMiddleware{
+		policy: p,
+		store:  store,
+	}
+}
+
+// Workspace otorisasi aksi terhadap workspace dengan id dari param
+func (pm *PolicyMiddleware) Workspace(action policy.Action, param string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		workspace, err := pm.loadWorkspace(c, param)
+		if err != nil {
+			return err
+		}
+
+		return pm.authorize(c, action, policy.WorkspaceResource(workspace))
+	}
+}
+
+// Document otorisasi aksi terhadap document dengan id dari param
+func (pm *PolicyMiddleware) Document(action policy.Action, param string) fiber.Handler {
+	return pm.document(action, "", param)
+}
+
+// WorkspaceDocument sama seperti Document, untuk route nested di bawah workspace.
+// Document yang bukan milik workspace di path dianggap tidak ada.
+func (pm *PolicyMiddleware) WorkspaceDocument(action policy.Action, workspaceParam, documentParam string) fiber.Handler {
+	return pm.document(action, workspaceParam, documentParam)
+}
+
+// ShareLink otorisasi akses anonim via token share link dari param
+func (pm *PolicyMiddleware) ShareLink(action policy.Action, param string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		share, err := pm.store.FindShareLink(c.Params(param))
+		if err != nil {
+			return notFoundOr(err, policy.ErrShareLinkNotFound)
+		}
+
+		if policy.Expired(share) {
+			return policy.ErrShareLinkExpired
+		}
+
+		// document atau workspace yang sudah dihapus ikut menonaktifkan share link
+		document, err := pm.store.FindDocument(share.DocumentID)
+		if err != nil {
+			return notFoundOr(err, policy.ErrShareLinkNotFound)
+		}
+
+		workspace, err := pm.store.FindWorkspace(document.WorkspaceID)
+		if err != nil {
+			return notFoundOr(err, policy.ErrShareLinkNotFound)
+		}
+
+		c.Locals(localsWorkspace, workspace)
+		c.Locals(localsDocument, document)
+		c.Locals(localsShare, share)
+
+		return pm.authorize(c, action, policy.ShareResource(workspace, document, share))
+	}
+}
+
+func (pm *PolicyMiddleware) document(action policy.Action, workspaceParam, documentParam string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseUint(c.Params(documentParam), 10, 64)
+		if err != nil {
+			return apperr.BadRequest("invalid_document_id", "invalid document id")
+		}
+
+		document, err := pm.store.FindDocument(id)
+		if err != nil {
+			return notFoundOr(err, policy.ErrDocumentNotFound)
+		}
+
+		if workspaceParam != "" {
+			workspaceID, err := strconv.ParseUint(c.Params(workspaceParam), 10, 64)
+			if err != nil {
+				return apperr.BadRequest("invalid_workspace_id", "invalid workspace id")
+			}
+			if document.WorkspaceID != workspaceID {
+				return policy.ErrDocumentNotFound
+			}
+		}
+
+		workspace, err := pm.store.FindWorkspace(document.WorkspaceID)
+		if err != nil {
+			return notFoundOr(err, policy.ErrWorkspaceNotFound)
+		}
+
+		c.Locals(localsWorkspace, workspace)
+		c.Locals(localsDocument, document)
+
+		return pm.authorize(c, action, policy.DocumentResource(workspace, document))
+	}
+}
+
+func (pm *PolicyMiddleware) loadWorkspace(c *fiber.Ctx, param string) (*schema.Workspace, error) {
+	id, err := strconv.ParseUint(c.Params(param), 10, 64)
+	if err != nil {
+		return nil, apperr.BadRequest("invalid_workspace_id", "invalid workspace id")
+	}
+
+	workspace, err := pm.store.FindWorkspace(id)
+	if err != nil {
+		return nil, notFoundOr(err, policy.ErrWorkspaceNotFound)
+	}
+
+	c.Locals(localsWorkspace, workspace)
+	return workspace, nil
+}
+
+func (pm *PolicyMiddleware) authorize(c *fiber.Ctx, action policy.Action, resource policy.Resource) error {
+	allowed, err := pm.policy.Can(policy.User(GetUserID(c)), action, resource)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return policy.Denied(action)
+	}
+
+	return c.Next()
+}
+
+// notFoundOr ubah record not found menjadi error not found yang diberikan
+func notFoundOr(err error, notFound error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return notFound
+	}
+	return err
+}
+
+// GetWorkspace workspace yang sudah dimuat PolicyMiddleware, nil jika tidak ada
+func GetWorkspace(c *fiber.Ctx) *schema.Workspace {
+	workspace, _ := c.Locals(localsWorkspace).(*schema.Workspace)
+	return workspace
+}
+
+// GetDocument document yang sudah dimuat PolicyMiddleware, nil jika tidak ada
+func GetDocument(c *fiber.Ctx) *schema.Document {
+	document, _ := c.Locals(localsDocument).(*schema.Document)
+	return document
+}
+
+// GetShare share link yang sudah dimuat PolicyMiddleware, nil jika tidak ada
+func GetShare(c *fiber.Ctx) *schema.SharedAccess {
+	share, _ := c.Locals(localsShare).(*schema.SharedAccess)
+	return share
+}
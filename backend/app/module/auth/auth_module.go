@@ -20,9 +20,12 @@ type AuthRouter struct {
 var NewAuthModule = fx.Options(
 	// register repository of auth module
 	fx.Provide(user_repo.NewUserRepository),
+	fx.Provide(user_repo.NewIdentityRepository),
+	fx.Provide(user_repo.NewRecoveryCodeRepository),
 
 	// register service of auth module
 	fx.Provide(service.NewAuthService),
+	fx.Provide(service.NewTwoFactorService),
 
 	// register controller of auth module
 	fx.Provide(controller.NewController),
@@ -44,12 +47,27 @@ func NewAuthRouter(fiber *fiber.App, controller *controller.Controller, authMw *
 func (_i *AuthRouter) RegisterAuthRoutes() {
 	// define controllers
 	authController := _i.Controller.Auth
+	twoFactorController := _i.Controller.TwoFactor
 
 	// define routes
 	_i.App.Route("/auth", func(router fiber.Router) {
+		router.Get("/providers", authController.Providers)
 		router.Get("/login", authController.Login)
+		router.Get("/login/:provider", authController.Login)
 		router.Get("/callback", authController.Callback)
+		router.Get("/callback/:provider", authController.Callback)
+		router.Post("/login", authController.LocalLogin)
+		router.Post("/login/2fa", authController.LocalLoginTwoFactor)
+		router.Post("/register", authController.Register)
 		router.Get("/me", _i.AuthMiddleware.RequireAuth(), authController.Me)
-		router.Post("/logout", _i.AuthMiddleware.RequireAuth(), authController.Logout)
+		router.Get("/link/:provider", _i.AuthMiddleware.RequireSession(), authController.Link)
+		router.Get("/identities", _i.AuthMiddleware.RequireAuth(), authController.ListIdentities)
+		router.Delete("/identities/:id", _i.AuthMiddleware.RequireSession(), authController.UnlinkIdentity)
+		router.Get("/2fa", _i.AuthMiddleware.RequireSession(), twoFactorController.Status)
+		router.Post("/2fa/setup", _i.AuthMiddleware.RequireSession(), twoFactorController.Setup)
+		router.Post("/2fa/enable", _i.AuthMiddleware.RequireSession(), twoFactorController.Enable)
+		router.Post("/2fa/disable", _i.AuthMiddleware.RequireSession(), twoFactorController.Disable)
+		router.Post("/2fa/recovery-codes", _i.AuthMiddleware.RequireSession(), twoFactorController.RegenerateRecoveryCodes)
+		router.Post("/logout", _i.AuthMiddleware.RequireSession(), authController.Logout)
 	})
 }
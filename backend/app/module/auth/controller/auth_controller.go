@@ -1,30 +1,52 @@
 package controller
 
 import (
+	"errors"
+	"strconv"
 	"time"
 
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/auth/request"
+	auth_response "git.dev.siap.id/kukuhkkh/app-diagram/app/module/auth/response"
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/auth/service"
+	session_service "git.dev.siap.id/kukuhkkh/app-diagram/app/module/usersession/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
 	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
 	"git.dev.siap.id/kukuhkkh/app-diagram/utils/response"
 	"github.com/gofiber/fiber/v2"
 	fsession "github.com/gofiber/fiber/v2/middleware/session"
 )
 
+// Challenge 2FA login lokal, disimpan di session sampai code dikirim
+const (
+	twoFactorChallengeTTL = 5 * time.Minute
+	twoFactorMaxAttempts  = 5
+)
+
 type authController struct {
-	authService    service.AuthService
-	cfg            *config.Config
-	cookieTemplate *fiber.Cookie
-	sessStore      *fsession.Store
+	authService      service.AuthService
+	twoFactorService service.TwoFactorService
+	cfg              *config.Config
+	cookieTemplate   *fiber.Cookie
+	sessStore        *fsession.Store
+	sessionService   session_service.SessionService
 }
 
 type AuthController interface {
+	Providers(c *fiber.Ctx) error
 	Login(c *fiber.Ctx) error
 	Callback(c *fiber.Ctx) error
+	Link(c *fiber.Ctx) error
+	ListIdentities(c *fiber.Ctx) error
+	UnlinkIdentity(c *fiber.Ctx) error
+	LocalLogin(c *fiber.Ctx) error
+	LocalLoginTwoFactor(c *fiber.Ctx) error
+	Register(c *fiber.Ctx) error
 	Me(c *fiber.Ctx) error
 	Logout(c *fiber.Ctx) error
 }
 
-func NewAuthController(authService service.AuthService, cfg *config.Config, sessStore *fsession.Store) AuthController {
+func NewAuthController(authService service.AuthService, twoFactorService service.TwoFactorService, cfg *config.Config, sessStore *fsession.Store, sessionService session_service.SessionService) AuthController {
 	tmpl := &fiber.Cookie{
 		Name:     cfg.Cookie.Name,
 		HTTPOnly: cfg.Cookie.HTTPOnly,
@@ -34,15 +56,27 @@ func NewAuthController(authService service.AuthService, cfg *config.Config, sess
 	}
 
 	return &authController{
-		authService:    authService,
-		cfg:            cfg,
-		cookieTemplate: tmpl,
-		sessStore:      sessStore,
+		authService:      authService,
+		twoFactorService: twoFactorService,
+		cfg:              cfg,
+		cookieTemplate:   tmpl,
+		sessStore:        sessStore,
+		sessionService:   sessionService,
 	}
 }
 
+// Providers daftar metode login yang tersedia untuk halaman login frontend
+func (_i *authController) Providers(c *fiber.Ctx) error {
+	return response.Resp(c, response.Response{
+		Data:     _i.authService.Providers(),
+		Messages: response.Messages{"Get providers success"},
+		Code:     fiber.StatusOK,
+	})
+}
+
+// Login redirect ke identity provider, tanpa :provider memakai provider default
 func (_i *authController) Login(c *fiber.Ctx) error {
-	url, state, verifier, err := _i.authService.GetAuthURL()
+	authReq, err := _i.authService.GetAuthURL(c.Params("provider"))
 	if err != nil {
 		return err
 	}
@@ -56,14 +90,17 @@ func (_i *authController) Login(c *fiber.Ctx) error {
 		return err
 	}
 
-	sess.Set("oidc_state", state)
-	sess.Set("oidc_verifier", verifier)
+	// Regenerate mempertahankan data session, sisa Link yang tidak selesai harus dibuang
+	sess.Delete("oidc_link_state")
+	sess.Set("oidc_provider", authReq.Provider)
+	sess.Set("oidc_state", authReq.State)
+	sess.Set("oidc_verifier", authReq.Verifier)
 
 	if err := sess.Save(); err != nil {
 		return err
 	}
 
-	return c.Redirect(url)
+	return c.Redirect(authReq.URL)
 }
 
 func (_i *authController) Callback(c *fiber.Ctx) error {
@@ -72,45 +109,221 @@ func (_i *authController) Callback(c *fiber.Ctx) error {
 		return err
 	}
 
+	sessionProvider, _ := sess.Get("oidc_provider").(string)
 	sessionState, _ := sess.Get("oidc_state").(string)
 	sessionVerifier, _ := sess.Get("oidc_verifier").(string)
+	linkState, _ := sess.Get("oidc_link_state").(string)
 
-	userID, err := _i.authService.HandleCallback(c.Query("code"), c.Query("state"), sessionState, sessionVerifier)
-	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	// Callback harus datang dari provider yang sama dengan saat login
+	if provider := c.Params("provider"); provider != "" && provider != sessionProvider {
+		return apperr.Unauthorized("invalid_state", "invalid state")
 	}
 
-	sess.Set("user_id", userID)
+	sess.Delete("oidc_provider")
 	sess.Delete("oidc_state")
 	sess.Delete("oidc_verifier")
+	sess.Delete("oidc_link_state")
 
-	if err := sess.Save(); err != nil {
+	// Menautkan identity ke user yang sedang login, session tetap milik user tersebut.
+	// Hanya berlaku untuk state yang dibuat oleh Link, bukan oleh Login berikutnya
+	if linkState != "" && linkState == sessionState {
+		userID, _ := sess.Get("user_id").(uint64)
+		if userID == 0 {
+			return middleware.ErrUnauthorized
+		}
+
+		if err := _i.authService.LinkCallback(userID, sessionProvider, c.Query("code"), c.Query("state"), sessionState, sessionVerifier); err != nil {
+			return err
+		}
+
+		if err := sess.Save(); err != nil {
+			return err
+		}
+
+		return c.Redirect(_i.cfg.App.FrontendUrl)
+	}
+
+	userID, tokens, err := _i.authService.HandleCallback(sessionProvider, c.Query("code"), c.Query("state"), sessionState, sessionVerifier)
+	if err != nil {
+		return err
+	}
+
+	if err := _i.startSession(c, userID, tokens); err != nil {
 		return err
 	}
 
 	return c.Redirect(_i.cfg.App.FrontendUrl)
 }
 
-func (_i *authController) Me(c *fiber.Ctx) error {
+// Link redirect ke identity provider untuk menautkan akun tambahan ke user yang sedang login
+func (_i *authController) Link(c *fiber.Ctx) error {
+	authReq, err := _i.authService.GetAuthURL(c.Params("provider"))
+	if err != nil {
+		return err
+	}
+
+	sess, err := _i.sessStore.Get(c)
+	if err != nil {
+		return err
+	}
+
+	sess.Set("oidc_provider", authReq.Provider)
+	sess.Set("oidc_state", authReq.State)
+	sess.Set("oidc_verifier", authReq.Verifier)
+	sess.Set("oidc_link_state", authReq.State)
+
+	if err := sess.Save(); err != nil {
+		return err
+	}
+
+	return c.Redirect(authReq.URL)
+}
+
+// ListIdentities identity provider yang tertaut ke user yang sedang login
+func (_i *authController) ListIdentities(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	res, err := _i.authService.ListIdentities(userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Data:     res,
+		Messages: response.Messages{"Get identities success"},
+		Code:     fiber.StatusOK,
+	})
+}
+
+// UnlinkIdentity melepas identity provider dari user yang sedang login
+func (_i *authController) UnlinkIdentity(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	identityID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_identity_id", "invalid identity id")
+	}
+
+	if err := _i.authService.UnlinkIdentity(userID, identityID); err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Messages: response.Messages{"Unlink identity success"},
+		Code:     fiber.StatusOK,
+	})
+}
+
+// LocalLogin login dengan email dan password, membuat session yang sama seperti Callback
+func (_i *authController) LocalLogin(c *fiber.Ctx) error {
+	var req request.LoginRequest
+	if err := response.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	res, twoFactorRequired, err := _i.authService.Login(req)
+	if err != nil {
+		return err
+	}
+
+	if twoFactorRequired {
+		return _i.startTwoFactorChallenge(c, res.ID)
+	}
+
+	if err := _i.startSession(c, res.ID, nil); err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Data:     res,
+		Messages: response.Messages{"Login success"},
+		Code:     fiber.StatusOK,
+	})
+}
+
+// LocalLoginTwoFactor langkah kedua login lokal dengan code TOTP atau recovery code
+func (_i *authController) LocalLoginTwoFactor(c *fiber.Ctx) error {
+	var req request.TwoFactorCodeRequest
+	if err := response.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
 	sess, err := _i.sessStore.Get(c)
 	if err != nil {
 		return err
 	}
 
-	id := sess.Get("user_id")
-	if id == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	userID, _ := sess.Get("two_factor_user_id").(uint64)
+	expiresAt, _ := sess.Get("two_factor_expires_at").(int64)
+	attempts, _ := sess.Get("two_factor_attempts").(int)
+
+	if userID == 0 || time.Now().Unix() > expiresAt || attempts >= twoFactorMaxAttempts {
+		_i.clearTwoFactorChallenge(sess)
+		if err := sess.Save(); err != nil {
+			return err
+		}
+		return service.ErrTwoFactorChallengeEnded
+	}
+
+	res, err := _i.twoFactorService.VerifyLogin(userID, req)
+	if err != nil {
+		// Percobaan gagal dihitung agar code 6 digit tidak bisa ditebak
+		if errors.Is(err, service.ErrInvalidTwoFactorCode) {
+			sess.Set("two_factor_attempts", attempts+1)
+		} else {
+			_i.clearTwoFactorChallenge(sess)
+		}
+		if saveErr := sess.Save(); saveErr != nil {
+			return saveErr
+		}
+		return err
+	}
+
+	if err := _i.startSession(c, res.ID, nil); err != nil {
+		return err
 	}
 
-	// Handle type assertion safely
-	var userID uint64
-	switch v := id.(type) {
-	case uint64:
-		userID = v
-	case float64:
-		userID = uint64(v)
-	default:
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid session data"})
+	return response.Resp(c, response.Response{
+		Data:     res,
+		Messages: response.Messages{"Login success"},
+		Code:     fiber.StatusOK,
+	})
+}
+
+// Register membuat akun lokal dan langsung login
+func (_i *authController) Register(c *fiber.Ctx) error {
+	var req request.RegisterRequest
+	if err := response.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	res, err := _i.authService.Register(req)
+	if err != nil {
+		return err
+	}
+
+	if err := _i.startSession(c, res.ID, nil); err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Data:     res,
+		Messages: response.Messages{"Register success"},
+		Code:     fiber.StatusCreated,
+	})
+}
+
+// Me user yang sedang login, lewat session maupun personal access token
+func (_i *authController) Me(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
 	}
 
 	res, err := _i.authService.Me(userID)
@@ -131,11 +344,20 @@ func (_i *authController) Logout(c *fiber.Ctx) error {
 		return err
 	}
 
+	// Provider diambil sebelum session dihapus
+	provider, _ := sess.Get("auth_provider").(string)
+
+	if ref, ok := sess.Get("session_ref").(uint64); ok {
+		if err := _i.sessionService.EndSession(ref); err != nil {
+			return err
+		}
+	}
+
 	if err := sess.Destroy(); err != nil {
 		return err
 	}
 
-	logoutURL := _i.authService.GetLogoutURL()
+	logoutURL := _i.authService.GetLogoutURL(provider)
 
 	return response.Resp(c, response.Response{
 		Messages: response.Messages{"Logout success"},
@@ -144,6 +366,70 @@ func (_i *authController) Logout(c *fiber.Ctx) error {
 	})
 }
 
+// startSession ganti session id untuk mencegah session fixation lalu simpan user_id
+// dan referensi ke session server-side, tokens nil untuk login lokal
+func (_i *authController) startSession(c *fiber.Ctx, userID uint64, tokens *session_service.IdPTokens) error {
+	sess, err := _i.sessStore.Get(c)
+	if err != nil {
+		return err
+	}
+
+	if err := sess.Regenerate(); err != nil {
+		return err
+	}
+
+	userSession, err := _i.sessionService.StartSession(userID, tokens, c.Get(fiber.HeaderUserAgent), c.IP())
+	if err != nil {
+		return err
+	}
+
+	_i.clearTwoFactorChallenge(sess)
+	sess.Set("user_id", userID)
+	sess.Set("session_ref", userSession.ID)
+	if tokens != nil {
+		sess.Set("auth_provider", tokens.Provider)
+	}
+
+	return sess.Save()
+}
+
+// startTwoFactorChallenge password sudah benar, session hanya menyimpan challenge
+// dan belum berisi user_id sampai faktor kedua diverifikasi
+func (_i *authController) startTwoFactorChallenge(c *fiber.Ctx, userID uint64) error {
+	sess, err := _i.sessStore.Get(c)
+	if err != nil {
+		return err
+	}
+
+	if err := sess.Regenerate(); err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(twoFactorChallengeTTL)
+	sess.Set("two_factor_user_id", userID)
+	sess.Set("two_factor_expires_at", expiresAt.Unix())
+	sess.Set("two_factor_attempts", 0)
+
+	if err := sess.Save(); err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Data: auth_response.LoginChallengeResponse{
+			TwoFactorRequired: true,
+			ExpiresAt:         expiresAt,
+		},
+		Messages: response.Messages{"Two-factor authentication required"},
+		Code:     fiber.StatusOK,
+	})
+}
+
+func (_i *authController) clearTwoFactorChallenge(sess *fsession.Session) {
+	sess.Delete("two_factor_user_id")
+	sess.Delete("two_factor_expires_at")
+	sess.Delete("two_factor_attempts")
+}
+
 func (_i *authController) makeCookie(value string, expires time.Time) *fiber.Cookie {
 	c := *(_i.cookieTemplate)
 	c.Value = value
@@ -2,16 +2,19 @@ package controller
 
 import (
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/auth/service"
+	session_service "git.dev.siap.id/kukuhkkh/app-diagram/app/module/usersession/service"
 	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
 	"github.com/gofiber/fiber/v2/middleware/session"
 )
 
 type Controller struct {
-	Auth AuthController
+	Auth      AuthController
+	TwoFactor TwoFactorController
 }
 
-func NewController(authService service.AuthService, cfg *config.Config, sessStore *session.Store) *Controller {
+func NewController(authService service.AuthService, twoFactorService service.TwoFactorService, cfg *config.Config, sessStore *session.Store, sessionService session_service.SessionService) *Controller {
 	return &Controller{
-		Auth: NewAuthController(authService, cfg, sessStore),
+		Auth:      NewAuthController(authService, twoFactorService, cfg, sessStore, sessionService),
+		TwoFactor: NewTwoFactorController(twoFactorService),
 	}
 }
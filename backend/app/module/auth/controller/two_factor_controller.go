@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/auth/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/auth/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type twoFactorController struct {
+	twoFactorService service.TwoFactorService
+}
+
+type TwoFactorController interface {
+	Status(c *fiber.Ctx) error
+	Setup(c *fiber.Ctx) error
+	Enable(c *fiber.Ctx) error
+	Disable(c *fiber.Ctx) error
+	RegenerateRecoveryCodes(c *fiber.Ctx) error
+}
+
+func NewTwoFactorController(twoFactorService service.TwoFactorService) TwoFactorController {
+	return &twoFactorController{
+		twoFactorService: twoFactorService,
+	}
+}
+
+// Status handler untuk status 2FA dan sisa recovery code
+func (_i *twoFactorController) Status(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	result, err := _i.twoFactorService.Status(userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Data:     result,
+		Messages: response.Messages{"Get two-factor status success"},
+		Code:     fiber.StatusOK,
+	})
+}
+
+// Setup handler untuk membuat secret baru, provisioning URI di-render frontend sebagai QR code
+func (_i *twoFactorController) Setup(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	result, err := _i.twoFactorService.Setup(userID)
+	if err != nil {
+		return err
+	}
+
+	// Secret hanya boleh tampil sekali
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	return response.Resp(c, response.Response{
+		Data:     result,
+		Messages: response.Messages{"Two-factor setup started"},
+		Code:     fiber.StatusOK,
+	})
+}
+
+// Enable handler untuk mengaktifkan 2FA dengan code pertama dari authenticator
+func (_i *twoFactorController) Enable(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	var req request.TwoFactorCodeRequest
+	if err := response.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	result, err := _i.twoFactorService.Enable(userID, req)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	return response.Resp(c, response.Response{
+		Data:     result,
+		Messages: response.Messages{"Two-factor authentication enabled"},
+		Code:     fiber.StatusOK,
+	})
+}
+
+// Disable handler untuk mematikan 2FA, butuh password dan code
+func (_i *twoFactorController) Disable(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	var req request.DisableTwoFactorRequest
+	if err := response.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if err := _i.twoFactorService.Disable(userID, req); err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Messages: response.Messages{"Two-factor authentication disabled"},
+		Code:     fiber.StatusOK,
+	})
+}
+
+// RegenerateRecoveryCodes handler untuk mengganti semua recovery code
+func (_i *twoFactorController) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	var req request.TwoFactorCodeRequest
+	if err := response.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	result, err := _i.twoFactorService.RegenerateRecoveryCodes(userID, req)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	return response.Resp(c, response.Response{
+		Data:     result,
+		Messages: response.Messages{"Recovery codes regenerated"},
+		Code:     fiber.StatusOK,
+	})
+}
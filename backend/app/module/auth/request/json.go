@@ -6,6 +6,17 @@ type LoginRequest struct {
 }
 
 type RegisterRequest struct {
+	Name     string `json:"name" example:"John Doe" validate:"omitempty,max=255"`
 	Email    string `json:"email" example:"john.doe@gmail.com" validate:"required,email"`
 	Password string `json:"password" example:"12345678" validate:"required,min=8,max=255"`
 }
+
+// TwoFactorCodeRequest code dari aplikasi authenticator atau recovery code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" example:"123456" validate:"required,max=32"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" example:"12345678" validate:"required,max=255"`
+	Code     string `json:"code" example:"123456" validate:"required,max=32"`
+}
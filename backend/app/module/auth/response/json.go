@@ -1,18 +1,57 @@
 package response
 
-type LoginResponse struct {
-	Token     string `json:"token"`
-	Type      string `json:"type"`
-	ExpiresAt int64  `json:"expires_at"`
-}
-
-type RegisterResponse struct {
-	ID    uint64 `json:"id"`
-	Email string `json:"email"`
-}
+import "time"
 
 type UserResponse struct {
 	ID    uint64 `json:"id"`
 	Name  string `json:"name"`
 	Email string `json:"email"`
 }
+
+type ProvidersResponse struct {
+	Local     LocalAuthResponse  `json:"local"`
+	Providers []ProviderResponse `json:"providers"`
+}
+
+type LocalAuthResponse struct {
+	Enabled           bool `json:"enabled"`
+	AllowRegistration bool `json:"allow_registration"`
+}
+
+type ProviderResponse struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	LoginURL    string `json:"login_url"`
+}
+
+type IdentityResponse struct {
+	ID           uint64     `json:"id"`
+	Provider     string     `json:"provider"`
+	ProviderName string     `json:"provider_name"`
+	Email        string     `json:"email"`
+	LastLoginAt  *time.Time `json:"last_login_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// LoginChallengeResponse password benar tetapi login masih butuh faktor kedua
+type LoginChallengeResponse struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+type TwoFactorStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorSetupResponse secret untuk enrollment, provisioning_uri di-render sebagai QR code
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// RecoveryCodesResponse recovery code hanya ditampilkan sekali
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
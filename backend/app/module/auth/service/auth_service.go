@@ -3,7 +3,6 @@ package service
 import (
 	"context"
 	"errors"
-	"fmt"
 	"strings"
 	"time"
 
@@ -11,237 +10,578 @@ import (
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/auth/request"
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/auth/response"
+	share_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/share/repository"
 	user_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/user/repository"
+	session_service "git.dev.siap.id/kukuhkkh/app-diagram/app/module/usersession/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
 	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
 	"git.dev.siap.id/kukuhkkh/app-diagram/utils/helpers"
-	"github.com/coreos/go-oidc/v3/oidc"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/sso"
 	"golang.org/x/oauth2"
+	"gorm.io/gorm"
 )
 
 // AuthService
 type AuthService interface {
-	Login(req request.LoginRequest) (res response.LoginResponse, err error)
-	Register(req request.RegisterRequest) (res response.RegisterResponse, err error)
+	Login(req request.LoginRequest) (res response.UserResponse, twoFactorRequired bool, err error)
+	Register(req request.RegisterRequest) (res response.UserResponse, err error)
 	Me(userID uint64) (res response.UserResponse, err error)
 
 	// OIDC methods
-	GetAuthURL() (url, state, verifier string, err error)
-	HandleCallback(code, state, sessionState, sessionVerifier string) (userID uint64, err error)
-	GetLogoutURL() string
+	Providers() response.ProvidersResponse
+	GetAuthURL(provider string) (req *AuthRequest, err error)
+	HandleCallback(provider, code, state, sessionState, sessionVerifier string) (userID uint64, tokens *session_service.IdPTokens, err error)
+	GetLogoutURL(provider string) string
+
+	// Identity yang tertaut ke user
+	LinkCallback(userID uint64, provider, code, state, sessionState, sessionVerifier string) error
+	ListIdentities(userID uint64) ([]response.IdentityResponse, error)
+	UnlinkIdentity(userID uint64, identityID uint64) error
+}
+
+// AuthRequest authorization code flow yang sedang berjalan, disimpan di session sampai callback
+type AuthRequest struct {
+	Provider string
+	URL      string
+	State    string
+	Verifier string
 }
 
 type userService struct {
-	userRepo user_repo.UserRepository
-	cfg      *config.Config
-	authMw   *middleware.AuthMiddleware
+	userRepo     user_repo.UserRepository
+	identityRepo user_repo.IdentityRepository
+	shareRepo    share_repo.ShareRepository
+	sso          *sso.Registry
+	cfg          *config.Config
+	authMw       *middleware.AuthMiddleware
 }
 
+// Error auth service
+var (
+	ErrInvalidCredentials = apperr.Unauthorized("invalid_credentials", "Email or password is incorrect")
+	ErrAccountDisabled    = apperr.Unauthorized("account_disabled", "account is disabled")
+	ErrEmailExists        = apperr.Conflict("email_already_exists", "email already exists")
+	ErrUserNotFound       = apperr.NotFound("user_not_found", "user not found")
+
+	ErrLocalAuthDisabled    = apperr.Forbidden("local_auth_disabled", "local authentication is disabled")
+	ErrRegistrationDisabled = apperr.Forbidden("registration_disabled", "registration is disabled")
+	ErrProviderNotFound     = apperr.NotFound("sso_provider_not_found", "sso provider not found")
+
+	ErrIdentityNotFound = apperr.NotFound("identity_not_found", "identity not found")
+	ErrIdentityInUse    = apperr.Conflict("identity_in_use", "this account is already linked to another user")
+	ErrAccountExists    = apperr.Conflict("account_exists", "an account with this email already exists, sign in to it and link this provider from your profile")
+	ErrLastLoginMethod  = apperr.Conflict("last_login_method", "cannot unlink the only way to sign in to this account")
+	ErrEmailRequired    = apperr.Unauthorized("email_required", "the identity provider did not return an email address")
+)
+
 // init AuthService
-func NewAuthService(userRepo user_repo.UserRepository, cfg *config.Config, authMw *middleware.AuthMiddleware) AuthService {
+func NewAuthService(
+	userRepo user_repo.UserRepository,
+	identityRepo user_repo.IdentityRepository,
+	shareRepo share_repo.ShareRepository,
+	registry *sso.Registry,
+	cfg *config.Config,
+	authMw *middleware.AuthMiddleware,
+) AuthService {
 	return &userService{
-		userRepo: userRepo,
-		cfg:      cfg,
-		authMw:   authMw,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		shareRepo:    shareRepo,
+		sso:          registry,
+		cfg:          cfg,
+		authMw:       authMw,
 	}
 }
 
-func (_i *userService) Login(req request.LoginRequest) (res response.LoginResponse, err error) {
-	// check user by email
-	user, err := _i.userRepo.FindUserByEmail(req.Email)
-	if err != nil {
-		err = errors.New("Email or password is incorrect")
+// Login autentikasi akun lokal dengan email dan password. Hash format lama
+// di-upgrade ke argon2id setelah password terverifikasi. twoFactorRequired
+// true berarti login baru selesai setelah TwoFactorService.VerifyLogin.
+func (_i *userService) Login(req request.LoginRequest) (res response.UserResponse, twoFactorRequired bool, err error) {
+	if !_i.cfg.Auth.Local.Enable {
+		err = ErrLocalAuthDisabled
 		return
 	}
 
-	if user == nil {
-		err = errors.New("Email or password is incorrect")
+	// check user by email
+	user, err := _i.userRepo.FindUserByEmail(helpers.NormalizeEmail(req.Email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = ErrInvalidCredentials
+		}
 		return
 	}
 
 	// check password
-	if !user.ComparePassword(req.Password) {
-		err = errors.New("Email or password is incorrect")
+	match, needsRehash := user.ComparePassword(req.Password)
+	if !match {
+		err = ErrInvalidCredentials
 		return
 	}
 
-	// do create token
-	claims, err := _i.authMw.GenerateTokenAccess(user.ID)
-	if err != nil {
+	// Dicek setelah password agar status akun tidak bocor ke yang tidak tahu password
+	if user.Disabled {
+		err = ErrAccountDisabled
 		return
 	}
 
-	res.Token = claims.Token
-	res.Type = claims.Type
-	res.ExpiresAt = claims.ExpiresAt.Unix()
+	if needsRehash {
+		hashed, hashErr := helpers.HashPassword(req.Password)
+		if hashErr != nil {
+			err = hashErr
+			return
+		}
+		user.Password = &hashed
+	}
+
+	// Last login dicatat setelah faktor kedua
+	twoFactorRequired = user.TwoFactorEnabled()
+	if !twoFactorRequired {
+		now := time.Now()
+		user.LastLogin = &now
+	}
 
-	return
+	if needsRehash || !twoFactorRequired {
+		if err = _i.userRepo.UpdateUser(user); err != nil {
+			return
+		}
+	}
+
+	// Pending invite tidak diklaim di sini karena email akun lokal belum diverifikasi
+
+	return toUserResponse(user), twoFactorRequired, nil
 }
 
-func (_i *userService) Register(req request.RegisterRequest) (res response.RegisterResponse, err error) {
+// Register membuat akun lokal baru
+func (_i *userService) Register(req request.RegisterRequest) (res response.UserResponse, err error) {
+	if !_i.cfg.Auth.Local.Enable {
+		err = ErrLocalAuthDisabled
+		return
+	}
+	if !_i.cfg.Auth.Local.AllowRegistration {
+		err = ErrRegistrationDisabled
+		return
+	}
+
+	email := helpers.NormalizeEmail(req.Email)
+
 	// check user by email
-	user, err := _i.userRepo.FindUserByEmail(req.Email)
-	if err != nil {
+	if _, err = _i.userRepo.FindUserByEmail(email); err == nil {
+		err = ErrEmailExists
+		return
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
 		return
 	}
 
-	if user != nil {
-		err = errors.New("email already exists")
+	hashed, err := helpers.HashPassword(req.Password)
+	if err != nil {
 		return
 	}
 
-	// do create user
-	user = &schema.User{
-		Email:    req.Email,
-		Password: &req.Password,
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = strings.SplitN(email, "@", 2)[0]
 	}
 
-	user, err = _i.userRepo.CreateUser(user)
+	// do create user
+	now := time.Now()
+	user, err := _i.userRepo.CreateUser(&schema.User{
+		Name:      name,
+		Email:     email,
+		Password:  &hashed,
+		LastLogin: &now,
+	})
 	if err != nil {
 		return
 	}
 
-	res.ID = user.ID
-	res.Email = user.Email
-
-	return
+	return toUserResponse(user), nil
 }
 
 func (_i *userService) Me(userID uint64) (res response.UserResponse, err error) {
 	// check user by id
 	user, err := _i.userRepo.FindUserByID(userID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = ErrUserNotFound
+		}
 		return
 	}
 
-	if user == nil {
-		err = errors.New("user not found")
-		return
+	return toUserResponse(user), nil
+}
+
+func (_i *userService) Providers() response.ProvidersResponse {
+	res := response.ProvidersResponse{
+		Local: response.LocalAuthResponse{
+			Enabled:           _i.cfg.Auth.Local.Enable,
+			AllowRegistration: _i.cfg.Auth.Local.Enable && _i.cfg.Auth.Local.AllowRegistration,
+		},
+		Providers: make([]response.ProviderResponse, 0, len(_i.sso.List())),
 	}
 
-	res.ID = user.ID
-	res.Name = user.Name
-	res.Email = user.Email
+	for _, p := range _i.sso.List() {
+		res.Providers = append(res.Providers, response.ProviderResponse{
+			Name:        p.Name,
+			DisplayName: p.DisplayName,
+			LoginURL:    "/auth/login/" + p.Name,
+		})
+	}
 
-	return
+	return res
 }
 
-func (s *userService) GetAuthURL() (url, state, verifier string, err error) {
-	state = helpers.GenerateRandomString(32)
-	verifier = helpers.GenerateRandomString(64)
+func (_i *userService) GetAuthURL(providerName string) (req *AuthRequest, err error) {
+	provider, err := _i.provider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	conf, err := provider.OAuth2Config(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := helpers.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	// 32 byte menjadi 43 karakter, panjang minimal code verifier RFC 7636
+	verifier, err := helpers.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
 	challenge := helpers.GenerateCodeChallenge(verifier)
 
-	conf := s.getOauth2Config()
-	url = conf.AuthCodeURL(state,
+	url := conf.AuthCodeURL(state,
 		oauth2.AccessTypeOffline,
 		oauth2.SetAuthURLParam("code_challenge", challenge),
 		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
 	)
 
-	return
+	return &AuthRequest{
+		Provider: provider.Name,
+		URL:      url,
+		State:    state,
+		Verifier: verifier,
+	}, nil
 }
 
-func (s *userService) HandleCallback(code, state, sessionState, sessionVerifier string) (userID uint64, err error) {
-	if state != sessionState {
-		return 0, errors.New("invalid state")
+func (_i *userService) HandleCallback(providerName, code, state, sessionState, sessionVerifier string) (userID uint64, tokens *session_service.IdPTokens, err error) {
+	claims, tokens, err := _i.exchange(providerName, code, state, sessionState, sessionVerifier)
+	if err != nil {
+		return 0, nil, err
 	}
 
-	ctx := context.Background()
-	endpoint := strings.TrimSuffix(s.cfg.Sso.Logto.Endpoint, "/")
-	if !strings.HasSuffix(endpoint, "/oidc") {
-		endpoint = endpoint + "/oidc"
+	now := time.Now()
+
+	// Provision user lewat external identity (provider + sub)
+	identity, err := _i.identityRepo.FindByProviderSubject(claims.Provider, claims.Subject)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil, err
+	}
+
+	var user *schema.User
+	if identity == nil {
+		user, err = _i.matchOrCreateUser(claims, now)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if _, err = _i.identityRepo.Create(&schema.ExternalIdentity{
+			UserID:      user.ID,
+			Provider:    claims.Provider,
+			Subject:     claims.Subject,
+			Email:       claims.Email,
+			LastLoginAt: &now,
+		}); err != nil {
+			return 0, nil, err
+		}
+	} else {
+		user, err = _i.userRepo.FindUserByID(identity.UserID)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		// User yang dinonaktifkan (misalnya lewat SCIM) tidak bisa login
+		if user.Disabled {
+			return 0, nil, ErrAccountDisabled
+		}
+
+		// Update last login
+		user.LastLogin = &now
+		if err := _i.userRepo.UpdateUser(user); err != nil {
+			return 0, nil, err
+		}
+
+		identity.Email = claims.Email
+		identity.LastLoginAt = &now
+		if err := _i.identityRepo.Update(identity); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	// Pending invite share document hanya diklaim jika email sudah diverifikasi IdP
+	if claims.EmailVerified && claims.Email != "" {
+		if err := _i.shareRepo.ClaimPendingInvites(claims.Email, user.ID); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	return user.ID, tokens, nil
+}
+
+// LinkCallback menautkan identity dari provider ke user yang sedang login
+func (_i *userService) LinkCallback(userID uint64, providerName, code, state, sessionState, sessionVerifier string) error {
+	claims, _, err := _i.exchange(providerName, code, state, sessionState, sessionVerifier)
+	if err != nil {
+		return err
+	}
+
+	identity, err := _i.identityRepo.FindByProviderSubject(claims.Provider, claims.Subject)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	now := time.Now()
+	if identity != nil {
+		if identity.UserID != userID {
+			return ErrIdentityInUse
+		}
+
+		// Sudah tertaut ke user ini, cukup perbarui data terakhir
+		identity.Email = claims.Email
+		identity.LastLoginAt = &now
+		return _i.identityRepo.Update(identity)
+	}
+
+	_, err = _i.identityRepo.Create(&schema.ExternalIdentity{
+		UserID:      userID,
+		Provider:    claims.Provider,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		LastLoginAt: &now,
+	})
+	return err
+}
+
+func (_i *userService) ListIdentities(userID uint64) ([]response.IdentityResponse, error) {
+	identities, err := _i.identityRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]response.IdentityResponse, 0, len(identities))
+	for i := range identities {
+		responses = append(responses, _i.toIdentityResponse(&identities[i]))
 	}
 
-	provider, err := oidc.NewProvider(ctx, endpoint)
+	return responses, nil
+}
+
+// UnlinkIdentity melepas identity, ditolak jika itu satu-satunya cara login user
+func (_i *userService) UnlinkIdentity(userID uint64, identityID uint64) error {
+	identity, err := _i.identityRepo.FindByID(identityID)
 	if err != nil {
-		return 0, err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrIdentityNotFound
+		}
+		return err
+	}
+
+	// identity milik user lain dianggap tidak ada
+	if identity.UserID != userID {
+		return ErrIdentityNotFound
+	}
+
+	user, err := _i.userRepo.FindUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Password == nil {
+		count, err := _i.identityRepo.CountByUserID(userID)
+		if err != nil {
+			return err
+		}
+		if count <= 1 {
+			return ErrLastLoginMethod
+		}
+	}
+
+	return _i.identityRepo.Delete(identity.ID)
+}
+
+// oidcClaims claim id_token yang sudah diverifikasi
+type oidcClaims struct {
+	Provider          string
+	Subject           string `json:"sub"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	SessionID         string `json:"sid"`
+}
+
+// exchange tukar authorization code dan verifikasi id_token dari provider, token IdP
+// dikembalikan untuk disimpan bersama session
+func (_i *userService) exchange(providerName, code, state, sessionState, sessionVerifier string) (*oidcClaims, *session_service.IdPTokens, error) {
+	if sessionState == "" || state != sessionState {
+		return nil, nil, apperr.Unauthorized("invalid_state", "invalid state")
+	}
+
+	provider, err := _i.provider(providerName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx := context.Background()
+	conf, err := provider.OAuth2Config(ctx)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	conf := s.getOauth2Config()
 	token, err := conf.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", sessionVerifier))
 	if err != nil {
-		return 0, err
+		return nil, nil, apperr.Unauthorized("token_exchange_failed", err.Error())
 	}
 
 	rawIDToken, ok := token.Extra("id_token").(string)
 	if !ok {
-		return 0, errors.New("no id_token in token response")
+		return nil, nil, apperr.Unauthorized("missing_id_token", "no id_token in token response")
 	}
 
-	verifier := provider.Verifier(&oidc.Config{ClientID: s.cfg.Sso.Logto.ClientId})
-	idToken, err := verifier.Verify(ctx, rawIDToken)
+	verifier, err := provider.Verifier(ctx)
 	if err != nil {
-		return 0, err
+		return nil, nil, err
 	}
 
-	var claims struct {
-		Subject string `json:"sub"`
-		Email   string `json:"email"`
-		Name    string `json:"name"`
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, nil, apperr.Unauthorized("invalid_id_token", err.Error())
 	}
+
+	var claims oidcClaims
 	if err := idToken.Claims(&claims); err != nil {
-		return 0, err
+		return nil, nil, err
+	}
+	claims.Provider = provider.Name
+	claims.Email = helpers.NormalizeEmail(claims.Email)
+
+	tokens := &session_service.IdPTokens{
+		Provider:     provider.Name,
+		Subject:      claims.Subject,
+		SessionID:    claims.SessionID,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		IDToken:      rawIDToken,
+		Expiry:       token.Expiry,
 	}
 
-	// Provision user
-	user, err := s.userRepo.FindUserByLogtoSub(claims.Subject)
-	now := time.Now()
-	if err != nil {
-		// Create new user
-		user = &schema.User{
-			LogtoSub:  claims.Subject,
-			Email:     claims.Email,
-			Name:      claims.Name,
-			LastLogin: &now,
+	return &claims, tokens, nil
+}
+
+// matchOrCreateUser cari user dengan email yang sama untuk ditautkan, atau buat user baru.
+// Penautan otomatis hanya jika email sudah diverifikasi IdP dan akun tersebut tidak punya
+// password lokal, karena email akun lokal tidak diverifikasi dan bisa didaftarkan orang lain.
+// User baru wajib punya email karena users.email unique.
+func (_i *userService) matchOrCreateUser(claims *oidcClaims, now time.Time) (*schema.User, error) {
+	if claims.Email == "" {
+		return nil, ErrEmailRequired
+	}
+
+	existing, err := _i.userRepo.FindUserByEmail(claims.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if existing != nil {
+		if !claims.EmailVerified || existing.Password != nil || existing.IsServiceAccount() {
+			return nil, ErrAccountExists
 		}
-		user, err = s.userRepo.CreateUser(user)
-		if err != nil {
-			return 0, err
+		if existing.Disabled {
+			return nil, ErrAccountDisabled
 		}
-	} else {
-		// Update last login
-		user.LastLogin = &now
-		if err := s.userRepo.UpdateUser(user); err != nil {
-			return 0, err
+
+		existing.LastLogin = &now
+		if err := _i.userRepo.UpdateUser(existing); err != nil {
+			return nil, err
 		}
+
+		return existing, nil
+	}
+
+	name := claims.Name
+	if name == "" {
+		name = claims.PreferredUsername
+	}
+	if name == "" {
+		name = strings.SplitN(claims.Email, "@", 2)[0]
 	}
 
-	return user.ID, nil
+	// Create new user
+	return _i.userRepo.CreateUser(&schema.User{
+		Email:     claims.Email,
+		Name:      name,
+		LastLogin: &now,
+	})
 }
 
-func (s *userService) GetLogoutURL() string {
-	endpoint := strings.TrimSuffix(s.cfg.Sso.Logto.Endpoint, "/")
-	basePath := endpoint
-	if !strings.HasSuffix(endpoint, "/oidc") {
-		basePath = endpoint + "/oidc"
+// GetLogoutURL end session URL provider yang dipakai login, kosong untuk login lokal
+func (_i *userService) GetLogoutURL(providerName string) string {
+	if providerName == "" {
+		return ""
 	}
 
-	// Logto end session endpoint
-	// format: <base>/session/end?post_logout_redirect_uri=<uri>&client_id=<id>
-	logoutURL := fmt.Sprintf("%s/session/end?client_id=%s&post_logout_redirect_uri=%s",
-		basePath,
-		s.cfg.Sso.Logto.ClientId,
-		s.cfg.Sso.Logto.PostLogoutRedirectUri,
+	provider, ok := _i.sso.Get(providerName)
+	if !ok {
+		return ""
+	}
+
+	return provider.LogoutURL(context.Background())
+}
+
+// provider ambil provider dari registry, nama kosong berarti provider default
+func (_i *userService) provider(name string) (*sso.Provider, error) {
+	var (
+		provider *sso.Provider
+		ok       bool
 	)
+	if name == "" {
+		provider, ok = _i.sso.Default()
+	} else {
+		provider, ok = _i.sso.Get(name)
+	}
+	if !ok {
+		return nil, ErrProviderNotFound
+	}
 
-	return logoutURL
+	return provider, nil
 }
 
-func (s *userService) getOauth2Config() *oauth2.Config {
-	endpoint := strings.TrimSuffix(s.cfg.Sso.Logto.Endpoint, "/")
+// Helper: convert schema to response
+func (_i *userService) toIdentityResponse(identity *schema.ExternalIdentity) response.IdentityResponse {
+	res := response.IdentityResponse{
+		ID:           identity.ID,
+		Provider:     identity.Provider,
+		ProviderName: identity.Provider,
+		Email:        identity.Email,
+		LastLoginAt:  identity.LastLoginAt,
+		CreatedAt:    identity.CreatedAt,
+	}
 
-	basePath := endpoint
-	if !strings.HasSuffix(endpoint, "/oidc") {
-		basePath = endpoint + "/oidc"
+	// Provider yang sudah dihapus dari config tetap ditampilkan dengan namanya
+	if provider, ok := _i.sso.Get(identity.Provider); ok {
+		res.ProviderName = provider.DisplayName
 	}
 
-	return &oauth2.Config{
-		ClientID:     s.cfg.Sso.Logto.ClientId,
-		ClientSecret: s.cfg.Sso.Logto.ClientSecret,
-		Endpoint: oauth2.Endpoint{
-			AuthURL:  basePath + "/auth",
-			TokenURL: basePath + "/token",
-		},
-		RedirectURL: s.cfg.Sso.Logto.CallbackUrl,
-		Scopes:      []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess, "profile", "email"},
+	return res
+}
+
+func toUserResponse(user *schema.User) response.UserResponse {
+	return response.UserResponse{
+		ID:    user.ID,
+		Name:  user.Name,
+		Email: user.Email,
 	}
 }
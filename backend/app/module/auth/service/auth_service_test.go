@@ -0,0 +1,33 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMatchOrCreateUserRequiresEmail(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	svc := f.auth.(*userService)
+
+	// Dua login tanpa email tidak boleh berakhir di unique index users.email
+	for _, subject := range []string{"sub-1", "sub-2"} {
+		user, err := svc.matchOrCreateUser(&oidcClaims{Provider: "idp", Subject: subject, Name: subject}, time.Now())
+		if !errors.Is(err, ErrEmailRequired) {
+			t.Fatalf("matchOrCreateUser(%s) = (%v, %v), want ErrEmailRequired", subject, user, err)
+		}
+	}
+}
+
+func TestMatchOrCreateUserCreatesUser(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	svc := f.auth.(*userService)
+
+	user, err := svc.matchOrCreateUser(&oidcClaims{Provider: "idp", Subject: "sub-1", Email: "bob@example.com"}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "bob" || user.LastLogin == nil {
+		t.Fatalf("created user = %+v, want name bob with last_login", user)
+	}
+}
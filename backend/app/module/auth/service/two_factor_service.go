@@ -0,0 +1,353 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/auth/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/auth/response"
+	user_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/user/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/helpers"
+	"gorm.io/gorm"
+)
+
+const (
+	// recoveryCodeCount jumlah recovery code yang dibuat setiap kali di-generate
+	recoveryCodeCount = 10
+
+	// recoveryCodeAlphabet alfabet base32, 32 karakter agar tidak ada bias modulo
+	recoveryCodeAlphabet = "abcdefghijklmnopqrstuvwxyz234567"
+
+	// totpSkew toleransi selisih jam perangkat user, satu time step ke depan dan belakang
+	totpSkew = 1
+)
+
+// Error two-factor authentication
+var (
+	ErrTwoFactorLocalOnly      = apperr.Forbidden("two_factor_local_only", "two-factor authentication is only available for accounts with a password")
+	ErrTwoFactorEnabled        = apperr.Conflict("two_factor_enabled", "two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = apperr.Conflict("two_factor_not_enabled", "two-factor authentication is not enabled")
+	ErrTwoFactorSetupRequired  = apperr.Conflict("two_factor_setup_required", "start two-factor setup before enabling it")
+	ErrInvalidTwoFactorCode    = apperr.Validation("invalid_two_factor_code", "invalid authentication code")
+	ErrTwoFactorChallengeEnded = apperr.Unauthorized("two_factor_challenge_expired", "two-factor challenge expired, please log in again")
+)
+
+// TwoFactorService TOTP (RFC 6238) dan recovery code untuk akun lokal.
+// Semua verifikasi dilakukan lokal tanpa layanan eksternal.
+type TwoFactorService interface {
+	Status(userID uint64) (res response.TwoFactorStatusResponse, err error)
+	Setup(userID uint64) (res response.TwoFactorSetupResponse, err error)
+	Enable(userID uint64, req request.TwoFactorCodeRequest) (res response.RecoveryCodesResponse, err error)
+	Disable(userID uint64, req request.DisableTwoFactorRequest) error
+	RegenerateRecoveryCodes(userID uint64, req request.TwoFactorCodeRequest) (res response.RecoveryCodesResponse, err error)
+	VerifyLogin(userID uint64, req request.TwoFactorCodeRequest) (res response.UserResponse, err error)
+}
+
+type twoFactorService struct {
+	userRepo         user_repo.UserRepository
+	recoveryCodeRepo user_repo.RecoveryCodeRepository
+	cfg              *config.Config
+}
+
+// NewTwoFactorService instance
+func NewTwoFactorService(
+	userRepo user_repo.UserRepository,
+	recoveryCodeRepo user_repo.RecoveryCodeRepository,
+	cfg *config.Config,
+) TwoFactorService {
+	return &twoFactorService{
+		userRepo:         userRepo,
+		recoveryCodeRepo: recoveryCodeRepo,
+		cfg:              cfg,
+	}
+}
+
+func (_i *twoFactorService) Status(userID uint64) (res response.TwoFactorStatusResponse, err error) {
+	user, err := _i.findUser(userID)
+	if err != nil {
+		return
+	}
+
+	res.Enabled = user.TwoFactorEnabled()
+	if res.Enabled {
+		res.EnabledAt = user.TotpEnabledAt
+		res.RecoveryCodesRemaining, err = _i.recoveryCodeRepo.CountUnused(userID)
+	}
+
+	return
+}
+
+// Setup mulai enrollment dengan secret baru. 2FA belum aktif sampai Enable
+// menerima code dari secret ini, setup ulang mengganti secret sebelumnya.
+func (_i *twoFactorService) Setup(userID uint64) (res response.TwoFactorSetupResponse, err error) {
+	user, err := _i.findUser(userID)
+	if err != nil {
+		return
+	}
+
+	if user.Password == nil {
+		err = ErrTwoFactorLocalOnly
+		return
+	}
+	if user.TwoFactorEnabled() {
+		err = ErrTwoFactorEnabled
+		return
+	}
+
+	secret, err := helpers.GenerateTOTPSecret()
+	if err != nil {
+		return
+	}
+
+	user.TotpSecret = &secret
+	user.TotpLastCounter = 0
+	if err = _i.userRepo.UpdateUser(user); err != nil {
+		return
+	}
+
+	return response.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: helpers.TOTPProvisioningURI(secret, _i.issuer(), user.Email),
+	}, nil
+}
+
+// Enable aktifkan 2FA setelah code pertama dari authenticator cocok
+func (_i *twoFactorService) Enable(userID uint64, req request.TwoFactorCodeRequest) (res response.RecoveryCodesResponse, err error) {
+	user, err := _i.findUser(userID)
+	if err != nil {
+		return
+	}
+
+	if user.TwoFactorEnabled() {
+		err = ErrTwoFactorEnabled
+		return
+	}
+	if user.TotpSecret == nil {
+		err = ErrTwoFactorSetupRequired
+		return
+	}
+
+	if ok, verifyErr := _i.verifyTOTP(user, req.Code); verifyErr != nil {
+		err = verifyErr
+		return
+	} else if !ok {
+		err = ErrInvalidTwoFactorCode
+		return
+	}
+
+	// Baca ulang agar totp_last_counter dari verifyTOTP tidak tertimpa
+	if user, err = _i.findUser(userID); err != nil {
+		return
+	}
+
+	now := time.Now()
+	user.TotpEnabledAt = &now
+	if err = _i.userRepo.UpdateUser(user); err != nil {
+		return
+	}
+
+	return _i.generateRecoveryCodes(userID)
+}
+
+// Disable butuh password dan code agar session yang dibajak tidak bisa mematikan 2FA
+func (_i *twoFactorService) Disable(userID uint64, req request.DisableTwoFactorRequest) error {
+	user, err := _i.findUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.TwoFactorEnabled() {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if match, _ := user.ComparePassword(req.Password); !match {
+		return ErrInvalidCredentials
+	}
+
+	if err := _i.verifyCode(user, req.Code); err != nil {
+		return err
+	}
+
+	if user, err = _i.findUser(userID); err != nil {
+		return err
+	}
+
+	user.TotpSecret = nil
+	user.TotpEnabledAt = nil
+	user.TotpLastCounter = 0
+	if err := _i.userRepo.UpdateUser(user); err != nil {
+		return err
+	}
+
+	return _i.recoveryCodeRepo.DeleteByUserID(userID)
+}
+
+// RegenerateRecoveryCodes ganti semua recovery code, code lama tidak berlaku lagi
+func (_i *twoFactorService) RegenerateRecoveryCodes(userID uint64, req request.TwoFactorCodeRequest) (res response.RecoveryCodesResponse, err error) {
+	user, err := _i.findUser(userID)
+	if err != nil {
+		return
+	}
+
+	if !user.TwoFactorEnabled() {
+		err = ErrTwoFactorNotEnabled
+		return
+	}
+
+	if err = _i.verifyCode(user, req.Code); err != nil {
+		return
+	}
+
+	return _i.generateRecoveryCodes(userID)
+}
+
+// VerifyLogin langkah kedua login lokal, userID berasal dari challenge di session
+func (_i *twoFactorService) VerifyLogin(userID uint64, req request.TwoFactorCodeRequest) (res response.UserResponse, err error) {
+	user, err := _i.findUser(userID)
+	if err != nil {
+		return
+	}
+
+	if user.Disabled {
+		err = ErrAccountDisabled
+		return
+	}
+
+	// 2FA dimatikan dari session lain selama challenge berjalan
+	if !user.TwoFactorEnabled() {
+		err = ErrTwoFactorChallengeEnded
+		return
+	}
+
+	if err = _i.verifyCode(user, req.Code); err != nil {
+		return
+	}
+
+	if user, err = _i.findUser(userID); err != nil {
+		return
+	}
+
+	now := time.Now()
+	user.LastLogin = &now
+	if err = _i.userRepo.UpdateUser(user); err != nil {
+		return
+	}
+
+	return toUserResponse(user), nil
+}
+
+// verifyCode terima code TOTP 6 digit atau recovery code
+func (_i *twoFactorService) verifyCode(user *schema.User, code string) error {
+	normalized := normalizeTwoFactorCode(code)
+
+	var ok bool
+	var err error
+	if isTOTPCode(normalized) {
+		ok, err = _i.verifyTOTP(user, normalized)
+	} else {
+		ok, err = _i.recoveryCodeRepo.Use(user.ID, helpers.Hash([]byte(normalized)), time.Now())
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+// verifyTOTP code yang cocok hanya bisa dipakai sekali
+func (_i *twoFactorService) verifyTOTP(user *schema.User, code string) (bool, error) {
+	if user.TotpSecret == nil {
+		return false, nil
+	}
+
+	counter, ok := helpers.VerifyTOTP(*user.TotpSecret, normalizeTwoFactorCode(code), time.Now(), totpSkew)
+	if !ok {
+		return false, nil
+	}
+
+	return _i.userRepo.UseTotpCounter(user.ID, counter)
+}
+
+func (_i *twoFactorService) generateRecoveryCodes(userID uint64) (res response.RecoveryCodesResponse, err error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for range recoveryCodeCount {
+		code, genErr := generateRecoveryCode()
+		if genErr != nil {
+			err = genErr
+			return
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, helpers.Hash([]byte(normalizeTwoFactorCode(code))))
+	}
+
+	if err = _i.recoveryCodeRepo.Replace(userID, hashes); err != nil {
+		return
+	}
+
+	return response.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (_i *twoFactorService) findUser(userID uint64) (*schema.User, error) {
+	user, err := _i.userRepo.FindUserByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// issuer nama aplikasi yang tampil di aplikasi authenticator
+func (_i *twoFactorService) issuer() string {
+	if _i.cfg.App.Name != "" {
+		return _i.cfg.App.Name
+	}
+	return "Diagram"
+}
+
+// generateRecoveryCode format xxxxx-xxxxx, 50 bit entropy
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, 0, 11)
+	for i, v := range b {
+		if i == 5 {
+			code = append(code, '-')
+		}
+		code = append(code, recoveryCodeAlphabet[int(v)%len(recoveryCodeAlphabet)])
+	}
+
+	return string(code), nil
+}
+
+// normalizeTwoFactorCode hapus spasi dan tanda hubung yang biasa ikut tersalin
+func normalizeTwoFactorCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code)))
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != helpers.TOTPDigits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/auth/request"
+	user_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/user/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/helpers"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const testPassword = "correct horse battery"
+
+type twoFactorFixture struct {
+	db        *database.Database
+	users     user_repo.UserRepository
+	auth      AuthService
+	twoFactor TwoFactorService
+	user      *schema.User
+	secret    string
+}
+
+// newTwoFactorFixture user lokal dengan 2FA aktif di sqlite in-memory
+func newTwoFactorFixture(t *testing.T) *twoFactorFixture {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&schema.User{}, &schema.RecoveryCode{}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Auth.Local.Enable = true
+
+	d := &database.Database{DB: db}
+	users := user_repo.NewUserRepository(d)
+	recoveryCodes := user_repo.NewRecoveryCodeRepository(d)
+
+	password, err := helpers.HashPassword(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := helpers.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	enabledAt := time.Now()
+
+	user, err := users.CreateUser(&schema.User{
+		Name:          "alice",
+		Email:         "alice@example.com",
+		Password:      &password,
+		TotpSecret:    &secret,
+		TotpEnabledAt: &enabledAt,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &twoFactorFixture{
+		db:        d,
+		users:     users,
+		auth:      NewAuthService(users, nil, nil, nil, cfg, nil),
+		twoFactor: NewTwoFactorService(users, recoveryCodes, cfg),
+		user:      user,
+		secret:    secret,
+	}
+}
+
+func (f *twoFactorFixture) currentCode(t *testing.T) string {
+	t.Helper()
+
+	code, err := helpers.TOTPCode(f.secret, helpers.TOTPCounter(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func (f *twoFactorFixture) verify(code string) error {
+	_, err := f.twoFactor.VerifyLogin(f.user.ID, request.TwoFactorCodeRequest{Code: code})
+	return err
+}
+
+func TestLoginRequiresSecondFactor(t *testing.T) {
+	f := newTwoFactorFixture(t)
+
+	_, required, err := f.auth.Login(request.LoginRequest{Email: "alice@example.com", Password: testPassword})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !required {
+		t.Fatal("Login twoFactorRequired = false, want true")
+	}
+
+	// Login belum selesai sebelum langkah kedua
+	user, err := f.users.FindUserByID(f.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.LastLogin != nil {
+		t.Fatal("last_login set before the second factor")
+	}
+
+	res, err := f.twoFactor.VerifyLogin(f.user.ID, request.TwoFactorCodeRequest{Code: f.currentCode(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ID != f.user.ID {
+		t.Fatalf("VerifyLogin user = %d, want %d", res.ID, f.user.ID)
+	}
+
+	user, err = f.users.FindUserByID(f.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.LastLogin == nil {
+		t.Fatal("last_login not set after the second factor")
+	}
+}
+
+func TestVerifyLoginRejectsReplayedCode(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	code := f.currentCode(t)
+
+	if err := f.verify(code); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.verify(code); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("replayed code error = %v, want ErrInvalidTwoFactorCode", err)
+	}
+}
+
+func TestVerifyLoginRejectsWrongCode(t *testing.T) {
+	f := newTwoFactorFixture(t)
+
+	code := "000000"
+	if code == f.currentCode(t) {
+		code = "111111"
+	}
+
+	for _, c := range []string{code, "not-a-code", ""} {
+		if err := f.verify(c); !errors.Is(err, ErrInvalidTwoFactorCode) {
+			t.Fatalf("VerifyLogin(%q) error = %v, want ErrInvalidTwoFactorCode", c, err)
+		}
+	}
+}
+
+func TestVerifyLoginRecoveryCodeSingleUse(t *testing.T) {
+	f := newTwoFactorFixture(t)
+
+	codes, err := f.twoFactor.RegenerateRecoveryCodes(f.user.ID, request.TwoFactorCodeRequest{Code: f.currentCode(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes.RecoveryCodes) != recoveryCodeCount {
+		t.Fatalf("recovery codes = %d, want %d", len(codes.RecoveryCodes), recoveryCodeCount)
+	}
+
+	code := codes.RecoveryCodes[0]
+	// Format yang diketik ulang user tetap diterima
+	if err := f.verify(" " + code + " "); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.verify(code); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("reused recovery code error = %v, want ErrInvalidTwoFactorCode", err)
+	}
+
+	status, err := f.twoFactor.Status(f.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.RecoveryCodesRemaining != recoveryCodeCount-1 {
+		t.Fatalf("recovery codes remaining = %d, want %d", status.RecoveryCodesRemaining, recoveryCodeCount-1)
+	}
+}
+
+func TestVerifyLoginDisabledDuringChallenge(t *testing.T) {
+	f := newTwoFactorFixture(t)
+
+	if _, _, err := f.auth.Login(request.LoginRequest{Email: "alice@example.com", Password: testPassword}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.db.DB.Model(&schema.User{}).Where("id = ?", f.user.ID).Update("disabled", true).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.verify(f.currentCode(t)); !errors.Is(err, ErrAccountDisabled) {
+		t.Fatalf("VerifyLogin error = %v, want ErrAccountDisabled", err)
+	}
+}
@@ -0,0 +1,73 @@
+package collab
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab/controller"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+)
+
+// CollabRouter adalah router untuk edit realtime
+type CollabRouter struct {
+	App        *fiber.App
+	Controller *controller.Controller
+	Hub        service.Hub
+	AuthMW     *middleware.AuthMiddleware
+	PolicyMW   *middleware.PolicyMiddleware
+}
+
+// Module adalah FX module untuk edit realtime document lewat WebSocket
+var NewCollabModule = fx.Options(
+	// register service
+	fx.Provide(service.NewHub),
+
+	// register controller
+	controller.Module,
+
+	// register router
+	fx.Provide(NewCollabRouter),
+)
+
+// NewCollabRouter membuat instance baru dari CollabRouter
+func NewCollabRouter(
+	app *fiber.App,
+	ctrl *controller.Controller,
+	hub service.Hub,
+	authMW *middleware.AuthMiddleware,
+	policyMW *middleware.PolicyMiddleware,
+) *CollabRouter {
+	return &CollabRouter{
+		App:        app,
+		Controller: ctrl,
+		Hub:        hub,
+		AuthMW:     authMW,
+		PolicyMW:   policyMW,
+	}
+}
+
+// RegisterCollabRoutes mendaftarkan routes untuk edit realtime
+func (_i *CollabRouter) RegisterCollabRoutes() {
+	// define controllers
+	collabController := _i.Controller.Collab
+
+	_i.App.Get("/api/v1/documents/:id/collab",
+		_i.AuthMW.RequireAuth(),
+		_i.PolicyMW.Document(policy.DocumentView, "id"),
+		collabController.Upgrade,
+		websocket.New(collabController.Connect),
+	)
+	_i.App.Get("/api/v1/documents/:id/presence",
+		_i.AuthMW.RequireAuth(),
+		_i.PolicyMW.Document(policy.DocumentView, "id"),
+		collabController.Presence,
+	)
+
+	// Snapshot terakhir disimpan saat server berhenti, sebelum koneksi database ditutup
+	_i.App.Hooks().OnShutdown(func() error {
+		_i.Hub.Shutdown()
+		return nil
+	})
+}
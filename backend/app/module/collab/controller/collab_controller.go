@@ -0,0 +1,247 @@
+package controller
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab/request"
+	collab_response "git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab/response"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab/service"
+	user_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/user/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/response"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// localsClient key Locals untuk client yang disiapkan sebelum upgrade
+	localsClient = "collab_client"
+
+	// maxMessageSize batas satu pesan dari editor
+	maxMessageSize = 4 << 20
+
+	// pongWait koneksi dianggap mati jika tidak ada pong selama ini
+	pongWait     = 60 * time.Second
+	pingInterval = pongWait * 9 / 10
+	writeWait    = 10 * time.Second
+)
+
+var ErrOriginNotAllowed = apperr.Forbidden("origin_not_allowed", "origin is not allowed")
+
+// CollabController
+type collabController struct {
+	hub      service.Hub
+	userRepo user_repo.UserRepository
+	policy   policy.Policy
+	origins  []string
+}
+
+type CollabControllerI interface {
+	Upgrade(c *fiber.Ctx) error
+	Connect(conn *websocket.Conn)
+	Presence(c *fiber.Ctx) error
+}
+
+func NewCollabController(hub service.Hub, userRepo user_repo.UserRepository, p policy.Policy, cfg *config.Config) CollabControllerI {
+	// Origin yang sama dengan CORS, cookie session ikut terkirim dari origin mana pun
+	allowed := cfg.Middleware.Cors.AllowOrigins
+	if cfg.App.FrontendUrl != "" {
+		allowed = cfg.App.FrontendUrl
+	}
+
+	var origins []string
+	for _, origin := range strings.Split(allowed, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+
+	return &collabController{
+		hub:      hub,
+		userRepo: userRepo,
+		policy:   p,
+		origins:  origins,
+	}
+}
+
+// Upgrade cek request sebelum upgrade ke WebSocket, dijalankan setelah PolicyMiddleware
+func (_i *collabController) Upgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+
+	if !_i.originAllowed(c.Get(fiber.HeaderOrigin)) {
+		return ErrOriginNotAllowed
+	}
+
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	document := middleware.GetDocument(c)
+	workspace := middleware.GetWorkspace(c)
+	if document == nil || workspace == nil {
+		return policy.ErrDocumentNotFound
+	}
+
+	// Viewer tetap bisa mengikuti perubahan tanpa bisa mengirim op
+	canEdit, err := _i.policy.Can(policy.User(userID), policy.DocumentEdit, policy.DocumentResource(workspace, document))
+	if err != nil {
+		return err
+	}
+
+	// Upgrade selalu GET sehingga lolos cek scope token, token read tetap hanya bisa mengikuti
+	if tok := middleware.GetAccessToken(c); tok != nil && tok.Scope != schema.TokenScopeWrite {
+		canEdit = false
+	}
+
+	// Nama ditampilkan di cursor editor lain
+	user, err := _i.userRepo.FindUserByID(userID)
+	if err != nil {
+		return middleware.ErrUnauthorized
+	}
+
+	c.Locals(localsClient, service.NewClient(userID, user.Name, document.ID, canEdit))
+
+	return c.Next()
+}
+
+// Connect handler WebSocket per editor
+func (_i *collabController) Connect(conn *websocket.Conn) {
+	client, _ := conn.Locals(localsClient).(*service.Client)
+	if client == nil {
+		_ = conn.Close()
+		return
+	}
+
+	_i.hub.Join(client)
+	defer _i.hub.Leave(client)
+
+	go _i.writePump(conn, client)
+
+	conn.SetReadLimit(maxMessageSize)
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		client.Touch()
+
+		var msg request.Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			client.Send(service.ErrInvalidMessage)
+			continue
+		}
+
+		switch msg.Type {
+		case request.MessageOperation:
+			_i.hub.Submit(client, msg)
+		case request.MessagePresence:
+			_i.hub.UpdatePresence(client, msg)
+		case request.MessageHeartbeat:
+			// Cukup memperbarui waktu pesan terakhir
+		default:
+			client.Send(service.ErrInvalidMessage)
+		}
+	}
+}
+
+// Presence handler untuk daftar user yang sedang membuka document
+func (_i *collabController) Presence(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	document := middleware.GetDocument(c)
+	if document == nil {
+		return policy.ErrDocumentNotFound
+	}
+
+	viewers, err := _i.hub.Viewers(document.ID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"presence retrieved successfully"},
+		Data: collab_response.Viewers{
+			DocumentID: document.ID,
+			Total:      len(viewers),
+			Viewers:    viewers,
+		},
+	})
+}
+
+// writePump satu-satunya goroutine yang menulis ke koneksi
+func (_i *collabController) writePump(conn *websocket.Conn, client *service.Client) {
+	ticker := time.NewTicker(pingInterval)
+	defer func() {
+		ticker.Stop()
+		_ = conn.Close()
+	}()
+
+	for {
+		select {
+		case payload := <-client.Outbox():
+			if err := write(conn, websocket.TextMessage, payload); err != nil {
+				client.Close()
+				return
+			}
+
+		case <-ticker.C:
+			if err := write(conn, websocket.PingMessage, nil); err != nil {
+				client.Close()
+				return
+			}
+
+		case <-client.Done():
+			// Kirim sisa pesan, termasuk alasan koneksi diputus
+			for {
+				select {
+				case payload := <-client.Outbox():
+					if err := write(conn, websocket.TextMessage, payload); err != nil {
+						return
+					}
+				default:
+					_ = write(conn, websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+					return
+				}
+			}
+		}
+	}
+}
+
+func (_i *collabController) originAllowed(origin string) bool {
+	// Klien non-browser tidak mengirim Origin
+	if origin == "" {
+		return true
+	}
+
+	for _, allowed := range _i.origins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func write(conn *websocket.Conn, messageType int, payload []byte) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return conn.WriteMessage(messageType, payload)
+}
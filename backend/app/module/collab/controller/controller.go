@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab/service"
+	user_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/user/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
+	"go.uber.org/fx"
+)
+
+// Controller aggregator
+type Controller struct {
+	Collab CollabControllerI
+}
+
+// NewController
+func NewController(collabController CollabControllerI) *Controller {
+	return &Controller{
+		Collab: collabController,
+	}
+}
+
+var Module = fx.Options(
+	fx.Provide(func(hub service.Hub, userRepo user_repo.UserRepository, p policy.Policy, cfg *config.Config) CollabControllerI {
+		return NewCollabController(hub, userRepo, p, cfg)
+	}),
+	fx.Provide(NewController),
+)
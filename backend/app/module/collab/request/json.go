@@ -0,0 +1,23 @@
+package request
+
+import "git.dev.siap.id/kukuhkkh/app-diagram/utils/ot"
+
+// Tipe pesan dari editor
+const (
+	MessageOperation = "op"
+	MessagePresence  = "presence"
+	MessageHeartbeat = "heartbeat"
+)
+
+// MaxSelections batas jumlah cursor/selection dalam satu pesan presence
+const MaxSelections = 100
+
+// Message pesan dari editor lewat WebSocket. Revision adalah revision server
+// terakhir yang sudah diterapkan editor saat op atau selection dibuat.
+type Message struct {
+	Type       string        `json:"type"`
+	Revision   int           `json:"revision"`
+	Op         *ot.Operation `json:"op"`
+	Selections []ot.Range    `json:"selections"`
+	Idle       bool          `json:"idle"`
+}
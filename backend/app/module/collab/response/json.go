@@ -0,0 +1,88 @@
+package response
+
+import (
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/ot"
+)
+
+// Tipe pesan ke editor
+const (
+	MessageInit      = "init"
+	MessageAck       = "ack"
+	MessageOperation = "op"
+	MessageError     = "error"
+
+	MessagePresence      = "presence"
+	MessagePresenceLeave = "presence_leave"
+)
+
+// Init dikirim sekali setelah terhubung, editor mulai dari content ini
+type Init struct {
+	Type     string `json:"type"`
+	ClientID string `json:"client_id"`
+	Revision int    `json:"revision"`
+	Content  string `json:"content"`
+	CanEdit  bool   `json:"can_edit"`
+	// Presence editor lain yang sedang membuka document
+	Presence []Presence `json:"presence"`
+}
+
+// Ack op milik editor sudah diterapkan sebagai revision ini
+type Ack struct {
+	Type     string `json:"type"`
+	Revision int    `json:"revision"`
+}
+
+// Operation op dari editor lain yang sudah di-transform terhadap revision sebelumnya
+type Operation struct {
+	Type     string        `json:"type"`
+	Revision int           `json:"revision"`
+	ClientID string        `json:"client_id"`
+	UserID   uint64        `json:"user_id"`
+	Op       *ot.Operation `json:"op"`
+}
+
+// Error jika Fatal true koneksi ditutup dan editor harus connect ulang untuk sinkron
+type Error struct {
+	Type    string `json:"type"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Fatal   bool   `json:"fatal"`
+}
+
+// Presence cursor/selection dan status editor lain. Selections relatif
+// terhadap content di Revision.
+type Presence struct {
+	Type       string     `json:"type"`
+	ClientID   string     `json:"client_id"`
+	UserID     uint64     `json:"user_id"`
+	Name       string     `json:"name"`
+	CanEdit    bool       `json:"can_edit"`
+	Idle       bool       `json:"idle"`
+	Revision   int        `json:"revision"`
+	Selections []ot.Range `json:"selections"`
+}
+
+// PresenceLeave editor menutup document
+type PresenceLeave struct {
+	Type     string `json:"type"`
+	ClientID string `json:"client_id"`
+}
+
+// Viewer user yang sedang membuka document, satu user bisa punya beberapa koneksi
+type Viewer struct {
+	UserID      uint64    `json:"user_id"`
+	Name        string    `json:"name"`
+	CanEdit     bool      `json:"can_edit"`
+	Idle        bool      `json:"idle"`
+	Connections int       `json:"connections"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+// Viewers daftar viewer document untuk dashboard
+type Viewers struct {
+	DocumentID uint64   `json:"document_id"`
+	Total      int      `json:"total"`
+	Viewers    []Viewer `json:"viewers"`
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/ot"
+	"github.com/google/uuid"
+)
+
+// clientBuffer jumlah pesan yang boleh antri sebelum editor dianggap terlalu lambat
+const clientBuffer = 256
+
+// Client satu koneksi editor ke sebuah document
+type Client struct {
+	ID         string
+	UserID     uint64
+	Name       string
+	DocumentID uint64
+	CanEdit    bool
+
+	outbox    chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// initialized init sudah dikirim, dijaga oleh lock room
+	initialized bool
+
+	mu sync.Mutex
+	// lastMessage pesan terakhir dari editor, termasuk heartbeat
+	lastMessage time.Time
+	// lastActive op atau perubahan cursor terakhir
+	lastActive time.Time
+	idle       bool
+	revision   int
+	selections []ot.Range
+	seq        uint64
+}
+
+func NewClient(userID uint64, name string, documentID uint64, canEdit bool) *Client {
+	now := time.Now()
+
+	return &Client{
+		ID:          uuid.NewString(),
+		UserID:      userID,
+		Name:        name,
+		DocumentID:  documentID,
+		CanEdit:     canEdit,
+		outbox:      make(chan []byte, clientBuffer),
+		done:        make(chan struct{}),
+		lastMessage: now,
+		lastActive:  now,
+	}
+}
+
+// Touch catat pesan dari editor, editor tanpa pesan sampai presenceTimeout diputus
+func (_i *Client) Touch() {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	_i.lastMessage = time.Now()
+}
+
+// markActive editor baru saja mengirim op
+func (_i *Client) markActive() {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	_i.lastActive = time.Now()
+	_i.idle = false
+}
+
+// setPresence simpan cursor/selection dan status idle yang dilaporkan editor.
+// Hasilnya true jika status idle berubah.
+func (_i *Client) setPresence(revision int, selections []ot.Range, idle bool) bool {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	changed := _i.idle != idle
+	_i.revision = revision
+	_i.selections = selections
+	_i.idle = idle
+	if !idle {
+		_i.lastActive = time.Now()
+	}
+
+	return changed
+}
+
+// presence snapshot presence editor dengan seq baru
+func (_i *Client) presence(now time.Time) (presenceState, int) {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	_i.seq++
+	return presenceState{
+		ClientID:   _i.ID,
+		UserID:     _i.UserID,
+		Name:       _i.Name,
+		CanEdit:    _i.CanEdit,
+		Idle:       _i.idleLocked(now),
+		Seq:        _i.seq,
+		Selections: _i.selections,
+	}, _i.revision
+}
+
+// idleAt true jika editor melaporkan idle atau tidak aktif sejak idleAfter
+func (_i *Client) idleAt(now time.Time) bool {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	return _i.idleLocked(now)
+}
+
+func (_i *Client) idleLocked(now time.Time) bool {
+	return _i.idle || now.Sub(_i.lastActive) > idleAfter
+}
+
+// timedOut true jika editor tidak mengirim pesan apa pun sejak batas waktu
+func (_i *Client) timedOut(now time.Time) bool {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	return now.Sub(_i.lastMessage) > presenceTimeout
+}
+
+// Outbox pesan yang harus ditulis ke WebSocket sesuai urutan
+func (_i *Client) Outbox() <-chan []byte {
+	return _i.outbox
+}
+
+// Done ditutup saat client harus diputus, sisa Outbox tetap dikirim lebih dulu
+func (_i *Client) Done() <-chan struct{} {
+	return _i.done
+}
+
+// Send antrikan pesan ke editor. Editor yang antriannya penuh diputus agar
+// tidak menahan editor lain di document yang sama.
+func (_i *Client) Send(message any) {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+
+	select {
+	case <-_i.done:
+		return
+	default:
+	}
+
+	select {
+	case _i.outbox <- payload:
+	default:
+		_i.Close()
+	}
+}
+
+// CloseWith kirim pesan terakhir lalu putus koneksi
+func (_i *Client) CloseWith(message any) {
+	_i.Send(message)
+	_i.Close()
+}
+
+func (_i *Client) Close() {
+	_i.closeOnce.Do(func() {
+		close(_i.done)
+	})
+}
@@ -0,0 +1,29 @@
+package service
+
+import "git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab/response"
+
+// Error yang dikirim ke editor lewat WebSocket. Error fatal menutup koneksi,
+// editor harus connect ulang untuk mendapatkan content terbaru.
+var (
+	ErrInvalidMessage   = clientError("invalid_message", "message is not valid", false)
+	ErrReadOnly         = clientError("read_only", "you don't have permission to edit this document", false)
+	ErrNotReady         = clientError("not_ready", "document is still loading", false)
+	ErrInvalidSelection = clientError("invalid_selection", "selections are not valid", false)
+	ErrRevisionOutdated = clientError("revision_outdated", "operation is based on a revision that is no longer available", true)
+	ErrInvalidOperation = clientError("invalid_operation", "operation does not apply to the document", true)
+	ErrDocumentTooLarge = clientError("document_too_large", "document exceeds the maximum size", true)
+	ErrResyncRequired   = clientError("resync_required", "editing session was reset, reconnect to continue", true)
+	ErrDocumentChanged  = clientError("document_changed", "document was saved outside this editing session, reconnect to load the latest version", true)
+	ErrUnavailable      = clientError("unavailable", "collaboration is temporarily unavailable", true)
+	ErrShutdown         = clientError("server_shutdown", "server is restarting, reconnect to continue", true)
+	ErrPresenceTimeout  = clientError("presence_timeout", "no heartbeat received, reconnect to continue", true)
+)
+
+func clientError(code, message string, fatal bool) response.Error {
+	return response.Error{
+		Type:    response.MessageError,
+		Code:    code,
+		Message: message,
+		Fatal:   fatal,
+	}
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab/response"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/pubsub"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const (
+	channelPrefix = "collab:document:"
+
+	// busTimeout batas waktu publish dan subscribe ke bus
+	busTimeout = 5 * time.Second
+
+	defaultSnapshotInterval = 30 * time.Second
+	snapshotDescription     = "Live editing session"
+)
+
+// Hub sesi edit realtime per document di instance ini. Op dari editor tidak
+// langsung diterapkan, tetapi di-publish ke bus lalu diterapkan semua instance
+// sesuai urutan dari bus sehingga state di setiap instance selalu sama.
+type Hub interface {
+	Join(client *Client)
+	Leave(client *Client)
+	Submit(client *Client, msg request.Message)
+	UpdatePresence(client *Client, msg request.Message)
+	Viewers(documentID uint64) ([]response.Viewer, error)
+	Invalidate(documentID uint64, version int)
+	Shutdown()
+}
+
+type hub struct {
+	bus         pubsub.Bus
+	registry    pubsub.Registry
+	versionRepo repository.DocumentVersionRepository
+	node        string
+	interval    time.Duration
+
+	mu    sync.Mutex
+	rooms map[uint64]*room
+	// flushing room yang sedang ditutup, room baru menunggu snapshot terakhirnya tersimpan
+	flushing map[uint64]chan struct{}
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewHub instance
+func NewHub(bus pubsub.Bus, registry pubsub.Registry, versionRepo repository.DocumentVersionRepository, cfg *config.Config) Hub {
+	interval := cfg.Collab.SnapshotInterval * time.Second
+	if interval <= 0 {
+		interval = defaultSnapshotInterval
+	}
+
+	h := &hub{
+		bus:         bus,
+		registry:    registry,
+		versionRepo: versionRepo,
+		node:        uuid.NewString(),
+		interval:    interval,
+		rooms:       make(map[uint64]*room),
+		flushing:    make(map[uint64]chan struct{}),
+		stop:        make(chan struct{}),
+	}
+
+	go h.snapshotLoop()
+	go h.presenceLoop()
+
+	return h
+}
+
+// Join tambahkan editor ke room document, init dikirim setelah state room siap
+func (_i *hub) Join(client *Client) {
+	_i.mu.Lock()
+
+	r, ok := _i.rooms[client.DocumentID]
+	if ok && r.isClosed() {
+		done := _i.detach(r)
+		go _i.flush(r, done)
+		ok = false
+	}
+
+	var flushing chan struct{}
+	if !ok {
+		r = newRoom(_i, client.DocumentID)
+		_i.rooms[client.DocumentID] = r
+		flushing = _i.flushing[client.DocumentID]
+	}
+
+	r.add(client)
+	_i.mu.Unlock()
+
+	if !ok {
+		go r.start(flushing)
+	}
+
+	_i.register(client)
+}
+
+// Leave keluarkan editor, room tanpa editor di-snapshot lalu ditutup
+func (_i *hub) Leave(client *Client) {
+	client.Close()
+	defer _i.unregister(client)
+
+	_i.mu.Lock()
+	r := _i.rooms[client.DocumentID]
+	if r == nil || !r.remove(client) {
+		_i.mu.Unlock()
+		return
+	}
+
+	done := _i.detach(r)
+	_i.mu.Unlock()
+
+	go _i.flush(r, done)
+}
+
+// Submit publish op editor ke bus
+func (_i *hub) Submit(client *Client, msg request.Message) {
+	if !client.CanEdit {
+		client.Send(ErrReadOnly)
+		return
+	}
+	if msg.Op == nil {
+		client.Send(ErrInvalidMessage)
+		return
+	}
+	client.markActive()
+
+	_i.mu.Lock()
+	r := _i.rooms[client.DocumentID]
+	_i.mu.Unlock()
+	if r == nil {
+		return
+	}
+
+	epoch, ok := r.epochFor(client)
+	if !ok {
+		client.Send(ErrNotReady)
+		return
+	}
+
+	payload, err := json.Marshal(envelope{
+		Kind:     kindOperation,
+		Epoch:    epoch,
+		Node:     _i.node,
+		ClientID: client.ID,
+		UserID:   client.UserID,
+		Revision: msg.Revision,
+		Op:       msg.Op,
+	})
+	if err != nil {
+		client.Send(ErrInvalidMessage)
+		return
+	}
+
+	if err := _i.publish(r.channel, payload); err != nil {
+		log.Error().Err(err).Uint64("document_id", client.DocumentID).Msg("collab publish failed")
+		client.CloseWith(ErrUnavailable)
+	}
+}
+
+// Invalidate document disimpan di luar sesi (save REST, restore, share link) sebagai
+// version. Room di semua instance yang dimulai dari versi lebih lama dibuang dan
+// editor connect ulang dari versi terbaru, bukan terus mengedit content lama.
+func (_i *hub) Invalidate(documentID uint64, version int) {
+	payload, err := json.Marshal(envelope{
+		Kind:    kindStale,
+		Node:    _i.node,
+		Version: version,
+	})
+	if err == nil {
+		err = _i.publish(channelName(documentID), payload)
+	}
+	if err == nil {
+		return
+	}
+
+	log.Warn().Err(err).Uint64("document_id", documentID).Msg("collab stale notification failed")
+
+	_i.mu.Lock()
+	r := _i.rooms[documentID]
+	_i.mu.Unlock()
+	if r != nil {
+		r.resetIfOlder(version)
+	}
+}
+
+// Shutdown putus semua editor dan simpan snapshot terakhir setiap room
+func (_i *hub) Shutdown() {
+	_i.stopOnce.Do(func() {
+		close(_i.stop)
+	})
+
+	_i.mu.Lock()
+	rooms := make([]*room, 0, len(_i.rooms))
+	dones := make([]chan struct{}, 0, len(_i.rooms))
+	for _, r := range _i.rooms {
+		rooms = append(rooms, r)
+		dones = append(dones, _i.detach(r))
+	}
+	_i.mu.Unlock()
+
+	for i, r := range rooms {
+		r.closeClients(ErrShutdown)
+		_i.flush(r, dones[i])
+	}
+}
+
+// discard tutup room yang state-nya harus dibuang, editor sudah diminta connect ulang
+func (_i *hub) discard(r *room) {
+	_i.mu.Lock()
+	if _i.rooms[r.documentID] != r {
+		_i.mu.Unlock()
+		return
+	}
+	done := _i.detach(r)
+	_i.mu.Unlock()
+
+	_i.flush(r, done)
+}
+
+// detach lepas room dari hub, harus dipanggil dengan lock hub
+func (_i *hub) detach(r *room) chan struct{} {
+	delete(_i.rooms, r.documentID)
+
+	done := make(chan struct{})
+	_i.flushing[r.documentID] = done
+	return done
+}
+
+// flush tutup room dan simpan content yang belum di-snapshot
+func (_i *hub) flush(r *room, done chan struct{}) {
+	// Snapshot terakhir yang bentrok dengan perubahan di luar sesi dibuang
+	if snapshot, dirty := r.close(); dirty {
+		_ = _i.save(r.documentID, snapshot)
+	}
+
+	_i.mu.Lock()
+	if _i.flushing[r.documentID] == done {
+		delete(_i.flushing, r.documentID)
+	}
+	_i.mu.Unlock()
+
+	close(done)
+}
+
+func (_i *hub) snapshotLoop() {
+	ticker := time.NewTicker(_i.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-_i.stop:
+			return
+		case <-ticker.C:
+		}
+
+		_i.mu.Lock()
+		rooms := make([]*room, 0, len(_i.rooms))
+		for _, r := range _i.rooms {
+			rooms = append(rooms, r)
+		}
+		_i.mu.Unlock()
+
+		for _, r := range rooms {
+			snapshot, ok := r.takeSnapshot()
+			if !ok {
+				continue
+			}
+
+			err := _i.save(r.documentID, snapshot)
+			switch {
+			case errors.Is(err, repository.ErrStaleVersion):
+				// Document diubah di luar sesi, semua instance diminta memuat ulang
+				r.invalidate()
+			case err != nil:
+				r.markDirty(snapshot.author)
+			}
+		}
+	}
+}
+
+// save simpan snapshot sebagai DocumentVersion jika content berubah.
+// Snapshot tidak divalidasi syntax mermaid karena diambil di tengah editing.
+func (_i *hub) save(documentID uint64, snapshot snapshot) error {
+	description := snapshotDescription
+	version := &schema.DocumentVersion{
+		DocumentID:        documentID,
+		Content:           snapshot.content,
+		ChangeDescription: &description,
+	}
+	if snapshot.author != 0 {
+		version.AuthorID = &snapshot.author
+	}
+
+	if _, err := _i.versionRepo.AppendSnapshot(version, snapshot.baseVersion); err != nil {
+		// Document sudah dihapus, tidak ada yang perlu disimpan
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+
+		if errors.Is(err, repository.ErrStaleVersion) {
+			log.Warn().Uint64("document_id", documentID).Msg("collab snapshot discarded, document changed outside the session")
+		} else {
+			log.Error().Err(err).Uint64("document_id", documentID).Msg("collab snapshot failed")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// loadContent versi terakhir, kosong dan versi 0 untuk document tanpa versi
+func (_i *hub) loadContent(documentID uint64) (string, int, error) {
+	version, err := _i.versionRepo.FindLatest(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", 0, nil
+		}
+		return "", 0, err
+	}
+
+	return version.Content, version.VersionNumber, nil
+}
+
+func (_i *hub) publish(channel string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), busTimeout)
+	defer cancel()
+
+	return _i.bus.Publish(ctx, channel, payload)
+}
+
+func channelName(documentID uint64) string {
+	return channelPrefix + strconv.FormatUint(documentID, 10)
+}
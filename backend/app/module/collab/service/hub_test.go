@@ -0,0 +1,189 @@
+package service
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab/response"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/ot"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/pubsub"
+	"gorm.io/gorm"
+)
+
+const testDocumentID = 1
+
+// fakeVersionRepo riwayat versi in-memory, hanya method yang dipakai hub
+type fakeVersionRepo struct {
+	repository.DocumentVersionRepository
+
+	mu       sync.Mutex
+	versions []schema.DocumentVersion
+}
+
+func (_i *fakeVersionRepo) FindLatest(uint64) (*schema.DocumentVersion, error) {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	if len(_i.versions) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	latest := _i.versions[len(_i.versions)-1]
+	return &latest, nil
+}
+
+func (_i *fakeVersionRepo) AppendSnapshot(version *schema.DocumentVersion, baseVersion int) (bool, error) {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	for _, v := range _i.versions {
+		if v.VersionNumber > baseVersion && !v.Live {
+			return false, repository.ErrStaleVersion
+		}
+	}
+
+	version.Live = true
+	version.VersionNumber = len(_i.versions) + 1
+	_i.versions = append(_i.versions, *version)
+	return true, nil
+}
+
+// save seperti save REST, versi baru di luar sesi
+func (_i *fakeVersionRepo) save(content string) int {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	number := len(_i.versions) + 1
+	_i.versions = append(_i.versions, schema.DocumentVersion{DocumentID: testDocumentID, VersionNumber: number, Content: content})
+	return number
+}
+
+func (_i *fakeVersionRepo) count() int {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	return len(_i.versions)
+}
+
+func newTestHub(t *testing.T, content string) (*hub, *fakeVersionRepo) {
+	t.Helper()
+
+	versions := &fakeVersionRepo{}
+	versions.save(content)
+
+	cfg := &config.Config{}
+	cfg.Collab.SnapshotInterval = 3600
+
+	h := NewHub(pubsub.NewMemoryBus(), pubsub.NewMemoryRegistry(), versions, cfg).(*hub)
+	t.Cleanup(h.Shutdown)
+
+	return h, versions
+}
+
+// message pesan berikutnya untuk client dengan tipe tertentu, pesan lain dilewati
+func message(t *testing.T, c *Client, messageType string) map[string]any {
+	t.Helper()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case payload := <-c.Outbox():
+			var msg map[string]any
+			if err := json.Unmarshal(payload, &msg); err != nil {
+				t.Fatal(err)
+			}
+			if msg["type"] == messageType {
+				return msg
+			}
+		case <-timeout:
+			t.Fatalf("no %s message", messageType)
+		}
+	}
+}
+
+func join(t *testing.T, h *hub) (*Client, map[string]any) {
+	t.Helper()
+
+	c := NewClient(1, "alice", testDocumentID, true)
+	h.Join(c)
+	t.Cleanup(func() { h.Leave(c) })
+
+	return c, message(t, c, response.MessageInit)
+}
+
+func insert(t *testing.T, h *hub, c *Client, revision int, base, text string) {
+	t.Helper()
+
+	op := (&ot.Operation{}).Retain(len([]rune(base))).Insert(text)
+	h.Submit(c, request.Message{Type: request.MessageOperation, Revision: revision, Op: op})
+	message(t, c, response.MessageAck)
+}
+
+func TestInvalidateResetsRoomAfterExternalSave(t *testing.T) {
+	h, versions := newTestHub(t, "graph TD")
+
+	editor, _ := join(t, h)
+	insert(t, h, editor, 0, "graph TD", "\nA")
+
+	// Save REST dari versi 1 yang tidak melihat edit live
+	version := versions.save("graph LR")
+	h.Invalidate(testDocumentID, version)
+
+	msg := message(t, editor, response.MessageError)
+	if msg["code"] != ErrDocumentChanged.Code {
+		t.Fatalf("error code = %v, want %s", msg["code"], ErrDocumentChanged.Code)
+	}
+	select {
+	case <-editor.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("editor not disconnected after external save")
+	}
+
+	// Editor yang connect ulang mendapat versi dari save REST
+	_, init := join(t, h)
+	if init["content"] != "graph LR" {
+		t.Fatalf("init content after reconnect = %q, want %q", init["content"], "graph LR")
+	}
+
+	// State room lama tidak boleh di-snapshot di atas save REST
+	if n := versions.count(); n != 2 {
+		t.Fatalf("versions = %d, want 2", n)
+	}
+}
+
+func TestInvalidateIgnoresVersionsTheRoomStartedFrom(t *testing.T) {
+	h, versions := newTestHub(t, "graph TD")
+
+	// Save REST sebelum room dibuka sudah dimuat oleh room
+	version := versions.save("graph LR")
+
+	editor, init := join(t, h)
+	if init["content"] != "graph LR" {
+		t.Fatalf("init content = %q, want %q", init["content"], "graph LR")
+	}
+
+	h.Invalidate(testDocumentID, version)
+	insert(t, h, editor, 0, "graph LR", "\nA")
+
+	select {
+	case <-editor.Done():
+		t.Fatal("editor disconnected by a save the room already includes")
+	default:
+	}
+}
+
+func TestInvalidateWithoutRoom(t *testing.T) {
+	h, versions := newTestHub(t, "graph TD")
+
+	h.Invalidate(testDocumentID, versions.save("graph LR"))
+
+	_, init := join(t, h)
+	if init["content"] != "graph LR" {
+		t.Fatalf("init content = %q, want %q", init["content"], "graph LR")
+	}
+}
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab/response"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/ot"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	presencePrefix = "collab:presence:"
+
+	// presenceInterval presence setiap editor di-publish ulang dan editor
+	// tanpa heartbeat diputus
+	presenceInterval = 15 * time.Second
+	// presenceTimeout editor tanpa pesan apa pun selama ini dianggap hilang
+	presenceTimeout = 45 * time.Second
+	// presenceTTL umur presence di instance lain dan di registry jika tidak diperbarui
+	presenceTTL = presenceTimeout + presenceInterval
+	// idleAfter editor tanpa op atau perubahan cursor selama ini dianggap idle
+	idleAfter = 2 * time.Minute
+)
+
+// presenceState presence satu koneksi editor di bus
+type presenceState struct {
+	ClientID   string     `json:"client_id"`
+	UserID     uint64     `json:"user_id"`
+	Name       string     `json:"name"`
+	CanEdit    bool       `json:"can_edit"`
+	Idle       bool       `json:"idle"`
+	Seq        uint64     `json:"seq"`
+	Selections []ot.Range `json:"selections"`
+}
+
+// presenceEntry presence yang disimpan room, Selections selalu relatif
+// terhadap revision room saat ini
+type presenceEntry struct {
+	presenceState
+	lastSeen time.Time
+}
+
+// viewer value di registry untuk daftar viewer lewat REST
+type viewer struct {
+	UserID   uint64    `json:"user_id"`
+	Name     string    `json:"name"`
+	CanEdit  bool      `json:"can_edit"`
+	Idle     bool      `json:"idle"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// UpdatePresence simpan cursor/selection editor lalu publish ke editor lain
+func (_i *hub) UpdatePresence(client *Client, msg request.Message) {
+	if len(msg.Selections) > request.MaxSelections {
+		client.Send(ErrInvalidSelection)
+		return
+	}
+	for _, selection := range msg.Selections {
+		if selection.Anchor < 0 || selection.Head < 0 {
+			client.Send(ErrInvalidSelection)
+			return
+		}
+	}
+
+	idleChanged := client.setPresence(msg.Revision, msg.Selections, msg.Idle)
+
+	_i.mu.Lock()
+	r := _i.rooms[client.DocumentID]
+	_i.mu.Unlock()
+	if r == nil {
+		return
+	}
+
+	_i.announce(r, client)
+	if idleChanged {
+		_i.register(client)
+	}
+}
+
+// Viewers user yang sedang membuka document di semua instance
+func (_i *hub) Viewers(documentID uint64) ([]response.Viewer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), busTimeout)
+	defer cancel()
+
+	members, err := _i.registry.Members(ctx, presenceKey(documentID))
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[uint64]*response.Viewer)
+	for _, value := range members {
+		var v viewer
+		if err := json.Unmarshal(value, &v); err != nil {
+			continue
+		}
+
+		current, ok := byUser[v.UserID]
+		if !ok {
+			byUser[v.UserID] = &response.Viewer{
+				UserID:      v.UserID,
+				Name:        v.Name,
+				CanEdit:     v.CanEdit,
+				Idle:        v.Idle,
+				Connections: 1,
+				LastSeen:    v.LastSeen,
+			}
+			continue
+		}
+
+		// User idle hanya jika semua koneksinya idle
+		current.Connections++
+		current.CanEdit = current.CanEdit || v.CanEdit
+		current.Idle = current.Idle && v.Idle
+		if v.LastSeen.After(current.LastSeen) {
+			current.LastSeen = v.LastSeen
+		}
+	}
+
+	viewers := make([]response.Viewer, 0, len(byUser))
+	for _, v := range byUser {
+		viewers = append(viewers, *v)
+	}
+	sort.Slice(viewers, func(a, b int) bool {
+		if viewers[a].Name != viewers[b].Name {
+			return viewers[a].Name < viewers[b].Name
+		}
+		return viewers[a].UserID < viewers[b].UserID
+	})
+
+	return viewers, nil
+}
+
+// announce publish presence editor ke semua instance
+func (_i *hub) announce(r *room, client *Client) {
+	epoch, ok := r.epochFor(client)
+	if !ok {
+		// Presence dikirim bersama init
+		return
+	}
+
+	state, revision := client.presence(time.Now())
+	payload, err := json.Marshal(envelope{
+		Kind:     kindPresence,
+		Epoch:    epoch,
+		Node:     _i.node,
+		Revision: revision,
+		Presence: &state,
+	})
+	if err != nil {
+		return
+	}
+
+	if err := _i.publish(r.channel, payload); err != nil {
+		log.Warn().Err(err).Uint64("document_id", client.DocumentID).Msg("collab presence publish failed")
+	}
+}
+
+// register perbarui entry editor di registry
+func (_i *hub) register(client *Client) {
+	now := time.Now()
+	value, err := json.Marshal(viewer{
+		UserID:   client.UserID,
+		Name:     client.Name,
+		CanEdit:  client.CanEdit,
+		Idle:     client.idleAt(now),
+		LastSeen: now,
+	})
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), busTimeout)
+	defer cancel()
+
+	if err := _i.registry.Put(ctx, presenceKey(client.DocumentID), client.ID, value, presenceTTL); err != nil {
+		log.Warn().Err(err).Uint64("document_id", client.DocumentID).Msg("collab presence register failed")
+	}
+}
+
+// unregister hapus editor dari registry dan dari presence instance lain
+func (_i *hub) unregister(client *Client) {
+	ctx, cancel := context.WithTimeout(context.Background(), busTimeout)
+	defer cancel()
+
+	if err := _i.registry.Remove(ctx, presenceKey(client.DocumentID), client.ID); err != nil {
+		log.Warn().Err(err).Uint64("document_id", client.DocumentID).Msg("collab presence unregister failed")
+	}
+
+	payload, err := json.Marshal(envelope{
+		Kind:     kindPresenceLeave,
+		Node:     _i.node,
+		ClientID: client.ID,
+	})
+	if err != nil {
+		return
+	}
+
+	if err := _i.bus.Publish(ctx, channelName(client.DocumentID), payload); err != nil {
+		log.Warn().Err(err).Uint64("document_id", client.DocumentID).Msg("collab presence publish failed")
+	}
+}
+
+// presenceLoop putus editor tanpa heartbeat, publish ulang presence editor
+// lain agar tidak dianggap hilang oleh instance lain, dan buang presence
+// dari instance yang sudah mati
+func (_i *hub) presenceLoop() {
+	ticker := time.NewTicker(presenceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-_i.stop:
+			return
+		case <-ticker.C:
+		}
+
+		_i.mu.Lock()
+		rooms := make([]*room, 0, len(_i.rooms))
+		for _, r := range _i.rooms {
+			rooms = append(rooms, r)
+		}
+		_i.mu.Unlock()
+
+		now := time.Now()
+		for _, r := range rooms {
+			r.expirePresence(now)
+
+			for _, c := range r.members() {
+				if c.timedOut(now) {
+					c.CloseWith(ErrPresenceTimeout)
+					continue
+				}
+
+				_i.announce(r, c)
+				_i.register(c)
+			}
+		}
+	}
+}
+
+// presenceLocked simpan presence dari bus dan teruskan ke editor lokal
+func (_i *room) presenceLocked(env envelope) {
+	p := env.Presence
+	if p == nil || env.Epoch != _i.epoch {
+		return
+	}
+	// Editor di instance ini sudah keluar, presence yang tertinggal diabaikan
+	if env.Node == _i.hub.node && _i.clients[p.ClientID] == nil {
+		return
+	}
+
+	current := _i.presence[p.ClientID]
+	if current != nil && p.Seq <= current.Seq {
+		return
+	}
+
+	selections, ok := _i.transformSelections(p.Selections, env.Revision)
+	if !ok && current != nil {
+		// Revision terlalu lama, pakai posisi terakhir yang sudah ikut di-transform
+		selections = current.Selections
+	}
+
+	entry := &presenceEntry{presenceState: *p, lastSeen: time.Now()}
+	entry.Selections = selections
+	_i.presence[p.ClientID] = entry
+
+	// Presence yang hanya diperbarui ulang tidak perlu dikirim lagi
+	if current != nil && current.Idle == entry.Idle && slices.Equal(current.Selections, entry.Selections) {
+		return
+	}
+
+	message := _i.presenceMessage(entry)
+	for _, c := range _i.clients {
+		if c.initialized && c.ID != p.ClientID {
+			c.Send(message)
+		}
+	}
+}
+
+// presenceLeaveLocked hapus presence editor yang keluar
+func (_i *room) presenceLeaveLocked(clientID string) {
+	if _, ok := _i.presence[clientID]; !ok {
+		return
+	}
+	delete(_i.presence, clientID)
+
+	message := response.PresenceLeave{
+		Type:     response.MessagePresenceLeave,
+		ClientID: clientID,
+	}
+	for _, c := range _i.clients {
+		if c.initialized {
+			c.Send(message)
+		}
+	}
+}
+
+// transformSelections geser selection dari revision editor ke revision saat ini
+func (_i *room) transformSelections(selections []ot.Range, revision int) ([]ot.Range, bool) {
+	s := &_i.state
+	if revision > s.Revision || revision < s.HistoryBase {
+		return nil, false
+	}
+
+	out := make([]ot.Range, len(selections))
+	copy(out, selections)
+	for _, op := range s.History[revision-s.HistoryBase:] {
+		for i := range out {
+			out[i] = out[i].Transform(op)
+		}
+	}
+
+	return out, true
+}
+
+// transformPresenceLocked geser cursor semua editor setelah op diterapkan
+func (_i *room) transformPresenceLocked(op *ot.Operation) {
+	for _, entry := range _i.presence {
+		if len(entry.Selections) == 0 {
+			continue
+		}
+
+		selections := make([]ot.Range, len(entry.Selections))
+		for i, selection := range entry.Selections {
+			selections[i] = selection.Transform(op)
+		}
+		entry.Selections = selections
+	}
+}
+
+// presenceListLocked presence semua editor kecuali exclude
+func (_i *room) presenceListLocked(exclude string) []response.Presence {
+	list := make([]response.Presence, 0, len(_i.presence))
+	for id, entry := range _i.presence {
+		if id != exclude {
+			list = append(list, _i.presenceMessage(entry))
+		}
+	}
+
+	return list
+}
+
+// presenceStatesLocked presence untuk balasan sync
+func (_i *room) presenceStatesLocked() []presenceState {
+	states := make([]presenceState, 0, len(_i.presence))
+	for _, entry := range _i.presence {
+		states = append(states, entry.presenceState)
+	}
+
+	return states
+}
+
+func (_i *room) presenceMessage(entry *presenceEntry) response.Presence {
+	selections := entry.Selections
+	if selections == nil {
+		selections = []ot.Range{}
+	}
+
+	return response.Presence{
+		Type:       response.MessagePresence,
+		ClientID:   entry.ClientID,
+		UserID:     entry.UserID,
+		Name:       entry.Name,
+		CanEdit:    entry.CanEdit,
+		Idle:       entry.Idle,
+		Revision:   _i.state.Revision,
+		Selections: selections,
+	}
+}
+
+// expirePresence buang presence editor di instance lain yang tidak diperbarui
+func (_i *room) expirePresence(now time.Time) {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	if _i.closed || _i.syncing {
+		return
+	}
+
+	for id, entry := range _i.presence {
+		if _i.clients[id] == nil && now.Sub(entry.lastSeen) > presenceTTL {
+			_i.presenceLeaveLocked(id)
+		}
+	}
+}
+
+func presenceKey(documentID uint64) string {
+	return presencePrefix + strconv.FormatUint(documentID, 10)
+}
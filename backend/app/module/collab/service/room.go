@@ -0,0 +1,658 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab/response"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/ot"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/pubsub"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// syncTimeout batas menunggu state dari instance lain, setelah itu
+	// room dianggap satu-satunya dan mulai dari versi terakhir di database
+	syncTimeout = 2 * time.Second
+
+	// historyLimit jumlah op terakhir yang disimpan untuk transform op yang
+	// dibuat dari revision lama. Op yang lebih tua ditolak dan editor harus sinkron ulang.
+	historyLimit = 500
+
+	// maxDocumentLength panjang maksimal document dalam rune
+	maxDocumentLength = ot.MaxLength
+)
+
+// Jenis pesan di bus
+const (
+	kindOperation   = "op"
+	kindSyncRequest = "sync_request"
+	kindSync        = "sync"
+	kindStale       = "stale"
+
+	kindPresence      = "presence"
+	kindPresenceLeave = "presence_leave"
+)
+
+// envelope pesan antar instance di channel document
+type envelope struct {
+	Kind     string        `json:"kind"`
+	Epoch    string        `json:"epoch,omitempty"`
+	Node     string        `json:"node"`
+	Nonce    string        `json:"nonce,omitempty"`
+	ClientID string        `json:"client_id,omitempty"`
+	UserID   uint64        `json:"user_id,omitempty"`
+	Revision int           `json:"revision"`
+	Version  int           `json:"version,omitempty"`
+	Op       *ot.Operation `json:"op,omitempty"`
+	State    *roomState    `json:"state,omitempty"`
+
+	Presence  *presenceState  `json:"presence,omitempty"`
+	Presences []presenceState `json:"presences,omitempty"`
+}
+
+// roomState state document yang identik di semua instance untuk epoch yang sama.
+// History[i] adalah op yang menghasilkan revision HistoryBase+i+1. BaseVersion
+// versi document di database saat epoch dimulai.
+type roomState struct {
+	BaseVersion int             `json:"base_version"`
+	Revision    int             `json:"revision"`
+	Content     string          `json:"content"`
+	HistoryBase int             `json:"history_base"`
+	History     []*ot.Operation `json:"history"`
+}
+
+// room satu document yang sedang diedit di instance ini.
+//
+// Saat dibuka, room subscribe ke channel document lalu publish sync_request.
+// Instance lain yang sudah memegang state membalas dengan sync berisi state
+// saat mereka memproses request tersebut, op setelah request di-buffer lalu
+// diterapkan ulang. Tanpa balasan, room mulai epoch baru dari database.
+// Jika dua epoch sempat berjalan bersamaan, epoch yang lebih baru dibuang.
+//
+// stale tanpa epoch dikirim setelah save di luar sesi, room yang BaseVersion-nya
+// lebih lama dari versi tersebut dibuang.
+type room struct {
+	hub        *hub
+	documentID uint64
+	channel    string
+	nonce      string
+
+	mu      sync.Mutex
+	clients map[string]*Client
+	sub     pubsub.Subscription
+	closed  bool
+
+	syncing     bool
+	requestSeen bool
+	buffer      []envelope
+	fallback    string
+	fallbackVer int
+
+	epoch string
+	state roomState
+
+	// presence editor di semua instance per client ID
+	presence map[string]*presenceEntry
+
+	// dirty ada op dari editor di instance ini yang belum di-snapshot
+	dirty      bool
+	lastAuthor uint64
+	// abandoned state tidak boleh disimpan karena berbeda dengan instance lain
+	abandoned bool
+}
+
+func newRoom(h *hub, documentID uint64) *room {
+	return &room{
+		hub:        h,
+		documentID: documentID,
+		channel:    channelName(documentID),
+		nonce:      uuid.NewString(),
+		clients:    make(map[string]*Client),
+		presence:   make(map[string]*presenceEntry),
+		syncing:    true,
+	}
+}
+
+func (_i *room) start(flushing chan struct{}) {
+	if flushing != nil {
+		<-flushing
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), busTimeout)
+	defer cancel()
+
+	// Subscribe lebih dulu agar save di luar sesi setelah content dibaca tidak terlewat
+	sub, err := _i.hub.bus.Subscribe(ctx, _i.channel, _i.handle)
+	if err != nil {
+		_i.fail(err)
+		return
+	}
+
+	content, version, err := _i.hub.loadContent(_i.documentID)
+	if err != nil {
+		_ = sub.Close()
+		_i.fail(err)
+		return
+	}
+
+	_i.mu.Lock()
+	if _i.closed {
+		_i.mu.Unlock()
+		_ = sub.Close()
+		return
+	}
+	_i.sub = sub
+	_i.fallback = content
+	_i.fallbackVer = version
+	_i.mu.Unlock()
+
+	// Bus in-process tidak punya instance lain yang bisa membalas
+	if !_i.hub.bus.Shared() {
+		_i.startEpoch()
+		return
+	}
+
+	payload, err := json.Marshal(envelope{
+		Kind:  kindSyncRequest,
+		Node:  _i.hub.node,
+		Nonce: _i.nonce,
+	})
+	if err == nil {
+		err = _i.hub.publish(_i.channel, payload)
+	}
+	if err != nil {
+		_i.fail(err)
+		return
+	}
+
+	time.AfterFunc(syncTimeout, _i.startEpoch)
+}
+
+// handle proses pesan bus, dipanggil berurutan oleh subscription
+func (_i *room) handle(payload []byte) {
+	var reply []byte
+
+	_i.mu.Lock()
+	if _i.closed {
+		_i.mu.Unlock()
+		return
+	}
+
+	if payload == nil {
+		// Pesan mungkin hilang saat koneksi bus terputus, state lokal masih
+		// konsisten sehingga tetap disimpan sebelum editor sinkron ulang
+		_i.resetLocked(ErrResyncRequired, false)
+		_i.mu.Unlock()
+		return
+	}
+
+	var env envelope
+	if err := json.Unmarshal(payload, &env); err != nil {
+		_i.mu.Unlock()
+		return
+	}
+
+	reply = _i.dispatchLocked(env)
+	_i.mu.Unlock()
+
+	if reply != nil {
+		if err := _i.hub.publish(_i.channel, reply); err != nil {
+			log.Warn().Err(err).Uint64("document_id", _i.documentID).Msg("collab sync reply failed")
+		}
+	}
+}
+
+// dispatchLocked panic dari envelope rusak tidak boleh mematikan goroutine
+// subscription, editor pengirim diputus dan envelope diabaikan
+func (_i *room) dispatchLocked(env envelope) (reply []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error().
+				Interface("panic", r).
+				Uint64("document_id", _i.documentID).
+				Str("client_id", env.ClientID).
+				Msg("collab envelope handler panicked")
+
+			if origin := _i.clients[env.ClientID]; origin != nil {
+				origin.CloseWith(ErrInvalidOperation)
+			}
+			reply = nil
+		}
+	}()
+
+	if _i.syncing {
+		_i.handleSyncingLocked(env)
+		return nil
+	}
+	return _i.handleReadyLocked(env)
+}
+
+func (_i *room) handleSyncingLocked(env envelope) {
+	switch {
+	case env.Kind == kindSyncRequest && env.Nonce == _i.nonce:
+		_i.requestSeen = true
+	case env.Kind == kindSync && env.Nonce == _i.nonce && _i.requestSeen && env.State != nil:
+		_i.epoch = env.Epoch
+		_i.state = *env.State
+
+		now := time.Now()
+		for _, p := range env.Presences {
+			_i.presence[p.ClientID] = &presenceEntry{presenceState: p, lastSeen: now}
+		}
+
+		_i.finishSyncLocked()
+	case env.Kind == kindOperation && _i.requestSeen,
+		env.Kind == kindPresence && _i.requestSeen,
+		env.Kind == kindPresenceLeave && _i.requestSeen,
+		env.Kind == kindStale:
+		// stale disimpan tanpa menunggu request karena content fallback mungkin
+		// dibaca sebelum save di luar sesi
+		_i.buffer = append(_i.buffer, env)
+	}
+}
+
+func (_i *room) handleReadyLocked(env envelope) []byte {
+	switch env.Kind {
+	case kindSyncRequest:
+		if env.Node == _i.hub.node {
+			return nil
+		}
+
+		reply, err := json.Marshal(envelope{
+			Kind:  kindSync,
+			Epoch: _i.epoch,
+			Node:  _i.hub.node,
+			Nonce: env.Nonce,
+			State: &_i.state,
+
+			Presences: _i.presenceStatesLocked(),
+		})
+		if err != nil {
+			return nil
+		}
+		return reply
+
+	case kindSync:
+		// Ada instance lain dengan epoch lebih lama, state room ini yang dibuang
+		if env.Epoch != "" && env.Epoch < _i.epoch {
+			_i.resetLocked(ErrResyncRequired, true)
+		}
+
+	case kindStale:
+		if _i.staleLocked(env) {
+			_i.resetLocked(ErrDocumentChanged, true)
+		}
+
+	case kindOperation:
+		switch {
+		case env.Epoch == _i.epoch:
+			_i.applyLocked(env)
+		case env.Epoch < _i.epoch:
+			_i.resetLocked(ErrResyncRequired, true)
+		}
+
+	case kindPresence:
+		_i.presenceLocked(env)
+
+	case kindPresenceLeave:
+		_i.presenceLeaveLocked(env.ClientID)
+	}
+
+	return nil
+}
+
+// staleLocked true jika stale berlaku untuk state room ini, baik untuk epoch
+// ini maupun save di luar sesi yang lebih baru dari BaseVersion
+func (_i *room) staleLocked(env envelope) bool {
+	if env.Epoch != "" {
+		return env.Epoch == _i.epoch
+	}
+	return env.Version > _i.state.BaseVersion
+}
+
+// applyLocked transform op terhadap op yang belum dilihat editor lalu terapkan.
+// Hasilnya deterministik sehingga semua instance menolak atau menerima op yang sama.
+func (_i *room) applyLocked(env envelope) {
+	origin := _i.clients[env.ClientID]
+	reject := func(e response.Error) {
+		if origin != nil {
+			origin.CloseWith(e)
+		}
+	}
+
+	s := &_i.state
+	if env.Op == nil || env.Revision > s.Revision {
+		reject(ErrInvalidOperation)
+		return
+	}
+	if env.Revision < s.HistoryBase {
+		reject(ErrRevisionOutdated)
+		return
+	}
+
+	op := env.Op
+	for _, concurrent := range s.History[env.Revision-s.HistoryBase:] {
+		var err error
+		if op, _, err = ot.Transform(op, concurrent); err != nil {
+			reject(ErrInvalidOperation)
+			return
+		}
+	}
+
+	if op.TargetLen > maxDocumentLength {
+		reject(ErrDocumentTooLarge)
+		return
+	}
+
+	content, err := op.Apply(s.Content)
+	if err != nil {
+		reject(ErrInvalidOperation)
+		return
+	}
+
+	s.Content = content
+	s.Revision++
+	s.History = append(s.History, op)
+
+	// Dipangkas per historyLimit op agar tidak menyalin slice di setiap op
+	if len(s.History) >= 2*historyLimit {
+		s.History = append([]*ot.Operation(nil), s.History[historyLimit:]...)
+		s.HistoryBase += historyLimit
+	}
+
+	_i.transformPresenceLocked(op)
+
+	if env.Node == _i.hub.node {
+		_i.dirty = true
+		_i.lastAuthor = env.UserID
+	}
+
+	for _, c := range _i.clients {
+		if !c.initialized {
+			continue
+		}
+
+		if c.ID == env.ClientID {
+			c.Send(response.Ack{
+				Type:     response.MessageAck,
+				Revision: s.Revision,
+			})
+			continue
+		}
+
+		c.Send(response.Operation{
+			Type:     response.MessageOperation,
+			Revision: s.Revision,
+			ClientID: env.ClientID,
+			UserID:   env.UserID,
+			Op:       op,
+		})
+	}
+}
+
+// startEpoch mulai sesi baru dari versi terakhir di database jika belum
+// mendapat state dari instance lain
+func (_i *room) startEpoch() {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	if _i.closed || !_i.syncing {
+		return
+	}
+
+	_i.epoch = newEpoch()
+	_i.state = roomState{Content: _i.fallback, BaseVersion: _i.fallbackVer}
+	_i.finishSyncLocked()
+}
+
+func (_i *room) finishSyncLocked() {
+	_i.syncing = false
+	_i.fallback = ""
+
+	buffered := _i.buffer
+	_i.buffer = nil
+	for _, env := range buffered {
+		switch {
+		case env.Kind == kindStale:
+			if _i.staleLocked(env) {
+				_i.resetLocked(ErrDocumentChanged, true)
+				return
+			}
+		case env.Kind == kindOperation && env.Epoch == _i.epoch:
+			_i.applyLocked(env)
+		case env.Kind == kindPresence:
+			_i.presenceLocked(env)
+		case env.Kind == kindPresenceLeave:
+			_i.presenceLeaveLocked(env.ClientID)
+		}
+	}
+
+	for _, c := range _i.clients {
+		if !c.initialized {
+			_i.initLocked(c)
+		}
+	}
+}
+
+func (_i *room) initLocked(c *Client) {
+	c.Send(response.Init{
+		Type:     response.MessageInit,
+		ClientID: c.ID,
+		Revision: _i.state.Revision,
+		Content:  _i.state.Content,
+		CanEdit:  c.CanEdit,
+		Presence: _i.presenceListLocked(c.ID),
+	})
+	c.initialized = true
+
+	// Editor lain baru tahu ada editor ini setelah init terkirim
+	go _i.hub.announce(_i, c)
+}
+
+// resetLocked putus semua editor agar connect ulang dan sinkron dari awal
+func (_i *room) resetLocked(message response.Error, abandon bool) {
+	_i.closed = true
+	_i.abandoned = abandon
+
+	for id, c := range _i.clients {
+		c.CloseWith(message)
+		delete(_i.clients, id)
+	}
+
+	go _i.hub.discard(_i)
+}
+
+// resetIfOlder reset lokal jika stale gagal di-publish ke bus
+func (_i *room) resetIfOlder(version int) {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	if !_i.closed && !_i.syncing && version > _i.state.BaseVersion {
+		_i.resetLocked(ErrDocumentChanged, true)
+	}
+}
+
+func (_i *room) fail(err error) {
+	log.Error().Err(err).Uint64("document_id", _i.documentID).Msg("collab room failed to open")
+
+	_i.mu.Lock()
+	_i.abandoned = true
+	_i.mu.Unlock()
+
+	_i.closeClients(ErrUnavailable)
+	_i.hub.discard(_i)
+}
+
+func (_i *room) add(client *Client) {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	_i.clients[client.ID] = client
+	if !_i.syncing {
+		_i.initLocked(client)
+	}
+}
+
+// remove true jika client ada di room dan room sekarang kosong
+func (_i *room) remove(client *Client) bool {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	if _i.clients[client.ID] != client {
+		return false
+	}
+	delete(_i.clients, client.ID)
+
+	if !_i.closed && !_i.syncing {
+		_i.presenceLeaveLocked(client.ID)
+	}
+
+	return len(_i.clients) == 0
+}
+
+// epochFor epoch untuk op baru, false jika client belum menerima init
+func (_i *room) epochFor(client *Client) (string, bool) {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	if _i.closed || _i.syncing || !client.initialized || _i.clients[client.ID] != client {
+		return "", false
+	}
+
+	return _i.epoch, true
+}
+
+func (_i *room) isClosed() bool {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	return _i.closed
+}
+
+func (_i *room) closeClients(message response.Error) {
+	for _, c := range _i.members() {
+		c.CloseWith(message)
+	}
+}
+
+// members editor yang terhubung ke room di instance ini
+func (_i *room) members() []*Client {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	clients := make([]*Client, 0, len(_i.clients))
+	for _, c := range _i.clients {
+		clients = append(clients, c)
+	}
+
+	return clients
+}
+
+// snapshot content room yang akan disimpan ke riwayat versi
+type snapshot struct {
+	content     string
+	author      uint64
+	baseVersion int
+}
+
+// takeSnapshot content yang perlu disimpan ke riwayat versi
+func (_i *room) takeSnapshot() (snapshot, bool) {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	if !_i.dirty || _i.closed || _i.syncing {
+		return snapshot{}, false
+	}
+
+	_i.dirty = false
+	return _i.snapshotLocked(), true
+}
+
+func (_i *room) snapshotLocked() snapshot {
+	return snapshot{
+		content:     _i.state.Content,
+		author:      _i.lastAuthor,
+		baseVersion: _i.state.BaseVersion,
+	}
+}
+
+// invalidate document diubah di luar sesi. Semua instance membuang state epoch
+// ini dan editor connect ulang dari versi terbaru di database.
+func (_i *room) invalidate() {
+	_i.mu.Lock()
+	if _i.closed || _i.syncing {
+		_i.mu.Unlock()
+		return
+	}
+
+	payload, err := json.Marshal(envelope{
+		Kind:  kindStale,
+		Epoch: _i.epoch,
+		Node:  _i.hub.node,
+	})
+	_i.mu.Unlock()
+
+	if err == nil {
+		err = _i.hub.publish(_i.channel, payload)
+	}
+	if err != nil {
+		log.Warn().Err(err).Uint64("document_id", _i.documentID).Msg("collab stale notification failed")
+
+		_i.mu.Lock()
+		if !_i.closed {
+			_i.resetLocked(ErrDocumentChanged, true)
+		}
+		_i.mu.Unlock()
+	}
+}
+
+// markDirty snapshot gagal disimpan, dicoba lagi di interval berikutnya
+func (_i *room) markDirty(author uint64) {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	_i.dirty = true
+	if _i.lastAuthor == 0 {
+		_i.lastAuthor = author
+	}
+}
+
+// close berhenti subscribe dan putus editor yang tersisa. Content dikembalikan
+// jika masih ada perubahan lokal yang belum di-snapshot.
+func (_i *room) close() (snap snapshot, dirty bool) {
+	_i.mu.Lock()
+	_i.closed = true
+
+	sub := _i.sub
+	_i.sub = nil
+
+	dirty = _i.dirty && !_i.syncing && !_i.abandoned
+	_i.dirty = false
+	snap = _i.snapshotLocked()
+
+	clients := make([]*Client, 0, len(_i.clients))
+	for id, c := range _i.clients {
+		clients = append(clients, c)
+		delete(_i.clients, id)
+	}
+	_i.mu.Unlock()
+
+	if sub != nil {
+		_ = sub.Close()
+	}
+	for _, c := range clients {
+		c.Close()
+	}
+
+	return snap, dirty
+}
+
+// newEpoch urut berdasarkan waktu, epoch yang lebih kecil lebih lama
+func newEpoch() string {
+	return fmt.Sprintf("%016x-%s", time.Now().UnixNano(), uuid.NewString()[:8])
+}
@@ -0,0 +1,75 @@
+package comment
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/comment/controller"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/comment/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/comment/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+)
+
+// CommentRouter adalah router untuk comment module
+type CommentRouter struct {
+	App        fiber.Router
+	Controller *controller.Controller
+	AuthMW     *middleware.AuthMiddleware
+	PolicyMW   *middleware.PolicyMiddleware
+}
+
+// Module adalah FX module untuk comment
+var NewCommentModule = fx.Options(
+	// register repository
+	fx.Provide(repository.NewCommentRepository),
+
+	// register service
+	fx.Provide(service.NewCommentService),
+
+	// register controller
+	controller.Module,
+
+	// register router
+	fx.Provide(NewCommentRouter),
+)
+
+// NewCommentRouter membuat instance baru dari CommentRouter
+func NewCommentRouter(
+	app *fiber.App,
+	ctrl *controller.Controller,
+	authMW *middleware.AuthMiddleware,
+	policyMW *middleware.PolicyMiddleware,
+) *CommentRouter {
+	return &CommentRouter{
+		App:        app,
+		Controller: ctrl,
+		AuthMW:     authMW,
+		PolicyMW:   policyMW,
+	}
+}
+
+// RegisterCommentRoutes mendaftarkan routes untuk comment
+func (_i *CommentRouter) RegisterCommentRoutes() {
+	// define controllers
+	commentController := _i.Controller.Comment
+
+	_i.App.Route("/api/v1", func(router fiber.Router) {
+		threadRoutes := router.Group("/documents/:id/threads", _i.AuthMW.RequireAuth())
+
+		// Hak resolve dan hapus milik user lain dicek di service
+		canView := _i.PolicyMW.Document(policy.CommentView, "id")
+		canComment := _i.PolicyMW.Document(policy.CommentCreate, "id")
+
+		threadRoutes.Get("", canView, commentController.ListThreads)
+		threadRoutes.Post("", canComment, commentController.CreateThread)
+		threadRoutes.Get("/:threadId", canView, commentController.GetThread)
+		threadRoutes.Delete("/:threadId", canView, commentController.DeleteThread)
+		threadRoutes.Post("/:threadId/resolve", canComment, commentController.ResolveThread)
+		threadRoutes.Post("/:threadId/reopen", canComment, commentController.ReopenThread)
+		threadRoutes.Post("/:threadId/comments", canComment, commentController.Reply)
+		threadRoutes.Put("/:threadId/comments/:commentId", canComment, commentController.UpdateComment)
+		threadRoutes.Delete("/:threadId/comments/:commentId", canView, commentController.DeleteComment)
+
+		router.Get("/mentions", _i.AuthMW.RequireAuth(), commentController.ListMentions)
+	})
+}
@@ -0,0 +1,343 @@
+package controller
+
+import (
+	"strconv"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/comment/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/comment/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CommentController
+type commentController struct {
+	commentService service.CommentService
+}
+
+type CommentControllerI interface {
+	ListThreads(c *fiber.Ctx) error
+	GetThread(c *fiber.Ctx) error
+	CreateThread(c *fiber.Ctx) error
+	DeleteThread(c *fiber.Ctx) error
+	ResolveThread(c *fiber.Ctx) error
+	ReopenThread(c *fiber.Ctx) error
+	Reply(c *fiber.Ctx) error
+	UpdateComment(c *fiber.Ctx) error
+	DeleteComment(c *fiber.Ctx) error
+	ListMentions(c *fiber.Ctx) error
+}
+
+func NewCommentController(commentService service.CommentService) CommentControllerI {
+	return &commentController{
+		commentService: commentService,
+	}
+}
+
+// ListThreads handler untuk list thread komentar document, bisa difilter ?status=open|resolved
+func (_i *commentController) ListThreads(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_document_id", "invalid document id")
+	}
+
+	status := schema.CommentStatus(c.Query("status"))
+	if status != "" && status != schema.CommentStatusOpen && status != schema.CommentStatusResolved {
+		return apperr.BadRequest("invalid_status", "status must be open or resolved")
+	}
+
+	result, err := _i.commentService.ListThreads(documentID, userID, status)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"comment threads retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// GetThread handler untuk get satu thread beserta komentarnya
+func (_i *commentController) GetThread(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, threadID, err := parseThreadParams(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := _i.commentService.GetThread(documentID, threadID, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"comment thread retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// CreateThread handler untuk membuka thread baru pada node, edge atau range baris
+func (_i *commentController) CreateThread(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_document_id", "invalid document id")
+	}
+
+	var req request.CreateThreadRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.ErrInvalidBody
+	}
+
+	// Validasi input
+	if err := response.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	result, err := _i.commentService.CreateThread(documentID, userID, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusCreated,
+		Messages: response.Messages{"comment thread created successfully"},
+		Data:     result,
+	})
+}
+
+// DeleteThread handler untuk menghapus thread beserta seluruh komentarnya
+func (_i *commentController) DeleteThread(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, threadID, err := parseThreadParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err := _i.commentService.DeleteThread(documentID, threadID, userID); err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"comment thread deleted successfully"},
+	})
+}
+
+// ResolveThread handler untuk menandai thread selesai
+func (_i *commentController) ResolveThread(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, threadID, err := parseThreadParams(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := _i.commentService.ResolveThread(documentID, threadID, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"comment thread resolved successfully"},
+		Data:     result,
+	})
+}
+
+// ReopenThread handler untuk membuka kembali thread yang sudah resolved
+func (_i *commentController) ReopenThread(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, threadID, err := parseThreadParams(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := _i.commentService.ReopenThread(documentID, threadID, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"comment thread reopened successfully"},
+		Data:     result,
+	})
+}
+
+// Reply handler untuk membalas thread
+func (_i *commentController) Reply(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, threadID, err := parseThreadParams(c)
+	if err != nil {
+		return err
+	}
+
+	var req request.CommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.ErrInvalidBody
+	}
+
+	// Validasi input
+	if err := response.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	result, err := _i.commentService.Reply(documentID, threadID, userID, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusCreated,
+		Messages: response.Messages{"comment created successfully"},
+		Data:     result,
+	})
+}
+
+// UpdateComment handler untuk mengubah isi komentar milik sendiri
+func (_i *commentController) UpdateComment(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, threadID, err := parseThreadParams(c)
+	if err != nil {
+		return err
+	}
+
+	commentID, err := strconv.ParseUint(c.Params("commentId"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_comment_id", "invalid comment id")
+	}
+
+	var req request.CommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.ErrInvalidBody
+	}
+
+	// Validasi input
+	if err := response.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	result, err := _i.commentService.UpdateComment(documentID, threadID, commentID, userID, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"comment updated successfully"},
+		Data:     result,
+	})
+}
+
+// DeleteComment handler untuk menghapus komentar
+func (_i *commentController) DeleteComment(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, threadID, err := parseThreadParams(c)
+	if err != nil {
+		return err
+	}
+
+	commentID, err := strconv.ParseUint(c.Params("commentId"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_comment_id", "invalid comment id")
+	}
+
+	if err := _i.commentService.DeleteComment(documentID, threadID, commentID, userID); err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"comment deleted successfully"},
+	})
+}
+
+// ListMentions handler untuk list komentar yang me-mention user login
+func (_i *commentController) ListMentions(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	page := 1
+	limit := 10
+
+	if p := c.Query("page"); p != "" {
+		if parsedPage, err := strconv.Atoi(p); err == nil && parsedPage > 0 {
+			page = parsedPage
+		}
+	}
+
+	if l := c.Query("limit"); l != "" {
+		if parsedLimit, err := strconv.Atoi(l); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
+			limit = parsedLimit
+		}
+	}
+
+	result, err := _i.commentService.ListMentions(userID, page, limit)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"mentions retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// parseThreadParams ambil document id dan thread id dari path
+func parseThreadParams(c *fiber.Ctx) (uint64, uint64, error) {
+	documentID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, apperr.BadRequest("invalid_document_id", "invalid document id")
+	}
+
+	threadID, err := strconv.ParseUint(c.Params("threadId"), 10, 64)
+	if err != nil {
+		return 0, 0, apperr.BadRequest("invalid_thread_id", "invalid thread id")
+	}
+
+	return documentID, threadID, nil
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/comment/service"
+	"go.uber.org/fx"
+)
+
+// Controller aggregator
+type Controller struct {
+	Comment CommentControllerI
+}
+
+// NewController
+func NewController(commentController CommentControllerI) *Controller {
+	return &Controller{
+		Comment: commentController,
+	}
+}
+
+var Module = fx.Options(
+	fx.Provide(func(commentService service.CommentService) CommentControllerI {
+		return NewCommentController(commentService)
+	}),
+	fx.Provide(NewController),
+)
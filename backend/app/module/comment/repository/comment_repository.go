@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
+	"gorm.io/gorm"
+)
+
+// CommentRepository
+type CommentRepository interface {
+	// CreateThread simpan thread beserta komentar pembuka dan mention-nya
+	CreateThread(thread *schema.CommentThread) (*schema.CommentThread, error)
+	FindThread(documentID uint64, id uint64) (*schema.CommentThread, error)
+	FindThreadsByDocumentID(documentID uint64, status schema.CommentStatus) ([]schema.CommentThread, error)
+	UpdateStatus(thread *schema.CommentThread) error
+	DeleteThread(id uint64) error
+
+	CreateComment(comment *schema.Comment) (*schema.Comment, error)
+	FindComment(threadID uint64, id uint64) (*schema.Comment, error)
+	// UpdateComment simpan body baru dan ganti seluruh mention komentar
+	UpdateComment(comment *schema.Comment) error
+	// DeleteComment hapus komentar, thread ikut dihapus jika tidak ada komentar tersisa
+	DeleteComment(comment *schema.Comment) (threadDeleted bool, err error)
+
+	FindMentionsByUserID(userID uint64, limit, offset int) ([]schema.CommentMention, error)
+	CountMentionsByUserID(userID uint64) (int64, error)
+}
+
+type commentRepository struct {
+	db *database.Database
+}
+
+func NewCommentRepository(db *database.Database) CommentRepository {
+	return &commentRepository{
+		db: db,
+	}
+}
+
+func (_i *commentRepository) CreateThread(thread *schema.CommentThread) (*schema.CommentThread, error) {
+	if err := _i.db.DB.Create(thread).Error; err != nil {
+		return nil, err
+	}
+
+	return _i.FindThread(thread.DocumentID, thread.ID)
+}
+
+func (_i *commentRepository) FindThread(documentID uint64, id uint64) (*schema.CommentThread, error) {
+	var thread schema.CommentThread
+	if err := _i.withComments(_i.db.DB).
+		Where("document_id = ? AND id = ?", documentID, id).
+		First(&thread).Error; err != nil {
+		return nil, err
+	}
+
+	return &thread, nil
+}
+
+// FindThreadsByDocumentID list thread document, status kosong berarti semua status
+func (_i *commentRepository) FindThreadsByDocumentID(documentID uint64, status schema.CommentStatus) ([]schema.CommentThread, error) {
+	query := _i.withComments(_i.db.DB).Where("document_id = ?", documentID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var threads []schema.CommentThread
+	if err := query.Order("created_at ASC, id ASC").Find(&threads).Error; err != nil {
+		return nil, err
+	}
+
+	return threads, nil
+}
+
+func (_i *commentRepository) UpdateStatus(thread *schema.CommentThread) error {
+	return _i.db.DB.Model(&schema.CommentThread{ID: thread.ID}).
+		Updates(map[string]any{
+			"status":      thread.Status,
+			"resolved_by": thread.ResolvedBy,
+			"resolved_at": thread.ResolvedAt,
+		}).Error
+}
+
+func (_i *commentRepository) DeleteThread(id uint64) error {
+	return _i.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("thread_id = ?", id).Delete(&schema.Comment{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", id).Delete(&schema.CommentThread{}).Error
+	})
+}
+
+func (_i *commentRepository) CreateComment(comment *schema.Comment) (*schema.Comment, error) {
+	if err := _i.db.DB.Create(comment).Error; err != nil {
+		return nil, err
+	}
+
+	return _i.FindComment(comment.ThreadID, comment.ID)
+}
+
+func (_i *commentRepository) FindComment(threadID uint64, id uint64) (*schema.Comment, error) {
+	var comment schema.Comment
+	if err := _i.db.DB.Preload("Author").
+		Preload("Mentions.User").
+		Where("thread_id = ? AND id = ?", threadID, id).
+		First(&comment).Error; err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+func (_i *commentRepository) UpdateComment(comment *schema.Comment) error {
+	return _i.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&schema.Comment{ID: comment.ID}).Update("body", comment.Body).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("comment_id = ?", comment.ID).Delete(&schema.CommentMention{}).Error; err != nil {
+			return err
+		}
+
+		if len(comment.Mentions) == 0 {
+			return nil
+		}
+		for i := range comment.Mentions {
+			comment.Mentions[i].CommentID = comment.ID
+		}
+		return tx.Create(&comment.Mentions).Error
+	})
+}
+
+func (_i *commentRepository) DeleteComment(comment *schema.Comment) (bool, error) {
+	threadDeleted := false
+
+	err := _i.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&schema.Comment{}, comment.ID).Error; err != nil {
+			return err
+		}
+
+		var remaining int64
+		if err := tx.Model(&schema.Comment{}).Where("thread_id = ?", comment.ThreadID).Count(&remaining).Error; err != nil {
+			return err
+		}
+		if remaining > 0 {
+			return nil
+		}
+
+		threadDeleted = true
+		return tx.Delete(&schema.CommentThread{}, comment.ThreadID).Error
+	})
+
+	return threadDeleted, err
+}
+
+// FindMentionsByUserID mention untuk user di document yang masih bisa ia akses
+// sebagai owner atau member workspace, terbaru lebih dulu
+func (_i *commentRepository) FindMentionsByUserID(userID uint64, limit, offset int) ([]schema.CommentMention, error) {
+	var mentions []schema.CommentMention
+	if err := _i.mentionsByUserQuery(userID).
+		Preload("Comment.Author").
+		Preload("Comment.Thread.Document").
+		Order("comment_mentions.created_at DESC, comment_mentions.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&mentions).Error; err != nil {
+		return nil, err
+	}
+
+	return mentions, nil
+}
+
+func (_i *commentRepository) CountMentionsByUserID(userID uint64) (int64, error) {
+	var count int64
+	if err := _i.mentionsByUserQuery(userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (_i *commentRepository) mentionsByUserQuery(userID uint64) *gorm.DB {
+	return _i.db.DB.Model(&schema.CommentMention{}).
+		Joins("JOIN comments ON comments.id = comment_mentions.comment_id AND comments.deleted_at IS NULL").
+		Joins("JOIN comment_threads ON comment_threads.id = comments.thread_id AND comment_threads.deleted_at IS NULL").
+		Joins("JOIN documents ON documents.id = comment_threads.document_id AND documents.deleted_at IS NULL").
+		Joins("JOIN workspaces ON workspaces.id = documents.workspace_id AND workspaces.deleted_at IS NULL").
+		Where("comment_mentions.user_id = ?", userID).
+		Where("workspaces.owner_id = ? OR EXISTS (SELECT 1 FROM workspace_members WHERE workspace_members.workspace_id = workspaces.id AND workspace_members.user_id = ?)", userID, userID)
+}
+
+// withComments preload komentar thread berurutan beserta author dan mention
+func (_i *commentRepository) withComments(db *gorm.DB) *gorm.DB {
+	return db.Preload("Comments", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("created_at ASC, id ASC")
+	}).
+		Preload("Comments.Author").
+		Preload("Comments.Mentions.User")
+}
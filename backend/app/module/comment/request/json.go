@@ -0,0 +1,24 @@
+package request
+
+// AnchorRequest target thread: NodeID untuk anchor node, EdgeFrom/EdgeTo untuk
+// anchor edge, LineStart/LineEnd (1-based, inklusif) untuk anchor lines
+type AnchorRequest struct {
+	Type      string `json:"type" validate:"required,oneof=node edge lines"`
+	NodeID    string `json:"node_id" validate:"omitempty,max=255"`
+	EdgeFrom  string `json:"edge_from" validate:"omitempty,max=255"`
+	EdgeTo    string `json:"edge_to" validate:"omitempty,max=255"`
+	LineStart int    `json:"line_start" validate:"omitempty,min=1"`
+	LineEnd   int    `json:"line_end" validate:"omitempty,min=1"`
+}
+
+// CreateThreadRequest VersionNumber versi yang sedang dilihat reviewer,
+// default versi terakhir
+type CreateThreadRequest struct {
+	VersionNumber *int          `json:"version_number" validate:"omitempty,min=1"`
+	Anchor        AnchorRequest `json:"anchor" validate:"required"`
+	Body          string        `json:"body" validate:"required,max=10000"`
+}
+
+type CommentRequest struct {
+	Body string `json:"body" validate:"required,max=10000"`
+}
@@ -0,0 +1,81 @@
+package response
+
+import (
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+)
+
+// Anchor posisi thread di versi VersionNumber. Line* berisi baris node/edge
+// untuk anchor mermaid. Outdated true jika target tidak ada lagi di versi
+// terbaru, posisi yang dikirim tetap posisi di VersionNumber.
+type Anchor struct {
+	Type          schema.CommentAnchorType `json:"type"`
+	NodeID        string                   `json:"node_id,omitempty"`
+	EdgeFrom      string                   `json:"edge_from,omitempty"`
+	EdgeTo        string                   `json:"edge_to,omitempty"`
+	LineStart     int                      `json:"line_start"`
+	LineEnd       int                      `json:"line_end"`
+	VersionNumber int                      `json:"version_number"`
+	Outdated      bool                     `json:"outdated"`
+}
+
+type Mention struct {
+	UserID uint64 `json:"user_id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+}
+
+type CommentResponse struct {
+	ID         uint64    `json:"id"`
+	ThreadID   uint64    `json:"thread_id"`
+	AuthorID   uint64    `json:"author_id"`
+	AuthorName string    `json:"author_name"`
+	Body       string    `json:"body"`
+	Mentions   []Mention `json:"mentions"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ThreadResponse VersionNumber versi document saat thread dibuat
+type ThreadResponse struct {
+	ID            uint64               `json:"id"`
+	DocumentID    uint64               `json:"document_id"`
+	VersionNumber int                  `json:"version_number"`
+	Anchor        Anchor               `json:"anchor"`
+	Status        schema.CommentStatus `json:"status"`
+	AuthorID      uint64               `json:"author_id"`
+	ResolvedBy    *uint64              `json:"resolved_by"`
+	ResolvedAt    *time.Time           `json:"resolved_at"`
+	Comments      []CommentResponse    `json:"comments"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+type ThreadListResponse struct {
+	Data          []ThreadResponse `json:"data"`
+	Total         int              `json:"total"`
+	LatestVersion int              `json:"latest_version"`
+}
+
+// MentionResponse komentar yang me-mention user login
+type MentionResponse struct {
+	ID            uint64               `json:"id"`
+	CommentID     uint64               `json:"comment_id"`
+	ThreadID      uint64               `json:"thread_id"`
+	DocumentID    uint64               `json:"document_id"`
+	WorkspaceID   uint64               `json:"workspace_id"`
+	DocumentTitle string               `json:"document_title"`
+	AuthorID      uint64               `json:"author_id"`
+	AuthorName    string               `json:"author_name"`
+	Body          string               `json:"body"`
+	ThreadStatus  schema.CommentStatus `json:"thread_status"`
+	CreatedAt     time.Time            `json:"created_at"`
+}
+
+type MentionListResponse struct {
+	Data  []MentionResponse `json:"data"`
+	Total int64             `json:"total"`
+	Page  int               `json:"page"`
+	Limit int               `json:"limit"`
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/diff"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/mermaid"
+)
+
+// revision content satu versi. Graph mermaid dan jumlah baris dihitung saat
+// pertama dipakai lalu dipakai ulang oleh semua thread.
+type revision struct {
+	content   string
+	graph     *mermaid.Graph
+	lineCount int
+	counted   bool
+}
+
+func newRevision(content string) *revision {
+	return &revision{content: content}
+}
+
+func (_i *revision) parsed() *mermaid.Graph {
+	if _i.graph == nil {
+		_i.graph, _ = mermaid.Parse(_i.content)
+	}
+	return _i.graph
+}
+
+func (_i *revision) lines() int {
+	if !_i.counted {
+		_i.lineCount, _i.counted = len(diff.SplitLines(_i.content)), true
+	}
+	return _i.lineCount
+}
+
+// transition perubahan dari satu versi ke versi lain. Line map dihitung sekali
+// untuk semua thread dengan AnchorVersion yang sama.
+type transition struct {
+	from, to *revision
+	lineMap  []int
+	mapped   bool
+}
+
+func (_i *transition) lines() []int {
+	if !_i.mapped {
+		_i.lineMap, _i.mapped = diff.LineMap(_i.from.content, _i.to.content), true
+	}
+	return _i.lineMap
+}
+
+// locate cari target anchor di content lalu isi posisi barisnya.
+// false jika target tidak ada.
+func locate(thread *schema.CommentThread, content *revision) bool {
+	switch thread.AnchorType {
+	case schema.CommentAnchorNode:
+		node := content.parsed().Node(thread.NodeID)
+		if node == nil {
+			return false
+		}
+		thread.LineStart, thread.LineEnd = node.Line, node.Line
+		return true
+
+	case schema.CommentAnchorEdge:
+		edge := findEdge(content.parsed(), thread.EdgeFrom, thread.EdgeTo)
+		if edge == nil {
+			return false
+		}
+		thread.LineStart, thread.LineEnd = edge.Line, edge.Line
+		return true
+
+	case schema.CommentAnchorLines:
+		return thread.LineStart >= 1 && thread.LineStart <= thread.LineEnd && thread.LineEnd <= content.lines()
+	}
+
+	return false
+}
+
+// reanchor pindahkan anchor thread dari content lama ke content baru.
+// Node yang id-nya diganti diikuti lewat label yang sama, range baris
+// mengikuti baris yang masih tersisa. false jika target sudah hilang,
+// anchor thread tidak diubah.
+func reanchor(thread *schema.CommentThread, change *transition) bool {
+	moved := *thread
+
+	switch thread.AnchorType {
+	case schema.CommentAnchorNode, schema.CommentAnchorEdge:
+		oldGraph, newGraph := change.from.parsed(), change.to.parsed()
+
+		if thread.AnchorType == schema.CommentAnchorNode {
+			moved.NodeID = renamedNode(oldGraph, newGraph, thread.NodeID)
+		} else {
+			moved.EdgeFrom = renamedNode(oldGraph, newGraph, thread.EdgeFrom)
+			moved.EdgeTo = renamedNode(oldGraph, newGraph, thread.EdgeTo)
+		}
+
+	case schema.CommentAnchorLines:
+		lineMap := change.lines()
+		start, end := 0, 0
+		for line := thread.LineStart; line <= thread.LineEnd && line <= len(lineMap); line++ {
+			if mapped := lineMap[line-1]; mapped >= 0 {
+				if start == 0 {
+					start = mapped + 1
+				}
+				end = mapped + 1
+			}
+		}
+		if start == 0 {
+			return false
+		}
+		moved.LineStart, moved.LineEnd = start, end
+	}
+
+	if !locate(&moved, change.to) {
+		return false
+	}
+
+	*thread = moved
+	return true
+}
+
+// renamedNode id node di graph baru. Jika id sudah tidak ada, node baru
+// dengan label yang sama dan belum ada di graph lama dianggap node yang
+// di-rename, selama hanya ada satu kandidat.
+func renamedNode(oldGraph, newGraph *mermaid.Graph, id string) string {
+	if newGraph.Node(id) != nil {
+		return id
+	}
+
+	old := oldGraph.Node(id)
+	if old == nil || old.Label == "" {
+		return id
+	}
+
+	candidate := ""
+	for _, node := range newGraph.Nodes {
+		if node.Label != old.Label || oldGraph.Node(node.ID) != nil {
+			continue
+		}
+		if candidate != "" {
+			return id
+		}
+		candidate = node.ID
+	}
+
+	if candidate == "" {
+		return id
+	}
+	return candidate
+}
+
+func findEdge(graph *mermaid.Graph, from, to string) *mermaid.Edge {
+	for _, edge := range graph.Edges {
+		if edge.From == from && edge.To == to {
+			return edge
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"testing"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+)
+
+type anchorFields struct {
+	nodeID, edgeFrom, edgeTo string
+	lineStart, lineEnd       int
+}
+
+func anchorOf(thread *schema.CommentThread) anchorFields {
+	return anchorFields{thread.NodeID, thread.EdgeFrom, thread.EdgeTo, thread.LineStart, thread.LineEnd}
+}
+
+func TestReanchor(t *testing.T) {
+	cases := []struct {
+		name   string
+		from   string
+		to     string
+		thread schema.CommentThread
+		ok     bool
+		want   schema.CommentThread
+	}{
+		{
+			name:   "node moved to another line",
+			from:   "graph TD\nA[Start] --> B",
+			to:     "graph TD\nC --> D\nA[Start] --> B",
+			thread: schema.CommentThread{AnchorType: schema.CommentAnchorNode, NodeID: "A", LineStart: 2, LineEnd: 2},
+			ok:     true,
+			want:   schema.CommentThread{AnchorType: schema.CommentAnchorNode, NodeID: "A", LineStart: 3, LineEnd: 3},
+		},
+		{
+			name:   "node renamed with same label",
+			from:   "graph TD\nA[Start] --> B",
+			to:     "graph TD\nbegin[Start] --> B",
+			thread: schema.CommentThread{AnchorType: schema.CommentAnchorNode, NodeID: "A", LineStart: 2, LineEnd: 2},
+			ok:     true,
+			want:   schema.CommentThread{AnchorType: schema.CommentAnchorNode, NodeID: "begin", LineStart: 2, LineEnd: 2},
+		},
+		{
+			name:   "node renamed with ambiguous label",
+			from:   "graph TD\nA[Start] --> B",
+			to:     "graph TD\nX[Start] --> Y[Start]",
+			thread: schema.CommentThread{AnchorType: schema.CommentAnchorNode, NodeID: "A", LineStart: 2, LineEnd: 2},
+			ok:     false,
+			want:   schema.CommentThread{AnchorType: schema.CommentAnchorNode, NodeID: "A", LineStart: 2, LineEnd: 2},
+		},
+		{
+			name:   "node removed",
+			from:   "graph TD\nA --> B",
+			to:     "graph TD\nB --> C",
+			thread: schema.CommentThread{AnchorType: schema.CommentAnchorNode, NodeID: "A", LineStart: 2, LineEnd: 2},
+			ok:     false,
+			want:   schema.CommentThread{AnchorType: schema.CommentAnchorNode, NodeID: "A", LineStart: 2, LineEnd: 2},
+		},
+		{
+			name:   "edge endpoint renamed",
+			from:   "graph TD\nA[Start] --> B[End]",
+			to:     "graph TD\nC --> D\nA[Start] --> stop[End]",
+			thread: schema.CommentThread{AnchorType: schema.CommentAnchorEdge, EdgeFrom: "A", EdgeTo: "B", LineStart: 2, LineEnd: 2},
+			ok:     true,
+			want:   schema.CommentThread{AnchorType: schema.CommentAnchorEdge, EdgeFrom: "A", EdgeTo: "stop", LineStart: 3, LineEnd: 3},
+		},
+		{
+			name:   "edge removed",
+			from:   "graph TD\nA --> B",
+			to:     "graph TD\nA\nB",
+			thread: schema.CommentThread{AnchorType: schema.CommentAnchorEdge, EdgeFrom: "A", EdgeTo: "B", LineStart: 2, LineEnd: 2},
+			ok:     false,
+			want:   schema.CommentThread{AnchorType: schema.CommentAnchorEdge, EdgeFrom: "A", EdgeTo: "B", LineStart: 2, LineEnd: 2},
+		},
+		{
+			name:   "lines shifted by insert above",
+			from:   "# Title\nfirst\nsecond\nthird",
+			to:     "# Title\nintro\n\nfirst\nsecond\nthird",
+			thread: schema.CommentThread{AnchorType: schema.CommentAnchorLines, LineStart: 2, LineEnd: 3},
+			ok:     true,
+			want:   schema.CommentThread{AnchorType: schema.CommentAnchorLines, LineStart: 4, LineEnd: 5},
+		},
+		{
+			name:   "lines shifted by delete above",
+			from:   "# Title\nintro\n\nfirst\nsecond",
+			to:     "# Title\nfirst\nsecond",
+			thread: schema.CommentThread{AnchorType: schema.CommentAnchorLines, LineStart: 4, LineEnd: 5},
+			ok:     true,
+			want:   schema.CommentThread{AnchorType: schema.CommentAnchorLines, LineStart: 2, LineEnd: 3},
+		},
+		{
+			name:   "range shrinks to remaining lines",
+			from:   "a\nb\nc\nd",
+			to:     "a\nc\nd",
+			thread: schema.CommentThread{AnchorType: schema.CommentAnchorLines, LineStart: 2, LineEnd: 3},
+			ok:     true,
+			want:   schema.CommentThread{AnchorType: schema.CommentAnchorLines, LineStart: 2, LineEnd: 2},
+		},
+		{
+			name:   "range deleted",
+			from:   "a\nb\nc\nd",
+			to:     "a\nd",
+			thread: schema.CommentThread{AnchorType: schema.CommentAnchorLines, LineStart: 2, LineEnd: 3},
+			ok:     false,
+			want:   schema.CommentThread{AnchorType: schema.CommentAnchorLines, LineStart: 2, LineEnd: 3},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			thread := tc.thread
+			ok := reanchor(&thread, &transition{from: newRevision(tc.from), to: newRevision(tc.to)})
+			if ok != tc.ok {
+				t.Fatalf("reanchor = %v, want %v", ok, tc.ok)
+			}
+			if got, want := anchorOf(&thread), anchorOf(&tc.want); got != want {
+				t.Fatalf("anchor = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
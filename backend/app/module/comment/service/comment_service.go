@@ -0,0 +1,649 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/comment/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/comment/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/comment/response"
+	document_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/repository"
+	user_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/user/repository"
+	workspace_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// CommentService adalah interface untuk business logic komentar document
+type CommentService interface {
+	ListThreads(documentID uint64, userID uint64, status schema.CommentStatus) (*response.ThreadListResponse, error)
+	GetThread(documentID uint64, threadID uint64, userID uint64) (*response.ThreadResponse, error)
+	CreateThread(documentID uint64, userID uint64, req *request.CreateThreadRequest) (*response.ThreadResponse, error)
+	DeleteThread(documentID uint64, threadID uint64, userID uint64) error
+	ResolveThread(documentID uint64, threadID uint64, userID uint64) (*response.ThreadResponse, error)
+	ReopenThread(documentID uint64, threadID uint64, userID uint64) (*response.ThreadResponse, error)
+
+	Reply(documentID uint64, threadID uint64, userID uint64, req *request.CommentRequest) (*response.CommentResponse, error)
+	UpdateComment(documentID uint64, threadID uint64, commentID uint64, userID uint64, req *request.CommentRequest) (*response.CommentResponse, error)
+	DeleteComment(documentID uint64, threadID uint64, commentID uint64, userID uint64) error
+
+	// ListMentions komentar yang me-mention user login
+	ListMentions(userID uint64, page, limit int) (*response.MentionListResponse, error)
+}
+
+type commentService struct {
+	commentRepo   repository.CommentRepository
+	documentRepo  document_repo.DocumentRepository
+	versionRepo   document_repo.DocumentVersionRepository
+	workspaceRepo workspace_repo.WorkspaceRepository
+	userRepo      user_repo.UserRepository
+	policy        policy.Policy
+}
+
+// NewCommentService instance
+func NewCommentService(
+	commentRepo repository.CommentRepository,
+	documentRepo document_repo.DocumentRepository,
+	versionRepo document_repo.DocumentVersionRepository,
+	workspaceRepo workspace_repo.WorkspaceRepository,
+	userRepo user_repo.UserRepository,
+	policy policy.Policy,
+) CommentService {
+	return &commentService{
+		commentRepo:   commentRepo,
+		documentRepo:  documentRepo,
+		versionRepo:   versionRepo,
+		workspaceRepo: workspaceRepo,
+		userRepo:      userRepo,
+		policy:        policy,
+	}
+}
+
+func (_i *commentService) ListThreads(documentID uint64, userID uint64, status schema.CommentStatus) (*response.ThreadListResponse, error) {
+	document, _, err := _i.authorize(documentID, userID, policy.CommentView)
+	if err != nil {
+		return nil, err
+	}
+
+	threads, err := _i.commentRepo.FindThreadsByDocumentID(document.ID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	anchors := newAnchorer(_i, document.ID)
+	latest, err := anchors.latest()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]response.ThreadResponse, 0, len(threads))
+	for i := range threads {
+		anchors.resolve(&threads[i])
+		responses = append(responses, *toThreadResponse(&threads[i]))
+	}
+
+	return &response.ThreadListResponse{
+		Data:          responses,
+		Total:         len(responses),
+		LatestVersion: latest,
+	}, nil
+}
+
+func (_i *commentService) GetThread(documentID uint64, threadID uint64, userID uint64) (*response.ThreadResponse, error) {
+	document, _, err := _i.authorize(documentID, userID, policy.CommentView)
+	if err != nil {
+		return nil, err
+	}
+
+	thread, err := _i.findThread(document.ID, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	newAnchorer(_i, document.ID).resolve(thread)
+
+	return toThreadResponse(thread), nil
+}
+
+// CreateThread buka thread baru di versi yang sedang dilihat reviewer.
+// Anchor harus ada di versi tersebut, lalu langsung digeser ke versi terbaru.
+func (_i *commentService) CreateThread(documentID uint64, userID uint64, req *request.CreateThreadRequest) (*response.ThreadResponse, error) {
+	document, workspace, err := _i.authorize(documentID, userID, policy.CommentCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	thread, err := newThread(document, &req.Anchor)
+	if err != nil {
+		return nil, err
+	}
+
+	var version *schema.DocumentVersion
+	if req.VersionNumber != nil {
+		version, err = _i.versionRepo.FindByNumber(document.ID, *req.VersionNumber)
+	} else {
+		version, err = _i.versionRepo.FindLatest(document.ID)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if req.VersionNumber != nil {
+				return nil, ErrVersionNotFound
+			}
+			return nil, ErrAnchorNotFound
+		}
+		return nil, err
+	}
+
+	if !locate(thread, newRevision(version.Content)) {
+		return nil, ErrAnchorNotFound
+	}
+
+	mentions, err := _i.mentions(req.Body, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	thread.AuthorID = userID
+	thread.VersionNumber = version.VersionNumber
+	thread.AnchorVersion = version.VersionNumber
+	thread.Status = schema.CommentStatusOpen
+	thread.Comments = []schema.Comment{{
+		AuthorID: userID,
+		Body:     req.Body,
+		Mentions: mentions,
+	}}
+
+	created, err := _i.commentRepo.CreateThread(thread)
+	if err != nil {
+		return nil, err
+	}
+
+	newAnchorer(_i, document.ID).resolve(created)
+
+	return toThreadResponse(created), nil
+}
+
+// DeleteThread hanya pembuat thread atau yang boleh moderasi komentar
+func (_i *commentService) DeleteThread(documentID uint64, threadID uint64, userID uint64) error {
+	document, workspace, err := _i.authorize(documentID, userID, policy.CommentView)
+	if err != nil {
+		return err
+	}
+
+	thread, err := _i.findThread(document.ID, threadID)
+	if err != nil {
+		return err
+	}
+
+	if thread.AuthorID != userID {
+		if err := _i.can(userID, policy.CommentModerate, workspace, document); err != nil {
+			return err
+		}
+	}
+
+	return _i.commentRepo.DeleteThread(thread.ID)
+}
+
+func (_i *commentService) ResolveThread(documentID uint64, threadID uint64, userID uint64) (*response.ThreadResponse, error) {
+	return _i.setStatus(documentID, threadID, userID, schema.CommentStatusResolved)
+}
+
+func (_i *commentService) ReopenThread(documentID uint64, threadID uint64, userID uint64) (*response.ThreadResponse, error) {
+	return _i.setStatus(documentID, threadID, userID, schema.CommentStatusOpen)
+}
+
+// setStatus resolve/reopen thread. Pembuat thread selalu boleh, user lain
+// butuh akses edit document.
+func (_i *commentService) setStatus(documentID uint64, threadID uint64, userID uint64, status schema.CommentStatus) (*response.ThreadResponse, error) {
+	document, workspace, err := _i.authorize(documentID, userID, policy.CommentCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	thread, err := _i.findThread(document.ID, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if thread.AuthorID != userID {
+		if err := _i.can(userID, policy.CommentResolve, workspace, document); err != nil {
+			return nil, err
+		}
+	}
+
+	if thread.Status == status {
+		if status == schema.CommentStatusOpen {
+			return nil, ErrThreadAlreadyOpen
+		}
+		return nil, ErrThreadAlreadyResolved
+	}
+
+	thread.Status = status
+	thread.ResolvedBy, thread.ResolvedAt = nil, nil
+	if status == schema.CommentStatusResolved {
+		now := time.Now()
+		thread.ResolvedBy, thread.ResolvedAt = &userID, &now
+	}
+
+	if err := _i.commentRepo.UpdateStatus(thread); err != nil {
+		return nil, err
+	}
+	thread.UpdatedAt = time.Now()
+
+	newAnchorer(_i, document.ID).resolve(thread)
+
+	return toThreadResponse(thread), nil
+}
+
+func (_i *commentService) Reply(documentID uint64, threadID uint64, userID uint64, req *request.CommentRequest) (*response.CommentResponse, error) {
+	document, workspace, err := _i.authorize(documentID, userID, policy.CommentCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	thread, err := _i.findThread(document.ID, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := _i.mentions(req.Body, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := _i.commentRepo.CreateComment(&schema.Comment{
+		ThreadID: thread.ID,
+		AuthorID: userID,
+		Body:     req.Body,
+		Mentions: mentions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toCommentResponse(comment), nil
+}
+
+// UpdateComment hanya author, mention dihitung ulang dari body baru
+func (_i *commentService) UpdateComment(documentID uint64, threadID uint64, commentID uint64, userID uint64, req *request.CommentRequest) (*response.CommentResponse, error) {
+	document, workspace, err := _i.authorize(documentID, userID, policy.CommentCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := _i.findComment(document.ID, threadID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.AuthorID != userID {
+		return nil, ErrNotCommentAuthor
+	}
+
+	mentions, err := _i.mentions(req.Body, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	comment.Body = req.Body
+	comment.Mentions = mentions
+	if err := _i.commentRepo.UpdateComment(comment); err != nil {
+		return nil, err
+	}
+
+	updated, err := _i.commentRepo.FindComment(comment.ThreadID, comment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toCommentResponse(updated), nil
+}
+
+// DeleteComment hanya author atau yang boleh moderasi komentar
+func (_i *commentService) DeleteComment(documentID uint64, threadID uint64, commentID uint64, userID uint64) error {
+	document, workspace, err := _i.authorize(documentID, userID, policy.CommentView)
+	if err != nil {
+		return err
+	}
+
+	comment, err := _i.findComment(document.ID, threadID, commentID)
+	if err != nil {
+		return err
+	}
+
+	if comment.AuthorID != userID {
+		if err := _i.can(userID, policy.CommentModerate, workspace, document); err != nil {
+			return err
+		}
+	}
+
+	_, err = _i.commentRepo.DeleteComment(comment)
+	return err
+}
+
+func (_i *commentService) ListMentions(userID uint64, page, limit int) (*response.MentionListResponse, error) {
+	// Validasi pagination
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+
+	mentions, err := _i.commentRepo.FindMentionsByUserID(userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := _i.commentRepo.CountMentionsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]response.MentionResponse, 0, len(mentions))
+	for _, mention := range mentions {
+		responses = append(responses, toMentionResponse(&mention))
+	}
+
+	return &response.MentionListResponse{
+		Data:  responses,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}, nil
+}
+
+// authorize ambil document beserta workspace lalu cek aksi
+func (_i *commentService) authorize(documentID uint64, userID uint64, action policy.Action) (*schema.Document, *schema.Workspace, error) {
+	document, err := _i.documentRepo.FindByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, policy.ErrDocumentNotFound
+		}
+		return nil, nil, err
+	}
+
+	workspace, err := _i.workspaceRepo.FindByID(document.WorkspaceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, policy.ErrWorkspaceNotFound
+		}
+		return nil, nil, err
+	}
+
+	if err := _i.can(userID, action, workspace, document); err != nil {
+		return nil, nil, err
+	}
+
+	return document, workspace, nil
+}
+
+func (_i *commentService) can(userID uint64, action policy.Action, workspace *schema.Workspace, document *schema.Document) error {
+	allowed, err := _i.policy.Can(policy.User(userID), action, policy.DocumentResource(workspace, document))
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return policy.Denied(action)
+	}
+
+	return nil
+}
+
+func (_i *commentService) findThread(documentID uint64, threadID uint64) (*schema.CommentThread, error) {
+	thread, err := _i.commentRepo.FindThread(documentID, threadID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrThreadNotFound
+		}
+		return nil, err
+	}
+
+	return thread, nil
+}
+
+// findComment komentar harus berada di thread milik document
+func (_i *commentService) findComment(documentID uint64, threadID uint64, commentID uint64) (*schema.Comment, error) {
+	thread, err := _i.findThread(documentID, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := _i.commentRepo.FindComment(thread.ID, commentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// newThread thread dengan anchor dari request, sesuai type document
+func newThread(document *schema.Document, req *request.AnchorRequest) (*schema.CommentThread, error) {
+	thread := &schema.CommentThread{
+		DocumentID: document.ID,
+		AnchorType: schema.CommentAnchorType(req.Type),
+	}
+
+	mermaidAnchor := thread.AnchorType == schema.CommentAnchorNode || thread.AnchorType == schema.CommentAnchorEdge
+	if mermaidAnchor != (document.Type == schema.DocumentTypeMermaid) {
+		return nil, ErrAnchorTypeMismatch
+	}
+
+	switch thread.AnchorType {
+	case schema.CommentAnchorNode:
+		if req.NodeID == "" {
+			return nil, ErrInvalidAnchor
+		}
+		thread.NodeID = req.NodeID
+
+	case schema.CommentAnchorEdge:
+		if req.EdgeFrom == "" || req.EdgeTo == "" {
+			return nil, ErrInvalidAnchor
+		}
+		thread.EdgeFrom, thread.EdgeTo = req.EdgeFrom, req.EdgeTo
+
+	case schema.CommentAnchorLines:
+		if req.LineStart == 0 || req.LineEnd < req.LineStart {
+			return nil, ErrInvalidAnchor
+		}
+		thread.LineStart, thread.LineEnd = req.LineStart, req.LineEnd
+	}
+
+	return thread, nil
+}
+
+// anchorer hitung anchor thread di versi terbaru document. Content versi dan
+// transition per AnchorVersion di-cache selama satu request, sehingga thread
+// dari versi yang sama hanya sekali parse dan diff.
+type anchorer struct {
+	service     *commentService
+	documentID  uint64
+	revisions   map[int]*revision
+	transitions map[int]*transition
+	version     *int
+}
+
+func newAnchorer(service *commentService, documentID uint64) *anchorer {
+	return &anchorer{
+		service:     service,
+		documentID:  documentID,
+		revisions:   make(map[int]*revision),
+		transitions: make(map[int]*transition),
+	}
+}
+
+// latest nomor versi terakhir document, 0 jika belum ada versi
+func (_i *anchorer) latest() (int, error) {
+	if _i.version != nil {
+		return *_i.version, nil
+	}
+
+	number := 0
+	version, err := _i.service.versionRepo.FindLatest(_i.documentID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+	if version != nil {
+		number = version.VersionNumber
+		_i.revisions[number] = newRevision(version.Content)
+	}
+
+	_i.version = &number
+	return number, nil
+}
+
+func (_i *anchorer) revision(number int) (*revision, error) {
+	if rev, ok := _i.revisions[number]; ok {
+		return rev, nil
+	}
+
+	version, err := _i.service.versionRepo.FindByNumber(_i.documentID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	rev := newRevision(version.Content)
+	_i.revisions[number] = rev
+	return rev, nil
+}
+
+// transition dari versi from ke versi terakhir
+func (_i *anchorer) transition(from, latest int) (*transition, error) {
+	if change, ok := _i.transitions[from]; ok {
+		return change, nil
+	}
+
+	source, err := _i.revision(from)
+	if err != nil {
+		return nil, err
+	}
+	target, err := _i.revision(latest)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &transition{from: source, to: target}
+	_i.transitions[from] = change
+	return change, nil
+}
+
+// resolve geser anchor thread dari AnchorVersion ke versi terbaru, hanya di
+// response dan tidak disimpan sehingga GET tidak menulis ke database. Anchor
+// tersimpan tetap di versi saat thread dibuat, sehingga thread yang target-nya
+// hilang kembali ter-anchor jika target muncul lagi di versi berikutnya.
+// Kegagalan hanya di-log, thread dikirim dengan anchor tersimpan.
+func (_i *anchorer) resolve(thread *schema.CommentThread) {
+	latest, err := _i.latest()
+	if err != nil || thread.AnchorVersion >= latest {
+		return
+	}
+
+	change, err := _i.transition(thread.AnchorVersion, latest)
+	if err != nil {
+		log.Warn().Err(err).Uint64("thread_id", thread.ID).Msg("comment re-anchor failed")
+		return
+	}
+
+	if reanchor(thread, change) {
+		thread.AnchorVersion = latest
+	} else {
+		thread.Outdated = true
+	}
+}
+
+// Helper: convert schema to response
+func toThreadResponse(thread *schema.CommentThread) *response.ThreadResponse {
+	comments := make([]response.CommentResponse, 0, len(thread.Comments))
+	for i := range thread.Comments {
+		comments = append(comments, *toCommentResponse(&thread.Comments[i]))
+	}
+
+	return &response.ThreadResponse{
+		ID:            thread.ID,
+		DocumentID:    thread.DocumentID,
+		VersionNumber: thread.VersionNumber,
+		Anchor: response.Anchor{
+			Type:          thread.AnchorType,
+			NodeID:        thread.NodeID,
+			EdgeFrom:      thread.EdgeFrom,
+			EdgeTo:        thread.EdgeTo,
+			LineStart:     thread.LineStart,
+			LineEnd:       thread.LineEnd,
+			VersionNumber: thread.AnchorVersion,
+			Outdated:      thread.Outdated,
+		},
+		Status:     thread.Status,
+		AuthorID:   thread.AuthorID,
+		ResolvedBy: thread.ResolvedBy,
+		ResolvedAt: thread.ResolvedAt,
+		Comments:   comments,
+		CreatedAt:  thread.CreatedAt,
+		UpdatedAt:  thread.UpdatedAt,
+	}
+}
+
+func toCommentResponse(comment *schema.Comment) *response.CommentResponse {
+	res := &response.CommentResponse{
+		ID:        comment.ID,
+		ThreadID:  comment.ThreadID,
+		AuthorID:  comment.AuthorID,
+		Body:      comment.Body,
+		Mentions:  make([]response.Mention, 0, len(comment.Mentions)),
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+
+	if comment.Author != nil {
+		res.AuthorName = comment.Author.Name
+	}
+
+	for _, mention := range comment.Mentions {
+		if mention.User == nil {
+			continue
+		}
+		res.Mentions = append(res.Mentions, response.Mention{
+			UserID: mention.UserID,
+			Name:   mention.User.Name,
+			Email:  mention.User.Email,
+		})
+	}
+
+	return res
+}
+
+func toMentionResponse(mention *schema.CommentMention) response.MentionResponse {
+	res := response.MentionResponse{
+		ID:        mention.ID,
+		CommentID: mention.CommentID,
+		CreatedAt: mention.CreatedAt,
+	}
+
+	comment := mention.Comment
+	if comment == nil {
+		return res
+	}
+
+	res.ThreadID = comment.ThreadID
+	res.AuthorID = comment.AuthorID
+	res.Body = comment.Body
+	if comment.Author != nil {
+		res.AuthorName = comment.Author.Name
+	}
+	if thread := comment.Thread; thread != nil {
+		res.DocumentID = thread.DocumentID
+		res.ThreadStatus = thread.Status
+		if thread.Document != nil {
+			res.WorkspaceID = thread.Document.WorkspaceID
+			res.DocumentTitle = thread.Document.Title
+		}
+	}
+
+	return res
+}
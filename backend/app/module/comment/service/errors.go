@@ -0,0 +1,18 @@
+package service
+
+import "git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+
+// Error comment service
+var (
+	ErrThreadNotFound  = apperr.NotFound("thread_not_found", "comment thread not found")
+	ErrCommentNotFound = apperr.NotFound("comment_not_found", "comment not found")
+	ErrVersionNotFound = apperr.NotFound("version_not_found", "version not found")
+
+	ErrAnchorTypeMismatch = apperr.Validation("anchor_type_mismatch", "node and edge anchors require a mermaid document, line anchors require a markdown document")
+	ErrInvalidAnchor      = apperr.Validation("invalid_anchor", "anchor requires node_id, edge_from and edge_to, or line_start and line_end")
+	ErrAnchorNotFound     = apperr.Validation("anchor_not_found", "anchor target does not exist in the document version")
+
+	ErrNotCommentAuthor      = apperr.Forbidden("not_comment_author", "only the author can edit this comment")
+	ErrThreadAlreadyOpen     = apperr.Conflict("thread_already_open", "comment thread is already open")
+	ErrThreadAlreadyResolved = apperr.Conflict("thread_already_resolved", "comment thread is already resolved")
+)
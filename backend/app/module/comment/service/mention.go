@@ -0,0 +1,45 @@
+package service
+
+import (
+	"regexp"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+)
+
+// maxMentions batas user yang di-mention dalam satu komentar
+const maxMentions = 20
+
+// mentionPattern @email, tidak cocok di tengah kata atau alamat email lain
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w.@])@([\w.%+-]+@[\w-]+(?:\.[\w-]+)+)`)
+
+// mentions cari @email di body dan ambil user yang merupakan owner atau
+// member workspace. Mention ke user lain diabaikan tanpa error.
+func (_i *commentService) mentions(body string, workspace *schema.Workspace) ([]schema.CommentMention, error) {
+	seen := make(map[uint64]bool)
+	var mentions []schema.CommentMention
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		if len(mentions) >= maxMentions {
+			break
+		}
+
+		user := _i.userRepo.CheckUserByEmail(match[1])
+		if user == nil || user.IsServiceAccount() || seen[user.ID] {
+			continue
+		}
+
+		role, err := _i.policy.Role(policy.User(user.ID), workspace)
+		if err != nil {
+			return nil, err
+		}
+		if role == "" {
+			continue
+		}
+
+		seen[user.ID] = true
+		mentions = append(mentions, schema.CommentMention{UserID: user.ID})
+	}
+
+	return mentions, nil
+}
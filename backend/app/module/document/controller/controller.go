@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/service"
+	"go.uber.org/fx"
+)
+
+// Controller aggregator
+type Controller struct {
+	Document DocumentControllerI
+	Version  VersionControllerI
+}
+
+// NewController
+func NewController(documentController DocumentControllerI, versionController VersionControllerI) *Controller {
+	return &Controller{
+		Document: documentController,
+		Version:  versionController,
+	}
+}
+
+var Module = fx.Options(
+	fx.Provide(func(documentService service.DocumentService) DocumentControllerI {
+		return NewDocumentController(documentService)
+	}),
+	fx.Provide(func(versionService service.VersionService) VersionControllerI {
+		return NewVersionController(versionService)
+	}),
+	fx.Provide(NewController),
+)
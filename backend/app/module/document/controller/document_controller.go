@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"strconv"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// DocumentController
+type documentController struct {
+	documentService service.DocumentService
+}
+
+type DocumentControllerI interface {
+	CreateDocument(c *fiber.Ctx) error
+	GetDocument(c *fiber.Ctx) error
+	ListDocuments(c *fiber.Ctx) error
+	UpdateDocument(c *fiber.Ctx) error
+	DeleteDocument(c *fiber.Ctx) error
+}
+
+func NewDocumentController(documentService service.DocumentService) DocumentControllerI {
+	return &documentController{
+		documentService: documentService,
+	}
+}
+
+// CreateDocument handler untuk membuat document baru di workspace
+func (_i *documentController) CreateDocument(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	workspaceID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_workspace_id", "invalid workspace id")
+	}
+
+	var req request.CreateDocumentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.ErrInvalidBody
+	}
+
+	// Validasi input
+	if err := response.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	result, err := _i.documentService.CreateDocument(workspaceID, userID, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusCreated,
+		Messages: response.Messages{"document created successfully"},
+		Data:     result,
+	})
+}
+
+// GetDocument handler untuk get single document
+func (_i *documentController) GetDocument(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	workspaceID, documentID, err := parseIDs(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := _i.documentService.GetDocument(workspaceID, documentID, userID)
+	if err != nil {
+		return err
+	}
+
+	// ETag dipakai client sebagai If-Match saat update
+	response.SetETag(c, strconv.Itoa(result.VersionNumber))
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"document retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// ListDocuments handler untuk list documents di workspace
+func (_i *documentController) ListDocuments(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	workspaceID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_workspace_id", "invalid workspace id")
+	}
+
+	page := 1
+	limit := 10
+
+	if p := c.Query("page"); p != "" {
+		if parsedPage, err := strconv.Atoi(p); err == nil && parsedPage > 0 {
+			page = parsedPage
+		}
+	}
+
+	if l := c.Query("limit"); l != "" {
+		if parsedLimit, err := strconv.Atoi(l); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
+			limit = parsedLimit
+		}
+	}
+
+	result, err := _i.documentService.ListDocuments(workspaceID, userID, page, limit)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"documents retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// UpdateDocument handler untuk update document
+func (_i *documentController) UpdateDocument(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	workspaceID, documentID, err := parseIDs(c)
+	if err != nil {
+		return err
+	}
+
+	var req request.UpdateDocumentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.ErrInvalidBody
+	}
+
+	// Validasi input (omitempty fields tidak di-validasi jika kosong)
+	if err := response.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	// Base version boleh dikirim lewat header If-Match
+	if req.BaseVersion, err = response.BaseVersion(c, req.BaseVersion); err != nil {
+		return err
+	}
+
+	result, err := _i.documentService.UpdateDocument(workspaceID, documentID, userID, &req)
+	if err != nil {
+		return err
+	}
+
+	response.SetETag(c, strconv.Itoa(result.VersionNumber))
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"document updated successfully"},
+		Data:     result,
+	})
+}
+
+// DeleteDocument handler untuk soft delete document
+func (_i *documentController) DeleteDocument(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	workspaceID, documentID, err := parseIDs(c)
+	if err != nil {
+		return err
+	}
+
+	err = _i.documentService.DeleteDocument(workspaceID, documentID, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"document deleted successfully"},
+	})
+}
+
+// parseIDs membaca workspace id dan document id dari route params
+func parseIDs(c *fiber.Ctx) (workspaceID uint64, documentID uint64, err error) {
+	workspaceID, err = strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, apperr.BadRequest("invalid_workspace_id", "invalid workspace id")
+	}
+
+	documentID, err = strconv.ParseUint(c.Params("documentId"), 10, 64)
+	if err != nil {
+		return 0, 0, apperr.BadRequest("invalid_document_id", "invalid document id")
+	}
+
+	return workspaceID, documentID, nil
+}
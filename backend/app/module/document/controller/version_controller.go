@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"strconv"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// VersionController
+type versionController struct {
+	versionService service.VersionService
+}
+
+type VersionControllerI interface {
+	ListVersions(c *fiber.Ctx) error
+	GetVersion(c *fiber.Ctx) error
+	GetCurrentVersion(c *fiber.Ctx) error
+	RestoreVersion(c *fiber.Ctx) error
+	DiffVersions(c *fiber.Ctx) error
+}
+
+func NewVersionController(versionService service.VersionService) VersionControllerI {
+	return &versionController{
+		versionService: versionService,
+	}
+}
+
+// ListVersions handler untuk list riwayat versi document
+func (_i *versionController) ListVersions(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_document_id", "invalid document id")
+	}
+
+	page := 1
+	limit := 10
+
+	if p := c.Query("page"); p != "" {
+		if parsedPage, err := strconv.Atoi(p); err == nil && parsedPage > 0 {
+			page = parsedPage
+		}
+	}
+
+	if l := c.Query("limit"); l != "" {
+		if parsedLimit, err := strconv.Atoi(l); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
+			limit = parsedLimit
+		}
+	}
+
+	result, err := _i.versionService.ListVersions(documentID, userID, page, limit)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"versions retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// GetVersion handler untuk get satu versi berdasarkan nomor versi
+func (_i *versionController) GetVersion(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, number, err := parseVersionParams(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := _i.versionService.GetVersion(documentID, userID, number)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"version retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// GetCurrentVersion handler untuk get versi terbaru (head) document
+func (_i *versionController) GetCurrentVersion(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_document_id", "invalid document id")
+	}
+
+	result, err := _i.versionService.GetCurrentVersion(documentID, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"current version retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// RestoreVersion handler untuk mengembalikan document ke versi sebelumnya
+func (_i *versionController) RestoreVersion(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, number, err := parseVersionParams(c)
+	if err != nil {
+		return err
+	}
+
+	var req request.RestoreVersionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return response.ErrInvalidBody
+		}
+		if err := response.ValidateStruct(req); err != nil {
+			return err
+		}
+	}
+
+	// Base version boleh dikirim lewat header If-Match
+	if req.BaseVersion, err = response.BaseVersion(c, req.BaseVersion); err != nil {
+		return err
+	}
+
+	result, err := _i.versionService.RestoreVersion(documentID, userID, number, req.BaseVersion)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusCreated,
+		Messages: response.Messages{"version restored successfully"},
+		Data:     result,
+	})
+}
+
+// DiffVersions handler untuk membandingkan dua versi document (?from=n&to=m)
+func (_i *versionController) DiffVersions(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_document_id", "invalid document id")
+	}
+
+	from, to := c.QueryInt("from"), c.QueryInt("to")
+	if from < 0 || to < 0 {
+		return apperr.BadRequest("invalid_version_number", "invalid version number")
+	}
+
+	result, err := _i.versionService.DiffVersions(documentID, userID, from, to)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"diff retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// parseVersionParams membaca document id dan nomor versi dari route params
+func parseVersionParams(c *fiber.Ctx) (documentID uint64, number int, err error) {
+	documentID, err = strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, apperr.BadRequest("invalid_document_id", "invalid document id")
+	}
+
+	number, err = strconv.Atoi(c.Params("number"))
+	if err != nil || number < 1 {
+		return 0, 0, apperr.BadRequest("invalid_version_number", "invalid version number")
+	}
+
+	return documentID, number, nil
+}
@@ -0,0 +1,77 @@
+package document
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/controller"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+)
+
+// DocumentRouter adalah router untuk document module
+type DocumentRouter struct {
+	App        fiber.Router
+	Controller *controller.Controller
+	AuthMW     *middleware.AuthMiddleware
+	PolicyMW   *middleware.PolicyMiddleware
+}
+
+// Module adalah FX module untuk document
+var NewDocumentModule = fx.Options(
+	// register repository
+	fx.Provide(repository.NewDocumentRepository),
+	fx.Provide(repository.NewDocumentVersionRepository),
+
+	// register service
+	fx.Provide(service.NewDocumentService),
+	fx.Provide(service.NewVersionService),
+
+	// register controller
+	controller.Module,
+
+	// register router
+	fx.Provide(NewDocumentRouter),
+)
+
+// NewDocumentRouter membuat instance baru dari DocumentRouter
+func NewDocumentRouter(
+	app *fiber.App,
+	ctrl *controller.Controller,
+	authMW *middleware.AuthMiddleware,
+	policyMW *middleware.PolicyMiddleware,
+) *DocumentRouter {
+	return &DocumentRouter{
+		App:        app,
+		Controller: ctrl,
+		AuthMW:     authMW,
+		PolicyMW:   policyMW,
+	}
+}
+
+// RegisterDocumentRoutes mendaftarkan routes untuk document
+func (_i *DocumentRouter) RegisterDocumentRoutes() {
+	// define controllers
+	documentController := _i.Controller.Document
+	versionController := _i.Controller.Version
+
+	_i.App.Route("/api/v1", func(router fiber.Router) {
+		documentRoutes := router.Group("/workspaces/:id/documents", _i.AuthMW.RequireAuth())
+
+		documentRoutes.Post("", _i.PolicyMW.Workspace(policy.DocumentCreate, "id"), documentController.CreateDocument)
+		documentRoutes.Get("", _i.PolicyMW.Workspace(policy.WorkspaceView, "id"), documentController.ListDocuments)
+		documentRoutes.Get("/:documentId", _i.PolicyMW.WorkspaceDocument(policy.DocumentView, "id", "documentId"), documentController.GetDocument)
+		documentRoutes.Put("/:documentId", _i.PolicyMW.WorkspaceDocument(policy.DocumentEdit, "id", "documentId"), documentController.UpdateDocument)
+		documentRoutes.Delete("/:documentId", _i.PolicyMW.WorkspaceDocument(policy.DocumentDelete, "id", "documentId"), documentController.DeleteDocument)
+
+		router.Get("/documents/:id/diff", _i.AuthMW.RequireAuth(), _i.PolicyMW.Document(policy.VersionView, "id"), versionController.DiffVersions)
+
+		versionRoutes := router.Group("/documents/:id/versions", _i.AuthMW.RequireAuth())
+
+		versionRoutes.Get("", _i.PolicyMW.Document(policy.VersionView, "id"), versionController.ListVersions)
+		versionRoutes.Get("/current", _i.PolicyMW.Document(policy.VersionView, "id"), versionController.GetCurrentVersion)
+		versionRoutes.Get("/:number", _i.PolicyMW.Document(policy.VersionView, "id"), versionController.GetVersion)
+		versionRoutes.Post("/:number/restore", _i.PolicyMW.Document(policy.VersionRestore, "id"), versionController.RestoreVersion)
+	})
+}
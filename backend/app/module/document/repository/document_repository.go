@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"errors"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
+	"gorm.io/gorm"
+)
+
+// DocumentRepository
+type DocumentRepository interface {
+	Create(document *schema.Document) (*schema.Document, error)
+	CreateWithVersion(document *schema.Document, version *schema.DocumentVersion) (*schema.Document, error)
+	FindByID(id uint64) (*schema.Document, error)
+	FindBySlug(workspaceID uint64, slug string) (*schema.Document, error)
+	FindByWorkspaceID(workspaceID uint64, limit, offset int) ([]schema.Document, error)
+	// FindAllByWorkspaceID seluruh document workspace urut judul, dipakai export workspace
+	FindAllByWorkspaceID(workspaceID uint64) ([]schema.Document, error)
+	CountByWorkspaceID(workspaceID uint64) (int64, error)
+	Update(document *schema.Document) error
+	UpdateFromVersion(document *schema.Document, columns []string, version *schema.DocumentVersion, baseVersion int) error
+	Delete(id uint64) error
+	CheckSlugExists(workspaceID uint64, slug string, excludeID uint64) bool
+}
+
+// ErrStaleVersion versi terakhir document sudah bukan versi dasar perubahan
+var ErrStaleVersion = errors.New("document has a newer version than the base version")
+
+type documentRepository struct {
+	db *database.Database
+}
+
+func NewDocumentRepository(db *database.Database) DocumentRepository {
+	return &documentRepository{
+		db: db,
+	}
+}
+
+func (_i *documentRepository) Create(document *schema.Document) (*schema.Document, error) {
+	if err := _i.db.DB.Create(document).Error; err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// CreateWithVersion membuat document beserta versi pertamanya dalam satu transaksi
+func (_i *documentRepository) CreateWithVersion(document *schema.Document, version *schema.DocumentVersion) (*schema.Document, error) {
+	err := _i.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(document).Error; err != nil {
+			return err
+		}
+
+		version.DocumentID = document.ID
+		return appendVersion(tx, version)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+func (_i *documentRepository) FindByID(id uint64) (*schema.Document, error) {
+	var document schema.Document
+	if err := _i.db.DB.Where("id = ?", id).First(&document).Error; err != nil {
+		return nil, err
+	}
+
+	return &document, nil
+}
+
+func (_i *documentRepository) FindByWorkspaceID(workspaceID uint64, limit, offset int) ([]schema.Document, error) {
+	var documents []schema.Document
+	if err := _i.db.DB.Where("workspace_id = ?", workspaceID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&documents).Error; err != nil {
+		return nil, err
+	}
+
+	return documents, nil
+}
+
+func (_i *documentRepository) FindAllByWorkspaceID(workspaceID uint64) ([]schema.Document, error) {
+	var documents []schema.Document
+	if err := _i.db.DB.Where("workspace_id = ?", workspaceID).
+		Order("title ASC, id ASC").
+		Find(&documents).Error; err != nil {
+		return nil, err
+	}
+
+	return documents, nil
+}
+
+func (_i *documentRepository) CountByWorkspaceID(workspaceID uint64) (int64, error) {
+	var count int64
+	if err := _i.db.DB.Model(&schema.Document{}).
+		Where("workspace_id = ?", workspaceID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (_i *documentRepository) Update(document *schema.Document) error {
+	// Save dipakai agar field boolean (is_public = false) tetap ikut ter-update
+	return _i.db.DB.Save(document).Error
+}
+
+// UpdateFromVersion update kolom metadata document yang berubah dan (jika version tidak nil)
+// menambahkan versi baru dalam satu transaksi, hanya jika versi terakhir document masih
+// baseVersion (0 untuk document tanpa versi). Dicek di dalam lock row document sehingga dua
+// save dari versi yang sama tidak saling menimpa. Hanya columns yang ditulis agar kolom lain
+// yang diubah request lain setelah document dibaca tidak tertimpa.
+func (_i *documentRepository) UpdateFromVersion(document *schema.Document, columns []string, version *schema.DocumentVersion, baseVersion int) error {
+	return _i.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockDocument(tx, document.ID); err != nil {
+			return err
+		}
+
+		var latest int
+		if err := tx.Model(&schema.DocumentVersion{}).
+			Where("document_id = ?", document.ID).
+			Select("COALESCE(MAX(version_number), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+		if latest != baseVersion {
+			return ErrStaleVersion
+		}
+
+		// Select agar kolom boolean (is_public = false) tetap ikut ter-update
+		if err := tx.Model(document).Select(columns).Updates(document).Error; err != nil {
+			return err
+		}
+
+		if version == nil {
+			return nil
+		}
+
+		version.DocumentID = document.ID
+		return appendVersion(tx, version)
+	})
+}
+
+func (_i *documentRepository) FindBySlug(workspaceID uint64, slug string) (*schema.Document, error) {
+	var document schema.Document
+	if err := _i.db.DB.Where("workspace_id = ? AND slug = ?", workspaceID, slug).First(&document).Error; err != nil {
+		return nil, err
+	}
+
+	return &document, nil
+}
+
+func (_i *documentRepository) Delete(id uint64) error {
+	return _i.db.DB.Model(&schema.Document{}).
+		Where("id = ?", id).
+		Delete(&schema.Document{}).Error
+}
+
+func (_i *documentRepository) CheckSlugExists(workspaceID uint64, slug string, excludeID uint64) bool {
+	var count int64
+
+	// Unscoped karena unique index juga berlaku untuk dokumen yang sudah di-soft delete
+	query := _i.db.DB.Unscoped().Model(&schema.Document{}).
+		Where("workspace_id = ? AND slug = ?", workspaceID, slug)
+
+	// Exclude current document jika sedang update
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+
+	query.Count(&count)
+	return count > 0
+}
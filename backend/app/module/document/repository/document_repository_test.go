@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDatabase sqlite in-memory, satu database per test
+func newTestDatabase(t *testing.T) *database.Database {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Setiap koneksi in-memory punya database sendiri
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&schema.Document{}, &schema.DocumentVersion{}); err != nil {
+		t.Fatal(err)
+	}
+
+	return &database.Database{DB: db}
+}
+
+func newTestDocument(t *testing.T, repo DocumentRepository) *schema.Document {
+	t.Helper()
+
+	document, err := repo.CreateWithVersion(
+		&schema.Document{WorkspaceID: 1, Title: "Old", Slug: "doc", Type: schema.DocumentTypeMermaid, IsPublic: true},
+		&schema.DocumentVersion{Content: "graph TD"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return document
+}
+
+func TestUpdateFromVersionWritesOnlyColumns(t *testing.T) {
+	repo := NewDocumentRepository(newTestDatabase(t))
+	document := newTestDocument(t, repo)
+
+	// Dua request membaca document yang sama sebelum salah satunya menyimpan
+	rename, err := repo.FindByID(document.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contentSave, err := repo.FindByID(document.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rename.Title = "New"
+	rename.IsPublic = false
+	if err := repo.UpdateFromVersion(rename, []string{"updated_at", "title", "is_public"}, nil, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Save content via share link dengan salinan lama tidak boleh mengembalikan judul
+	contentSave.UpdatedAt = time.Now()
+	if err := repo.UpdateFromVersion(contentSave, []string{"updated_at"}, &schema.DocumentVersion{Content: "graph LR"}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := repo.FindByID(document.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Title != "New" || stored.IsPublic {
+		t.Fatalf("document = {title: %q, is_public: %v}, want {title: %q, is_public: false}", stored.Title, stored.IsPublic, "New")
+	}
+}
+
+func TestUpdateFromVersionRejectsStaleBase(t *testing.T) {
+	repo := NewDocumentRepository(newTestDatabase(t))
+	document := newTestDocument(t, repo)
+
+	if err := repo.UpdateFromVersion(document, []string{"updated_at"}, &schema.DocumentVersion{Content: "graph LR"}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	document.Title = "Lost"
+	err := repo.UpdateFromVersion(document, []string{"updated_at", "title"}, &schema.DocumentVersion{Content: "graph RL"}, 1)
+	if !errors.Is(err, ErrStaleVersion) {
+		t.Fatalf("UpdateFromVersion(base 1) error = %v, want ErrStaleVersion", err)
+	}
+
+	stored, err := repo.FindByID(document.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Title != "Old" {
+		t.Fatalf("title = %q, want %q", stored.Title, "Old")
+	}
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DocumentVersionRepository
+type DocumentVersionRepository interface {
+	AppendSnapshot(version *schema.DocumentVersion, baseVersion int) (bool, error)
+	FindByDocumentID(documentID uint64, limit, offset int) ([]schema.DocumentVersion, error)
+	CountByDocumentID(documentID uint64) (int64, error)
+	FindByNumber(documentID uint64, number int) (*schema.DocumentVersion, error)
+	FindLatest(documentID uint64) (*schema.DocumentVersion, error)
+}
+
+type documentVersionRepository struct {
+	db *database.Database
+}
+
+func NewDocumentVersionRepository(db *database.Database) DocumentVersionRepository {
+	return &documentVersionRepository{
+		db: db,
+	}
+}
+
+// AppendSnapshot simpan snapshot live editing yang dimulai dari baseVersion.
+// ErrStaleVersion jika setelah baseVersion ada versi dari luar sesi (save REST,
+// restore, share link), snapshot lain dari sesi yang sama boleh dilewati.
+// Snapshot dilewati jika content sama dengan versi terakhir, pengecekan di
+// dalam lock row document sehingga snapshot yang sama dari beberapa instance
+// hanya tersimpan sekali.
+func (_i *documentVersionRepository) AppendSnapshot(version *schema.DocumentVersion, baseVersion int) (bool, error) {
+	created := false
+
+	err := _i.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockDocument(tx, version.DocumentID); err != nil {
+			return err
+		}
+
+		var external int64
+		if err := tx.Model(&schema.DocumentVersion{}).
+			Where("document_id = ? AND version_number > ? AND live = ?", version.DocumentID, baseVersion, false).
+			Count(&external).Error; err != nil {
+			return err
+		}
+		if external > 0 {
+			return ErrStaleVersion
+		}
+
+		var latest schema.DocumentVersion
+		if err := tx.Where("document_id = ?", version.DocumentID).
+			Order("version_number DESC").
+			Limit(1).
+			Find(&latest).Error; err != nil {
+			return err
+		}
+
+		if latest.ID != 0 && latest.Content == version.Content {
+			return nil
+		}
+
+		version.Live = true
+		if err := appendVersion(tx, version); err != nil {
+			return err
+		}
+
+		created = true
+		return tx.Model(&schema.Document{}).
+			Where("id = ?", version.DocumentID).
+			Update("updated_at", time.Now()).Error
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return created, nil
+}
+
+func (_i *documentVersionRepository) FindByDocumentID(documentID uint64, limit, offset int) ([]schema.DocumentVersion, error) {
+	var versions []schema.DocumentVersion
+	if err := _i.db.DB.Where("document_id = ?", documentID).
+		Order("version_number DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&versions).Error; err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+func (_i *documentVersionRepository) CountByDocumentID(documentID uint64) (int64, error) {
+	var count int64
+	if err := _i.db.DB.Model(&schema.DocumentVersion{}).
+		Where("document_id = ?", documentID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (_i *documentVersionRepository) FindByNumber(documentID uint64, number int) (*schema.DocumentVersion, error) {
+	var version schema.DocumentVersion
+	if err := _i.db.DB.Where("document_id = ? AND version_number = ?", documentID, number).
+		First(&version).Error; err != nil {
+		return nil, err
+	}
+
+	return &version, nil
+}
+
+func (_i *documentVersionRepository) FindLatest(documentID uint64) (*schema.DocumentVersion, error) {
+	var version schema.DocumentVersion
+	if err := _i.db.DB.Where("document_id = ?", documentID).
+		Order("version_number DESC").
+		First(&version).Error; err != nil {
+		return nil, err
+	}
+
+	return &version, nil
+}
+
+// appendVersion harus dipanggil di dalam transaksi. Row document di-lock
+// supaya dua save yang bersamaan tidak mendapatkan VersionNumber yang sama.
+func appendVersion(tx *gorm.DB, version *schema.DocumentVersion) error {
+	if err := lockDocument(tx, version.DocumentID); err != nil {
+		return err
+	}
+
+	var latest int
+	if err := tx.Model(&schema.DocumentVersion{}).
+		Where("document_id = ?", version.DocumentID).
+		Select("COALESCE(MAX(version_number), 0)").
+		Scan(&latest).Error; err != nil {
+		return err
+	}
+
+	version.ID = 0
+	version.VersionNumber = latest + 1
+
+	return tx.Create(version).Error
+}
+
+// lockDocument lock row document sampai transaksi selesai
+func lockDocument(tx *gorm.DB, documentID uint64) error {
+	var document schema.Document
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", documentID).
+		First(&document).Error
+}
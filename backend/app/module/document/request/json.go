@@ -0,0 +1,25 @@
+package request
+
+type CreateDocumentRequest struct {
+	Title             string  `json:"title" validate:"required,min=1,max=255"`
+	Type              string  `json:"type" validate:"omitempty,oneof=mermaid markdown"`
+	IsPublic          bool    `json:"is_public" validate:"omitempty"`
+	Content           string  `json:"content" validate:"omitempty"`
+	ChangeDescription *string `json:"change_description" validate:"omitempty,max=500"`
+}
+
+// UpdateDocumentRequest BaseVersion version_number yang terakhir dilihat client,
+// boleh diganti header If-Match
+type UpdateDocumentRequest struct {
+	BaseVersion       *int    `json:"base_version" validate:"omitempty,min=0"`
+	Title             *string `json:"title" validate:"omitempty,min=1,max=255"`
+	Slug              *string `json:"slug" validate:"omitempty,min=1,max=255"`
+	IsPublic          *bool   `json:"is_public" validate:"omitempty"`
+	Content           *string `json:"content" validate:"omitempty"`
+	ChangeDescription *string `json:"change_description" validate:"omitempty,max=500"`
+}
+
+// RestoreVersionRequest BaseVersion wajib, boleh diganti header If-Match
+type RestoreVersionRequest struct {
+	BaseVersion *int `json:"base_version" validate:"omitempty,min=0"`
+}
@@ -0,0 +1,77 @@
+package response
+
+import (
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/diff"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/mermaid"
+)
+
+type DocumentResponse struct {
+	ID            uint64              `json:"id"`
+	WorkspaceID   uint64              `json:"workspace_id"`
+	Title         string              `json:"title"`
+	Type          schema.DocumentType `json:"type"`
+	Slug          string              `json:"slug"`
+	IsPublic      bool                `json:"is_public"`
+	VersionNumber int                 `json:"version_number,omitempty"`
+	Content       *string             `json:"content,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+
+	// Diagnostics hasil validasi mermaid saat save, hanya diisi di response create/update
+	Diagnostics []mermaid.Diagnostic `json:"diagnostics,omitempty"`
+}
+
+// ConflictResponse dikirim bersama 409 jika base version client sudah tertinggal.
+// Current berisi versi terakhir dalam bentuk response endpoint yang dipanggil
+// (document atau share link), Merge usulan three-way merge dengan versi terakhir.
+type ConflictResponse[T any] struct {
+	BaseVersion int               `json:"base_version"`
+	Current     T                 `json:"current"`
+	Merge       *diff.MergeResult `json:"merge,omitempty"`
+}
+
+type DocumentListResponse struct {
+	Data  []DocumentResponse `json:"data"`
+	Total int64              `json:"total"`
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
+}
+
+type VersionResponse struct {
+	ID                uint64    `json:"id"`
+	DocumentID        uint64    `json:"document_id"`
+	VersionNumber     int       `json:"version_number"`
+	Content           string    `json:"content"`
+	AuthorID          *uint64   `json:"author_id"`
+	ChangeDescription *string   `json:"change_description"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// VersionSummaryResponse dipakai di list supaya content tidak ikut dikirim
+type VersionSummaryResponse struct {
+	ID                uint64    `json:"id"`
+	DocumentID        uint64    `json:"document_id"`
+	VersionNumber     int       `json:"version_number"`
+	AuthorID          *uint64   `json:"author_id"`
+	ChangeDescription *string   `json:"change_description"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type VersionListResponse struct {
+	Data  []VersionSummaryResponse `json:"data"`
+	Total int64                    `json:"total"`
+	Page  int                      `json:"page"`
+	Limit int                      `json:"limit"`
+}
+
+type DiffResponse struct {
+	DocumentID  uint64              `json:"document_id"`
+	Type        schema.DocumentType `json:"type"`
+	FromVersion int                 `json:"from_version"`
+	ToVersion   int                 `json:"to_version"`
+	Unified     string              `json:"unified"`
+	Structural  *mermaid.GraphDiff  `json:"structural,omitempty"`
+}
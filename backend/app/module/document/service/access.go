@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/response"
+	workspace_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/diff"
+	"gorm.io/gorm"
+)
+
+// findWorkspace ambil workspace dan normalisasi error not found
+func findWorkspace(workspaceRepo workspace_repo.WorkspaceRepository, id uint64) (*schema.Workspace, error) {
+	workspace, err := workspaceRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, policy.ErrWorkspaceNotFound
+		}
+		return nil, err
+	}
+
+	return workspace, nil
+}
+
+// findDocument ambil document beserta workspace-nya
+func findDocument(documentRepo repository.DocumentRepository, workspaceRepo workspace_repo.WorkspaceRepository, id uint64) (*schema.Document, *schema.Workspace, error) {
+	document, err := documentRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, policy.ErrDocumentNotFound
+		}
+		return nil, nil, err
+	}
+
+	workspace, err := findWorkspace(workspaceRepo, document.WorkspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return document, workspace, nil
+}
+
+// versionNumber nomor versi terakhir, 0 untuk document tanpa versi
+func versionNumber(latest *schema.DocumentVersion) int {
+	if latest == nil {
+		return 0
+	}
+	return latest.VersionNumber
+}
+
+// versionConflict error 409 untuk endpoint document
+func versionConflict(documentRepo repository.DocumentRepository, versionRepo repository.DocumentVersionRepository, documentID uint64, baseVersion int, content *string) error {
+	document, err := documentRepo.FindByID(documentID)
+	if err != nil {
+		return err
+	}
+
+	return VersionConflict(versionRepo, documentID, baseVersion, content, func(latest *schema.DocumentVersion) *response.DocumentResponse {
+		return toDocumentResponse(document, latest)
+	})
+}
+
+// VersionConflict error 409 berisi versi terakhir dan usulan merge jika client
+// mengirim content. current membentuk versi terakhir sesuai response endpoint,
+// dipakai juga oleh save via share link.
+func VersionConflict[T any](versionRepo repository.DocumentVersionRepository, documentID uint64, baseVersion int, content *string, current func(latest *schema.DocumentVersion) T) error {
+	latest, err := versionRepo.FindLatest(documentID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		latest = nil
+	}
+
+	data := &response.ConflictResponse[T]{
+		BaseVersion: baseVersion,
+		Current:     current(latest),
+	}
+
+	if content != nil {
+		data.Merge, err = mergeWithLatest(versionRepo, documentID, baseVersion, *content, latest)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ErrVersionConflict.WithData(data)
+}
+
+// mergeWithLatest three-way merge content client dengan versi terakhir,
+// nil jika base version tidak ada di riwayat
+func mergeWithLatest(versionRepo repository.DocumentVersionRepository, documentID uint64, baseVersion int, content string, latest *schema.DocumentVersion) (*diff.MergeResult, error) {
+	if baseVersion > versionNumber(latest) {
+		return nil, nil
+	}
+
+	base := ""
+	if baseVersion > 0 {
+		version, err := versionRepo.FindByNumber(documentID, baseVersion)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		base = version.Content
+	}
+
+	current := ""
+	if latest != nil {
+		current = latest.Content
+	}
+
+	result := diff.Merge(base, content, current, "yours", fmt.Sprintf("version %d", versionNumber(latest)))
+	return &result, nil
+}
@@ -0,0 +1,377 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	collab_service "git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/response"
+	lint_service "git.dev.siap.id/kukuhkkh/app-diagram/app/module/lint/service"
+	workspace_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/helpers"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/mermaid"
+	"gorm.io/gorm"
+)
+
+// DocumentService adalah interface untuk business logic document
+type DocumentService interface {
+	CreateDocument(workspaceID uint64, userID uint64, req *request.CreateDocumentRequest) (*response.DocumentResponse, error)
+	GetDocument(workspaceID uint64, id uint64, userID uint64) (*response.DocumentResponse, error)
+	ListDocuments(workspaceID uint64, userID uint64, page, limit int) (*response.DocumentListResponse, error)
+	UpdateDocument(workspaceID uint64, id uint64, userID uint64, req *request.UpdateDocumentRequest) (*response.DocumentResponse, error)
+	DeleteDocument(workspaceID uint64, id uint64, userID uint64) error
+}
+
+type documentService struct {
+	documentRepo  repository.DocumentRepository
+	versionRepo   repository.DocumentVersionRepository
+	workspaceRepo workspace_repo.WorkspaceRepository
+	lintService   lint_service.LintService
+	collabHub     collab_service.Hub
+	policy        policy.Policy
+}
+
+// NewDocumentService instance
+func NewDocumentService(
+	documentRepo repository.DocumentRepository,
+	versionRepo repository.DocumentVersionRepository,
+	workspaceRepo workspace_repo.WorkspaceRepository,
+	lintService lint_service.LintService,
+	collabHub collab_service.Hub,
+	policy policy.Policy,
+) DocumentService {
+	return &documentService{
+		documentRepo:  documentRepo,
+		versionRepo:   versionRepo,
+		workspaceRepo: workspaceRepo,
+		lintService:   lintService,
+		collabHub:     collabHub,
+		policy:        policy,
+	}
+}
+
+func (_i *documentService) CreateDocument(workspaceID uint64, userID uint64, req *request.CreateDocumentRequest) (*response.DocumentResponse, error) {
+	workspace, err := findWorkspace(_i.workspaceRepo, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validasi akses: minimal editor yang bisa membuat document
+	allowed, err := _i.policy.Can(policy.User(userID), policy.DocumentCreate, policy.WorkspaceResource(workspace))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, policy.Denied(policy.DocumentCreate)
+	}
+
+	docType := schema.DocumentTypeMermaid
+	if req.Type != "" {
+		docType = schema.DocumentType(req.Type)
+	}
+
+	// Validasi syntax mermaid sesuai setting workspace
+	diagnostics, err := _i.lintService.ValidateContent(workspace, docType, req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	document := &schema.Document{
+		WorkspaceID: workspace.ID,
+		Title:       req.Title,
+		Type:        docType,
+		Slug:        _i.generateSlug(workspace.ID, req.Title),
+		IsPublic:    req.IsPublic,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	description := req.ChangeDescription
+	if description == nil {
+		initial := "Initial version"
+		description = &initial
+	}
+
+	version := &schema.DocumentVersion{
+		Content:           req.Content,
+		AuthorID:          &userID,
+		ChangeDescription: description,
+	}
+
+	created, err := _i.documentRepo.CreateWithVersion(document, version)
+	if err != nil {
+		return nil, err
+	}
+
+	res := toDocumentResponse(created, version)
+	res.Diagnostics = diagnostics
+
+	return res, nil
+}
+
+func (_i *documentService) GetDocument(workspaceID uint64, id uint64, userID uint64) (*response.DocumentResponse, error) {
+	document, workspace, err := _i.findWorkspaceDocument(workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validasi akses: member, workspace public, document public, atau di-share ke user
+	allowed, err := _i.policy.Can(policy.User(userID), policy.DocumentView, policy.DocumentResource(workspace, document))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, policy.Denied(policy.DocumentView)
+	}
+
+	latest, err := _i.findLatestVersion(document.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDocumentResponse(document, latest), nil
+}
+
+func (_i *documentService) ListDocuments(workspaceID uint64, userID uint64, page, limit int) (*response.DocumentListResponse, error) {
+	workspace, err := findWorkspace(_i.workspaceRepo, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validasi akses: sama seperti GetWorkspace
+	allowed, err := _i.policy.Can(policy.User(userID), policy.WorkspaceView, policy.WorkspaceResource(workspace))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, policy.Denied(policy.WorkspaceView)
+	}
+
+	// Validasi pagination
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+
+	documents, err := _i.documentRepo.FindByWorkspaceID(workspace.ID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := _i.documentRepo.CountByWorkspaceID(workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]response.DocumentResponse, 0, len(documents))
+	for _, doc := range documents {
+		responses = append(responses, *toDocumentResponse(&doc, nil))
+	}
+
+	return &response.DocumentListResponse{
+		Data:  responses,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}, nil
+}
+
+func (_i *documentService) UpdateDocument(workspaceID uint64, id uint64, userID uint64, req *request.UpdateDocumentRequest) (*response.DocumentResponse, error) {
+	document, workspace, err := _i.findWorkspaceDocument(workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := policy.DocumentResource(workspace, document)
+
+	// Validasi akses: minimal editor atau user dengan share permission edit
+	allowed, err := _i.policy.Can(policy.User(userID), policy.DocumentEdit, resource)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, policy.Denied(policy.DocumentEdit)
+	}
+
+	// Visibility document hanya bisa diubah oleh yang boleh share document
+	if req.IsPublic != nil {
+		allowed, err := _i.policy.Can(policy.User(userID), policy.DocumentShare, resource)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, apperr.Forbidden("permission_denied", "you don't have permission to change visibility of this document")
+		}
+	}
+
+	if req.BaseVersion == nil {
+		return nil, ErrBaseVersionRequired
+	}
+
+	latest, err := _i.findLatestVersion(document.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Perubahan harus dibuat dari versi terakhir agar save orang lain tidak tertimpa
+	if *req.BaseVersion != versionNumber(latest) {
+		return nil, versionConflict(_i.documentRepo, _i.versionRepo, document.ID, *req.BaseVersion, req.Content)
+	}
+
+	// Update fields jika ada, hanya kolom yang dikirim yang ditulis ke database
+	columns := []string{"updated_at"}
+	if req.Title != nil && *req.Title != "" {
+		document.Title = *req.Title
+		columns = append(columns, "title")
+	}
+
+	if req.Slug != nil {
+		slug := helpers.Slug(*req.Slug)
+		if slug == "" {
+			return nil, ErrInvalidSlug
+		}
+
+		// Check if slug sudah dipakai document lain di workspace yang sama
+		if _i.documentRepo.CheckSlugExists(workspace.ID, slug, document.ID) {
+			return nil, apperr.Conflict("document_slug_taken", fmt.Sprintf("document with slug '%s' already exists", slug))
+		}
+		document.Slug = slug
+		columns = append(columns, "slug")
+	}
+
+	if req.IsPublic != nil {
+		document.IsPublic = *req.IsPublic
+		columns = append(columns, "is_public")
+	}
+
+	// Versi baru hanya dibuat jika content benar-benar berubah
+	var version *schema.DocumentVersion
+	var diagnostics []mermaid.Diagnostic
+	if req.Content != nil && (latest == nil || latest.Content != *req.Content) {
+		diagnostics, err = _i.lintService.ValidateContent(workspace, document.Type, *req.Content)
+		if err != nil {
+			return nil, err
+		}
+
+		version = &schema.DocumentVersion{
+			Content:           *req.Content,
+			AuthorID:          &userID,
+			ChangeDescription: req.ChangeDescription,
+		}
+	}
+
+	document.UpdatedAt = time.Now()
+
+	if err := _i.documentRepo.UpdateFromVersion(document, columns, version, *req.BaseVersion); err != nil {
+		// Ada save lain di antara pengecekan di atas dan transaksi
+		if errors.Is(err, repository.ErrStaleVersion) {
+			return nil, versionConflict(_i.documentRepo, _i.versionRepo, document.ID, *req.BaseVersion, req.Content)
+		}
+		return nil, err
+	}
+
+	if version != nil {
+		latest = version
+		_i.collabHub.Invalidate(document.ID, version.VersionNumber)
+	}
+
+	res := toDocumentResponse(document, latest)
+	res.Diagnostics = diagnostics
+
+	return res, nil
+}
+
+func (_i *documentService) DeleteDocument(workspaceID uint64, id uint64, userID uint64) error {
+	document, workspace, err := _i.findWorkspaceDocument(workspaceID, id)
+	if err != nil {
+		return err
+	}
+
+	// Validasi akses: minimal admin
+	allowed, err := _i.policy.Can(policy.User(userID), policy.DocumentDelete, policy.DocumentResource(workspace, document))
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return policy.Denied(policy.DocumentDelete)
+	}
+
+	if err := _i.documentRepo.Delete(document.ID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Helper: ambil document dan pastikan document milik workspace yang diminta
+func (_i *documentService) findWorkspaceDocument(workspaceID uint64, id uint64) (*schema.Document, *schema.Workspace, error) {
+	document, workspace, err := findDocument(_i.documentRepo, _i.workspaceRepo, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if document.WorkspaceID != workspaceID {
+		return nil, nil, policy.ErrDocumentNotFound
+	}
+
+	return document, workspace, nil
+}
+
+// Helper: ambil versi terakhir, nil jika document belum punya versi
+func (_i *documentService) findLatestVersion(documentID uint64) (*schema.DocumentVersion, error) {
+	latest, err := _i.versionRepo.FindLatest(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return latest, nil
+}
+
+// Helper: generate slug unik per workspace, contoh: "my-diagram", "my-diagram-2"
+func (_i *documentService) generateSlug(workspaceID uint64, title string) string {
+	base := helpers.Slug(title)
+	if base == "" {
+		base = "untitled"
+	}
+
+	slug := base
+	for n := 2; _i.documentRepo.CheckSlugExists(workspaceID, slug, 0); n++ {
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+
+	return slug
+}
+
+// Helper: convert schema to response
+func toDocumentResponse(document *schema.Document, version *schema.DocumentVersion) *response.DocumentResponse {
+	res := &response.DocumentResponse{
+		ID:          document.ID,
+		WorkspaceID: document.WorkspaceID,
+		Title:       document.Title,
+		Type:        document.Type,
+		Slug:        document.Slug,
+		IsPublic:    document.IsPublic,
+		CreatedAt:   document.CreatedAt,
+		UpdatedAt:   document.UpdatedAt,
+	}
+
+	if version != nil {
+		res.VersionNumber = version.VersionNumber
+		res.Content = &version.Content
+	}
+
+	return res
+}
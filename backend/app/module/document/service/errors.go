@@ -0,0 +1,12 @@
+package service
+
+import "git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+
+// Error document dan version service
+var (
+	ErrVersionNotFound = apperr.NotFound("version_not_found", "version not found")
+	ErrInvalidSlug     = apperr.Validation("invalid_slug", "document slug is invalid")
+
+	ErrBaseVersionRequired = apperr.PreconditionRequired("base_version_required", "base_version or If-Match header is required")
+	ErrVersionConflict     = apperr.Conflict("version_conflict", "document has been changed since the base version")
+)
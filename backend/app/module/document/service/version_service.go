@@ -0,0 +1,282 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	collab_service "git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/response"
+	workspace_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/diff"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/mermaid"
+	"gorm.io/gorm"
+)
+
+// VersionService adalah interface untuk business logic riwayat versi document
+type VersionService interface {
+	ListVersions(documentID uint64, userID uint64, page, limit int) (*response.VersionListResponse, error)
+	GetVersion(documentID uint64, userID uint64, number int) (*response.VersionResponse, error)
+	GetCurrentVersion(documentID uint64, userID uint64) (*response.VersionResponse, error)
+	RestoreVersion(documentID uint64, userID uint64, number int, baseVersion *int) (*response.VersionResponse, error)
+	DiffVersions(documentID uint64, userID uint64, from, to int) (*response.DiffResponse, error)
+}
+
+type versionService struct {
+	documentRepo  repository.DocumentRepository
+	versionRepo   repository.DocumentVersionRepository
+	workspaceRepo workspace_repo.WorkspaceRepository
+	collabHub     collab_service.Hub
+	policy        policy.Policy
+}
+
+// NewVersionService instance
+func NewVersionService(
+	documentRepo repository.DocumentRepository,
+	versionRepo repository.DocumentVersionRepository,
+	workspaceRepo workspace_repo.WorkspaceRepository,
+	collabHub collab_service.Hub,
+	policy policy.Policy,
+) VersionService {
+	return &versionService{
+		documentRepo:  documentRepo,
+		versionRepo:   versionRepo,
+		workspaceRepo: workspaceRepo,
+		collabHub:     collabHub,
+		policy:        policy,
+	}
+}
+
+func (_i *versionService) ListVersions(documentID uint64, userID uint64, page, limit int) (*response.VersionListResponse, error) {
+	document, err := _i.authorizeRead(documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validasi pagination
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+
+	versions, err := _i.versionRepo.FindByDocumentID(document.ID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := _i.versionRepo.CountByDocumentID(document.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]response.VersionSummaryResponse, 0, len(versions))
+	for _, v := range versions {
+		responses = append(responses, response.VersionSummaryResponse{
+			ID:                v.ID,
+			DocumentID:        v.DocumentID,
+			VersionNumber:     v.VersionNumber,
+			AuthorID:          v.AuthorID,
+			ChangeDescription: v.ChangeDescription,
+			CreatedAt:         v.CreatedAt,
+		})
+	}
+
+	return &response.VersionListResponse{
+		Data:  responses,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}, nil
+}
+
+func (_i *versionService) GetVersion(documentID uint64, userID uint64, number int) (*response.VersionResponse, error) {
+	document, err := _i.authorizeRead(documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := _i.versionRepo.FindByNumber(document.ID, number)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+
+	return toVersionResponse(version), nil
+}
+
+func (_i *versionService) GetCurrentVersion(documentID uint64, userID uint64) (*response.VersionResponse, error) {
+	document, err := _i.authorizeRead(documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := _i.versionRepo.FindLatest(document.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+
+	return toVersionResponse(version), nil
+}
+
+// RestoreVersion membuat versi baru dengan content dari versi n, riwayat lama tidak dihapus.
+// Content tidak divalidasi ulang agar versi lama yang invalid tetap bisa dikembalikan.
+func (_i *versionService) RestoreVersion(documentID uint64, userID uint64, number int, baseVersion *int) (*response.VersionResponse, error) {
+	document, workspace, err := findDocument(_i.documentRepo, _i.workspaceRepo, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validasi akses: minimal editor atau user dengan share permission edit
+	allowed, err := _i.policy.Can(policy.User(userID), policy.VersionRestore, policy.DocumentResource(workspace, document))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, policy.Denied(policy.VersionRestore)
+	}
+
+	// Restore juga menimpa content, versi yang dilihat client wajib dikirim
+	if baseVersion == nil {
+		return nil, ErrBaseVersionRequired
+	}
+
+	source, err := _i.versionRepo.FindByNumber(document.ID, number)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+
+	latest, err := _i.versionRepo.FindLatest(document.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	base := *baseVersion
+	if base != latest.VersionNumber {
+		return nil, versionConflict(_i.documentRepo, _i.versionRepo, document.ID, base, nil)
+	}
+
+	description := fmt.Sprintf("Restored from v%d", source.VersionNumber)
+	version := &schema.DocumentVersion{
+		Content:           source.Content,
+		AuthorID:          &userID,
+		ChangeDescription: &description,
+	}
+
+	document.UpdatedAt = time.Now()
+
+	if err := _i.documentRepo.UpdateFromVersion(document, []string{"updated_at"}, version, base); err != nil {
+		// Ada save lain di antara pengecekan di atas dan transaksi
+		if errors.Is(err, repository.ErrStaleVersion) {
+			return nil, versionConflict(_i.documentRepo, _i.versionRepo, document.ID, base, nil)
+		}
+		return nil, err
+	}
+	_i.collabHub.Invalidate(document.ID, version.VersionNumber)
+
+	return toVersionResponse(version), nil
+}
+
+// DiffVersions membandingkan dua versi. to = 0 berarti versi terbaru,
+// from = 0 berarti versi tepat sebelum to.
+func (_i *versionService) DiffVersions(documentID uint64, userID uint64, from, to int) (*response.DiffResponse, error) {
+	document, err := _i.authorizeRead(documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var toVersion *schema.DocumentVersion
+	if to == 0 {
+		toVersion, err = _i.versionRepo.FindLatest(document.ID)
+	} else {
+		toVersion, err = _i.versionRepo.FindByNumber(document.ID, to)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+
+	if from == 0 {
+		from = max(toVersion.VersionNumber-1, 1)
+	}
+
+	fromVersion, err := _i.versionRepo.FindByNumber(document.ID, from)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+
+	res := &response.DiffResponse{
+		DocumentID:  document.ID,
+		Type:        document.Type,
+		FromVersion: fromVersion.VersionNumber,
+		ToVersion:   toVersion.VersionNumber,
+		Unified: diff.Unified(
+			fromVersion.Content,
+			toVersion.Content,
+			fmt.Sprintf("%s@v%d", document.Slug, fromVersion.VersionNumber),
+			fmt.Sprintf("%s@v%d", document.Slug, toVersion.VersionNumber),
+			3,
+		),
+	}
+
+	// Diff struktural hanya untuk Mermaid yang jenis diagramnya bisa dikenali
+	if document.Type == schema.DocumentTypeMermaid {
+		oldGraph, _ := mermaid.Parse(fromVersion.Content)
+		newGraph, _ := mermaid.Parse(toVersion.Content)
+		if oldGraph.Type != mermaid.DiagramUnknown && newGraph.Type != mermaid.DiagramUnknown {
+			res.Structural = mermaid.DiffGraphs(oldGraph, newGraph)
+		}
+	}
+
+	return res, nil
+}
+
+// Helper: ambil document dan validasi akses baca
+func (_i *versionService) authorizeRead(documentID uint64, userID uint64) (*schema.Document, error) {
+	document, workspace, err := findDocument(_i.documentRepo, _i.workspaceRepo, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := _i.policy.Can(policy.User(userID), policy.VersionView, policy.DocumentResource(workspace, document))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, policy.Denied(policy.VersionView)
+	}
+
+	return document, nil
+}
+
+// Helper: convert schema to response
+func toVersionResponse(version *schema.DocumentVersion) *response.VersionResponse {
+	return &response.VersionResponse{
+		ID:                version.ID,
+		DocumentID:        version.DocumentID,
+		VersionNumber:     version.VersionNumber,
+		Content:           version.Content,
+		AuthorID:          version.AuthorID,
+		ChangeDescription: version.ChangeDescription,
+		CreatedAt:         version.CreatedAt,
+	}
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/export/service"
+	"go.uber.org/fx"
+)
+
+// Controller aggregator
+type Controller struct {
+	Export ExportControllerI
+}
+
+// NewController
+func NewController(exportController ExportControllerI) *Controller {
+	return &Controller{
+		Export: exportController,
+	}
+}
+
+var Module = fx.Options(
+	fx.Provide(func(exportService service.ExportService) ExportControllerI {
+		return NewExportController(exportService)
+	}),
+	fx.Provide(NewController),
+)
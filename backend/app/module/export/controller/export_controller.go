@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"strconv"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/export/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type exportController struct {
+	exportService service.ExportService
+}
+
+type ExportControllerI interface {
+	CreateDocumentExport(c *fiber.Ctx) error
+	CreateWorkspaceExport(c *fiber.Ctx) error
+	GetJob(c *fiber.Ctx) error
+	Download(c *fiber.Ctx) error
+}
+
+func NewExportController(exportService service.ExportService) ExportControllerI {
+	return &exportController{
+		exportService: exportService,
+	}
+}
+
+// CreateDocumentExport handler untuk antre export PDF satu document
+func (_i *exportController) CreateDocumentExport(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_document_id", "invalid document id")
+	}
+
+	result, err := _i.exportService.CreateDocumentExport(documentID, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusAccepted,
+		Messages: response.Messages{"export queued successfully"},
+		Data:     result,
+	})
+}
+
+// CreateWorkspaceExport handler untuk antre export PDF seluruh document workspace
+func (_i *exportController) CreateWorkspaceExport(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	workspaceID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_workspace_id", "invalid workspace id")
+	}
+
+	result, err := _i.exportService.CreateWorkspaceExport(workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusAccepted,
+		Messages: response.Messages{"export queued successfully"},
+		Data:     result,
+	})
+}
+
+// GetJob handler untuk cek status export
+func (_i *exportController) GetJob(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	jobID, err := parseExportID(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := _i.exportService.GetJob(jobID, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"export retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// Download handler untuk unduh PDF hasil export yang sudah selesai
+func (_i *exportController) Download(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	jobID, err := parseExportID(c)
+	if err != nil {
+		return err
+	}
+
+	file, err := _i.exportService.Download(jobID, userID)
+	if err != nil {
+		return err
+	}
+
+	c.Attachment(file.FileName)
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+
+	// Content ditutup oleh fasthttp setelah stream selesai
+	return c.SendStream(file.Content, int(file.FileSize))
+}
+
+func parseExportID(c *fiber.Ctx) (uint64, error) {
+	jobID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, apperr.BadRequest("invalid_export_id", "invalid export id")
+	}
+
+	return jobID, nil
+}
@@ -0,0 +1,67 @@
+package export
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/export/controller"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/export/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/export/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+)
+
+// ExportRouter adalah router untuk export module
+type ExportRouter struct {
+	App        fiber.Router
+	Controller *controller.Controller
+	AuthMW     *middleware.AuthMiddleware
+	PolicyMW   *middleware.PolicyMiddleware
+}
+
+// Module adalah FX module untuk export
+var NewExportModule = fx.Options(
+	// register repository
+	fx.Provide(repository.NewExportRepository),
+
+	// register service
+	fx.Provide(service.NewExportService),
+
+	// register controller
+	controller.Module,
+
+	// register router
+	fx.Provide(NewExportRouter),
+)
+
+// NewExportRouter membuat instance baru dari ExportRouter
+func NewExportRouter(
+	app *fiber.App,
+	ctrl *controller.Controller,
+	authMW *middleware.AuthMiddleware,
+	policyMW *middleware.PolicyMiddleware,
+) *ExportRouter {
+	return &ExportRouter{
+		App:        app,
+		Controller: ctrl,
+		AuthMW:     authMW,
+		PolicyMW:   policyMW,
+	}
+}
+
+// RegisterExportRoutes mendaftarkan routes untuk export
+func (_i *ExportRouter) RegisterExportRoutes() {
+	// define controllers
+	exportController := _i.Controller.Export
+
+	_i.App.Route("/api/v1", func(router fiber.Router) {
+		// Export hanya membaca document, token read scope boleh membuat export
+		router.Post("/documents/:id/exports", _i.AuthMW.RequireAuthReadOnly(),
+			_i.PolicyMW.Document(policy.DocumentView, "id"), exportController.CreateDocumentExport)
+		router.Post("/workspaces/:id/exports", _i.AuthMW.RequireAuthReadOnly(),
+			_i.PolicyMW.Workspace(policy.WorkspaceView, "id"), exportController.CreateWorkspaceExport)
+
+		exportRoutes := router.Group("/exports", _i.AuthMW.RequireAuth())
+		exportRoutes.Get("/:id", exportController.GetJob)
+		exportRoutes.Get("/:id/download", exportController.Download)
+	})
+}
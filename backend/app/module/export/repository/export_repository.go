@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
+)
+
+// ExportRepository
+type ExportRepository interface {
+	Create(job *schema.ExportJob) (*schema.ExportJob, error)
+	FindByID(id uint64) (*schema.ExportJob, error)
+	// FindPendingIDs job pending terlama lebih dulu
+	FindPendingIDs(limit int) ([]uint64, error)
+	// Claim ubah job pending menjadi running, false jika sudah diambil worker lain
+	Claim(id uint64, startedAt time.Time) (bool, error)
+	Complete(id uint64, filePath, fileName string, fileSize int64, completedAt time.Time) error
+	Fail(id uint64, msg string, completedAt time.Time) error
+	// FailStale gagalkan job running yang dimulai sebelum before, misalnya karena server restart
+	FailStale(before time.Time, msg string) (int64, error)
+	// FindFinishedBefore job completed/failed yang selesai sebelum before, terlama lebih dulu
+	FindFinishedBefore(before time.Time, limit int) ([]schema.ExportJob, error)
+	Delete(id uint64) error
+}
+
+type exportRepository struct {
+	db *database.Database
+}
+
+func NewExportRepository(db *database.Database) ExportRepository {
+	return &exportRepository{
+		db: db,
+	}
+}
+
+func (_i *exportRepository) Create(job *schema.ExportJob) (*schema.ExportJob, error) {
+	if err := _i.db.DB.Create(job).Error; err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (_i *exportRepository) FindByID(id uint64) (*schema.ExportJob, error) {
+	var job schema.ExportJob
+	if err := _i.db.DB.Where("id = ?", id).First(&job).Error; err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (_i *exportRepository) FindPendingIDs(limit int) ([]uint64, error) {
+	var ids []uint64
+	if err := _i.db.DB.Model(&schema.ExportJob{}).
+		Where("status = ?", schema.ExportStatusPending).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (_i *exportRepository) Claim(id uint64, startedAt time.Time) (bool, error) {
+	result := _i.db.DB.Model(&schema.ExportJob{}).
+		Where("id = ? AND status = ?", id, schema.ExportStatusPending).
+		Updates(map[string]any{
+			"status":     schema.ExportStatusRunning,
+			"started_at": startedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (_i *exportRepository) Complete(id uint64, filePath, fileName string, fileSize int64, completedAt time.Time) error {
+	return _i.db.DB.Model(&schema.ExportJob{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":       schema.ExportStatusCompleted,
+			"file_path":    filePath,
+			"file_name":    fileName,
+			"file_size":    fileSize,
+			"completed_at": completedAt,
+		}).Error
+}
+
+func (_i *exportRepository) Fail(id uint64, msg string, completedAt time.Time) error {
+	return _i.db.DB.Model(&schema.ExportJob{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":       schema.ExportStatusFailed,
+			"error":        msg,
+			"completed_at": completedAt,
+		}).Error
+}
+
+func (_i *exportRepository) FailStale(before time.Time, msg string) (int64, error) {
+	result := _i.db.DB.Model(&schema.ExportJob{}).
+		Where("status = ? AND started_at < ?", schema.ExportStatusRunning, before).
+		Updates(map[string]any{
+			"status":       schema.ExportStatusFailed,
+			"error":        msg,
+			"completed_at": time.Now(),
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+func (_i *exportRepository) FindFinishedBefore(before time.Time, limit int) ([]schema.ExportJob, error) {
+	var jobs []schema.ExportJob
+	if err := _i.db.DB.
+		Where("status IN ? AND completed_at < ?", []schema.ExportStatus{schema.ExportStatusCompleted, schema.ExportStatusFailed}, before).
+		Order("completed_at ASC, id ASC").
+		Limit(limit).
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (_i *exportRepository) Delete(id uint64) error {
+	return _i.db.DB.Delete(&schema.ExportJob{}, id).Error
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDatabase sqlite in-memory, satu database per test
+func newTestDatabase(t *testing.T) *database.Database {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Setiap koneksi in-memory punya database sendiri
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&schema.ExportJob{}); err != nil {
+		t.Fatal(err)
+	}
+
+	return &database.Database{DB: db}
+}
+
+func TestFindFinishedBefore(t *testing.T) {
+	repo := NewExportRepository(newTestDatabase(t))
+	now := time.Now()
+
+	var ids []uint64
+	for _, status := range []schema.ExportStatus{schema.ExportStatusCompleted, schema.ExportStatusFailed, schema.ExportStatusRunning, schema.ExportStatusCompleted} {
+		job, err := repo.Create(&schema.ExportJob{WorkspaceID: 1, RequestedBy: 1, Status: status})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, job.ID)
+	}
+
+	if err := repo.Complete(ids[0], "exports/a.pdf", "a.pdf", 1, now.Add(-2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Fail(ids[1], "failed", now.Add(-3*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Complete(ids[3], "exports/b.pdf", "b.pdf", 1, now); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := repo.FindFinishedBefore(now.Add(-time.Hour), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 || jobs[0].ID != ids[1] || jobs[1].ID != ids[0] {
+		t.Fatalf("jobs = %+v, want ids [%d %d]", jobs, ids[1], ids[0])
+	}
+
+	if err := repo.Delete(ids[1]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.FindByID(ids[1]); err == nil {
+		t.Fatal("deleted job still exists")
+	}
+}
@@ -0,0 +1,31 @@
+package response
+
+import (
+	"io"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+)
+
+// ExportJobResponse DocumentID nil untuk export workspace. DownloadPath hanya
+// diisi jika status completed.
+type ExportJobResponse struct {
+	ID           uint64              `json:"id"`
+	WorkspaceID  uint64              `json:"workspace_id"`
+	DocumentID   *uint64             `json:"document_id"`
+	Status       schema.ExportStatus `json:"status"`
+	FileName     string              `json:"file_name,omitempty"`
+	FileSize     int64               `json:"file_size,omitempty"`
+	Error        string              `json:"error,omitempty"`
+	DownloadPath string              `json:"download_path,omitempty"`
+	StartedAt    *time.Time          `json:"started_at"`
+	CompletedAt  *time.Time          `json:"completed_at"`
+	CreatedAt    time.Time           `json:"created_at"`
+}
+
+// ExportFile file hasil export, Content wajib ditutup oleh pemanggil
+type ExportFile struct {
+	Content  io.ReadCloser
+	FileName string
+	FileSize int64
+}
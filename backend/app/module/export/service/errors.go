@@ -0,0 +1,10 @@
+package service
+
+import "git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+
+// Error export service
+var (
+	ErrExportNotFound = apperr.NotFound("export_not_found", "export not found")
+	ErrExportNotReady = apperr.Conflict("export_not_ready", "export is not completed yet")
+	ErrEmptyWorkspace = apperr.Validation("empty_workspace", "workspace has no documents to export")
+)
@@ -0,0 +1,431 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	document_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/export/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/export/response"
+	render_service "git.dev.siap.id/kukuhkkh/app-diagram/app/module/render/service"
+	workspace_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/helpers"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/pdf"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/storage"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// maxErrorLength panjang kolom export_jobs.error
+const maxErrorLength = 500
+
+// ExportService adalah interface untuk export document dan workspace ke PDF.
+// Export dikerjakan di background, client polling GetJob lalu Download.
+type ExportService interface {
+	CreateDocumentExport(documentID uint64, userID uint64) (*response.ExportJobResponse, error)
+	CreateWorkspaceExport(workspaceID uint64, userID uint64) (*response.ExportJobResponse, error)
+	// GetJob dan Download hanya untuk user yang meminta export. Download mengecek
+	// ulang akses ke workspace/document, file dihapus setelah retention.
+	GetJob(jobID uint64, userID uint64) (*response.ExportJobResponse, error)
+	Download(jobID uint64, userID uint64) (*response.ExportFile, error)
+}
+
+type exportService struct {
+	exportRepo    repository.ExportRepository
+	documentRepo  document_repo.DocumentRepository
+	versionRepo   document_repo.DocumentVersionRepository
+	workspaceRepo workspace_repo.WorkspaceRepository
+	renderService render_service.RenderService
+	storage       storage.Storage
+	policy        policy.Policy
+	runner        *runner
+}
+
+// NewExportService instance, worker export ikut lifecycle aplikasi
+func NewExportService(
+	lifecycle fx.Lifecycle,
+	exportRepo repository.ExportRepository,
+	documentRepo document_repo.DocumentRepository,
+	versionRepo document_repo.DocumentVersionRepository,
+	workspaceRepo workspace_repo.WorkspaceRepository,
+	renderService render_service.RenderService,
+	storage storage.Storage,
+	policy policy.Policy,
+) ExportService {
+	service := &exportService{
+		exportRepo:    exportRepo,
+		documentRepo:  documentRepo,
+		versionRepo:   versionRepo,
+		workspaceRepo: workspaceRepo,
+		renderService: renderService,
+		storage:       storage,
+		policy:        policy,
+	}
+	service.runner = newRunner(service)
+
+	lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			service.runner.recover()
+			return nil
+		},
+		OnStop: service.runner.stop,
+	})
+
+	return service
+}
+
+func (_i *exportService) CreateDocumentExport(documentID uint64, userID uint64) (*response.ExportJobResponse, error) {
+	document, err := _i.documentRepo.FindByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, policy.ErrDocumentNotFound
+		}
+		return nil, err
+	}
+
+	workspace, err := _i.findWorkspace(document.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := _i.can(userID, policy.DocumentView, policy.DocumentResource(workspace, document)); err != nil {
+		return nil, err
+	}
+
+	return _i.create(&schema.ExportJob{
+		WorkspaceID: workspace.ID,
+		DocumentID:  &document.ID,
+		RequestedBy: userID,
+	})
+}
+
+func (_i *exportService) CreateWorkspaceExport(workspaceID uint64, userID uint64) (*response.ExportJobResponse, error) {
+	workspace, err := _i.findWorkspace(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := _i.can(userID, policy.WorkspaceView, policy.WorkspaceResource(workspace)); err != nil {
+		return nil, err
+	}
+
+	count, err := _i.documentRepo.CountByWorkspaceID(workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrEmptyWorkspace
+	}
+
+	return _i.create(&schema.ExportJob{
+		WorkspaceID: workspace.ID,
+		RequestedBy: userID,
+	})
+}
+
+func (_i *exportService) GetJob(jobID uint64, userID uint64) (*response.ExportJobResponse, error) {
+	job, err := _i.findJob(jobID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toExportJobResponse(job), nil
+}
+
+func (_i *exportService) Download(jobID uint64, userID uint64) (*response.ExportFile, error) {
+	job, err := _i.findJob(jobID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Akses bisa dicabut setelah export selesai
+	if _, _, err := _i.authorizeJob(job); err != nil {
+		return nil, err
+	}
+
+	if job.Status != schema.ExportStatusCompleted {
+		return nil, ErrExportNotReady.WithData(toExportJobResponse(job))
+	}
+
+	content, err := _i.storage.Open(job.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.ExportFile{
+		Content:  content,
+		FileName: job.FileName,
+		FileSize: job.FileSize,
+	}, nil
+}
+
+func (_i *exportService) create(job *schema.ExportJob) (*response.ExportJobResponse, error) {
+	job.Status = schema.ExportStatusPending
+
+	job, err := _i.exportRepo.Create(job)
+	if err != nil {
+		return nil, err
+	}
+
+	_i.runner.enqueue()
+
+	return toExportJobResponse(job), nil
+}
+
+// run kerjakan satu job yang sudah di-claim worker
+func (_i *exportService) run(jobID uint64) {
+	job, err := _i.exportRepo.FindByID(jobID)
+	if err != nil {
+		log.Error().Err(err).Uint64("export_id", jobID).Msg("failed to load export job")
+		return
+	}
+
+	path, name, size, err := _i.generate(job)
+	if err != nil {
+		log.Error().Err(err).Uint64("export_id", job.ID).Msg("export job failed")
+		if err := _i.exportRepo.Fail(job.ID, failureMessage(err), time.Now()); err != nil {
+			log.Error().Err(err).Uint64("export_id", job.ID).Msg("failed to mark export job as failed")
+		}
+		return
+	}
+
+	if err := _i.exportRepo.Complete(job.ID, path, name, size, time.Now()); err != nil {
+		log.Error().Err(err).Uint64("export_id", job.ID).Msg("failed to mark export job as completed")
+	}
+}
+
+// generate tulis PDF lalu upload ke storage. Akses dicek ulang dengan hak
+// requester saat job dikerjakan, document yang tidak lagi boleh dilihat dilewati.
+func (_i *exportService) generate(job *schema.ExportJob) (path string, name string, size int64, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("pdf writer panic: %v", r)
+		}
+	}()
+
+	workspace, document, err := _i.authorizeJob(job)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	subject := policy.User(job.RequestedBy)
+	title := workspace.Name
+
+	var documents []schema.Document
+	if document != nil {
+		documents = []schema.Document{*document}
+		title = document.Title
+	} else {
+		documents, err = _i.documentRepo.FindAllByWorkspaceID(workspace.ID)
+		if err != nil {
+			return "", "", 0, err
+		}
+	}
+
+	chapters := make([]pdf.Chapter, 0, len(documents))
+	for i := range documents {
+		document := &documents[i]
+
+		allowed, err := _i.policy.Can(subject, policy.DocumentView, policy.DocumentResource(workspace, document))
+		if err != nil {
+			return "", "", 0, err
+		}
+		if !allowed {
+			continue
+		}
+
+		latest, err := _i.versionRepo.FindLatest(document.ID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return "", "", 0, err
+		}
+
+		chapters = append(chapters, pdf.Chapter{
+			Title:    document.Title,
+			Markdown: document.Type == schema.DocumentTypeMarkdown,
+			Content:  latest.Content,
+		})
+	}
+	if len(chapters) == 0 {
+		return "", "", 0, ErrEmptyWorkspace
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Write(&buf, chapters, pdf.Options{
+		Title: title,
+		TOC:   job.DocumentID == nil,
+		Embed: _i.renderService.EmbedResolver(subject, workspace),
+	}); err != nil {
+		return "", "", 0, err
+	}
+	size = int64(buf.Len())
+
+	// Local storage disajikan publik, key acak agar file hanya bisa diunduh lewat API
+	token, err := helpers.GenerateSecureToken(32)
+	if err != nil {
+		return "", "", 0, err
+	}
+	path = fmt.Sprintf("exports/%s.pdf", token)
+
+	if _, err := _i.storage.Upload(context.Background(), path, &buf); err != nil {
+		return "", "", 0, err
+	}
+
+	name = helpers.Slug(title)
+	if name == "" {
+		name = "export"
+	}
+
+	return path, name + ".pdf", size, nil
+}
+
+// purge hapus job yang selesai sebelum before beserta file hasilnya. Row job
+// dihapus setelah file terhapus, file yang gagal dihapus dicoba lagi di
+// putaran berikutnya.
+func (_i *exportService) purge(before time.Time) {
+	for {
+		jobs, err := _i.exportRepo.FindFinishedBefore(before, purgeBatch)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to load expired export jobs")
+			return
+		}
+
+		removed := 0
+		for i := range jobs {
+			job := &jobs[i]
+
+			if job.FilePath != "" {
+				if err := _i.storage.Delete(job.FilePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					log.Warn().Err(err).Uint64("export_id", job.ID).Msg("failed to delete export file")
+					continue
+				}
+			}
+
+			if err := _i.exportRepo.Delete(job.ID); err != nil {
+				log.Warn().Err(err).Uint64("export_id", job.ID).Msg("failed to delete export job")
+				continue
+			}
+			removed++
+		}
+
+		if len(jobs) < purgeBatch || removed == 0 {
+			return
+		}
+	}
+}
+
+// authorizeJob cek akses requester ke workspace atau document yang di-export.
+// Document nil untuk export workspace.
+func (_i *exportService) authorizeJob(job *schema.ExportJob) (*schema.Workspace, *schema.Document, error) {
+	workspace, err := _i.findWorkspace(job.WorkspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if job.DocumentID == nil {
+		if err := _i.can(job.RequestedBy, policy.WorkspaceView, policy.WorkspaceResource(workspace)); err != nil {
+			return nil, nil, err
+		}
+		return workspace, nil, nil
+	}
+
+	document, err := _i.documentRepo.FindByID(*job.DocumentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, policy.ErrDocumentNotFound
+		}
+		return nil, nil, err
+	}
+	if err := _i.can(job.RequestedBy, policy.DocumentView, policy.DocumentResource(workspace, document)); err != nil {
+		return nil, nil, err
+	}
+
+	return workspace, document, nil
+}
+
+func (_i *exportService) findJob(jobID uint64, userID uint64) (*schema.ExportJob, error) {
+	job, err := _i.exportRepo.FindByID(jobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExportNotFound
+		}
+		return nil, err
+	}
+
+	// Job milik user lain dianggap tidak ada
+	if job.RequestedBy != userID {
+		return nil, ErrExportNotFound
+	}
+
+	return job, nil
+}
+
+func (_i *exportService) findWorkspace(workspaceID uint64) (*schema.Workspace, error) {
+	workspace, err := _i.workspaceRepo.FindByID(workspaceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, policy.ErrWorkspaceNotFound
+		}
+		return nil, err
+	}
+
+	return workspace, nil
+}
+
+func (_i *exportService) can(userID uint64, action policy.Action, resource policy.Resource) error {
+	allowed, err := _i.policy.Can(policy.User(userID), action, resource)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return policy.Denied(action)
+	}
+
+	return nil
+}
+
+// failureMessage pesan error yang disimpan di job, detail error internal hanya di log
+func failureMessage(err error) string {
+	msg := "export failed"
+
+	var appErr *apperr.Error
+	if errors.As(err, &appErr) && appErr.Message != "" {
+		msg = appErr.Message
+	}
+
+	if len(msg) > maxErrorLength {
+		msg = msg[:maxErrorLength]
+	}
+	return msg
+}
+
+func toExportJobResponse(job *schema.ExportJob) *response.ExportJobResponse {
+	res := &response.ExportJobResponse{
+		ID:          job.ID,
+		WorkspaceID: job.WorkspaceID,
+		DocumentID:  job.DocumentID,
+		Status:      job.Status,
+		Error:       job.Error,
+		StartedAt:   job.StartedAt,
+		CompletedAt: job.CompletedAt,
+		CreatedAt:   job.CreatedAt,
+	}
+
+	if job.Status == schema.ExportStatusCompleted {
+		res.FileName = job.FileName
+		res.FileSize = job.FileSize
+		res.DownloadPath = fmt.Sprintf("/api/v1/exports/%d/download", job.ID)
+	}
+
+	return res
+}
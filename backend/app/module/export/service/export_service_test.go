@@ -0,0 +1,217 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	document_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/export/repository"
+	workspace_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/storage"
+)
+
+const testUserID = 7
+
+type fakeExportRepo struct {
+	repository.ExportRepository
+	jobs    map[uint64]*schema.ExportJob
+	deleted []uint64
+}
+
+func (_i *fakeExportRepo) FindByID(id uint64) (*schema.ExportJob, error) {
+	job, ok := _i.jobs[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	copied := *job
+	return &copied, nil
+}
+
+func (_i *fakeExportRepo) FindFinishedBefore(before time.Time, limit int) ([]schema.ExportJob, error) {
+	var jobs []schema.ExportJob
+	for _, job := range _i.jobs {
+		if job.CompletedAt != nil && job.CompletedAt.Before(before) && len(jobs) < limit {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+func (_i *fakeExportRepo) Delete(id uint64) error {
+	delete(_i.jobs, id)
+	_i.deleted = append(_i.deleted, id)
+	return nil
+}
+
+type fakeWorkspaceRepo struct {
+	workspace_repo.WorkspaceRepository
+}
+
+func (fakeWorkspaceRepo) FindByID(id uint64) (*schema.Workspace, error) {
+	return &schema.Workspace{ID: id}, nil
+}
+
+type fakeDocumentRepo struct {
+	document_repo.DocumentRepository
+}
+
+func (fakeDocumentRepo) FindByID(id uint64) (*schema.Document, error) {
+	return &schema.Document{ID: id, WorkspaceID: 1, Title: "Doc"}, nil
+}
+
+// fakePolicy izinkan action yang ada di allowed
+type fakePolicy struct {
+	policy.Policy
+	allowed map[policy.Action]bool
+}
+
+func (_i fakePolicy) Can(_ policy.Subject, action policy.Action, _ policy.Resource) (bool, error) {
+	return _i.allowed[action], nil
+}
+
+type fakeStorage struct {
+	storage.Storage
+	files   map[string]string
+	failing map[string]bool
+}
+
+func (_i *fakeStorage) Open(filename string) (io.ReadCloser, error) {
+	content, ok := _i.files[filename]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+func (_i *fakeStorage) Delete(filename string) error {
+	if _i.failing[filename] {
+		return errors.New("storage unavailable")
+	}
+	if _, ok := _i.files[filename]; !ok {
+		return fs.ErrNotExist
+	}
+	delete(_i.files, filename)
+	return nil
+}
+
+func newTestExportService(jobs map[uint64]*schema.ExportJob, files *fakeStorage, allowed ...policy.Action) (*exportService, *fakeExportRepo) {
+	p := fakePolicy{allowed: map[policy.Action]bool{}}
+	for _, action := range allowed {
+		p.allowed[action] = true
+	}
+
+	repo := &fakeExportRepo{jobs: jobs}
+	return &exportService{
+		exportRepo:    repo,
+		documentRepo:  fakeDocumentRepo{},
+		workspaceRepo: fakeWorkspaceRepo{},
+		storage:       files,
+		policy:        p,
+	}, repo
+}
+
+func completedJob(id uint64, documentID *uint64, completedAt time.Time) *schema.ExportJob {
+	return &schema.ExportJob{
+		ID:          id,
+		WorkspaceID: 1,
+		DocumentID:  documentID,
+		RequestedBy: testUserID,
+		Status:      schema.ExportStatusCompleted,
+		FilePath:    "exports/file.pdf",
+		FileName:    "file.pdf",
+		CompletedAt: &completedAt,
+	}
+}
+
+func TestDownloadRechecksAccess(t *testing.T) {
+	documentID := uint64(3)
+
+	cases := []struct {
+		name    string
+		job     *schema.ExportJob
+		allowed []policy.Action
+		denied  bool
+	}{
+		{"workspace export allowed", completedJob(1, nil, time.Now()), []policy.Action{policy.WorkspaceView}, false},
+		{"workspace access revoked", completedJob(1, nil, time.Now()), nil, true},
+		{"document export allowed", completedJob(1, &documentID, time.Now()), []policy.Action{policy.DocumentView}, false},
+		{"document access revoked", completedJob(1, &documentID, time.Now()), []policy.Action{policy.WorkspaceView}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			files := &fakeStorage{files: map[string]string{"exports/file.pdf": "%PDF-"}}
+			svc, _ := newTestExportService(map[uint64]*schema.ExportJob{1: tc.job}, files, tc.allowed...)
+
+			file, err := svc.Download(1, testUserID)
+			if tc.denied {
+				if err == nil {
+					t.Fatal("Download succeeded after access was revoked")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			file.Content.Close()
+		})
+	}
+}
+
+func TestPurgeDeletesExpiredJobsAndFiles(t *testing.T) {
+	now := time.Now()
+	jobs := map[uint64]*schema.ExportJob{
+		1: completedJob(1, nil, now.Add(-2*retention)),
+		2: completedJob(2, nil, now.Add(-2*retention)),
+		3: completedJob(3, nil, now),
+		4: {ID: 4, Status: schema.ExportStatusFailed, CompletedAt: ptr(now.Add(-2 * retention))},
+		5: {ID: 5, Status: schema.ExportStatusPending},
+	}
+	jobs[1].FilePath = "exports/old.pdf"
+	jobs[2].FilePath = "exports/broken.pdf"
+	jobs[3].FilePath = "exports/new.pdf"
+
+	files := &fakeStorage{
+		files:   map[string]string{"exports/old.pdf": "", "exports/broken.pdf": "", "exports/new.pdf": ""},
+		failing: map[string]bool{"exports/broken.pdf": true},
+	}
+	svc, repo := newTestExportService(jobs, files)
+
+	svc.purge(now.Add(-retention))
+
+	// File yang gagal dihapus tetap punya row job agar dicoba lagi
+	for _, id := range []uint64{2, 3, 5} {
+		if _, ok := repo.jobs[id]; !ok {
+			t.Fatalf("job %d was deleted", id)
+		}
+	}
+	for _, id := range []uint64{1, 4} {
+		if _, ok := repo.jobs[id]; ok {
+			t.Fatalf("job %d was not deleted", id)
+		}
+	}
+	if _, ok := files.files["exports/old.pdf"]; ok {
+		t.Fatal("expired export file was not deleted")
+	}
+	if _, ok := files.files["exports/new.pdf"]; !ok {
+		t.Fatal("recent export file was deleted")
+	}
+
+	// File yang sudah tidak ada tidak menahan penghapusan job
+	files.failing = nil
+	delete(files.files, "exports/broken.pdf")
+	svc.purge(now.Add(-retention))
+	if _, ok := repo.jobs[2]; ok {
+		t.Fatal("job with missing file was not deleted")
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	workerCount  = 2
+	pollInterval = 30 * time.Second
+	// staleAfter job running lebih lama dari ini dianggap mati, misalnya server restart di tengah export
+	staleAfter = 30 * time.Minute
+
+	// retention job selesai beserta file PDF-nya dihapus setelah selama ini
+	retention     = 24 * time.Hour
+	purgeInterval = time.Hour
+	purgeBatch    = 100
+)
+
+// runner worker background export. Antrian disimpan di tabel export_jobs
+// sehingga beberapa instance aplikasi bisa berbagi antrian, worker mengambil
+// job dengan Claim agar satu job hanya dikerjakan sekali.
+type runner struct {
+	service *exportService
+	wake    chan struct{}
+	once    sync.Once
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func newRunner(service *exportService) *runner {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &runner{
+		service: service,
+		wake:    make(chan struct{}, 1),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// recover gagalkan job yang tertinggal dari proses sebelumnya lalu lanjutkan antrian
+func (_i *runner) recover() {
+	failed, err := _i.service.exportRepo.FailStale(time.Now().Add(-staleAfter), "export was interrupted, please try again")
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fail stale export jobs")
+	} else if failed > 0 {
+		log.Warn().Int64("count", failed).Msg("stale export jobs marked as failed")
+	}
+
+	_i.enqueue()
+}
+
+// enqueue bangunkan worker, worker dan pembersih job lama dijalankan saat job
+// pertama masuk
+func (_i *runner) enqueue() {
+	_i.once.Do(func() {
+		for range workerCount {
+			_i.wg.Add(1)
+			go _i.work()
+		}
+
+		_i.wg.Add(1)
+		go _i.clean()
+	})
+
+	select {
+	case _i.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (_i *runner) stop(ctx context.Context) error {
+	_i.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		_i.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (_i *runner) work() {
+	defer _i.wg.Done()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		_i.drain()
+
+		select {
+		case <-_i.ctx.Done():
+			return
+		case <-_i.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain kerjakan job pending sampai antrian kosong
+func (_i *runner) drain() {
+	repo := _i.service.exportRepo
+
+	for _i.ctx.Err() == nil {
+		ids, err := repo.FindPendingIDs(workerCount)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to load pending export jobs")
+			return
+		}
+		if len(ids) == 0 {
+			return
+		}
+
+		for _, id := range ids {
+			claimed, err := repo.Claim(id, time.Now())
+			if err != nil {
+				log.Error().Err(err).Uint64("export_id", id).Msg("failed to claim export job")
+				return
+			}
+			if claimed {
+				_i.service.run(id)
+			}
+		}
+	}
+}
+
+// clean hapus job yang sudah melewati retention secara berkala
+func (_i *runner) clean() {
+	defer _i.wg.Done()
+
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		_i.service.purge(time.Now().Add(-retention))
+
+		select {
+		case <-_i.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/lint/service"
+	"go.uber.org/fx"
+)
+
+// Controller aggregator
+type Controller struct {
+	Lint LintControllerI
+}
+
+// NewController
+func NewController(lintController LintControllerI) *Controller {
+	return &Controller{
+		Lint: lintController,
+	}
+}
+
+var Module = fx.Options(
+	fx.Provide(func(lintService service.LintService) LintControllerI {
+		return NewLintController(lintService)
+	}),
+	fx.Provide(NewController),
+)
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/lint/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/lint/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type lintController struct {
+	lintService service.LintService
+}
+
+type LintControllerI interface {
+	Lint(c *fiber.Ctx) error
+}
+
+func NewLintController(lintService service.LintService) LintControllerI {
+	return &lintController{
+		lintService: lintService,
+	}
+}
+
+// Lint handler untuk validasi syntax content tanpa menyimpan, dipakai editor dan CI.
+// Selalu 200, hasil validasi ada di field valid.
+func (_i *lintController) Lint(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	var req request.LintRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.ErrInvalidBody
+	}
+
+	// Validasi input
+	if err := response.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	result := _i.lintService.Lint(&req)
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"content linted successfully"},
+		Data:     result,
+	})
+}
@@ -0,0 +1,52 @@
+package lint
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/lint/controller"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/lint/service"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+)
+
+// LintRouter adalah router untuk lint module
+type LintRouter struct {
+	App        fiber.Router
+	Controller *controller.Controller
+	AuthMW     *middleware.AuthMiddleware
+}
+
+// Module adalah FX module untuk validasi syntax content
+var NewLintModule = fx.Options(
+	// register service
+	fx.Provide(service.NewLintService),
+
+	// register controller
+	controller.Module,
+
+	// register router
+	fx.Provide(NewLintRouter),
+)
+
+// NewLintRouter membuat instance baru dari LintRouter
+func NewLintRouter(
+	app *fiber.App,
+	ctrl *controller.Controller,
+	authMW *middleware.AuthMiddleware,
+) *LintRouter {
+	return &LintRouter{
+		App:        app,
+		Controller: ctrl,
+		AuthMW:     authMW,
+	}
+}
+
+// RegisterLintRoutes mendaftarkan routes untuk lint
+func (_i *LintRouter) RegisterLintRoutes() {
+	// define controllers
+	lintController := _i.Controller.Lint
+
+	_i.App.Route("/api/v1", func(router fiber.Router) {
+		// Lint tidak menyimpan apa pun, personal access token scope read dari CI cukup
+		router.Post("/lint", _i.AuthMW.RequireAuthReadOnly(), lintController.Lint)
+	})
+}
@@ -0,0 +1,7 @@
+package request
+
+// LintRequest Type jenis document, default mermaid
+type LintRequest struct {
+	Content string `json:"content" validate:"required"`
+	Type    string `json:"type" validate:"omitempty,oneof=mermaid markdown"`
+}
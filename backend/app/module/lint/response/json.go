@@ -0,0 +1,17 @@
+package response
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/mermaid"
+)
+
+// LintResponse Valid false jika ada diagnostic dengan severity error
+type LintResponse struct {
+	Valid       bool                 `json:"valid"`
+	DiagramType mermaid.DiagramType  `json:"diagram_type,omitempty"`
+	Diagnostics []mermaid.Diagnostic `json:"diagnostics"`
+}
+
+// InvalidContentResponse dikirim bersama 422 saat content ditolak karena syntax error
+type InvalidContentResponse struct {
+	Diagnostics []mermaid.Diagnostic `json:"diagnostics"`
+}
@@ -0,0 +1,8 @@
+package service
+
+import "git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+
+// Error lint service
+var (
+	ErrInvalidMermaid = apperr.Validation("invalid_mermaid", "mermaid content has syntax errors")
+)
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"strings"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/lint/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/lint/response"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/mermaid"
+)
+
+// LintService adalah interface untuk validasi syntax content document
+type LintService interface {
+	Lint(req *request.LintRequest) *response.LintResponse
+
+	// ValidateContent dipakai saat document disimpan, sesuai setting mermaid_validation workspace.
+	// Error 422 berisi diagnostics jika strict dan ada error, selain itu diagnostics dikembalikan
+	// agar bisa ditampilkan sebagai warning.
+	ValidateContent(workspace *schema.Workspace, docType schema.DocumentType, content string) ([]mermaid.Diagnostic, error)
+}
+
+type lintService struct{}
+
+// NewLintService instance
+func NewLintService() LintService {
+	return &lintService{}
+}
+
+func (_i *lintService) Lint(req *request.LintRequest) *response.LintResponse {
+	// Markdown belum di-lint, selalu valid
+	if req.Type == string(schema.DocumentTypeMarkdown) {
+		return &response.LintResponse{Valid: true, Diagnostics: []mermaid.Diagnostic{}}
+	}
+
+	graph, diags := mermaid.Parse(req.Content)
+	if diags == nil {
+		diags = []mermaid.Diagnostic{}
+	}
+
+	return &response.LintResponse{
+		Valid:       !mermaid.HasErrors(diags),
+		DiagramType: graph.Type,
+		Diagnostics: diags,
+	}
+}
+
+func (_i *lintService) ValidateContent(workspace *schema.Workspace, docType schema.DocumentType, content string) ([]mermaid.Diagnostic, error) {
+	if docType != schema.DocumentTypeMermaid || workspace.MermaidValidation == schema.MermaidValidationOff {
+		return nil, nil
+	}
+
+	// Document baru boleh dibuat kosong
+	if strings.TrimSpace(content) == "" {
+		return nil, nil
+	}
+
+	_, diags := mermaid.Parse(content)
+	if workspace.MermaidValidation != schema.MermaidValidationWarn && mermaid.HasErrors(diags) {
+		return nil, ErrInvalidMermaid.WithData(&response.InvalidContentResponse{Diagnostics: diags})
+	}
+
+	return diags, nil
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/render/service"
+	"go.uber.org/fx"
+)
+
+// Controller aggregator
+type Controller struct {
+	Render RenderControllerI
+}
+
+// NewController
+func NewController(renderController RenderControllerI) *Controller {
+	return &Controller{
+		Render: renderController,
+	}
+}
+
+var Module = fx.Options(
+	fx.Provide(func(renderService service.RenderService) RenderControllerI {
+		return NewRenderController(renderService)
+	}),
+	fx.Provide(NewController),
+)
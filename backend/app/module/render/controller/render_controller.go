@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"strconv"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/render/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/render/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// contentSecurityPolicies hasil render dibuka langsung di browser, script diblokir.
+// HTML markdown boleh memuat gambar dari luar.
+var contentSecurityPolicies = map[service.Format]string{
+	service.FormatSVG:  "default-src 'none'; style-src 'unsafe-inline'",
+	service.FormatHTML: "default-src 'none'; style-src 'unsafe-inline'; img-src https: data:",
+}
+
+type renderController struct {
+	renderService service.RenderService
+}
+
+type RenderControllerI interface {
+	RenderSVG(c *fiber.Ctx) error
+	RenderPNG(c *fiber.Ctx) error
+	RenderHTML(c *fiber.Ctx) error
+}
+
+func NewRenderController(renderService service.RenderService) RenderControllerI {
+	return &renderController{
+		renderService: renderService,
+	}
+}
+
+// RenderSVG handler untuk render document menjadi SVG (?version=n&token=)
+func (_i *renderController) RenderSVG(c *fiber.Ctx) error {
+	return _i.render(c, service.FormatSVG)
+}
+
+// RenderPNG handler untuk render document menjadi PNG (?version=n&scale=2&token=)
+func (_i *renderController) RenderPNG(c *fiber.Ctx) error {
+	return _i.render(c, service.FormatPNG)
+}
+
+// RenderHTML handler untuk render document markdown menjadi HTML tersanitasi (?version=n&token=)
+func (_i *renderController) RenderHTML(c *fiber.Ctx) error {
+	return _i.render(c, service.FormatHTML)
+}
+
+func (_i *renderController) render(c *fiber.Ctx, format service.Format) error {
+	documentID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_document_id", "invalid document id")
+	}
+
+	var query request.RenderQuery
+	if err := c.QueryParser(&query); err != nil {
+		return apperr.BadRequest("invalid_query", "invalid query parameter")
+	}
+
+	// Validasi input
+	if err := response.ValidateStruct(query); err != nil {
+		return err
+	}
+
+	result, err := _i.renderService.Render(documentID, middleware.GetUserID(c), format, &query)
+	if err != nil {
+		return err
+	}
+
+	// Document privat atau via share link tidak boleh disimpan shared cache, client
+	// tetap revalidasi dengan ETag karena akses bisa dicabut
+	if result.Public {
+		c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	} else {
+		c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	}
+	response.SetETag(c, result.ETag)
+
+	if c.Fresh() {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	if csp, ok := contentSecurityPolicies[format]; ok {
+		c.Set(fiber.HeaderContentSecurityPolicy, csp)
+	}
+	c.Set(fiber.HeaderContentType, result.ContentType)
+
+	return c.Send(result.Content)
+}
@@ -0,0 +1,55 @@
+package render
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/render/controller"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/render/service"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+)
+
+// RenderRouter adalah router untuk render module
+type RenderRouter struct {
+	App        fiber.Router
+	Controller *controller.Controller
+	AuthMW     *middleware.AuthMiddleware
+}
+
+// Module adalah FX module untuk render document menjadi gambar dan HTML
+var NewRenderModule = fx.Options(
+	// register service
+	fx.Provide(service.NewRenderService),
+
+	// register controller
+	controller.Module,
+
+	// register router
+	fx.Provide(NewRenderRouter),
+)
+
+// NewRenderRouter membuat instance baru dari RenderRouter
+func NewRenderRouter(
+	app *fiber.App,
+	ctrl *controller.Controller,
+	authMW *middleware.AuthMiddleware,
+) *RenderRouter {
+	return &RenderRouter{
+		App:        app,
+		Controller: ctrl,
+		AuthMW:     authMW,
+	}
+}
+
+// RegisterRenderRoutes mendaftarkan routes untuk render
+func (_i *RenderRouter) RegisterRenderRoutes() {
+	// define controllers
+	renderController := _i.Controller.Render
+
+	_i.App.Route("/api/v1", func(router fiber.Router) {
+		// Tanpa RequireAuth: document publik dan share link (?token=) bisa dirender anonim,
+		// otorisasi dilakukan di service
+		router.Get("/documents/:id/render.svg", _i.AuthMW.OptionalAuth(), renderController.RenderSVG)
+		router.Get("/documents/:id/render.png", _i.AuthMW.OptionalAuth(), renderController.RenderPNG)
+		router.Get("/documents/:id/render.html", _i.AuthMW.OptionalAuth(), renderController.RenderHTML)
+	})
+}
@@ -0,0 +1,10 @@
+package request
+
+// RenderQuery query parameter render document. Version 0 berarti versi terakhir,
+// Scale hanya dipakai untuk PNG dan Token untuk akses via share link, yang
+// tidak boleh digabung dengan Version.
+type RenderQuery struct {
+	Version int     `query:"version" validate:"omitempty,min=1"`
+	Scale   float64 `query:"scale" validate:"omitempty,min=1,max=4"`
+	Token   string  `query:"token"`
+}
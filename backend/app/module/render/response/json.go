@@ -0,0 +1,10 @@
+package response
+
+// RenderResponse hasil render yang dikirim apa adanya sebagai body, bukan JSON.
+// ETag adalah hash content sekaligus key cache di storage.
+type RenderResponse struct {
+	Content     []byte
+	ContentType string
+	ETag        string
+	Public      bool // document bisa dilihat tanpa login, boleh di-cache proxy
+}
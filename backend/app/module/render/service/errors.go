@@ -0,0 +1,12 @@
+package service
+
+import "git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+
+// Error render service
+var (
+	ErrVersionNotFound    = apperr.NotFound("version_not_found", "version not found")
+	ErrNotMermaid         = apperr.Validation("not_mermaid", "only mermaid documents can be rendered")
+	ErrNotMarkdown        = apperr.Validation("not_markdown", "only markdown documents can be rendered as HTML")
+	ErrUnsupportedDiagram = apperr.Validation("unsupported_diagram", "diagram type is not supported by the renderer")
+	ErrVersionNotShared   = apperr.Forbidden("version_not_shared", "share links only give access to the latest version")
+)
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	document_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/repository"
+	lint_response "git.dev.siap.id/kukuhkkh/app-diagram/app/module/lint/response"
+	lint_service "git.dev.siap.id/kukuhkkh/app-diagram/app/module/lint/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/render/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/render/response"
+	share_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/share/repository"
+	workspace_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/helpers"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/markdown"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/mermaid"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/render"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/storage"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// Format output render
+type Format string
+
+const (
+	FormatSVG  Format = "svg"
+	FormatPNG  Format = "png"
+	FormatHTML Format = "html" // hanya untuk document markdown
+)
+
+var contentTypes = map[Format]string{
+	FormatSVG:  "image/svg+xml",
+	FormatPNG:  "image/png",
+	FormatHTML: "text/html; charset=utf-8",
+}
+
+// RenderService adalah interface untuk render document Mermaid menjadi gambar
+type RenderService interface {
+	// Render gambar versi document, atau HTML untuk document markdown. Tanpa token,
+	// akses mengikuti policy DocumentView sehingga document publik bisa dirender anonim (userID 0).
+	Render(documentID uint64, userID uint64, format Format, query *request.RenderQuery) (*response.RenderResponse, error)
+
+	// MarkdownHTML render content markdown yang sudah diotorisasi, dipakai juga oleh share view.
+	// Embed ![[slug]] hanya menampilkan document workspace yang boleh dilihat subject.
+	MarkdownHTML(subject policy.Subject, workspace *schema.Workspace, content string) (string, error)
+
+	// EmbedResolver resolve ![[slug]] ke content diagram terbaru, dipakai juga oleh export PDF
+	EmbedResolver(subject policy.Subject, workspace *schema.Workspace) markdown.EmbedResolver
+}
+
+type renderService struct {
+	documentRepo  document_repo.DocumentRepository
+	versionRepo   document_repo.DocumentVersionRepository
+	workspaceRepo workspace_repo.WorkspaceRepository
+	shareRepo     share_repo.ShareRepository
+	storage       storage.Storage
+	policy        policy.Policy
+}
+
+// NewRenderService instance
+func NewRenderService(
+	documentRepo document_repo.DocumentRepository,
+	versionRepo document_repo.DocumentVersionRepository,
+	workspaceRepo workspace_repo.WorkspaceRepository,
+	shareRepo share_repo.ShareRepository,
+	storage storage.Storage,
+	policy policy.Policy,
+) RenderService {
+	return &renderService{
+		documentRepo:  documentRepo,
+		versionRepo:   versionRepo,
+		workspaceRepo: workspaceRepo,
+		shareRepo:     shareRepo,
+		storage:       storage,
+		policy:        policy,
+	}
+}
+
+func (_i *renderService) Render(documentID uint64, userID uint64, format Format, query *request.RenderQuery) (*response.RenderResponse, error) {
+	// Share link sama seperti halaman share, hanya versi terakhir tanpa riwayat
+	if query.Token != "" && query.Version != 0 {
+		return nil, ErrVersionNotShared
+	}
+
+	document, workspace, err := _i.authorize(documentID, userID, query.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == FormatHTML {
+		return _i.renderHTML(document, workspace, userID, query)
+	}
+
+	if document.Type != schema.DocumentTypeMermaid {
+		return nil, ErrNotMermaid
+	}
+
+	version, err := _i.findVersion(document.ID, query.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := 1.0
+	if format == FormatPNG && query.Scale > 0 {
+		scale = query.Scale
+	}
+
+	// Key cache dari content, bukan nomor versi, agar versi dengan content sama
+	// dan document hasil restore memakai render yang sama
+	key := helpers.Hash([]byte(render.Version + "\x00" + string(format) + "\x00" +
+		strconv.FormatFloat(scale, 'f', -1, 64) + "\x00" + version.Content))
+	path := "renders/" + key + "." + string(format)
+
+	content, err := _i.cached(path)
+	if err != nil {
+		content, err = _i.render(version.Content, format, scale)
+		if err != nil {
+			return nil, err
+		}
+
+		// Gagal menyimpan cache tidak menggagalkan request, render ulang di request berikutnya
+		if _, err := _i.storage.Upload(context.Background(), path, bytes.NewReader(content)); err != nil {
+			log.Warn().Err(err).Str("path", path).Msg("failed to cache rendered document")
+		}
+	}
+
+	return &response.RenderResponse{
+		Content:     content,
+		ContentType: contentTypes[format],
+		ETag:        key,
+		Public:      query.Token == "" && (workspace.IsPublic || document.IsPublic),
+	}, nil
+}
+
+func (_i *renderService) MarkdownHTML(subject policy.Subject, workspace *schema.Workspace, content string) (string, error) {
+	return markdown.Render(content, _i.EmbedResolver(subject, workspace))
+}
+
+func (_i *renderService) EmbedResolver(subject policy.Subject, workspace *schema.Workspace) markdown.EmbedResolver {
+	return func(slug string) (string, bool, error) {
+		embedded, err := _i.documentRepo.FindBySlug(workspace.ID, slug)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", false, nil
+			}
+			return "", false, err
+		}
+
+		if embedded.Type != schema.DocumentTypeMermaid {
+			return "", false, nil
+		}
+
+		allowed, err := _i.policy.Can(subject, policy.DocumentView, policy.DocumentResource(workspace, embedded))
+		if err != nil || !allowed {
+			return "", false, err
+		}
+
+		latest, err := _i.versionRepo.FindLatest(embedded.ID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", false, nil
+			}
+			return "", false, err
+		}
+
+		return latest.Content, true, nil
+	}
+}
+
+// Helper: render document markdown menjadi HTML. Tidak di-cache karena hasil embed
+// bergantung pada isi document lain dan akses pembaca.
+func (_i *renderService) renderHTML(document *schema.Document, workspace *schema.Workspace, userID uint64, query *request.RenderQuery) (*response.RenderResponse, error) {
+	if document.Type != schema.DocumentTypeMarkdown {
+		return nil, ErrNotMarkdown
+	}
+
+	version, err := _i.findVersion(document.ID, query.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pembaca via share link dianggap anonim
+	subject := policy.User(userID)
+	if query.Token != "" {
+		subject = policy.Subject{}
+	}
+
+	html, err := _i.MarkdownHTML(subject, workspace, version.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.RenderResponse{
+		Content:     []byte(html),
+		ContentType: contentTypes[FormatHTML],
+		ETag:        helpers.Hash([]byte(html)),
+		// Embed privat bisa ikut tampil untuk user yang login, hanya render anonim yang boleh di-cache publik
+		Public: subject.UserID == 0 && query.Token == "" && (workspace.IsPublic || document.IsPublic),
+	}, nil
+}
+
+// Helper: otorisasi via share link jika token dikirim, selain itu via policy DocumentView
+func (_i *renderService) authorize(documentID uint64, userID uint64, token string) (*schema.Document, *schema.Workspace, error) {
+	document, err := _i.documentRepo.FindByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, policy.ErrDocumentNotFound
+		}
+		return nil, nil, err
+	}
+
+	workspace, err := _i.workspaceRepo.FindByID(document.WorkspaceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, policy.ErrDocumentNotFound
+		}
+		return nil, nil, err
+	}
+
+	if token == "" {
+		allowed, err := _i.policy.Can(policy.User(userID), policy.DocumentView, policy.DocumentResource(workspace, document))
+		if err != nil {
+			return nil, nil, err
+		}
+		if !allowed {
+			return nil, nil, policy.Denied(policy.DocumentView)
+		}
+		return document, workspace, nil
+	}
+
+	share, err := _i.shareRepo.FindByToken(token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, policy.ErrShareLinkNotFound
+		}
+		return nil, nil, err
+	}
+
+	if policy.Expired(share) {
+		return nil, nil, policy.ErrShareLinkExpired
+	}
+
+	// Share link document lain ditolak oleh policy dan dianggap tidak ada
+	allowed, err := _i.policy.Can(policy.Subject{}, policy.ShareView, policy.ShareResource(workspace, document, share))
+	if err != nil {
+		return nil, nil, err
+	}
+	if !allowed {
+		return nil, nil, policy.Denied(policy.ShareView)
+	}
+
+	return document, workspace, nil
+}
+
+// Helper: versi tertentu, atau versi terakhir jika number 0
+func (_i *renderService) findVersion(documentID uint64, number int) (*schema.DocumentVersion, error) {
+	var version *schema.DocumentVersion
+	var err error
+	if number > 0 {
+		version, err = _i.versionRepo.FindByNumber(documentID, number)
+	} else {
+		version, err = _i.versionRepo.FindLatest(documentID)
+	}
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+
+	return version, nil
+}
+
+// Helper: baca hasil render dari storage, error jika belum ada
+func (_i *renderService) cached(path string) ([]byte, error) {
+	if _, err := _i.storage.Stat(path); err != nil {
+		return nil, err
+	}
+
+	file, err := _i.storage.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+// Helper: parse dan render content. Content dengan syntax error tidak bisa digambar
+// walaupun workspace memakai mermaid_validation warn atau off.
+func (_i *renderService) render(content string, format Format, scale float64) ([]byte, error) {
+	graph, diags := mermaid.Parse(content)
+	if mermaid.HasErrors(diags) {
+		return nil, lint_service.ErrInvalidMermaid.WithData(&lint_response.InvalidContentResponse{Diagnostics: diags})
+	}
+
+	var out []byte
+	var err error
+	if format == FormatPNG {
+		out, err = render.PNG(graph, scale)
+	} else {
+		out, err = render.SVG(graph)
+	}
+
+	if errors.Is(err, render.ErrUnsupported) {
+		return nil, ErrUnsupportedDiagram
+	}
+	return out, err
+}
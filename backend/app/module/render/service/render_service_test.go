@@ -0,0 +1,19 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/render/request"
+)
+
+func TestRenderShareLinkRejectsVersion(t *testing.T) {
+	svc := &renderService{}
+
+	for _, format := range []Format{FormatSVG, FormatPNG, FormatHTML} {
+		_, err := svc.Render(1, 0, format, &request.RenderQuery{Token: "token", Version: 1})
+		if !errors.Is(err, ErrVersionNotShared) {
+			t.Fatalf("Render(%s, token, version=1) error = %v, want ErrVersionNotShared", format, err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/scim/service"
+	"go.uber.org/fx"
+)
+
+// Controller aggregator
+type Controller struct {
+	User      UserControllerI
+	Group     GroupControllerI
+	Discovery DiscoveryControllerI
+}
+
+// NewController
+func NewController(userController UserControllerI, groupController GroupControllerI, discoveryController DiscoveryControllerI) *Controller {
+	return &Controller{
+		User:      userController,
+		Group:     groupController,
+		Discovery: discoveryController,
+	}
+}
+
+var Module = fx.Options(
+	fx.Provide(func(userService service.UserService) UserControllerI {
+		return NewUserController(userService)
+	}),
+	fx.Provide(func(groupService service.GroupService) GroupControllerI {
+		return NewGroupController(groupService)
+	}),
+	fx.Provide(NewDiscoveryController),
+	fx.Provide(NewController),
+)
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/scim/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// DiscoveryController endpoint discovery SCIM yang dibaca IdP saat koneksi diuji
+type discoveryController struct{}
+
+type DiscoveryControllerI interface {
+	ServiceProviderConfig(c *fiber.Ctx) error
+	ResourceTypes(c *fiber.Ctx) error
+}
+
+func NewDiscoveryController() DiscoveryControllerI {
+	return &discoveryController{}
+}
+
+// ServiceProviderConfig fitur SCIM yang didukung
+func (_i *discoveryController) ServiceProviderConfig(c *fiber.Ctx) error {
+	return response.Send(c, fiber.StatusOK, response.ServiceProviderConfig{
+		Schemas: []string{response.SchemaServiceProviderConfig},
+		Patch:   response.Supported{Supported: true},
+		Bulk:    response.BulkSupported{Supported: false},
+		Filter:  response.FilterSupported{Supported: true, MaxResults: 100},
+		AuthenticationSchemes: []response.AuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Bearer Token",
+			Description: "Authentication with the token configured in scim.token",
+			Primary:     true,
+		}},
+	})
+}
+
+// ResourceTypes resource yang tersedia
+func (_i *discoveryController) ResourceTypes(c *fiber.Ctx) error {
+	resourceTypes := []response.ResourceType{
+		{
+			Schemas:  []string{response.SchemaResourceType},
+			ID:       "User",
+			Name:     "User",
+			Endpoint: "/Users",
+			Schema:   response.SchemaUser,
+		},
+		{
+			Schemas:  []string{response.SchemaResourceType},
+			ID:       "Group",
+			Name:     "Group",
+			Endpoint: "/Groups",
+			Schema:   response.SchemaGroup,
+		},
+	}
+
+	return response.Send(c, fiber.StatusOK, response.ListResponse{
+		Schemas:      []string{response.SchemaListResponse},
+		TotalResults: int64(len(resourceTypes)),
+		StartIndex:   1,
+		ItemsPerPage: len(resourceTypes),
+		Resources:    resourceTypes,
+	})
+}
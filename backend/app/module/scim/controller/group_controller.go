@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/scim/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/scim/response"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/scim/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GroupController
+type groupController struct {
+	groupService service.GroupService
+}
+
+type GroupControllerI interface {
+	ListGroups(c *fiber.Ctx) error
+	GetGroup(c *fiber.Ctx) error
+	CreateGroup(c *fiber.Ctx) error
+	ReplaceGroup(c *fiber.Ctx) error
+	PatchGroup(c *fiber.Ctx) error
+	DeleteGroup(c *fiber.Ctx) error
+}
+
+func NewGroupController(groupService service.GroupService) GroupControllerI {
+	return &groupController{
+		groupService: groupService,
+	}
+}
+
+// ListGroups handler untuk list group, mendukung filter displayName eq
+func (_i *groupController) ListGroups(c *fiber.Ctx) error {
+	var query request.ListQuery
+	if err := c.QueryParser(&query); err != nil {
+		return ErrInvalidSyntax
+	}
+
+	result, err := _i.groupService.ListGroups(&query)
+	if err != nil {
+		return err
+	}
+
+	return response.Send(c, fiber.StatusOK, result)
+}
+
+// GetGroup handler untuk detail group beserta member
+func (_i *groupController) GetGroup(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return service.ErrGroupNotFound
+	}
+
+	var query request.ListQuery
+	if err := c.QueryParser(&query); err != nil {
+		return ErrInvalidSyntax
+	}
+
+	result, err := _i.groupService.GetGroup(id, !service.ExcludesMembers(query.ExcludedAttributes))
+	if err != nil {
+		return err
+	}
+
+	return response.Send(c, fiber.StatusOK, result)
+}
+
+// CreateGroup handler untuk membuat workspace dari group IdP
+func (_i *groupController) CreateGroup(c *fiber.Ctx) error {
+	var req request.Group
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	result, err := _i.groupService.CreateGroup(&req)
+	if err != nil {
+		return err
+	}
+
+	return response.Send(c, fiber.StatusCreated, result)
+}
+
+// ReplaceGroup handler untuk mengganti nama dan seluruh member group (PUT)
+func (_i *groupController) ReplaceGroup(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return service.ErrGroupNotFound
+	}
+
+	var req request.Group
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	result, err := _i.groupService.ReplaceGroup(id, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Send(c, fiber.StatusOK, result)
+}
+
+// PatchGroup handler untuk menambah atau menghapus member dan mengganti nama group
+func (_i *groupController) PatchGroup(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return service.ErrGroupNotFound
+	}
+
+	var req request.Patch
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	result, err := _i.groupService.PatchGroup(id, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Send(c, fiber.StatusOK, result)
+}
+
+// DeleteGroup handler untuk menghapus workspace group
+func (_i *groupController) DeleteGroup(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return service.ErrGroupNotFound
+	}
+
+	if err := _i.groupService.DeleteGroup(id); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/scim/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/scim/response"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/scim/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrInvalidSyntax body bukan JSON SCIM yang valid
+var ErrInvalidSyntax = apperr.BadRequest("scim_invalid_syntax", "request body is not valid SCIM JSON")
+
+// UserController
+type userController struct {
+	userService service.UserService
+}
+
+type UserControllerI interface {
+	ListUsers(c *fiber.Ctx) error
+	GetUser(c *fiber.Ctx) error
+	CreateUser(c *fiber.Ctx) error
+	ReplaceUser(c *fiber.Ctx) error
+	PatchUser(c *fiber.Ctx) error
+	DeleteUser(c *fiber.Ctx) error
+}
+
+func NewUserController(userService service.UserService) UserControllerI {
+	return &userController{
+		userService: userService,
+	}
+}
+
+// ListUsers handler untuk list user, mendukung filter userName/externalId eq
+func (_i *userController) ListUsers(c *fiber.Ctx) error {
+	var query request.ListQuery
+	if err := c.QueryParser(&query); err != nil {
+		return ErrInvalidSyntax
+	}
+
+	result, err := _i.userService.ListUsers(&query)
+	if err != nil {
+		return err
+	}
+
+	return response.Send(c, fiber.StatusOK, result)
+}
+
+// GetUser handler untuk detail user
+func (_i *userController) GetUser(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return service.ErrUserNotFound
+	}
+
+	result, err := _i.userService.GetUser(id)
+	if err != nil {
+		return err
+	}
+
+	return response.Send(c, fiber.StatusOK, result)
+}
+
+// CreateUser handler untuk provisioning user baru
+func (_i *userController) CreateUser(c *fiber.Ctx) error {
+	var req request.User
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	result, err := _i.userService.CreateUser(&req)
+	if err != nil {
+		return err
+	}
+
+	return response.Send(c, fiber.StatusCreated, result)
+}
+
+// ReplaceUser handler untuk mengganti seluruh attribute user (PUT)
+func (_i *userController) ReplaceUser(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return service.ErrUserNotFound
+	}
+
+	var req request.User
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	result, err := _i.userService.ReplaceUser(id, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Send(c, fiber.StatusOK, result)
+}
+
+// PatchUser handler untuk perubahan sebagian, termasuk active=false saat deprovisioning
+func (_i *userController) PatchUser(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return service.ErrUserNotFound
+	}
+
+	var req request.Patch
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	result, err := _i.userService.PatchUser(id, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Send(c, fiber.StatusOK, result)
+}
+
+// DeleteUser handler untuk deprovisioning, user dinonaktifkan bukan dihapus
+func (_i *userController) DeleteUser(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return service.ErrUserNotFound
+	}
+
+	if err := _i.userService.DeleteUser(id); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// parseID id resource SCIM adalah id numerik dalam bentuk string
+func parseID(c *fiber.Ctx) (uint64, error) {
+	return strconv.ParseUint(c.Params("id"), 10, 64)
+}
+
+// parseBody body di-decode langsung karena IdP tidak selalu mengirim Content-Type
+func parseBody(c *fiber.Ctx, body any) error {
+	if err := json.Unmarshal(c.Body(), body); err != nil {
+		return ErrInvalidSyntax
+	}
+	return nil
+}
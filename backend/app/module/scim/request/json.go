@@ -0,0 +1,88 @@
+package request
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Bool boolean SCIM, sebagian IdP (Microsoft Entra) mengirim "True"/"False" sebagai string
+type Bool bool
+
+func (_i *Bool) UnmarshalJSON(data []byte) error {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		*_i = Bool(b)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	switch strings.ToLower(s) {
+	case "true":
+		*_i = true
+	case "false":
+		*_i = false
+	default:
+		return &json.UnsupportedValueError{Str: s}
+	}
+
+	return nil
+}
+
+type Name struct {
+	Formatted  string `json:"formatted"`
+	GivenName  string `json:"givenName"`
+	FamilyName string `json:"familyName"`
+}
+
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type"`
+	Primary Bool   `json:"primary"`
+}
+
+// User resource urn:ietf:params:scim:schemas:core:2.0:User
+type User struct {
+	Schemas     []string `json:"schemas"`
+	ExternalID  string   `json:"externalId"`
+	UserName    string   `json:"userName"`
+	Name        *Name    `json:"name"`
+	DisplayName string   `json:"displayName"`
+	Emails      []Email  `json:"emails"`
+	Active      *Bool    `json:"active"`
+}
+
+type Member struct {
+	Value string `json:"value"`
+}
+
+// Group resource urn:ietf:params:scim:schemas:core:2.0:Group
+type Group struct {
+	Schemas     []string `json:"schemas"`
+	ExternalID  string   `json:"externalId"`
+	DisplayName string   `json:"displayName"`
+	Members     []Member `json:"members"`
+}
+
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Patch request urn:ietf:params:scim:api:messages:2.0:PatchOp
+type Patch struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// ListQuery query parameter list resource
+type ListQuery struct {
+	Filter             string `query:"filter"`
+	StartIndex         int    `query:"startIndex"`
+	Count              int    `query:"count"`
+	ExcludedAttributes string `query:"excludedAttributes"`
+}
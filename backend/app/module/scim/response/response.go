@@ -0,0 +1,56 @@
+package response
+
+import (
+	"errors"
+	"strconv"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// scimTypes code error aplikasi ke scimType RFC 7644 section 3.12
+var scimTypes = map[string]string{
+	"scim_invalid_filter": "invalidFilter",
+	"scim_invalid_syntax": "invalidSyntax",
+	"scim_invalid_path":   "invalidPath",
+	"scim_invalid_value":  "invalidValue",
+	"scim_user_exists":    "uniqueness",
+	"scim_group_exists":   "uniqueness",
+}
+
+// Send response SCIM dengan media type application/scim+json
+func Send(c *fiber.Ctx, status int, body any) error {
+	c.Status(status)
+	return c.JSON(body, ContentType)
+}
+
+// HandleErrors middleware yang mengubah error handler berikutnya menjadi
+// response error SCIM, IdP tidak memahami format response.Response aplikasi
+func HandleErrors(c *fiber.Ctx) error {
+	err := c.Next()
+	if err == nil {
+		return nil
+	}
+
+	status := fiber.StatusInternalServerError
+	res := Error{
+		Schemas: []string{SchemaError},
+		Detail:  "internal server error",
+	}
+
+	var fiberErr *fiber.Error
+	if e, ok := apperr.As(err); ok {
+		status = e.Status()
+		res.ScimType = scimTypes[e.Code]
+		res.Detail = e.Message
+	} else if errors.As(err, &fiberErr) {
+		status = fiberErr.Code
+		res.Detail = fiberErr.Message
+	} else {
+		log.Error().Err(err).Msg("scim request failed")
+	}
+
+	res.Status = strconv.Itoa(status)
+	return Send(c, status, res)
+}
@@ -0,0 +1,102 @@
+package scim
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/scim/controller"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/scim/response"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/scim/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/helpers"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+)
+
+// ErrUnauthorized bearer token SCIM tidak ada atau salah
+var ErrUnauthorized = apperr.Unauthorized("scim_unauthorized", "invalid scim bearer token")
+
+// ScimRouter adalah router untuk SCIM module
+type ScimRouter struct {
+	App        fiber.Router
+	Controller *controller.Controller
+	Cfg        *config.Config
+}
+
+// Module adalah FX module untuk provisioning SCIM 2.0.
+// Repository user dan workspace disediakan module auth dan workspace.
+var NewScimModule = fx.Options(
+	// register service
+	fx.Provide(service.NewUserService),
+	fx.Provide(service.NewGroupService),
+
+	// register controller
+	controller.Module,
+
+	// register router
+	fx.Provide(NewScimRouter),
+)
+
+// NewScimRouter membuat instance baru dari ScimRouter
+func NewScimRouter(
+	app *fiber.App,
+	ctrl *controller.Controller,
+	cfg *config.Config,
+) *ScimRouter {
+	return &ScimRouter{
+		App:        app,
+		Controller: ctrl,
+		Cfg:        cfg,
+	}
+}
+
+// RegisterScimRoutes mendaftarkan routes SCIM, tidak ada route jika scim.enable false
+func (_i *ScimRouter) RegisterScimRoutes() {
+	if !_i.Cfg.Scim.Enable {
+		return
+	}
+
+	// define controllers
+	userController := _i.Controller.User
+	groupController := _i.Controller.Group
+	discoveryController := _i.Controller.Discovery
+
+	_i.App.Route("/scim/v2", func(router fiber.Router) {
+		router.Use(response.HandleErrors, _i.requireToken)
+
+		router.Get("/ServiceProviderConfig", discoveryController.ServiceProviderConfig)
+		router.Get("/ResourceTypes", discoveryController.ResourceTypes)
+
+		router.Get("/Users", userController.ListUsers)
+		router.Post("/Users", userController.CreateUser)
+		router.Get("/Users/:id", userController.GetUser)
+		router.Put("/Users/:id", userController.ReplaceUser)
+		router.Patch("/Users/:id", userController.PatchUser)
+		router.Delete("/Users/:id", userController.DeleteUser)
+
+		router.Get("/Groups", groupController.ListGroups)
+		router.Post("/Groups", groupController.CreateGroup)
+		router.Get("/Groups/:id", groupController.GetGroup)
+		router.Put("/Groups/:id", groupController.ReplaceGroup)
+		router.Patch("/Groups/:id", groupController.PatchGroup)
+		router.Delete("/Groups/:id", groupController.DeleteGroup)
+	})
+}
+
+// requireToken cek header "Authorization: Bearer <scim.token>", dibandingkan
+// lewat hash agar waktu perbandingan tidak bergantung pada isi token
+func (_i *ScimRouter) requireToken(c *fiber.Ctx) error {
+	header := c.Get(fiber.HeaderAuthorization)
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return ErrUnauthorized
+	}
+
+	given := helpers.Hash([]byte(strings.TrimSpace(header[7:])))
+	expected := helpers.Hash([]byte(_i.Cfg.Scim.Token))
+	if subtle.ConstantTimeCompare([]byte(given), []byte(expected)) != 1 {
+		return ErrUnauthorized
+	}
+
+	return c.Next()
+}
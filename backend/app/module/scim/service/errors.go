@@ -0,0 +1,18 @@
+package service
+
+import "git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+
+// Error SCIM, code dipetakan ke scimType di response error SCIM
+var (
+	ErrUserNotFound       = apperr.NotFound("scim_user_not_found", "user not found")
+	ErrGroupNotFound      = apperr.NotFound("scim_group_not_found", "group not found")
+	ErrUserExists         = apperr.Conflict("scim_user_exists", "a user with this userName already exists")
+	ErrGroupExists        = apperr.Conflict("scim_group_exists", "a group with this displayName already exists")
+	ErrInvalidFilter      = apperr.BadRequest("scim_invalid_filter", "unsupported filter, only 'attribute eq \"value\"' is supported")
+	ErrInvalidEmail       = apperr.BadRequest("scim_invalid_value", "userName or emails must contain a valid email address")
+	ErrInvalidDisplayName = apperr.BadRequest("scim_invalid_value", "displayName is required and must be at most 255 characters")
+	ErrInvalidMember      = apperr.BadRequest("scim_invalid_value", "group member must be a provisioned user")
+	ErrInvalidPath        = apperr.BadRequest("scim_invalid_path", "unsupported patch path")
+	ErrInvalidOp          = apperr.BadRequest("scim_invalid_value", "unsupported patch operation")
+	ErrGroupsUnavailable  = apperr.Forbidden("scim_groups_unavailable", "scim groups require scim.owner_email to be an existing user")
+)
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// filterPattern satu-satunya bentuk filter yang didukung: <attribute> eq "<value>".
+// Bentuk ini yang dipakai IdP untuk mencari resource sebelum membuat yang baru.
+var filterPattern = regexp.MustCompile(`(?i)^\s*([a-z][a-z0-9._:]*)\s+eq\s+("(?:[^"\\]|\\.)*")\s*$`)
+
+// filter hasil parse, attribute dalam huruf kecil karena nama attribute SCIM case-insensitive
+type filter struct {
+	Attribute string
+	Value     string
+}
+
+// parseFilter parse filter list resource, filter kosong menghasilkan nil
+func parseFilter(raw string) (*filter, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	matches := filterPattern.FindStringSubmatch(raw)
+	if matches == nil {
+		return nil, ErrInvalidFilter
+	}
+
+	var value string
+	if err := json.Unmarshal([]byte(matches[2]), &value); err != nil {
+		return nil, ErrInvalidFilter
+	}
+
+	return &filter{
+		Attribute: strings.ToLower(matches[1]),
+		Value:     value,
+	}, nil
+}
@@ -0,0 +1,478 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/scim/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/scim/response"
+	user_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/user/repository"
+	workspace_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/helpers"
+	"gorm.io/gorm"
+)
+
+// memberValuePath path PATCH untuk satu member, contoh: members[value eq "12"]
+var memberValuePath = strings.ToLower(`members[value eq "`)
+
+// GroupService provisioning group SCIM. Setiap group adalah workspace milik
+// scim.owner_email dan anggotanya adalah member workspace tersebut. Owner
+// tidak ditampilkan sebagai member karena aksesnya tidak berasal dari IdP.
+type GroupService interface {
+	ListGroups(query *request.ListQuery) (*response.ListResponse, error)
+	GetGroup(id uint64, withMembers bool) (*response.Group, error)
+	CreateGroup(req *request.Group) (*response.Group, error)
+	ReplaceGroup(id uint64, req *request.Group) (*response.Group, error)
+	PatchGroup(id uint64, req *request.Patch) (*response.Group, error)
+	DeleteGroup(id uint64) error
+}
+
+type groupService struct {
+	userRepo      user_repo.UserRepository
+	workspaceRepo workspace_repo.WorkspaceRepository
+	memberRepo    workspace_repo.MemberRepository
+	cfg           *config.Config
+}
+
+// NewGroupService instance
+func NewGroupService(
+	userRepo user_repo.UserRepository,
+	workspaceRepo workspace_repo.WorkspaceRepository,
+	memberRepo workspace_repo.MemberRepository,
+	cfg *config.Config,
+) GroupService {
+	return &groupService{
+		userRepo:      userRepo,
+		workspaceRepo: workspaceRepo,
+		memberRepo:    memberRepo,
+		cfg:           cfg,
+	}
+}
+
+func (_i *groupService) ListGroups(query *request.ListQuery) (*response.ListResponse, error) {
+	owner, err := _i.owner()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := parseFilter(query.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var name string
+	if f != nil {
+		if strings.TrimPrefix(f.Attribute, strings.ToLower(response.SchemaGroup)+":") != "displayname" {
+			return nil, ErrInvalidFilter
+		}
+		if name = strings.TrimSpace(f.Value); name == "" {
+			return listResponse([]response.Group{}, 0, 1), nil
+		}
+	}
+
+	startIndex, count := page(query)
+
+	total, err := _i.workspaceRepo.CountByOwnerID(owner.ID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces, err := _i.workspaceRepo.FindByOwnerID(owner.ID, name, count, startIndex-1)
+	if err != nil {
+		return nil, err
+	}
+
+	withMembers := !ExcludesMembers(query.ExcludedAttributes)
+	resources := make([]response.Group, 0, len(workspaces))
+	for i := range workspaces {
+		res, err := _i.toGroupResponse(&workspaces[i], withMembers)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, *res)
+	}
+
+	return listResponse(resources, total, startIndex), nil
+}
+
+func (_i *groupService) GetGroup(id uint64, withMembers bool) (*response.Group, error) {
+	workspace, err := _i.findGroup(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return _i.toGroupResponse(workspace, withMembers)
+}
+
+func (_i *groupService) CreateGroup(req *request.Group) (*response.Group, error) {
+	owner, err := _i.owner()
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := _i.checkName(req.DisplayName, owner.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs, err := _i.memberIDs(req.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, err := _i.workspaceRepo.Create(&schema.Workspace{
+		Name:    name,
+		OwnerID: owner.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := _i.syncMembers(workspace, userIDs); err != nil {
+		return nil, err
+	}
+
+	return _i.toGroupResponse(workspace, true)
+}
+
+func (_i *groupService) ReplaceGroup(id uint64, req *request.Group) (*response.Group, error) {
+	workspace, err := _i.findGroup(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if workspace.Name, err = _i.checkName(req.DisplayName, workspace.OwnerID, workspace.ID); err != nil {
+		return nil, err
+	}
+
+	userIDs, err := _i.memberIDs(req.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := _i.workspaceRepo.Update(workspace); err != nil {
+		return nil, err
+	}
+
+	if err := _i.syncMembers(workspace, userIDs); err != nil {
+		return nil, err
+	}
+
+	return _i.toGroupResponse(workspace, true)
+}
+
+// PatchGroup operasi patch diterapkan ke nama dan daftar member saat ini,
+// perubahan member baru ditulis setelah semua operasi valid
+func (_i *groupService) PatchGroup(id uint64, req *request.Patch) (*response.Group, error) {
+	workspace, err := _i.findGroup(id)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := _i.memberRepo.FindByWorkspaceID(workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[uint64]bool, len(current))
+	for _, member := range current {
+		members[member.UserID] = true
+	}
+
+	name := workspace.Name
+	for _, op := range req.Operations {
+		if err := _i.patchGroup(&name, members, op); err != nil {
+			return nil, err
+		}
+	}
+
+	if name != workspace.Name {
+		if workspace.Name, err = _i.checkName(name, workspace.OwnerID, workspace.ID); err != nil {
+			return nil, err
+		}
+		if err := _i.workspaceRepo.Update(workspace); err != nil {
+			return nil, err
+		}
+	}
+
+	userIDs := make([]uint64, 0, len(members))
+	for userID := range members {
+		userIDs = append(userIDs, userID)
+	}
+
+	if err := _i.syncMembers(workspace, userIDs); err != nil {
+		return nil, err
+	}
+
+	return _i.toGroupResponse(workspace, true)
+}
+
+// DeleteGroup soft delete workspace, dokumen di dalamnya masih bisa dipulihkan dari database
+func (_i *groupService) DeleteGroup(id uint64) error {
+	workspace, err := _i.findGroup(id)
+	if err != nil {
+		return err
+	}
+
+	return _i.workspaceRepo.Delete(workspace.ID)
+}
+
+func (_i *groupService) patchGroup(name *string, members map[uint64]bool, op request.PatchOperation) error {
+	operation := strings.ToLower(op.Op)
+	if operation != "add" && operation != "replace" && operation != "remove" {
+		return ErrInvalidOp
+	}
+
+	path := strings.TrimPrefix(strings.ToLower(op.Path), strings.ToLower(response.SchemaGroup)+":")
+
+	switch {
+	case path == "":
+		// Tanpa path, value berisi attribute yang diganti, contoh {"displayName": "..."}
+		if operation == "remove" {
+			return ErrInvalidPath
+		}
+
+		var value request.Group
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return ErrInvalidOp
+		}
+		if value.DisplayName != "" {
+			*name = value.DisplayName
+		}
+		if value.Members != nil {
+			return _i.patchMembers(members, operation, value.Members)
+		}
+		return nil
+
+	case path == "displayname":
+		if operation == "remove" {
+			return ErrInvalidPath
+		}
+		if err := json.Unmarshal(op.Value, name); err != nil {
+			return ErrInvalidOp
+		}
+		return nil
+
+	case path == "members":
+		var values []request.Member
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &values); err != nil {
+				return ErrInvalidOp
+			}
+		}
+
+		// remove tanpa value menghapus semua member
+		if operation == "remove" && len(values) == 0 {
+			clear(members)
+			return nil
+		}
+		return _i.patchMembers(members, operation, values)
+
+	case strings.HasPrefix(path, memberValuePath) && strings.HasSuffix(path, `"]`):
+		if operation != "remove" {
+			return ErrInvalidPath
+		}
+		value := op.Path[len(memberValuePath) : len(op.Path)-2]
+		return _i.patchMembers(members, operation, []request.Member{{Value: value}})
+	}
+
+	return ErrInvalidPath
+}
+
+func (_i *groupService) patchMembers(members map[uint64]bool, operation string, values []request.Member) error {
+	// Member yang dihapus tidak perlu divalidasi, user-nya mungkin sudah tidak ada
+	if operation == "remove" {
+		for _, value := range values {
+			if userID, err := strconv.ParseUint(value.Value, 10, 64); err == nil {
+				delete(members, userID)
+			}
+		}
+		return nil
+	}
+
+	userIDs, err := _i.memberIDs(values)
+	if err != nil {
+		return err
+	}
+
+	if operation == "replace" {
+		clear(members)
+	}
+	for _, userID := range userIDs {
+		members[userID] = true
+	}
+
+	return nil
+}
+
+// syncMembers samakan member workspace dengan userIDs. Role member yang sudah
+// ada tidak diubah, member baru mendapat scim.member_role.
+func (_i *groupService) syncMembers(workspace *schema.Workspace, userIDs []uint64) error {
+	current, err := _i.memberRepo.FindByWorkspaceID(workspace.ID)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[uint64]bool, len(userIDs))
+	for _, userID := range userIDs {
+		// Owner sudah punya akses penuh tanpa baris member
+		if userID != workspace.OwnerID {
+			wanted[userID] = true
+		}
+	}
+
+	for _, member := range current {
+		if wanted[member.UserID] {
+			delete(wanted, member.UserID)
+			continue
+		}
+		if err := _i.memberRepo.Delete(member.ID); err != nil {
+			return err
+		}
+	}
+
+	for userID := range wanted {
+		if _, err := _i.memberRepo.Create(&schema.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      userID,
+			Role:        _i.memberRole(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// memberIDs validasi member group, harus user manusia yang ada
+func (_i *groupService) memberIDs(values []request.Member) ([]uint64, error) {
+	userIDs := make([]uint64, 0, len(values))
+	for _, value := range values {
+		userID, err := strconv.ParseUint(value.Value, 10, 64)
+		if err != nil {
+			return nil, ErrInvalidMember
+		}
+
+		if _, err := _i.userRepo.FindHumanUser(userID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrInvalidMember
+			}
+			return nil, err
+		}
+
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, nil
+}
+
+func (_i *groupService) checkName(displayName string, ownerID uint64, excludeID uint64) (string, error) {
+	name := strings.TrimSpace(displayName)
+	if name == "" || len(name) > 255 {
+		return "", ErrInvalidDisplayName
+	}
+
+	if _i.workspaceRepo.CheckNameExists(name, ownerID, excludeID) {
+		return "", ErrGroupExists
+	}
+
+	return name, nil
+}
+
+// findGroup workspace yang bukan milik owner SCIM dianggap tidak ada
+func (_i *groupService) findGroup(id uint64) (*schema.Workspace, error) {
+	owner, err := _i.owner()
+	if err != nil {
+		return nil, err
+	}
+
+	workspace, err := _i.workspaceRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGroupNotFound
+		}
+		return nil, err
+	}
+
+	if workspace.OwnerID != owner.ID {
+		return nil, ErrGroupNotFound
+	}
+
+	return workspace, nil
+}
+
+func (_i *groupService) owner() (*schema.User, error) {
+	email := helpers.NormalizeEmail(_i.cfg.Scim.OwnerEmail)
+	if email == "" {
+		return nil, ErrGroupsUnavailable
+	}
+
+	owner, err := _i.userRepo.FindUserByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGroupsUnavailable
+		}
+		return nil, err
+	}
+
+	return owner, nil
+}
+
+func (_i *groupService) memberRole() schema.WorkspaceRole {
+	if _i.cfg.Scim.MemberRole == "" {
+		return schema.WorkspaceRoleEditor
+	}
+	return schema.WorkspaceRole(_i.cfg.Scim.MemberRole)
+}
+
+// Helper: convert schema to response
+func (_i *groupService) toGroupResponse(workspace *schema.Workspace, withMembers bool) (*response.Group, error) {
+	res := &response.Group{
+		Schemas:     []string{response.SchemaGroup},
+		ID:          strconv.FormatUint(workspace.ID, 10),
+		DisplayName: workspace.Name,
+		Meta: response.Meta{
+			ResourceType: "Group",
+			Created:      workspace.CreatedAt,
+			LastModified: workspace.UpdatedAt,
+		},
+	}
+
+	if !withMembers {
+		return res, nil
+	}
+
+	members, err := _i.memberRepo.FindByWorkspaceID(workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Members = make([]response.Member, 0, len(members))
+	for _, member := range members {
+		display := ""
+		if member.User != nil {
+			display = member.User.Name
+		}
+		res.Members = append(res.Members, response.Member{
+			Value:   strconv.FormatUint(member.UserID, 10),
+			Display: display,
+		})
+	}
+
+	return res, nil
+}
+
+// ExcludesMembers true jika excludedAttributes berisi members, dipakai IdP
+// untuk list group tanpa memuat semua anggota
+func ExcludesMembers(excludedAttributes string) bool {
+	for _, attribute := range strings.Split(excludedAttributes, ",") {
+		if strings.EqualFold(strings.TrimSpace(attribute), "members") {
+			return true
+		}
+	}
+	return false
+}
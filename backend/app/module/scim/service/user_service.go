@@ -0,0 +1,443 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/scim/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/scim/response"
+	user_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/user/repository"
+	session_service "git.dev.siap.id/kukuhkkh/app-diagram/app/module/usersession/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/helpers"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 100
+)
+
+// UserService provisioning user SCIM. Hanya user manusia yang terlihat, service
+// account dikelola dari workspace. userName SCIM adalah email user.
+type UserService interface {
+	ListUsers(query *request.ListQuery) (*response.ListResponse, error)
+	GetUser(id uint64) (*response.User, error)
+	CreateUser(req *request.User) (*response.User, error)
+	ReplaceUser(id uint64, req *request.User) (*response.User, error)
+	PatchUser(id uint64, req *request.Patch) (*response.User, error)
+	DeleteUser(id uint64) error
+}
+
+type userService struct {
+	userRepo       user_repo.UserRepository
+	sessionService session_service.SessionService
+}
+
+// NewUserService instance
+func NewUserService(userRepo user_repo.UserRepository, sessionService session_service.SessionService) UserService {
+	return &userService{
+		userRepo:       userRepo,
+		sessionService: sessionService,
+	}
+}
+
+func (_i *userService) ListUsers(query *request.ListQuery) (*response.ListResponse, error) {
+	f, err := parseFilter(query.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var userFilter user_repo.UserFilter
+	if f != nil {
+		switch strings.TrimPrefix(f.Attribute, strings.ToLower(response.SchemaUser)+":") {
+		case "username", "emails", "emails.value":
+			userFilter.Email = helpers.NormalizeEmail(f.Value)
+		case "externalid":
+			userFilter.ScimExternalID = f.Value
+		default:
+			return nil, ErrInvalidFilter
+		}
+
+		// Nilai kosong tidak boleh berubah menjadi "tanpa filter"
+		if userFilter.Email == "" && userFilter.ScimExternalID == "" {
+			return listResponse([]response.User{}, 0, 1), nil
+		}
+	}
+
+	startIndex, count := page(query)
+
+	total, err := _i.userRepo.CountHumanUsers(userFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := _i.userRepo.FindHumanUsers(userFilter, count, startIndex-1)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]response.User, 0, len(users))
+	for i := range users {
+		resources = append(resources, toUserResponse(&users[i]))
+	}
+
+	return listResponse(resources, total, startIndex), nil
+}
+
+func (_i *userService) GetUser(id uint64) (*response.User, error) {
+	user, err := _i.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := toUserResponse(user)
+	return &res, nil
+}
+
+func (_i *userService) CreateUser(req *request.User) (*response.User, error) {
+	user := &schema.User{Kind: schema.UserKindHuman}
+	if err := _i.apply(user, req); err != nil {
+		return nil, err
+	}
+
+	user, err := _i.userRepo.CreateUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	res := toUserResponse(user)
+	return &res, nil
+}
+
+func (_i *userService) ReplaceUser(id uint64, req *request.User) (*response.User, error) {
+	user, err := _i.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return _i.save(user, req)
+}
+
+// PatchUser operasi patch diterapkan ke representasi SCIM user saat ini,
+// hasilnya disimpan dengan aturan yang sama seperti PUT
+func (_i *userService) PatchUser(id uint64, req *request.Patch) (*response.User, error) {
+	user, err := _i.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := toUserRequest(user)
+	for _, op := range req.Operations {
+		if err := patchUser(resource, op); err != nil {
+			return nil, err
+		}
+	}
+
+	return _i.save(user, resource)
+}
+
+// DeleteUser menonaktifkan user. Data user tidak dihapus karena masih
+// direferensikan dokumen, versi, dan workspace miliknya.
+func (_i *userService) DeleteUser(id uint64) error {
+	user, err := _i.findUser(id)
+	if err != nil {
+		return err
+	}
+
+	active := request.Bool(false)
+	resource := toUserRequest(user)
+	resource.Active = &active
+
+	_, err = _i.save(user, resource)
+	return err
+}
+
+func (_i *userService) save(user *schema.User, req *request.User) (*response.User, error) {
+	wasDisabled := user.Disabled
+	if err := _i.apply(user, req); err != nil {
+		return nil, err
+	}
+
+	if err := _i.userRepo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+
+	// Deprovisioning: session yang masih aktif ikut dicabut, personal access
+	// token langsung ditolak middleware karena akun disabled
+	if user.Disabled && !wasDisabled {
+		if err := _i.sessionService.RevokeUserSessions(user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	res := toUserResponse(user)
+	return &res, nil
+}
+
+// apply salin attribute SCIM ke user, user.ID 0 berarti user baru
+func (_i *userService) apply(user *schema.User, req *request.User) error {
+	email, ok := resolveEmail(req)
+	if !ok {
+		return ErrInvalidEmail
+	}
+
+	if email != user.Email {
+		existing, err := _i.userRepo.FindUserByEmail(email)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if existing != nil {
+			return ErrUserExists
+		}
+	}
+
+	externalID := strings.TrimSpace(req.ExternalID)
+	if externalID != "" && (user.ScimExternalID == nil || *user.ScimExternalID != externalID) {
+		count, err := _i.userRepo.CountHumanUsers(user_repo.UserFilter{ScimExternalID: externalID})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrUserExists
+		}
+	}
+
+	user.Email = email
+	user.Name = resolveName(req, email)
+	user.ScimExternalID = nil
+	if externalID != "" {
+		user.ScimExternalID = &externalID
+	}
+
+	// active tidak dikirim berarti aktif (RFC 7643 default)
+	user.Disabled = req.Active != nil && !bool(*req.Active)
+
+	return nil
+}
+
+func (_i *userService) findUser(id uint64) (*schema.User, error) {
+	user, err := _i.userRepo.FindHumanUser(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// patchUser terapkan satu operasi PATCH ke resource user
+func patchUser(resource *request.User, op request.PatchOperation) error {
+	operation := strings.ToLower(op.Op)
+	if operation != "add" && operation != "replace" && operation != "remove" {
+		return ErrInvalidOp
+	}
+
+	// Tanpa path, value berisi attribute yang diganti, contoh {"active": false}
+	if op.Path == "" {
+		if operation == "remove" {
+			return ErrInvalidPath
+		}
+
+		var attributes map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &attributes); err != nil {
+			return ErrInvalidOp
+		}
+		for path, value := range attributes {
+			if err := setUserAttribute(resource, path, value, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return setUserAttribute(resource, op.Path, op.Value, operation == "remove")
+}
+
+// setUserAttribute attribute yang tidak dikenal diabaikan, IdP sering mengirim
+// attribute yang tidak disimpan aplikasi seperti title atau extension enterprise
+func setUserAttribute(resource *request.User, path string, value json.RawMessage, remove bool) error {
+	path = strings.TrimPrefix(strings.ToLower(path), strings.ToLower(response.SchemaUser)+":")
+
+	var err error
+	switch {
+	case path == "active":
+		if remove {
+			resource.Active = nil
+			return nil
+		}
+		var active request.Bool
+		err = json.Unmarshal(value, &active)
+		resource.Active = &active
+	case path == "username":
+		if remove {
+			return ErrInvalidPath
+		}
+		err = json.Unmarshal(value, &resource.UserName)
+	case path == "displayname":
+		resource.DisplayName = ""
+		if !remove {
+			err = json.Unmarshal(value, &resource.DisplayName)
+		}
+	case path == "externalid":
+		resource.ExternalID = ""
+		if !remove {
+			err = json.Unmarshal(value, &resource.ExternalID)
+		}
+	case path == "name":
+		resource.Name = nil
+		if !remove {
+			err = json.Unmarshal(value, &resource.Name)
+		}
+	case strings.HasPrefix(path, "name."):
+		if resource.Name == nil {
+			resource.Name = &request.Name{}
+		}
+		var part string
+		if !remove {
+			err = json.Unmarshal(value, &part)
+		}
+		switch strings.TrimPrefix(path, "name.") {
+		case "formatted":
+			resource.Name.Formatted = part
+		case "givenname":
+			resource.Name.GivenName = part
+		case "familyname":
+			resource.Name.FamilyName = part
+		}
+	case path == "emails":
+		resource.Emails = nil
+		if !remove {
+			err = json.Unmarshal(value, &resource.Emails)
+		}
+	case strings.HasPrefix(path, "emails[") && strings.HasSuffix(path, "].value"):
+		// contoh: emails[type eq "work"].value, hanya satu email yang disimpan
+		if remove {
+			resource.Emails = nil
+			return nil
+		}
+		var email string
+		err = json.Unmarshal(value, &email)
+		resource.Emails = []request.Email{{Value: email, Primary: true}}
+	}
+
+	if err != nil {
+		return ErrInvalidOp
+	}
+
+	return nil
+}
+
+// resolveEmail email primary, lalu email pertama, lalu userName
+func resolveEmail(req *request.User) (string, bool) {
+	candidates := make([]string, 0, len(req.Emails)+1)
+	for _, email := range req.Emails {
+		if email.Primary {
+			candidates = append([]string{email.Value}, candidates...)
+		} else {
+			candidates = append(candidates, email.Value)
+		}
+	}
+	candidates = append(candidates, req.UserName)
+
+	for _, candidate := range candidates {
+		email := helpers.NormalizeEmail(candidate)
+		if email == "" {
+			continue
+		}
+		if addr, err := mail.ParseAddress(email); err == nil && addr.Address == email {
+			return email, true
+		}
+	}
+
+	return "", false
+}
+
+func resolveName(req *request.User, email string) string {
+	if name := strings.TrimSpace(req.DisplayName); name != "" {
+		return name
+	}
+
+	if req.Name != nil {
+		if name := strings.TrimSpace(req.Name.Formatted); name != "" {
+			return name
+		}
+		if name := strings.TrimSpace(req.Name.GivenName + " " + req.Name.FamilyName); name != "" {
+			return name
+		}
+	}
+
+	return strings.SplitN(email, "@", 2)[0]
+}
+
+// page startIndex SCIM dimulai dari 1
+func page(query *request.ListQuery) (startIndex int, count int) {
+	startIndex = query.StartIndex
+	if startIndex < 1 {
+		startIndex = 1
+	}
+
+	count = query.Count
+	if count <= 0 {
+		count = defaultPageSize
+	}
+	if count > maxPageSize {
+		count = maxPageSize
+	}
+
+	return startIndex, count
+}
+
+func listResponse[T any](resources []T, total int64, startIndex int) *response.ListResponse {
+	return &response.ListResponse{
+		Schemas:      []string{response.SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+// Helper: convert schema to request, dipakai sebagai dasar operasi PATCH
+func toUserRequest(user *schema.User) *request.User {
+	active := request.Bool(!user.Disabled)
+	req := &request.User{
+		UserName:    user.Email,
+		DisplayName: user.Name,
+		Emails:      []request.Email{{Value: user.Email, Type: "work", Primary: true}},
+		Active:      &active,
+	}
+	if user.ScimExternalID != nil {
+		req.ExternalID = *user.ScimExternalID
+	}
+
+	return req
+}
+
+// Helper: convert schema to response
+func toUserResponse(user *schema.User) response.User {
+	res := response.User{
+		Schemas:     []string{response.SchemaUser},
+		ID:          strconv.FormatUint(user.ID, 10),
+		UserName:    user.Email,
+		Name:        response.Name{Formatted: user.Name},
+		DisplayName: user.Name,
+		Emails:      []response.Email{{Value: user.Email, Type: "work", Primary: true}},
+		Active:      !user.Disabled,
+		Meta: response.Meta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+		},
+	}
+	if user.ScimExternalID != nil {
+		res.ExternalID = *user.ScimExternalID
+	}
+
+	return res
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/share/service"
+	"go.uber.org/fx"
+)
+
+// Controller aggregator
+type Controller struct {
+	Share ShareControllerI
+}
+
+// NewController
+func NewController(shareController ShareControllerI) *Controller {
+	return &Controller{
+		Share: shareController,
+	}
+}
+
+var Module = fx.Options(
+	fx.Provide(func(shareService service.ShareService) ShareControllerI {
+		return NewShareController(shareService)
+	}),
+	fx.Provide(NewController),
+)
@@ -0,0 +1,266 @@
+package controller
+
+import (
+	"strconv"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/share/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/share/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ShareController
+type shareController struct {
+	shareService service.ShareService
+}
+
+type ShareControllerI interface {
+	CreateLink(c *fiber.Ctx) error
+	ListLinks(c *fiber.Ctx) error
+	RevokeShare(c *fiber.Ctx) error
+	ShareWithUser(c *fiber.Ctx) error
+	ListUserShares(c *fiber.Ctx) error
+	SharedWithMe(c *fiber.Ctx) error
+	GetSharedDocument(c *fiber.Ctx) error
+	UpdateSharedDocument(c *fiber.Ctx) error
+}
+
+func NewShareController(shareService service.ShareService) ShareControllerI {
+	return &shareController{
+		shareService: shareService,
+	}
+}
+
+// CreateLink handler untuk membuat share link document
+func (_i *shareController) CreateLink(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_document_id", "invalid document id")
+	}
+
+	var req request.CreateShareLinkRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.ErrInvalidBody
+	}
+
+	// Validasi input
+	if err := response.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	result, err := _i.shareService.CreateLink(documentID, userID, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusCreated,
+		Messages: response.Messages{"share link created successfully"},
+		Data:     result,
+	})
+}
+
+// ListLinks handler untuk list share link document
+func (_i *shareController) ListLinks(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_document_id", "invalid document id")
+	}
+
+	result, err := _i.shareService.ListLinks(documentID, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"share links retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// RevokeShare handler untuk mencabut share link atau share ke user
+func (_i *shareController) RevokeShare(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_document_id", "invalid document id")
+	}
+
+	shareID, err := strconv.ParseUint(c.Params("shareId"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_share_id", "invalid share id")
+	}
+
+	if err := _i.shareService.RevokeShare(documentID, shareID, userID); err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"share revoked successfully"},
+	})
+}
+
+// ShareWithUser handler untuk share document ke user by email
+func (_i *shareController) ShareWithUser(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_document_id", "invalid document id")
+	}
+
+	var req request.ShareWithUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.ErrInvalidBody
+	}
+
+	// Validasi input
+	if err := response.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	result, err := _i.shareService.ShareWithUser(documentID, userID, &req)
+	if err != nil {
+		return err
+	}
+
+	message := "document shared successfully"
+	if result.Pending {
+		message = "invite saved, access is granted when the user first logs in"
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusCreated,
+		Messages: response.Messages{message},
+		Data:     result,
+	})
+}
+
+// ListUserShares handler untuk list user yang mendapat akses ke document
+func (_i *shareController) ListUserShares(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	documentID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_document_id", "invalid document id")
+	}
+
+	result, err := _i.shareService.ListUserShares(documentID, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"document users retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// SharedWithMe handler untuk list document yang dibagikan ke user yang login
+func (_i *shareController) SharedWithMe(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	// Parse query parameters untuk pagination
+	page := 1
+	limit := 10
+
+	if p := c.Query("page"); p != "" {
+		if parsedPage, err := strconv.Atoi(p); err == nil && parsedPage > 0 {
+			page = parsedPage
+		}
+	}
+
+	if l := c.Query("limit"); l != "" {
+		if parsedLimit, err := strconv.Atoi(l); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
+			limit = parsedLimit
+		}
+	}
+
+	result, err := _i.shareService.SharedWithMe(userID, page, limit)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"shared documents retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// GetSharedDocument handler publik untuk membuka document via token
+func (_i *shareController) GetSharedDocument(c *fiber.Ctx) error {
+	result, err := _i.shareService.GetSharedDocument(c.Params("token"))
+	if err != nil {
+		return err
+	}
+
+	// ETag dipakai client sebagai If-Match saat update
+	response.SetETag(c, strconv.Itoa(result.VersionNumber))
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"shared document retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// UpdateSharedDocument handler publik untuk edit document via token dengan permission edit
+func (_i *shareController) UpdateSharedDocument(c *fiber.Ctx) error {
+	var req request.UpdateSharedDocumentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.ErrInvalidBody
+	}
+
+	// Validasi input
+	if err := response.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	// Base version boleh dikirim lewat header If-Match
+	var err error
+	if req.BaseVersion, err = response.BaseVersion(c, req.BaseVersion); err != nil {
+		return err
+	}
+
+	result, err := _i.shareService.UpdateSharedDocument(c.Params("token"), &req)
+	if err != nil {
+		return err
+	}
+
+	response.SetETag(c, strconv.Itoa(result.VersionNumber))
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"shared document updated successfully"},
+		Data:     result,
+	})
+}
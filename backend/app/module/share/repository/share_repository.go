@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
+	"gorm.io/gorm"
+)
+
+// ShareRepository
+type ShareRepository interface {
+	Create(share *schema.SharedAccess) (*schema.SharedAccess, error)
+	FindByID(id uint64) (*schema.SharedAccess, error)
+	FindByToken(token string) (*schema.SharedAccess, error)
+	FindLinksByDocumentID(documentID uint64) ([]schema.SharedAccess, error)
+	Delete(id uint64) error
+
+	// Share ke user tertentu (langsung atau pending invite by email)
+	SaveGrant(share *schema.SharedAccess) (*schema.SharedAccess, error)
+	FindGrantsByDocumentID(documentID uint64) ([]schema.SharedAccess, error)
+	FindGrantsByUserID(userID uint64, limit, offset int) ([]schema.SharedAccess, error)
+	CountGrantsByUserID(userID uint64) (int64, error)
+	ClaimPendingInvites(email string, userID uint64) error
+}
+
+type shareRepository struct {
+	db *database.Database
+}
+
+func NewShareRepository(db *database.Database) ShareRepository {
+	return &shareRepository{
+		db: db,
+	}
+}
+
+func (_i *shareRepository) Create(share *schema.SharedAccess) (*schema.SharedAccess, error) {
+	if err := _i.db.DB.Create(share).Error; err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+func (_i *shareRepository) FindByID(id uint64) (*schema.SharedAccess, error) {
+	var share schema.SharedAccess
+	if err := _i.db.DB.Where("id = ?", id).First(&share).Error; err != nil {
+		return nil, err
+	}
+
+	return &share, nil
+}
+
+func (_i *shareRepository) FindByToken(token string) (*schema.SharedAccess, error) {
+	var share schema.SharedAccess
+	if err := _i.db.DB.Where("access_token = ?", token).
+		Where("user_id IS NULL AND invite_email IS NULL").
+		First(&share).Error; err != nil {
+		return nil, err
+	}
+
+	return &share, nil
+}
+
+// FindLinksByDocumentID list share link anonim (tanpa user) milik document
+func (_i *shareRepository) FindLinksByDocumentID(documentID uint64) ([]schema.SharedAccess, error) {
+	var shares []schema.SharedAccess
+	if err := _i.db.DB.Where("document_id = ? AND user_id IS NULL AND invite_email IS NULL", documentID).
+		Order("created_at DESC").
+		Find(&shares).Error; err != nil {
+		return nil, err
+	}
+
+	return shares, nil
+}
+
+func (_i *shareRepository) Delete(id uint64) error {
+	return _i.db.DB.Model(&schema.SharedAccess{}).
+		Where("id = ?", id).
+		Delete(&schema.SharedAccess{}).Error
+}
+
+// SaveGrant membuat atau memperbarui share untuk user/email pada document.
+// Baris yang sudah di-revoke (soft delete) dipakai ulang karena unique index
+// (document_id, user_id) tetap berlaku untuk baris yang terhapus.
+func (_i *shareRepository) SaveGrant(share *schema.SharedAccess) (*schema.SharedAccess, error) {
+	err := _i.db.DB.Transaction(func(tx *gorm.DB) error {
+		query := tx.Unscoped().Where("document_id = ?", share.DocumentID)
+		if share.UserID != nil {
+			query = query.Where("user_id = ?", *share.UserID)
+		} else {
+			query = query.Where("user_id IS NULL AND invite_email = ?", *share.InviteEmail)
+		}
+
+		var existing schema.SharedAccess
+		err := query.First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(share).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		existing.Permission = share.Permission
+		existing.ExpiresAt = share.ExpiresAt
+		existing.DeletedAt = gorm.DeletedAt{}
+		if err := tx.Unscoped().Save(&existing).Error; err != nil {
+			return err
+		}
+
+		*share = existing
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return share, nil
+}
+
+// FindGrantsByDocumentID list share ke user dan pending invite milik document
+func (_i *shareRepository) FindGrantsByDocumentID(documentID uint64) ([]schema.SharedAccess, error) {
+	var shares []schema.SharedAccess
+	if err := _i.db.DB.Preload("User").
+		Where("document_id = ? AND (user_id IS NOT NULL OR invite_email IS NOT NULL)", documentID).
+		Order("created_at DESC").
+		Find(&shares).Error; err != nil {
+		return nil, err
+	}
+
+	return shares, nil
+}
+
+// FindGrantsByUserID list document yang dibagikan ke user ("Shared with me"),
+// tanpa share yang expired atau document/workspace yang sudah dihapus
+func (_i *shareRepository) FindGrantsByUserID(userID uint64, limit, offset int) ([]schema.SharedAccess, error) {
+	var shares []schema.SharedAccess
+	if err := _i.grantsByUserQuery(userID).
+		Preload("Document").
+		Order("shared_access.created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&shares).Error; err != nil {
+		return nil, err
+	}
+
+	return shares, nil
+}
+
+func (_i *shareRepository) CountGrantsByUserID(userID uint64) (int64, error) {
+	var count int64
+	if err := _i.grantsByUserQuery(userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (_i *shareRepository) grantsByUserQuery(userID uint64) *gorm.DB {
+	return _i.db.DB.Model(&schema.SharedAccess{}).
+		Joins("JOIN documents ON documents.id = shared_access.document_id AND documents.deleted_at IS NULL").
+		Joins("JOIN workspaces ON workspaces.id = documents.workspace_id AND workspaces.deleted_at IS NULL").
+		Where("shared_access.user_id = ?", userID).
+		Where("shared_access.expires_at IS NULL OR shared_access.expires_at > ?", time.Now())
+}
+
+// ClaimPendingInvites memindahkan pending invite untuk email ke user yang baru login.
+// Jika user sudah punya share di document yang sama, permission tertinggi yang dipakai.
+func (_i *shareRepository) ClaimPendingInvites(email string, userID uint64) error {
+	return _i.db.DB.Transaction(func(tx *gorm.DB) error {
+		var invites []schema.SharedAccess
+		if err := tx.Where("user_id IS NULL AND invite_email = ?", email).Find(&invites).Error; err != nil {
+			return err
+		}
+
+		for _, invite := range invites {
+			var existing schema.SharedAccess
+			err := tx.Unscoped().Where("document_id = ? AND user_id = ?", invite.DocumentID, userID).First(&existing).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				if err := tx.Model(&invite).Updates(map[string]any{
+					"user_id":      userID,
+					"invite_email": nil,
+				}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			if existing.DeletedAt.Valid || invite.Permission == schema.PermissionEdit {
+				existing.Permission = invite.Permission
+				existing.ExpiresAt = invite.ExpiresAt
+			}
+			existing.DeletedAt = gorm.DeletedAt{}
+			if err := tx.Unscoped().Save(&existing).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(&invite).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,22 @@
+package request
+
+import "time"
+
+type CreateShareLinkRequest struct {
+	Permission string     `json:"permission" validate:"omitempty,oneof=view edit"`
+	ExpiresAt  *time.Time `json:"expires_at" validate:"omitempty"`
+}
+
+// UpdateSharedDocumentRequest BaseVersion version_number yang terakhir dilihat
+// client, boleh diganti header If-Match
+type UpdateSharedDocumentRequest struct {
+	BaseVersion       *int    `json:"base_version" validate:"omitempty,min=0"`
+	Content           string  `json:"content" validate:"omitempty"`
+	ChangeDescription *string `json:"change_description" validate:"omitempty,max=500"`
+}
+
+type ShareWithUserRequest struct {
+	Email      string     `json:"email" validate:"required,email,max=255"`
+	Permission string     `json:"permission" validate:"omitempty,oneof=view edit"`
+	ExpiresAt  *time.Time `json:"expires_at" validate:"omitempty"`
+}
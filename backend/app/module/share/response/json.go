@@ -0,0 +1,68 @@
+package response
+
+import (
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/mermaid"
+)
+
+type ShareLinkResponse struct {
+	ID          uint64            `json:"id"`
+	DocumentID  uint64            `json:"document_id"`
+	AccessToken string            `json:"access_token"`
+	Path        string            `json:"path"`
+	Permission  schema.Permission `json:"permission"`
+	ExpiresAt   *time.Time        `json:"expires_at"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+type SharedDocumentResponse struct {
+	ID            uint64              `json:"id"`
+	Title         string              `json:"title"`
+	Type          schema.DocumentType `json:"type"`
+	Slug          string              `json:"slug"`
+	Permission    schema.Permission   `json:"permission"`
+	VersionNumber int                 `json:"version_number"`
+	Content       string              `json:"content"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+
+	// HTML hasil render content markdown, hanya diisi di response GET
+	HTML string `json:"html,omitempty"`
+
+	// Diagnostics hasil validasi mermaid, hanya diisi di response update
+	Diagnostics []mermaid.Diagnostic `json:"diagnostics,omitempty"`
+}
+
+// ShareUserResponse share ke user tertentu; Pending true jika penerima belum pernah login
+type ShareUserResponse struct {
+	ID         uint64            `json:"id"`
+	DocumentID uint64            `json:"document_id"`
+	UserID     *uint64           `json:"user_id"`
+	Email      string            `json:"email"`
+	Name       string            `json:"name,omitempty"`
+	Pending    bool              `json:"pending"`
+	Permission schema.Permission `json:"permission"`
+	ExpiresAt  *time.Time        `json:"expires_at"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+type SharedWithMeResponse struct {
+	ShareID     uint64              `json:"share_id"`
+	DocumentID  uint64              `json:"document_id"`
+	WorkspaceID uint64              `json:"workspace_id"`
+	Title       string              `json:"title"`
+	Type        schema.DocumentType `json:"type"`
+	Slug        string              `json:"slug"`
+	Permission  schema.Permission   `json:"permission"`
+	ExpiresAt   *time.Time          `json:"expires_at"`
+	SharedAt    time.Time           `json:"shared_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+type SharedWithMeListResponse struct {
+	Data  []SharedWithMeResponse `json:"data"`
+	Total int64                  `json:"total"`
+	Page  int                    `json:"page"`
+	Limit int                    `json:"limit"`
+}
@@ -0,0 +1,11 @@
+package service
+
+import "git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+
+// Error share service
+var (
+	ErrShareNotFound           = apperr.NotFound("share_not_found", "share not found")
+	ErrExpiresInPast           = apperr.Validation("expires_in_past", "expires_at must be in the future")
+	ErrOwnerHasAccess          = apperr.Conflict("owner_has_access", "workspace owner already has access to this document")
+	ErrServiceAccountRecipient = apperr.Conflict("service_account_recipient", "documents cannot be shared with service accounts")
+)
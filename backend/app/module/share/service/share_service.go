@@ -0,0 +1,510 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	collab_service "git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab/service"
+	document_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/repository"
+	document_service "git.dev.siap.id/kukuhkkh/app-diagram/app/module/document/service"
+	lint_service "git.dev.siap.id/kukuhkkh/app-diagram/app/module/lint/service"
+	render_service "git.dev.siap.id/kukuhkkh/app-diagram/app/module/render/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/share/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/share/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/share/response"
+	user_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/user/repository"
+	workspace_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/helpers"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/mermaid"
+	"gorm.io/gorm"
+)
+
+// ShareService adalah interface untuk business logic share link document
+type ShareService interface {
+	CreateLink(documentID uint64, userID uint64, req *request.CreateShareLinkRequest) (*response.ShareLinkResponse, error)
+	ListLinks(documentID uint64, userID uint64) ([]response.ShareLinkResponse, error)
+	RevokeShare(documentID uint64, shareID uint64, userID uint64) error
+
+	// Share ke user tertentu by email
+	ShareWithUser(documentID uint64, userID uint64, req *request.ShareWithUserRequest) (*response.ShareUserResponse, error)
+	ListUserShares(documentID uint64, userID uint64) ([]response.ShareUserResponse, error)
+	SharedWithMe(userID uint64, page, limit int) (*response.SharedWithMeListResponse, error)
+
+	// Akses anonim via token
+	GetSharedDocument(token string) (*response.SharedDocumentResponse, error)
+	UpdateSharedDocument(token string, req *request.UpdateSharedDocumentRequest) (*response.SharedDocumentResponse, error)
+}
+
+type shareService struct {
+	shareRepo     repository.ShareRepository
+	documentRepo  document_repo.DocumentRepository
+	versionRepo   document_repo.DocumentVersionRepository
+	workspaceRepo workspace_repo.WorkspaceRepository
+	userRepo      user_repo.UserRepository
+	lintService   lint_service.LintService
+	renderService render_service.RenderService
+	collabHub     collab_service.Hub
+	policy        policy.Policy
+}
+
+// NewShareService instance
+func NewShareService(
+	shareRepo repository.ShareRepository,
+	documentRepo document_repo.DocumentRepository,
+	versionRepo document_repo.DocumentVersionRepository,
+	workspaceRepo workspace_repo.WorkspaceRepository,
+	userRepo user_repo.UserRepository,
+	lintService lint_service.LintService,
+	renderService render_service.RenderService,
+	collabHub collab_service.Hub,
+	policy policy.Policy,
+) ShareService {
+	return &shareService{
+		shareRepo:     shareRepo,
+		documentRepo:  documentRepo,
+		versionRepo:   versionRepo,
+		workspaceRepo: workspaceRepo,
+		userRepo:      userRepo,
+		lintService:   lintService,
+		renderService: renderService,
+		collabHub:     collabHub,
+		policy:        policy,
+	}
+}
+
+func (_i *shareService) CreateLink(documentID uint64, userID uint64, req *request.CreateShareLinkRequest) (*response.ShareLinkResponse, error) {
+	document, _, err := _i.authorizeManage(documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrExpiresInPast
+	}
+
+	permission := schema.PermissionView
+	if req.Permission != "" {
+		permission = schema.Permission(req.Permission)
+	}
+
+	token, err := helpers.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	share := &schema.SharedAccess{
+		DocumentID:  document.ID,
+		AccessToken: token,
+		Permission:  permission,
+		ExpiresAt:   req.ExpiresAt,
+	}
+
+	created, err := _i.shareRepo.Create(share)
+	if err != nil {
+		return nil, err
+	}
+
+	return toLinkResponse(created), nil
+}
+
+func (_i *shareService) ListLinks(documentID uint64, userID uint64) ([]response.ShareLinkResponse, error) {
+	document, _, err := _i.authorizeManage(documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := _i.shareRepo.FindLinksByDocumentID(document.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]response.ShareLinkResponse, 0, len(shares))
+	for _, share := range shares {
+		responses = append(responses, *toLinkResponse(&share))
+	}
+
+	return responses, nil
+}
+
+// RevokeShare mencabut share link maupun share ke user/pending invite
+func (_i *shareService) RevokeShare(documentID uint64, shareID uint64, userID uint64) error {
+	document, _, err := _i.authorizeManage(documentID, userID)
+	if err != nil {
+		return err
+	}
+
+	share, err := _i.shareRepo.FindByID(shareID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrShareNotFound
+		}
+		return err
+	}
+
+	if share.DocumentID != document.ID {
+		return ErrShareNotFound
+	}
+
+	return _i.shareRepo.Delete(share.ID)
+}
+
+func (_i *shareService) GetSharedDocument(token string) (*response.SharedDocumentResponse, error) {
+	share, document, workspace, err := _i.resolveToken(token, policy.ShareView)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := _i.versionRepo.FindLatest(document.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	res := toSharedDocumentResponse(document, share, latest)
+
+	// Markdown ikut dirender agar share view tidak perlu JavaScript. Pembaca link
+	// anonim, embed hanya menampilkan document publik.
+	if document.Type == schema.DocumentTypeMarkdown && latest != nil {
+		res.HTML, err = _i.renderService.MarkdownHTML(policy.Subject{}, workspace, latest.Content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func (_i *shareService) UpdateSharedDocument(token string, req *request.UpdateSharedDocumentRequest) (*response.SharedDocumentResponse, error) {
+	share, document, workspace, err := _i.resolveToken(token, policy.ShareEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.BaseVersion == nil {
+		return nil, document_service.ErrBaseVersionRequired
+	}
+
+	latest, err := _i.findLatestVersion(document.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := 0
+	if latest != nil {
+		current = latest.VersionNumber
+	}
+
+	// Save via share link mengikuti aturan base version yang sama dengan endpoint document
+	if *req.BaseVersion != current {
+		return nil, _i.conflict(document, share, *req.BaseVersion, req.Content)
+	}
+
+	// Versi baru hanya dibuat jika content benar-benar berubah
+	var diagnostics []mermaid.Diagnostic
+	if latest == nil || latest.Content != req.Content {
+		// Validasi syntax mermaid sesuai setting workspace
+		diagnostics, err = _i.lintService.ValidateContent(workspace, document.Type, req.Content)
+		if err != nil {
+			return nil, err
+		}
+
+		description := req.ChangeDescription
+		if description == nil {
+			viaLink := "Edited via share link"
+			description = &viaLink
+		}
+
+		version := &schema.DocumentVersion{
+			Content:           req.Content,
+			ChangeDescription: description,
+		}
+
+		document.UpdatedAt = time.Now()
+		if err := _i.documentRepo.UpdateFromVersion(document, []string{"updated_at"}, version, *req.BaseVersion); err != nil {
+			if errors.Is(err, document_repo.ErrStaleVersion) {
+				return nil, _i.conflict(document, share, *req.BaseVersion, req.Content)
+			}
+			return nil, err
+		}
+		latest = version
+		_i.collabHub.Invalidate(document.ID, version.VersionNumber)
+	}
+
+	res := toSharedDocumentResponse(document, share, latest)
+	res.Diagnostics = diagnostics
+
+	return res, nil
+}
+
+func (_i *shareService) ShareWithUser(documentID uint64, userID uint64, req *request.ShareWithUserRequest) (*response.ShareUserResponse, error) {
+	document, workspace, err := _i.authorizeManage(documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrExpiresInPast
+	}
+
+	permission := schema.PermissionView
+	if req.Permission != "" {
+		permission = schema.Permission(req.Permission)
+	}
+
+	token, err := helpers.GenerateSecureToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	share := &schema.SharedAccess{
+		DocumentID:  document.ID,
+		AccessToken: token,
+		Permission:  permission,
+		ExpiresAt:   req.ExpiresAt,
+	}
+
+	// User yang sudah terdaftar langsung mendapat akses, selain itu disimpan
+	// sebagai pending invite sampai user tersebut login pertama kali
+	email := helpers.NormalizeEmail(req.Email)
+	recipient, err := _i.userRepo.FindUserByEmail(email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if recipient != nil {
+		if recipient.ID == workspace.OwnerID {
+			return nil, ErrOwnerHasAccess
+		}
+		// Service account hanya mengakses workspace pemiliknya
+		if recipient.IsServiceAccount() {
+			return nil, ErrServiceAccountRecipient
+		}
+		share.UserID = &recipient.ID
+	} else {
+		share.InviteEmail = &email
+	}
+
+	saved, err := _i.shareRepo.SaveGrant(share)
+	if err != nil {
+		return nil, err
+	}
+	saved.User = recipient
+
+	return toUserShareResponse(saved), nil
+}
+
+func (_i *shareService) ListUserShares(documentID uint64, userID uint64) ([]response.ShareUserResponse, error) {
+	document, _, err := _i.authorizeManage(documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := _i.shareRepo.FindGrantsByDocumentID(document.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]response.ShareUserResponse, 0, len(shares))
+	for _, share := range shares {
+		responses = append(responses, *toUserShareResponse(&share))
+	}
+
+	return responses, nil
+}
+
+func (_i *shareService) SharedWithMe(userID uint64, page, limit int) (*response.SharedWithMeListResponse, error) {
+	// Validasi pagination
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+
+	shares, err := _i.shareRepo.FindGrantsByUserID(userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := _i.shareRepo.CountGrantsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]response.SharedWithMeResponse, 0, len(shares))
+	for _, share := range shares {
+		if share.Document == nil {
+			continue
+		}
+		responses = append(responses, response.SharedWithMeResponse{
+			ShareID:     share.ID,
+			DocumentID:  share.Document.ID,
+			WorkspaceID: share.Document.WorkspaceID,
+			Title:       share.Document.Title,
+			Type:        share.Document.Type,
+			Slug:        share.Document.Slug,
+			Permission:  share.Permission,
+			ExpiresAt:   share.ExpiresAt,
+			SharedAt:    share.CreatedAt,
+			UpdatedAt:   share.Document.UpdatedAt,
+		})
+	}
+
+	return &response.SharedWithMeListResponse{
+		Data:  responses,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}, nil
+}
+
+// Helper: validasi token share link (belum di-revoke, belum expired, document masih ada)
+// dan pastikan permission link cukup untuk aksi
+func (_i *shareService) resolveToken(token string, action policy.Action) (*schema.SharedAccess, *schema.Document, *schema.Workspace, error) {
+	if token == "" {
+		return nil, nil, nil, policy.ErrShareLinkNotFound
+	}
+
+	share, err := _i.shareRepo.FindByToken(token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil, policy.ErrShareLinkNotFound
+		}
+		return nil, nil, nil, err
+	}
+
+	if policy.Expired(share) {
+		return nil, nil, nil, policy.ErrShareLinkExpired
+	}
+
+	document, err := _i.documentRepo.FindByID(share.DocumentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil, policy.ErrShareLinkNotFound
+		}
+		return nil, nil, nil, err
+	}
+
+	// workspace yang sudah di-soft delete ikut menonaktifkan share link
+	workspace, err := _i.workspaceRepo.FindByID(document.WorkspaceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil, policy.ErrShareLinkNotFound
+		}
+		return nil, nil, nil, err
+	}
+
+	allowed, err := _i.policy.Can(policy.Subject{}, action, policy.ShareResource(workspace, document, share))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !allowed {
+		return nil, nil, nil, policy.Denied(action)
+	}
+
+	return share, document, workspace, nil
+}
+
+// Helper: hanya admin/owner workspace yang bisa mengelola share
+func (_i *shareService) authorizeManage(documentID uint64, userID uint64) (*schema.Document, *schema.Workspace, error) {
+	document, err := _i.documentRepo.FindByID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, policy.ErrDocumentNotFound
+		}
+		return nil, nil, err
+	}
+
+	workspace, err := _i.workspaceRepo.FindByID(document.WorkspaceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, policy.ErrWorkspaceNotFound
+		}
+		return nil, nil, err
+	}
+
+	allowed, err := _i.policy.Can(policy.User(userID), policy.DocumentShare, policy.DocumentResource(workspace, document))
+	if err != nil {
+		return nil, nil, err
+	}
+	if !allowed {
+		return nil, nil, policy.Denied(policy.DocumentShare)
+	}
+
+	return document, workspace, nil
+}
+
+// Helper: convert schema to response
+func toLinkResponse(share *schema.SharedAccess) *response.ShareLinkResponse {
+	return &response.ShareLinkResponse{
+		ID:          share.ID,
+		DocumentID:  share.DocumentID,
+		AccessToken: share.AccessToken,
+		Path:        "/s/" + share.AccessToken,
+		Permission:  share.Permission,
+		ExpiresAt:   share.ExpiresAt,
+		CreatedAt:   share.CreatedAt,
+	}
+}
+
+func toUserShareResponse(share *schema.SharedAccess) *response.ShareUserResponse {
+	res := &response.ShareUserResponse{
+		ID:         share.ID,
+		DocumentID: share.DocumentID,
+		UserID:     share.UserID,
+		Pending:    share.UserID == nil,
+		Permission: share.Permission,
+		ExpiresAt:  share.ExpiresAt,
+		CreatedAt:  share.CreatedAt,
+	}
+
+	if share.User != nil {
+		res.Email = share.User.Email
+		res.Name = share.User.Name
+	} else if share.InviteEmail != nil {
+		res.Email = *share.InviteEmail
+	}
+
+	return res
+}
+
+// conflict error 409 dengan versi terakhir dalam bentuk response share link
+func (_i *shareService) conflict(document *schema.Document, share *schema.SharedAccess, baseVersion int, content string) error {
+	return document_service.VersionConflict(_i.versionRepo, document.ID, baseVersion, &content, func(latest *schema.DocumentVersion) *response.SharedDocumentResponse {
+		return toSharedDocumentResponse(document, share, latest)
+	})
+}
+
+// findLatestVersion versi terakhir, nil jika document belum punya versi
+func (_i *shareService) findLatestVersion(documentID uint64) (*schema.DocumentVersion, error) {
+	latest, err := _i.versionRepo.FindLatest(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return latest, nil
+}
+
+func toSharedDocumentResponse(document *schema.Document, share *schema.SharedAccess, version *schema.DocumentVersion) *response.SharedDocumentResponse {
+	res := &response.SharedDocumentResponse{
+		ID:         document.ID,
+		Title:      document.Title,
+		Type:       document.Type,
+		Slug:       document.Slug,
+		Permission: share.Permission,
+		UpdatedAt:  document.UpdatedAt,
+	}
+
+	if version != nil {
+		res.VersionNumber = version.VersionNumber
+		res.Content = version.Content
+	}
+
+	return res
+}
@@ -0,0 +1,75 @@
+package share
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/share/controller"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/share/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/share/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+)
+
+// ShareRouter adalah router untuk share module
+type ShareRouter struct {
+	App        fiber.Router
+	Controller *controller.Controller
+	AuthMW     *middleware.AuthMiddleware
+	PolicyMW   *middleware.PolicyMiddleware
+}
+
+// Module adalah FX module untuk share
+var NewShareModule = fx.Options(
+	// register repository
+	fx.Provide(repository.NewShareRepository),
+
+	// register service
+	fx.Provide(service.NewShareService),
+
+	// register controller
+	controller.Module,
+
+	// register router
+	fx.Provide(NewShareRouter),
+)
+
+// NewShareRouter membuat instance baru dari ShareRouter
+func NewShareRouter(
+	app *fiber.App,
+	ctrl *controller.Controller,
+	authMW *middleware.AuthMiddleware,
+	policyMW *middleware.PolicyMiddleware,
+) *ShareRouter {
+	return &ShareRouter{
+		App:        app,
+		Controller: ctrl,
+		AuthMW:     authMW,
+		PolicyMW:   policyMW,
+	}
+}
+
+// RegisterShareRoutes mendaftarkan routes untuk share
+func (_i *ShareRouter) RegisterShareRoutes() {
+	// define controllers
+	shareController := _i.Controller.Share
+
+	_i.App.Route("/api/v1", func(router fiber.Router) {
+		shareRoutes := router.Group("/documents/:id/shares", _i.AuthMW.RequireAuth())
+
+		canShare := _i.PolicyMW.Document(policy.DocumentShare, "id")
+
+		shareRoutes.Post("", canShare, shareController.CreateLink)
+		shareRoutes.Get("", canShare, shareController.ListLinks)
+		shareRoutes.Post("/users", canShare, shareController.ShareWithUser)
+		shareRoutes.Get("/users", canShare, shareController.ListUserShares)
+		shareRoutes.Delete("/:shareId", canShare, shareController.RevokeShare)
+
+		router.Get("/shared-with-me", _i.AuthMW.RequireAuth(), shareController.SharedWithMe)
+	})
+
+	// Public routes, tanpa RequireAuth: akses ditentukan oleh token
+	_i.App.Route("/s", func(router fiber.Router) {
+		router.Get("/:token", _i.PolicyMW.ShareLink(policy.ShareView, "token"), shareController.GetSharedDocument)
+		router.Put("/:token", _i.PolicyMW.ShareLink(policy.ShareEdit, "token"), shareController.UpdateSharedDocument)
+	})
+}
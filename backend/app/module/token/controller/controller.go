@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/token/service"
+	"go.uber.org/fx"
+)
+
+// Controller aggregator
+type Controller struct {
+	Token TokenControllerI
+}
+
+// NewController
+func NewController(tokenController TokenControllerI) *Controller {
+	return &Controller{
+		Token: tokenController,
+	}
+}
+
+var Module = fx.Options(
+	fx.Provide(func(tokenService service.TokenService) TokenControllerI {
+		return NewTokenController(tokenService)
+	}),
+	fx.Provide(NewController),
+)
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"strconv"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/token/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/token/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type tokenController struct {
+	tokenService service.TokenService
+}
+
+type TokenControllerI interface {
+	CreateToken(c *fiber.Ctx) error
+	ListTokens(c *fiber.Ctx) error
+	RevokeToken(c *fiber.Ctx) error
+}
+
+func NewTokenController(tokenService service.TokenService) TokenControllerI {
+	return &tokenController{
+		tokenService: tokenService,
+	}
+}
+
+// CreateToken handler untuk membuat personal access token
+func (_i *tokenController) CreateToken(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	var req request.CreateTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.ErrInvalidBody
+	}
+
+	// Validasi input
+	if err := response.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	result, err := _i.tokenService.CreateToken(userID, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusCreated,
+		Messages: response.Messages{"token created successfully, copy it now as it will not be shown again"},
+		Data:     result,
+	})
+}
+
+// ListTokens handler untuk list personal access token milik user
+func (_i *tokenController) ListTokens(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	result, err := _i.tokenService.ListTokens(userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"tokens retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// RevokeToken handler untuk mencabut personal access token
+func (_i *tokenController) RevokeToken(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	tokenID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_token_id", "invalid token id")
+	}
+
+	if err := _i.tokenService.RevokeToken(userID, tokenID); err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"token revoked successfully"},
+	})
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
+)
+
+// TokenRepository
+type TokenRepository interface {
+	Create(token *schema.PersonalAccessToken) (*schema.PersonalAccessToken, error)
+	FindByID(id uint64) (*schema.PersonalAccessToken, error)
+	FindByHash(hash string) (*schema.PersonalAccessToken, error)
+	FindByUserID(userID uint64) ([]schema.PersonalAccessToken, error)
+	TouchLastUsed(id uint64, at time.Time) error
+	Delete(id uint64) error
+	DeleteByUserID(userID uint64) error
+}
+
+type tokenRepository struct {
+	db *database.Database
+}
+
+func NewTokenRepository(db *database.Database) TokenRepository {
+	return &tokenRepository{
+		db: db,
+	}
+}
+
+func (_i *tokenRepository) Create(token *schema.PersonalAccessToken) (*schema.PersonalAccessToken, error) {
+	if err := _i.db.DB.Create(token).Error; err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (_i *tokenRepository) FindByID(id uint64) (*schema.PersonalAccessToken, error) {
+	var token schema.PersonalAccessToken
+	if err := _i.db.DB.Where("id = ?", id).First(&token).Error; err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// FindByHash cari token beserta user pemiliknya
+func (_i *tokenRepository) FindByHash(hash string) (*schema.PersonalAccessToken, error) {
+	var token schema.PersonalAccessToken
+	if err := _i.db.DB.Preload("User").Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (_i *tokenRepository) FindByUserID(userID uint64) ([]schema.PersonalAccessToken, error) {
+	var tokens []schema.PersonalAccessToken
+	if err := _i.db.DB.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// TouchLastUsed update last_used_at tanpa mengubah updated_at
+func (_i *tokenRepository) TouchLastUsed(id uint64, at time.Time) error {
+	return _i.db.DB.Model(&schema.PersonalAccessToken{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", at).Error
+}
+
+func (_i *tokenRepository) Delete(id uint64) error {
+	return _i.db.DB.Where("id = ?", id).Delete(&schema.PersonalAccessToken{}).Error
+}
+
+func (_i *tokenRepository) DeleteByUserID(userID uint64) error {
+	return _i.db.DB.Where("user_id = ?", userID).Delete(&schema.PersonalAccessToken{}).Error
+}
@@ -0,0 +1,9 @@
+package request
+
+import "time"
+
+type CreateTokenRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scope     string     `json:"scope" validate:"omitempty,oneof=read write"`
+	ExpiresAt *time.Time `json:"expires_at" validate:"omitempty"`
+}
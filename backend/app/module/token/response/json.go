@@ -0,0 +1,23 @@
+package response
+
+import (
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+)
+
+type TokenResponse struct {
+	ID          uint64            `json:"id"`
+	Name        string            `json:"name"`
+	TokenPrefix string            `json:"token_prefix"`
+	Scope       schema.TokenScope `json:"scope"`
+	ExpiresAt   *time.Time        `json:"expires_at"`
+	LastUsedAt  *time.Time        `json:"last_used_at"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// CreatedTokenResponse token baru; Token hanya dikembalikan sekali saat dibuat
+type CreatedTokenResponse struct {
+	TokenResponse
+	Token string `json:"token"`
+}
@@ -0,0 +1,9 @@
+package service
+
+import "git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+
+// Error token service
+var (
+	ErrTokenNotFound = apperr.NotFound("token_not_found", "token not found")
+	ErrExpiresInPast = apperr.Validation("expires_in_past", "expires_at must be in the future")
+)
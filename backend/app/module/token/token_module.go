@@ -0,0 +1,60 @@
+package token
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/token/controller"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/token/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/token/service"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+)
+
+// TokenRouter adalah router untuk token module
+type TokenRouter struct {
+	App        fiber.Router
+	Controller *controller.Controller
+	AuthMW     *middleware.AuthMiddleware
+}
+
+// Module adalah FX module untuk personal access token
+var NewTokenModule = fx.Options(
+	// register repository
+	fx.Provide(repository.NewTokenRepository),
+
+	// register service
+	fx.Provide(service.NewTokenService),
+
+	// register controller
+	controller.Module,
+
+	// register router
+	fx.Provide(NewTokenRouter),
+)
+
+// NewTokenRouter membuat instance baru dari TokenRouter
+func NewTokenRouter(
+	app *fiber.App,
+	ctrl *controller.Controller,
+	authMW *middleware.AuthMiddleware,
+) *TokenRouter {
+	return &TokenRouter{
+		App:        app,
+		Controller: ctrl,
+		AuthMW:     authMW,
+	}
+}
+
+// RegisterTokenRoutes mendaftarkan routes untuk token
+func (_i *TokenRouter) RegisterTokenRoutes() {
+	// define controllers
+	tokenController := _i.Controller.Token
+
+	_i.App.Route("/api/v1", func(router fiber.Router) {
+		// Token hanya bisa dikelola lewat session agar token yang bocor tidak bisa membuat token baru
+		tokenRoutes := router.Group("/tokens", _i.AuthMW.RequireSession())
+
+		tokenRoutes.Post("", tokenController.CreateToken)
+		tokenRoutes.Get("", tokenController.ListTokens)
+		tokenRoutes.Delete("/:id", tokenController.RevokeToken)
+	})
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
+)
+
+// IdentityRepository akses external identity (akun di identity provider OIDC)
+type IdentityRepository interface {
+	FindByID(id uint64) (*schema.ExternalIdentity, error)
+	FindByProviderSubject(provider string, subject string) (*schema.ExternalIdentity, error)
+	FindByUserID(userID uint64) ([]schema.ExternalIdentity, error)
+	CountByUserID(userID uint64) (int64, error)
+	Create(identity *schema.ExternalIdentity) (*schema.ExternalIdentity, error)
+	Update(identity *schema.ExternalIdentity) error
+	Delete(id uint64) error
+}
+
+type identityRepository struct {
+	DB *database.Database
+}
+
+func NewIdentityRepository(db *database.Database) IdentityRepository {
+	return &identityRepository{
+		DB: db,
+	}
+}
+
+func (_i *identityRepository) FindByID(id uint64) (*schema.ExternalIdentity, error) {
+	var identity schema.ExternalIdentity
+	if err := _i.DB.DB.Where("id = ?", id).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (_i *identityRepository) FindByProviderSubject(provider string, subject string) (*schema.ExternalIdentity, error) {
+	var identity schema.ExternalIdentity
+	if err := _i.DB.DB.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (_i *identityRepository) FindByUserID(userID uint64) ([]schema.ExternalIdentity, error) {
+	var identities []schema.ExternalIdentity
+	if err := _i.DB.DB.Where("user_id = ?", userID).Order("created_at ASC").Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+func (_i *identityRepository) CountByUserID(userID uint64) (int64, error) {
+	var count int64
+	if err := _i.DB.DB.Model(&schema.ExternalIdentity{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (_i *identityRepository) Create(identity *schema.ExternalIdentity) (*schema.ExternalIdentity, error) {
+	if err := _i.DB.DB.Create(identity).Error; err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (_i *identityRepository) Update(identity *schema.ExternalIdentity) error {
+	return _i.DB.DB.Save(identity).Error
+}
+
+func (_i *identityRepository) Delete(id uint64) error {
+	return _i.DB.DB.Where("id = ?", id).Delete(&schema.ExternalIdentity{}).Error
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
+	"gorm.io/gorm"
+)
+
+// RecoveryCodeRepository akses recovery code 2FA
+type RecoveryCodeRepository interface {
+	Replace(userID uint64, codeHashes []string) error
+	CountUnused(userID uint64) (int64, error)
+	Use(userID uint64, codeHash string, at time.Time) (bool, error)
+	DeleteByUserID(userID uint64) error
+}
+
+type recoveryCodeRepository struct {
+	DB *database.Database
+}
+
+func NewRecoveryCodeRepository(db *database.Database) RecoveryCodeRepository {
+	return &recoveryCodeRepository{
+		DB: db,
+	}
+}
+
+// Replace hapus semua recovery code user lalu simpan yang baru
+func (_i *recoveryCodeRepository) Replace(userID uint64, codeHashes []string) error {
+	return _i.DB.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&schema.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		codes := make([]schema.RecoveryCode, 0, len(codeHashes))
+		for _, hash := range codeHashes {
+			codes = append(codes, schema.RecoveryCode{UserID: userID, CodeHash: hash})
+		}
+
+		return tx.Create(&codes).Error
+	})
+}
+
+func (_i *recoveryCodeRepository) CountUnused(userID uint64) (int64, error) {
+	var count int64
+	if err := _i.DB.DB.Model(&schema.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Use tandai code terpakai, false jika code tidak ada atau sudah dipakai.
+// Update bersyarat used_at IS NULL membuat satu code tidak bisa dipakai dua request sekaligus.
+func (_i *recoveryCodeRepository) Use(userID uint64, codeHash string, at time.Time) (bool, error) {
+	res := _i.DB.DB.Model(&schema.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", at)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (_i *recoveryCodeRepository) DeleteByUserID(userID uint64) error {
+	return _i.DB.DB.Where("user_id = ?", userID).Delete(&schema.RecoveryCode{}).Error
+}
@@ -4,8 +4,15 @@ import (
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
 	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
 	"git.dev.siap.id/kukuhkkh/app-diagram/utils/helpers"
+	"gorm.io/gorm"
 )
 
+// UserFilter filter list user manusia, field kosong tidak dipakai
+type UserFilter struct {
+	Email          string
+	ScimExternalID string
+}
+
 type userRepository struct {
 	DB *database.Database
 }
@@ -14,10 +21,20 @@ type userRepository struct {
 type UserRepository interface {
 	FindUserByID(id uint64) (user *schema.User, err error)
 	FindUserByEmail(email string) (user *schema.User, err error)
-	FindUserByLogtoSub(sub string) (user *schema.User, err error)
 	CheckUserByEmail(email string) (user *schema.User)
 	CreateUser(user *schema.User) (res *schema.User, err error)
 	UpdateUser(user *schema.User) error
+	DeleteUser(id uint64) error
+	UseTotpCounter(id uint64, counter int64) (bool, error)
+
+	// Service account milik workspace
+	FindServiceAccounts(workspaceID uint64) (users []schema.User, err error)
+	FindServiceAccount(workspaceID uint64, id uint64) (user *schema.User, err error)
+
+	// User manusia, dipakai provisioning SCIM
+	FindHumanUser(id uint64) (user *schema.User, err error)
+	FindHumanUsers(filter UserFilter, limit, offset int) (users []schema.User, err error)
+	CountHumanUsers(filter UserFilter) (count int64, err error)
 }
 
 func NewUserRepository(db *database.Database) UserRepository {
@@ -33,38 +50,101 @@ func (_i *userRepository) FindUserByID(id uint64) (user *schema.User, err error)
 	return
 }
 
+// FindUserByEmail tidak case-sensitive, akun lama mungkin tersimpan dengan huruf besar
 func (_i *userRepository) FindUserByEmail(email string) (user *schema.User, err error) {
-	if err := _i.DB.DB.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := _i.DB.DB.Where("LOWER(email) = ?", helpers.NormalizeEmail(email)).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (_i *userRepository) FindUserByLogtoSub(sub string) (user *schema.User, err error) {
-	if err := _i.DB.DB.Where("logto_sub = ?", sub).First(&user).Error; err != nil {
+func (_i *userRepository) CheckUserByEmail(email string) (user *schema.User) {
+	if err := _i.DB.DB.Where("LOWER(email) = ?", helpers.NormalizeEmail(email)).First(&user).Error; err != nil {
+		return nil
+	}
+	return
+}
+
+func (_i *userRepository) CreateUser(user *schema.User) (res *schema.User, err error) {
+	if err := _i.DB.DB.Create(&user).Error; err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (_i *userRepository) UpdateUser(user *schema.User) error {
+	return _i.DB.DB.Save(user).Error
+}
+
+func (_i *userRepository) DeleteUser(id uint64) error {
+	return _i.DB.DB.Where("id = ?", id).Delete(&schema.User{}).Error
+}
+
+// UseTotpCounter simpan time step TOTP yang dipakai, false jika step tersebut
+// atau yang lebih baru sudah pernah dipakai (code yang sama tidak bisa dipakai ulang)
+func (_i *userRepository) UseTotpCounter(id uint64, counter int64) (bool, error) {
+	res := _i.DB.DB.Model(&schema.User{}).
+		Where("id = ? AND totp_last_counter < ?", id, counter).
+		UpdateColumn("totp_last_counter", counter)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (_i *userRepository) FindServiceAccounts(workspaceID uint64) (users []schema.User, err error) {
+	if err := _i.DB.DB.Where("kind = ? AND workspace_id = ?", schema.UserKindService, workspaceID).
+		Order("name ASC").
+		Find(&users).Error; err != nil {
 		return nil, err
 	}
 	return
 }
 
-func (_i *userRepository) CheckUserByEmail(email string) (user *schema.User) {
-	_ = _i.DB.DB.Where("email = ?", email).First(&user).Error
+func (_i *userRepository) FindServiceAccount(workspaceID uint64, id uint64) (user *schema.User, err error) {
+	if err := _i.DB.DB.Where("kind = ? AND workspace_id = ? AND id = ?", schema.UserKindService, workspaceID, id).
+		First(&user).Error; err != nil {
+		return nil, err
+	}
 	return
 }
 
-func (_i *userRepository) CreateUser(user *schema.User) (res *schema.User, err error) {
-	if user.Password != nil {
-		hashed := helpers.Hash([]byte(*user.Password))
-		user.Password = &hashed
+func (_i *userRepository) FindHumanUser(id uint64) (user *schema.User, err error) {
+	if err := _i.DB.DB.Where("kind = ? AND id = ?", schema.UserKindHuman, id).
+		First(&user).Error; err != nil {
+		return nil, err
 	}
+	return
+}
 
-	if err := _i.DB.DB.Create(&user).Error; err != nil {
+func (_i *userRepository) FindHumanUsers(filter UserFilter, limit, offset int) (users []schema.User, err error) {
+	if err := _i.humanQuery(filter).
+		Order("id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&users).Error; err != nil {
 		return nil, err
 	}
+	return
+}
 
-	return user, nil
+func (_i *userRepository) CountHumanUsers(filter UserFilter) (count int64, err error) {
+	if err := _i.humanQuery(filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return
 }
 
-func (_i *userRepository) UpdateUser(user *schema.User) error {
-	return _i.DB.DB.Save(user).Error
+func (_i *userRepository) humanQuery(filter UserFilter) *gorm.DB {
+	query := _i.DB.DB.Model(&schema.User{}).Where("kind = ?", schema.UserKindHuman)
+
+	if filter.Email != "" {
+		query = query.Where("LOWER(email) = ?", helpers.NormalizeEmail(filter.Email))
+	}
+	if filter.ScimExternalID != "" {
+		query = query.Where("scim_external_id = ?", filter.ScimExternalID)
+	}
+
+	return query
 }
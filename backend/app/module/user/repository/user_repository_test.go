@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDatabase sqlite in-memory, satu database per test
+func newTestDatabase(t *testing.T) *database.Database {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Setiap koneksi in-memory punya database sendiri
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&schema.User{}, &schema.RecoveryCode{}); err != nil {
+		t.Fatal(err)
+	}
+
+	return &database.Database{DB: db}
+}
+
+func TestUseTotpCounterRejectsReplay(t *testing.T) {
+	db := newTestDatabase(t)
+	repo := NewUserRepository(db)
+
+	user, err := repo.CreateUser(&schema.User{Name: "alice", Email: "alice@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		counter int64
+		want    bool
+	}{
+		{100, true},
+		{100, false}, // code yang sama dipakai ulang
+		{99, false},  // code lama dalam jendela skew
+		{101, true},
+		{100, false},
+	}
+
+	for _, step := range steps {
+		ok, err := repo.UseTotpCounter(user.ID, step.counter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != step.want {
+			t.Fatalf("UseTotpCounter(%d) = %v, want %v", step.counter, ok, step.want)
+		}
+	}
+
+	stored, err := repo.FindUserByID(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.TotpLastCounter != 101 {
+		t.Fatalf("totp_last_counter = %d, want 101", stored.TotpLastCounter)
+	}
+}
+
+func TestUseTotpCounterPerUser(t *testing.T) {
+	db := newTestDatabase(t)
+	repo := NewUserRepository(db)
+
+	alice, err := repo.CreateUser(&schema.User{Name: "alice", Email: "alice@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := repo.CreateUser(&schema.User{Name: "bob", Email: "bob@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []uint64{alice.ID, bob.ID} {
+		ok, err := repo.UseTotpCounter(id, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("UseTotpCounter(user %d) = false, want true", id)
+		}
+	}
+}
+
+func TestRecoveryCodeSingleUse(t *testing.T) {
+	db := newTestDatabase(t)
+	repo := NewRecoveryCodeRepository(db)
+
+	const userID = 1
+	if err := repo.Replace(userID, []string{"hash-a", "hash-b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	steps := []struct {
+		hash string
+		want bool
+	}{
+		{"hash-a", true},
+		{"hash-a", false},
+		{"hash-unknown", false},
+		{"hash-b", true},
+	}
+
+	for _, step := range steps {
+		ok, err := repo.Use(userID, step.hash, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != step.want {
+			t.Fatalf("Use(%s) = %v, want %v", step.hash, ok, step.want)
+		}
+	}
+
+	count, err := repo.CountUnused(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Fatalf("CountUnused = %d, want 0", count)
+	}
+}
+
+func TestRecoveryCodeReplaceInvalidatesOldCodes(t *testing.T) {
+	db := newTestDatabase(t)
+	repo := NewRecoveryCodeRepository(db)
+
+	const userID = 1
+	if err := repo.Replace(userID, []string{"old"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Replace(userID, []string{"new"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := repo.Use(userID, "old", time.Now()); err != nil || ok {
+		t.Fatalf("Use(old) = (%v, %v), want (false, nil)", ok, err)
+	}
+	if ok, err := repo.Use(userID, "new", time.Now()); err != nil || !ok {
+		t.Fatalf("Use(new) = (%v, %v), want (true, nil)", ok, err)
+	}
+}
+
+func TestFindUserByEmailIgnoresCase(t *testing.T) {
+	db := newTestDatabase(t)
+	repo := NewUserRepository(db)
+
+	// Akun dari sebelum email dinormalisasi
+	user, err := repo.CreateUser(&schema.User{Name: "alice", Email: "Alice@Example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, email := range []string{"alice@example.com", "ALICE@EXAMPLE.COM", " Alice@Example.com "} {
+		found, err := repo.FindUserByEmail(email)
+		if err != nil {
+			t.Fatalf("FindUserByEmail(%q): %v", email, err)
+		}
+		if found.ID != user.ID {
+			t.Fatalf("FindUserByEmail(%q) = user %d, want %d", email, found.ID, user.ID)
+		}
+
+		if checked := repo.CheckUserByEmail(email); checked == nil || checked.ID != user.ID {
+			t.Fatalf("CheckUserByEmail(%q) = %v, want user %d", email, checked, user.ID)
+		}
+	}
+
+	if _, err := repo.FindUserByEmail("bob@example.com"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("FindUserByEmail(unknown) error = %v, want ErrRecordNotFound", err)
+	}
+}
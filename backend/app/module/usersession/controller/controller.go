@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/usersession/service"
+	"go.uber.org/fx"
+)
+
+// Controller aggregator
+type Controller struct {
+	Session SessionControllerI
+}
+
+// NewController
+func NewController(sessionController SessionControllerI) *Controller {
+	return &Controller{
+		Session: sessionController,
+	}
+}
+
+var Module = fx.Options(
+	fx.Provide(func(sessionService service.SessionService) SessionControllerI {
+		return NewSessionController(sessionService)
+	}),
+	fx.Provide(NewController),
+)
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"strconv"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/usersession/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SessionController
+type sessionController struct {
+	sessionService service.SessionService
+}
+
+type SessionControllerI interface {
+	ListSessions(c *fiber.Ctx) error
+	RevokeSession(c *fiber.Ctx) error
+	BackChannelLogout(c *fiber.Ctx) error
+}
+
+func NewSessionController(sessionService service.SessionService) SessionControllerI {
+	return &sessionController{
+		sessionService: sessionService,
+	}
+}
+
+// ListSessions handler untuk list session aktif milik user
+func (_i *sessionController) ListSessions(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	var currentID uint64
+	if current := middleware.GetUserSession(c); current != nil {
+		currentID = current.ID
+	}
+
+	result, err := _i.sessionService.ListSessions(userID, currentID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"sessions retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// RevokeSession handler untuk mengakhiri session, termasuk session di perangkat lain
+func (_i *sessionController) RevokeSession(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_session_id", "invalid session id")
+	}
+
+	if err := _i.sessionService.RevokeSession(userID, id); err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"session revoked successfully"},
+	})
+}
+
+// BackChannelLogout handler publik untuk logout token dari identity provider
+func (_i *sessionController) BackChannelLogout(c *fiber.Ctx) error {
+	// Response back-channel logout tidak boleh di-cache
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	logoutToken := c.FormValue("logout_token")
+	if logoutToken == "" {
+		return service.ErrInvalidLogoutToken
+	}
+
+	if err := _i.sessionService.BackChannelLogout(c.Params("provider"), logoutToken); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
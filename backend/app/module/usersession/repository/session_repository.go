@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/sso"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrLogoutTokenUsed jti logout token sudah pernah diproses
+var ErrLogoutTokenUsed = errors.New("logout token has already been used")
+
+// SessionRepository
+type SessionRepository interface {
+	Create(session *schema.UserSession) (*schema.UserSession, error)
+	FindByID(id uint64) (*schema.UserSession, error)
+	FindActiveByUserID(userID uint64, now time.Time) ([]schema.UserSession, error)
+	Update(session *schema.UserSession) error
+	// UpdateLocked jalankan fn dengan row session terkunci, hasil disimpan jika fn mengembalikan changed true
+	UpdateLocked(id uint64, fn func(session *schema.UserSession) (changed bool, err error)) (*schema.UserSession, error)
+	TouchLastSeen(id uint64, at time.Time) error
+	// FindWithPlainTokens session yang masih menyimpan token IdP tanpa enkripsi
+	FindWithPlainTokens(limit int) ([]schema.UserSession, error)
+	// UpdateTokens simpan token IdP tanpa mengubah kolom lain
+	UpdateTokens(session *schema.UserSession) error
+	Revoke(id uint64, at time.Time) error
+	// RevokeByLogoutToken catat jti logout token lalu cabut session IdP berdasarkan
+	// sid, atau subject jika sid kosong. ErrLogoutTokenUsed jika jti sudah tercatat.
+	RevokeByLogoutToken(token *schema.LogoutToken, sid string, subject string, at time.Time) (int64, error)
+	RevokeByUserID(userID uint64, at time.Time) (int64, error)
+}
+
+type sessionRepository struct {
+	db *database.Database
+}
+
+func NewSessionRepository(db *database.Database) SessionRepository {
+	return &sessionRepository{
+		db: db,
+	}
+}
+
+func (_i *sessionRepository) Create(session *schema.UserSession) (*schema.UserSession, error) {
+	if err := _i.db.DB.Create(session).Error; err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (_i *sessionRepository) FindByID(id uint64) (*schema.UserSession, error) {
+	var session schema.UserSession
+	if err := _i.db.DB.Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (_i *sessionRepository) FindActiveByUserID(userID uint64, now time.Time) ([]schema.UserSession, error) {
+	var sessions []schema.UserSession
+	if err := _i.db.DB.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (_i *sessionRepository) Update(session *schema.UserSession) error {
+	return _i.db.DB.Save(session).Error
+}
+
+// UpdateLocked baca session dengan SELECT ... FOR UPDATE lalu jalankan fn di
+// dalam transaksi yang sama. Request lain (termasuk dari instance lain) yang
+// mengunci session yang sama menunggu sampai transaksi selesai dan membaca hasilnya.
+func (_i *sessionRepository) UpdateLocked(id uint64, fn func(session *schema.UserSession) (changed bool, err error)) (*schema.UserSession, error) {
+	var session schema.UserSession
+	err := _i.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).
+			First(&session).Error; err != nil {
+			return err
+		}
+
+		changed, err := fn(&session)
+		if err != nil || !changed {
+			return err
+		}
+
+		return tx.Save(&session).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// TouchLastSeen update last_seen_at tanpa mengubah updated_at
+func (_i *sessionRepository) TouchLastSeen(id uint64, at time.Time) error {
+	return _i.db.DB.Model(&schema.UserSession{}).Where("id = ?", id).UpdateColumn("last_seen_at", at).Error
+}
+
+func (_i *sessionRepository) FindWithPlainTokens(limit int) ([]schema.UserSession, error) {
+	sealed := sso.SealedPrefix + "%"
+
+	var sessions []schema.UserSession
+	if err := _i.db.DB.
+		Where("(access_token <> '' AND access_token NOT LIKE ?) OR (refresh_token <> '' AND refresh_token NOT LIKE ?) OR (id_token <> '' AND id_token NOT LIKE ?)", sealed, sealed, sealed).
+		Order("id ASC").
+		Limit(limit).
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// UpdateTokens tanpa mengubah updated_at
+func (_i *sessionRepository) UpdateTokens(session *schema.UserSession) error {
+	return _i.db.DB.Model(&schema.UserSession{}).
+		Where("id = ?", session.ID).
+		UpdateColumns(map[string]any{
+			"access_token":  session.AccessToken,
+			"refresh_token": session.RefreshToken,
+			"id_token":      session.IDToken,
+		}).Error
+}
+
+func (_i *sessionRepository) Revoke(id uint64, at time.Time) error {
+	return _i.db.DB.Model(&schema.UserSession{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+// RevokeByLogoutToken jti dan pencabutan session dalam satu transaksi, sehingga
+// logout token yang gagal diproses bisa dikirim ulang IdP. jti yang sudah
+// kedaluwarsa dibersihkan di transaksi yang sama.
+func (_i *sessionRepository) RevokeByLogoutToken(token *schema.LogoutToken, sid string, subject string, at time.Time) (int64, error) {
+	var revoked int64
+
+	err := _i.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at < ?", at).Delete(&schema.LogoutToken{}).Error; err != nil {
+			return err
+		}
+
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(token)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrLogoutTokenUsed
+		}
+
+		query := tx.Model(&schema.UserSession{}).Where("provider = ? AND revoked_at IS NULL", token.Provider)
+		if sid != "" {
+			query = query.Where("idp_session_id = ?", sid)
+		} else {
+			query = query.Where("subject = ?", subject)
+		}
+
+		res = query.Update("revoked_at", at)
+		revoked = res.RowsAffected
+		return res.Error
+	})
+
+	return revoked, err
+}
+
+func (_i *sessionRepository) RevokeByUserID(userID uint64, at time.Time) (int64, error) {
+	res := _i.db.DB.Model(&schema.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at)
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDatabase sqlite in-memory, satu database per test
+func newTestDatabase(t *testing.T) *database.Database {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Setiap koneksi in-memory punya database sendiri
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&schema.UserSession{}, &schema.LogoutToken{}); err != nil {
+		t.Fatal(err)
+	}
+
+	return &database.Database{DB: db}
+}
+
+func createSession(t *testing.T, repo SessionRepository, sid string, now time.Time) *schema.UserSession {
+	t.Helper()
+
+	userSession, err := repo.Create(&schema.UserSession{
+		UserID:       1,
+		Provider:     "logto",
+		Subject:      "user-1",
+		IdPSessionID: sid,
+		LastSeenAt:   now,
+		ExpiresAt:    now.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return userSession
+}
+
+func TestRevokeByLogoutToken(t *testing.T) {
+	repo := NewSessionRepository(newTestDatabase(t))
+	now := time.Now()
+	createSession(t, repo, "sid-1", now)
+	createSession(t, repo, "sid-2", now)
+
+	revoked, err := repo.RevokeByLogoutToken(&schema.LogoutToken{Provider: "logto", JTI: "jti-1", ExpiresAt: now.Add(time.Minute)}, "sid-1", "user-1", now)
+	if err != nil || revoked != 1 {
+		t.Fatalf("RevokeByLogoutToken = %d, %v, want 1, nil", revoked, err)
+	}
+
+	// jti yang sama ditolak walaupun menunjuk session lain
+	_, err = repo.RevokeByLogoutToken(&schema.LogoutToken{Provider: "logto", JTI: "jti-1", ExpiresAt: now.Add(time.Minute)}, "sid-2", "user-1", now)
+	if !errors.Is(err, ErrLogoutTokenUsed) {
+		t.Fatalf("replay error = %v, want ErrLogoutTokenUsed", err)
+	}
+	active, err := repo.FindActiveByUserID(1, now)
+	if err != nil || len(active) != 1 {
+		t.Fatalf("active sessions = %d, %v, want 1", len(active), err)
+	}
+
+	// Tanpa sid semua session subject dicabut, jti kedaluwarsa dibersihkan
+	later := now.Add(2 * time.Minute)
+	revoked, err = repo.RevokeByLogoutToken(&schema.LogoutToken{Provider: "logto", JTI: "jti-2", ExpiresAt: later.Add(time.Minute)}, "", "user-1", later)
+	if err != nil || revoked != 1 {
+		t.Fatalf("RevokeByLogoutToken subject = %d, %v, want 1, nil", revoked, err)
+	}
+
+	var jtis []string
+	if err := repo.(*sessionRepository).db.DB.Model(&schema.LogoutToken{}).Pluck("jti", &jtis).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(jtis) != 1 || jtis[0] != "jti-2" {
+		t.Fatalf("stored jti = %v, want [jti-2]", jtis)
+	}
+}
+
+func TestFindWithPlainTokens(t *testing.T) {
+	repo := NewSessionRepository(newTestDatabase(t))
+	now := time.Now()
+
+	plain := createSession(t, repo, "sid-1", now)
+	plain.AccessToken, plain.RefreshToken = "enc:v1:a", "refresh"
+	sealed := createSession(t, repo, "sid-2", now)
+	sealed.AccessToken, sealed.IDToken = "enc:v1:a", "enc:v1:b"
+	createSession(t, repo, "sid-3", now)
+	for _, userSession := range []*schema.UserSession{plain, sealed} {
+		if err := repo.UpdateTokens(userSession); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sessions, err := repo.FindWithPlainTokens(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != plain.ID {
+		t.Fatalf("sessions with plaintext tokens = %v, want only session %d", sessions, plain.ID)
+	}
+}
@@ -0,0 +1,16 @@
+package response
+
+import (
+	"time"
+)
+
+type SessionResponse struct {
+	ID         uint64    `json:"id"`
+	Provider   string    `json:"provider"` // kosong untuk login lokal
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	Current    bool      `json:"current"` // session yang dipakai request ini
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"errors"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+)
+
+// Error session service
+var (
+	ErrSessionNotFound     = apperr.NotFound("session_not_found", "session not found")
+	ErrSessionRevoked      = apperr.Unauthorized("session_revoked", "session has been revoked, please log in again")
+	ErrSessionExpired      = apperr.Unauthorized("session_expired", "session has expired, please log in again")
+	ErrInvalidLogoutToken  = apperr.BadRequest("invalid_logout_token", "invalid logout token")
+	ErrLogoutTokenReplayed = apperr.BadRequest("logout_token_replayed", "logout token has already been used")
+
+	// errRefreshSkipped refresh ditunda karena IdP tidak bisa dihubungi, session tetap dipakai
+	errRefreshSkipped = errors.New("session refresh skipped")
+)
@@ -0,0 +1,468 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/usersession/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/usersession/response"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/session"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/sso"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+const (
+	// refreshLeeway access token di-refresh sedikit sebelum benar-benar kedaluwarsa
+	refreshLeeway = 30 * time.Second
+
+	// refreshTimeout batas waktu request refresh ke IdP, row session terkunci selama refresh berjalan
+	refreshTimeout = 10 * time.Second
+
+	// lastSeenResolution interval minimal update last_seen_at agar tidak menulis ke database setiap request
+	lastSeenResolution = time.Minute
+
+	// backChannelLogoutEvent event wajib di logout token (OIDC Back-Channel Logout 1.0)
+	backChannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+	// Nama kolom token IdP, dipakai sebagai additional data enkripsi
+	columnAccessToken  = "access_token"
+	columnRefreshToken = "refresh_token"
+	columnIDToken      = "id_token"
+
+	// sealBatch jumlah session dengan token lama yang dienkripsi per query saat startup
+	sealBatch = 100
+)
+
+// IdPTokens token dari identity provider yang disimpan bersama session
+type IdPTokens struct {
+	Provider     string
+	Subject      string
+	SessionID    string // claim sid dari id_token
+	AccessToken  string
+	RefreshToken string
+	IDToken      string
+	Expiry       time.Time
+}
+
+// SessionService adalah interface untuk business logic session login user
+type SessionService interface {
+	StartSession(userID uint64, tokens *IdPTokens, userAgent string, ipAddress string) (*schema.UserSession, error)
+	ValidateSession(id uint64) (*schema.UserSession, error)
+	EndSession(id uint64) error
+	ListSessions(userID uint64, currentID uint64) ([]response.SessionResponse, error)
+	RevokeSession(userID uint64, id uint64) error
+	RevokeUserSessions(userID uint64) error
+	BackChannelLogout(provider string, logoutToken string) error
+}
+
+type sessionService struct {
+	sessionRepo repository.SessionRepository
+	sso         *sso.Registry
+	cipher      *sso.TokenCipher
+	cfg         *config.Config
+}
+
+// NewSessionService instance, token IdP yang masih tersimpan tanpa enkripsi
+// dienkripsi saat aplikasi start
+func NewSessionService(
+	lifecycle fx.Lifecycle,
+	sessionRepo repository.SessionRepository,
+	registry *sso.Registry,
+	cipher *sso.TokenCipher,
+	cfg *config.Config,
+) SessionService {
+	service := &sessionService{
+		sessionRepo: sessionRepo,
+		sso:         registry,
+		cipher:      cipher,
+		cfg:         cfg,
+	}
+
+	lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			service.sealLegacyTokens()
+			return nil
+		},
+	})
+
+	return service
+}
+
+// StartSession mencatat session baru setelah login berhasil, tokens nil untuk login lokal
+func (_i *sessionService) StartSession(userID uint64, tokens *IdPTokens, userAgent string, ipAddress string) (*schema.UserSession, error) {
+	now := time.Now()
+
+	userSession := &schema.UserSession{
+		UserID:     userID,
+		UserAgent:  truncate(userAgent, 255),
+		IPAddress:  truncate(ipAddress, 64),
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(session.Expiration(_i.cfg)),
+	}
+
+	if tokens != nil {
+		userSession.Provider = tokens.Provider
+		userSession.Subject = tokens.Subject
+		userSession.IdPSessionID = tokens.SessionID
+		if err := _i.sealTokens(userSession, tokens.AccessToken, tokens.RefreshToken, tokens.IDToken); err != nil {
+			return nil, err
+		}
+		if !tokens.Expiry.IsZero() {
+			userSession.TokenExpiresAt = &tokens.Expiry
+		}
+	}
+
+	return _i.sessionRepo.Create(userSession)
+}
+
+// ValidateSession dipanggil setiap request yang memakai session. Access token IdP
+// yang kedaluwarsa di-refresh, jika IdP menolak refresh maka session diakhiri.
+func (_i *sessionService) ValidateSession(id uint64) (*schema.UserSession, error) {
+	userSession, err := _i.findActive(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if userSession.TokenExpired(now, refreshLeeway) {
+		if userSession, err = _i.refresh(id); err != nil {
+			return nil, err
+		}
+	}
+
+	if now.Sub(userSession.LastSeenAt) >= lastSeenResolution {
+		if err := _i.sessionRepo.TouchLastSeen(userSession.ID, now); err != nil {
+			return nil, err
+		}
+		userSession.LastSeenAt = now
+	}
+
+	return userSession, nil
+}
+
+// EndSession mencabut session saat logout
+func (_i *sessionService) EndSession(id uint64) error {
+	return _i.sessionRepo.Revoke(id, time.Now())
+}
+
+func (_i *sessionService) ListSessions(userID uint64, currentID uint64) ([]response.SessionResponse, error) {
+	sessions, err := _i.sessionRepo.FindActiveByUserID(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]response.SessionResponse, 0, len(sessions))
+	for i := range sessions {
+		responses = append(responses, toSessionResponse(&sessions[i], currentID))
+	}
+
+	return responses, nil
+}
+
+func (_i *sessionService) RevokeSession(userID uint64, id uint64) error {
+	userSession, err := _i.findActive(id)
+	if err != nil {
+		if errors.Is(err, ErrSessionRevoked) || errors.Is(err, ErrSessionExpired) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+
+	// session milik user lain dianggap tidak ada
+	if userSession.UserID != userID {
+		return ErrSessionNotFound
+	}
+
+	return _i.sessionRepo.Revoke(userSession.ID, time.Now())
+}
+
+// RevokeUserSessions mencabut semua session user, dipakai saat akun dinonaktifkan
+func (_i *sessionService) RevokeUserSessions(userID uint64) error {
+	_, err := _i.sessionRepo.RevokeByUserID(userID, time.Now())
+	return err
+}
+
+// BackChannelLogout mengakhiri session yang ditunjuk logout token dari IdP,
+// berdasarkan sid jika ada atau seluruh session user (sub) dari IdP tersebut
+func (_i *sessionService) BackChannelLogout(providerName string, logoutToken string) error {
+	provider, ok := _i.sso.Get(providerName)
+	if !ok {
+		return ErrInvalidLogoutToken
+	}
+
+	ctx := context.Background()
+	verifier, err := provider.Verifier(ctx)
+	if err != nil {
+		return err
+	}
+
+	token, err := verifier.Verify(ctx, logoutToken)
+	if err != nil {
+		return ErrInvalidLogoutToken
+	}
+
+	var claims struct {
+		Subject   string                     `json:"sub"`
+		SessionID string                     `json:"sid"`
+		Events    map[string]json.RawMessage `json:"events"`
+		Nonce     *string                    `json:"nonce"`
+		ID        string                     `json:"jti"`
+	}
+	if err := token.Claims(&claims); err != nil {
+		return ErrInvalidLogoutToken
+	}
+
+	// Logout token wajib punya event back-channel logout dan tidak boleh punya nonce
+	if _, ok := claims.Events[backChannelLogoutEvent]; !ok || claims.Nonce != nil {
+		return ErrInvalidLogoutToken
+	}
+	if claims.Subject == "" && claims.SessionID == "" {
+		return ErrInvalidLogoutToken
+	}
+	// jti dicatat sampai token kedaluwarsa agar logout token yang sama tidak bisa diputar ulang
+	if claims.ID == "" {
+		return ErrInvalidLogoutToken
+	}
+
+	used := &schema.LogoutToken{
+		Provider:  provider.Name,
+		JTI:       truncate(claims.ID, 255),
+		ExpiresAt: token.Expiry,
+	}
+	revoked, err := _i.sessionRepo.RevokeByLogoutToken(used, claims.SessionID, claims.Subject, time.Now())
+	if err != nil {
+		if errors.Is(err, repository.ErrLogoutTokenUsed) {
+			return ErrLogoutTokenReplayed
+		}
+		return err
+	}
+
+	log.Info().
+		Str("provider", provider.Name).
+		Str("sub", claims.Subject).
+		Str("sid", claims.SessionID).
+		Int64("revoked", revoked).
+		Msg("back-channel logout")
+
+	return nil
+}
+
+// refresh tukar refresh token dengan token baru dari IdP. Refresh token yang
+// dirotasi IdP hanya bisa dipakai sekali, row session dikunci selama refresh
+// agar request bersamaan dari instance mana pun tidak memakai token yang sama.
+func (_i *sessionService) refresh(id uint64) (*schema.UserSession, error) {
+	expired := false
+
+	userSession, err := _i.sessionRepo.UpdateLocked(id, func(userSession *schema.UserSession) (bool, error) {
+		// Dicek ulang di bawah lock, request lain mungkin sudah me-refresh atau mencabut session
+		now := time.Now()
+		if userSession.RevokedAt != nil {
+			return false, ErrSessionRevoked
+		}
+		if !userSession.Active(now) {
+			return false, ErrSessionExpired
+		}
+		if !userSession.TokenExpired(now, refreshLeeway) {
+			return false, nil
+		}
+
+		refreshed, err := _i.exchange(userSession)
+		if err != nil {
+			return false, err
+		}
+		if !refreshed {
+			// IdP menolak refresh, session dicabut di transaksi yang sama
+			userSession.RevokedAt = &now
+			expired = true
+		}
+		return true, nil
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSessionNotFound
+		}
+		if errors.Is(err, errRefreshSkipped) {
+			return _i.findActive(id)
+		}
+		return nil, err
+	}
+	if expired {
+		return nil, ErrSessionExpired
+	}
+
+	return userSession, nil
+}
+
+// exchange minta token baru ke IdP dan isi ke session. false berarti session
+// tidak bisa diperpanjang dan harus diakhiri.
+func (_i *sessionService) exchange(userSession *schema.UserSession) (bool, error) {
+	provider, ok := _i.sso.Get(userSession.Provider)
+	if !ok {
+		// Provider sudah dihapus dari config, session tidak bisa diperpanjang
+		return false, nil
+	}
+
+	conf, err := provider.OAuth2Config(context.Background())
+	if err != nil {
+		// IdP tidak bisa dihubungi, session tetap dipakai dan refresh dicoba lagi di request berikutnya
+		log.Warn().Err(err).Str("provider", provider.Name).Msg("session refresh skipped")
+		return false, errRefreshSkipped
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+
+	refreshToken, err := _i.cipher.Open(columnRefreshToken, userSession.RefreshToken)
+	if err != nil {
+		// Token tidak bisa dibuka dengan sso.token_key sekarang, user harus login ulang
+		log.Warn().Err(err).Uint64("session_id", userSession.ID).Msg("session refresh token unreadable")
+		return false, nil
+	}
+
+	token, err := conf.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) {
+			// IdP menolak refresh token: user dinonaktifkan, session IdP berakhir, atau token dicabut
+			return false, nil
+		}
+
+		log.Warn().Err(err).Str("provider", provider.Name).Msg("session refresh failed")
+		return false, errRefreshSkipped
+	}
+
+	// Refresh token dan id_token lama tetap dipakai jika IdP tidak mengirim yang baru
+	if token.RefreshToken != "" {
+		refreshToken = token.RefreshToken
+	}
+	idToken, _ := token.Extra("id_token").(string)
+	if idToken == "" {
+		if idToken, err = _i.cipher.Open(columnIDToken, userSession.IDToken); err != nil {
+			idToken = ""
+		}
+	}
+	if err := _i.sealTokens(userSession, token.AccessToken, refreshToken, idToken); err != nil {
+		return false, err
+	}
+	if !token.Expiry.IsZero() {
+		userSession.TokenExpiresAt = &token.Expiry
+	} else {
+		userSession.TokenExpiresAt = nil
+	}
+
+	return true, nil
+}
+
+// sealTokens enkripsi token IdP ke kolom session
+func (_i *sessionService) sealTokens(userSession *schema.UserSession, accessToken, refreshToken, idToken string) error {
+	var err error
+	if userSession.AccessToken, err = _i.cipher.Seal(columnAccessToken, accessToken); err != nil {
+		return err
+	}
+	if userSession.RefreshToken, err = _i.cipher.Seal(columnRefreshToken, refreshToken); err != nil {
+		return err
+	}
+	userSession.IDToken, err = _i.cipher.Seal(columnIDToken, idToken)
+	return err
+}
+
+// sealLegacyTokens enkripsi token IdP yang disimpan sebelum enkripsi dipakai.
+// Gagal di sini tidak menghentikan aplikasi, dicoba lagi saat start berikutnya.
+func (_i *sessionService) sealLegacyTokens() {
+	sealed := 0
+	for {
+		sessions, err := _i.sessionRepo.FindWithPlainTokens(sealBatch)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to find sessions with plaintext tokens")
+			return
+		}
+
+		for i := range sessions {
+			userSession := &sessions[i]
+			if err := _i.sealPlainTokens(userSession); err != nil {
+				log.Error().Err(err).Msg("failed to encrypt stored identity provider tokens")
+				return
+			}
+			if err := _i.sessionRepo.UpdateTokens(userSession); err != nil {
+				log.Error().Err(err).Uint64("session_id", userSession.ID).Msg("failed to encrypt stored identity provider tokens")
+				return
+			}
+		}
+
+		sealed += len(sessions)
+		if len(sessions) < sealBatch {
+			break
+		}
+	}
+
+	if sealed > 0 {
+		log.Info().Int("sessions", sealed).Msg("encrypted stored identity provider tokens")
+	}
+}
+
+// sealPlainTokens enkripsi kolom yang belum terenkripsi, kolom lain tidak diubah
+func (_i *sessionService) sealPlainTokens(userSession *schema.UserSession) error {
+	columns := map[string]*string{
+		columnAccessToken:  &userSession.AccessToken,
+		columnRefreshToken: &userSession.RefreshToken,
+		columnIDToken:      &userSession.IDToken,
+	}
+	for column, value := range columns {
+		if sso.Sealed(*value) {
+			continue
+		}
+		sealed, err := _i.cipher.Seal(column, *value)
+		if err != nil {
+			return err
+		}
+		*value = sealed
+	}
+	return nil
+}
+
+func (_i *sessionService) findActive(id uint64) (*schema.UserSession, error) {
+	userSession, err := _i.sessionRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	if userSession.RevokedAt != nil {
+		return nil, ErrSessionRevoked
+	}
+	if !userSession.Active(time.Now()) {
+		return nil, ErrSessionExpired
+	}
+
+	return userSession, nil
+}
+
+// Helper: convert schema to response
+func toSessionResponse(userSession *schema.UserSession, currentID uint64) response.SessionResponse {
+	return response.SessionResponse{
+		ID:         userSession.ID,
+		Provider:   userSession.Provider,
+		UserAgent:  userSession.UserAgent,
+		IPAddress:  userSession.IPAddress,
+		Current:    userSession.ID == currentID,
+		LastSeenAt: userSession.LastSeenAt,
+		ExpiresAt:  userSession.ExpiresAt,
+		CreatedAt:  userSession.CreatedAt,
+	}
+}
+
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max]
+	}
+	return s
+}
@@ -0,0 +1,59 @@
+package usersession
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/usersession/controller"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/usersession/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/usersession/service"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+)
+
+// UserSessionRouter adalah router untuk user session module
+type UserSessionRouter struct {
+	App        fiber.Router
+	Controller *controller.Controller
+	AuthMW     *middleware.AuthMiddleware
+}
+
+// Module adalah FX module untuk session login user
+var NewUserSessionModule = fx.Options(
+	// register repository
+	fx.Provide(repository.NewSessionRepository),
+
+	// register service
+	fx.Provide(service.NewSessionService),
+
+	// register controller
+	controller.Module,
+
+	// register router
+	fx.Provide(NewUserSessionRouter),
+)
+
+// NewUserSessionRouter membuat instance baru dari UserSessionRouter
+func NewUserSessionRouter(
+	app *fiber.App,
+	ctrl *controller.Controller,
+	authMW *middleware.AuthMiddleware,
+) *UserSessionRouter {
+	return &UserSessionRouter{
+		App:        app,
+		Controller: ctrl,
+		AuthMW:     authMW,
+	}
+}
+
+// RegisterUserSessionRoutes mendaftarkan routes untuk user session
+func (_i *UserSessionRouter) RegisterUserSessionRoutes() {
+	// define controllers
+	sessionController := _i.Controller.Session
+
+	_i.App.Route("/auth", func(router fiber.Router) {
+		router.Get("/sessions", _i.AuthMW.RequireSession(), sessionController.ListSessions)
+		router.Delete("/sessions/:id", _i.AuthMW.RequireSession(), sessionController.RevokeSession)
+
+		// dipanggil langsung oleh identity provider (OIDC Back-Channel Logout)
+		router.Post("/backchannel-logout/:provider", sessionController.BackChannelLogout)
+	})
+}
@@ -7,13 +7,21 @@ import (
 
 // Controller aggregator
 type Controller struct {
-	Workspace WorkspaceControllerI
+	Workspace      WorkspaceControllerI
+	Member         MemberControllerI
+	ServiceAccount ServiceAccountControllerI
 }
 
 // NewController
-func NewController(workspaceController WorkspaceControllerI) *Controller {
+func NewController(
+	workspaceController WorkspaceControllerI,
+	memberController MemberControllerI,
+	serviceAccountController ServiceAccountControllerI,
+) *Controller {
 	return &Controller{
-		Workspace: workspaceController,
+		Workspace:      workspaceController,
+		Member:         memberController,
+		ServiceAccount: serviceAccountController,
 	}
 }
 
@@ -21,5 +29,11 @@ var Module = fx.Options(
 	fx.Provide(func(workspaceService service.WorkspaceService) WorkspaceControllerI {
 		return NewWorkspaceController(workspaceService)
 	}),
+	fx.Provide(func(memberService service.MemberService) MemberControllerI {
+		return NewMemberController(memberService)
+	}),
+	fx.Provide(func(serviceAccountService service.ServiceAccountService) ServiceAccountControllerI {
+		return NewServiceAccountController(serviceAccountService)
+	}),
 	fx.Provide(NewController),
 )
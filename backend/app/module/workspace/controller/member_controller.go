@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"strconv"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MemberController
+type memberController struct {
+	memberService service.MemberService
+}
+
+type MemberControllerI interface {
+	ListMembers(c *fiber.Ctx) error
+	AddMember(c *fiber.Ctx) error
+	UpdateMember(c *fiber.Ctx) error
+	RemoveMember(c *fiber.Ctx) error
+}
+
+func NewMemberController(memberService service.MemberService) MemberControllerI {
+	return &memberController{
+		memberService: memberService,
+	}
+}
+
+// ListMembers handler untuk list member workspace
+func (_i *memberController) ListMembers(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	workspaceID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_workspace_id", "invalid workspace id")
+	}
+
+	result, err := _i.memberService.ListMembers(workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"members retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// AddMember handler untuk menambahkan user ke workspace
+func (_i *memberController) AddMember(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	workspaceID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_workspace_id", "invalid workspace id")
+	}
+
+	var req request.AddMemberRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.ErrInvalidBody
+	}
+
+	// Validasi input
+	if err := response.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	result, err := _i.memberService.AddMember(workspaceID, userID, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusCreated,
+		Messages: response.Messages{"member added successfully"},
+		Data:     result,
+	})
+}
+
+// UpdateMember handler untuk mengubah role member
+func (_i *memberController) UpdateMember(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	workspaceID, memberUserID, err := parseMemberParams(c)
+	if err != nil {
+		return err
+	}
+
+	var req request.UpdateMemberRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.ErrInvalidBody
+	}
+
+	// Validasi input
+	if err := response.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	result, err := _i.memberService.UpdateMember(workspaceID, userID, memberUserID, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"member updated successfully"},
+		Data:     result,
+	})
+}
+
+// RemoveMember handler untuk mengeluarkan member dari workspace
+func (_i *memberController) RemoveMember(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	workspaceID, memberUserID, err := parseMemberParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err := _i.memberService.RemoveMember(workspaceID, userID, memberUserID); err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"member removed successfully"},
+	})
+}
+
+// parseMemberParams parse workspace id dan user id member dari path
+func parseMemberParams(c *fiber.Ctx) (uint64, uint64, error) {
+	workspaceID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, apperr.BadRequest("invalid_workspace_id", "invalid workspace id")
+	}
+
+	memberUserID, err := strconv.ParseUint(c.Params("userId"), 10, 64)
+	if err != nil {
+		return 0, 0, apperr.BadRequest("invalid_user_id", "invalid user id")
+	}
+
+	return workspaceID, memberUserID, nil
+}
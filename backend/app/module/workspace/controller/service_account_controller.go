@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"strconv"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
+	token_request "git.dev.siap.id/kukuhkkh/app-diagram/app/module/token/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ServiceAccountController
+type serviceAccountController struct {
+	serviceAccountService service.ServiceAccountService
+}
+
+type ServiceAccountControllerI interface {
+	ListServiceAccounts(c *fiber.Ctx) error
+	CreateServiceAccount(c *fiber.Ctx) error
+	UpdateServiceAccount(c *fiber.Ctx) error
+	DeleteServiceAccount(c *fiber.Ctx) error
+	CreateToken(c *fiber.Ctx) error
+	ListTokens(c *fiber.Ctx) error
+	RevokeToken(c *fiber.Ctx) error
+}
+
+func NewServiceAccountController(serviceAccountService service.ServiceAccountService) ServiceAccountControllerI {
+	return &serviceAccountController{
+		serviceAccountService: serviceAccountService,
+	}
+}
+
+// ListServiceAccounts handler untuk list service account workspace
+func (_i *serviceAccountController) ListServiceAccounts(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	workspaceID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_workspace_id", "invalid workspace id")
+	}
+
+	result, err := _i.serviceAccountService.ListServiceAccounts(workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"service accounts retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// CreateServiceAccount handler untuk membuat service account di workspace
+func (_i *serviceAccountController) CreateServiceAccount(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	workspaceID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_workspace_id", "invalid workspace id")
+	}
+
+	var req request.CreateServiceAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.ErrInvalidBody
+	}
+
+	// Validasi input
+	if err := response.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	result, err := _i.serviceAccountService.CreateServiceAccount(workspaceID, userID, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusCreated,
+		Messages: response.Messages{"service account created successfully"},
+		Data:     result,
+	})
+}
+
+// UpdateServiceAccount handler untuk mengubah nama, role, atau menonaktifkan service account
+func (_i *serviceAccountController) UpdateServiceAccount(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	workspaceID, accountID, err := parseServiceAccountParams(c)
+	if err != nil {
+		return err
+	}
+
+	var req request.UpdateServiceAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.ErrInvalidBody
+	}
+
+	// Validasi input
+	if err := response.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	result, err := _i.serviceAccountService.UpdateServiceAccount(workspaceID, userID, accountID, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"service account updated successfully"},
+		Data:     result,
+	})
+}
+
+// DeleteServiceAccount handler untuk menghapus service account beserta tokennya
+func (_i *serviceAccountController) DeleteServiceAccount(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	workspaceID, accountID, err := parseServiceAccountParams(c)
+	if err != nil {
+		return err
+	}
+
+	if err := _i.serviceAccountService.DeleteServiceAccount(workspaceID, userID, accountID); err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"service account deleted successfully"},
+	})
+}
+
+// CreateToken handler untuk membuat token service account
+func (_i *serviceAccountController) CreateToken(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	workspaceID, accountID, err := parseServiceAccountParams(c)
+	if err != nil {
+		return err
+	}
+
+	var req token_request.CreateTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.ErrInvalidBody
+	}
+
+	// Validasi input
+	if err := response.ValidateStruct(req); err != nil {
+		return err
+	}
+
+	result, err := _i.serviceAccountService.CreateToken(workspaceID, userID, accountID, &req)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusCreated,
+		Messages: response.Messages{"token created successfully, copy it now as it will not be shown again"},
+		Data:     result,
+	})
+}
+
+// ListTokens handler untuk list token service account
+func (_i *serviceAccountController) ListTokens(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	workspaceID, accountID, err := parseServiceAccountParams(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := _i.serviceAccountService.ListTokens(workspaceID, userID, accountID)
+	if err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"tokens retrieved successfully"},
+		Data:     result,
+	})
+}
+
+// RevokeToken handler untuk mencabut token service account
+func (_i *serviceAccountController) RevokeToken(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == 0 {
+		return middleware.ErrUnauthorized
+	}
+
+	workspaceID, accountID, err := parseServiceAccountParams(c)
+	if err != nil {
+		return err
+	}
+
+	tokenID, err := strconv.ParseUint(c.Params("tokenId"), 10, 64)
+	if err != nil {
+		return apperr.BadRequest("invalid_token_id", "invalid token id")
+	}
+
+	if err := _i.serviceAccountService.RevokeToken(workspaceID, userID, accountID, tokenID); err != nil {
+		return err
+	}
+
+	return response.Resp(c, response.Response{
+		Code:     fiber.StatusOK,
+		Messages: response.Messages{"token revoked successfully"},
+	})
+}
+
+// parseServiceAccountParams parse workspace id dan id service account dari path
+func parseServiceAccountParams(c *fiber.Ctx) (uint64, uint64, error) {
+	workspaceID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, apperr.BadRequest("invalid_workspace_id", "invalid workspace id")
+	}
+
+	accountID, err := strconv.ParseUint(c.Params("accountId"), 10, 64)
+	if err != nil {
+		return 0, 0, apperr.BadRequest("invalid_service_account_id", "invalid service account id")
+	}
+
+	return workspaceID, accountID, nil
+}
@@ -6,6 +6,7 @@ import (
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/request"
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
 	"git.dev.siap.id/kukuhkkh/app-diagram/utils/response"
 	"github.com/gofiber/fiber/v2"
 )
@@ -32,18 +33,12 @@ func NewWorkspaceController(workspaceService service.WorkspaceService) Workspace
 func (_i *workspaceController) CreateWorkspace(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 	if userID == 0 {
-		return response.Resp(c, response.Response{
-			Code:     fiber.StatusUnauthorized,
-			Messages: response.Messages{"user not authenticated"},
-		})
+		return middleware.ErrUnauthorized
 	}
 
 	var req request.CreateWorkspaceRequest
 	if err := c.BodyParser(&req); err != nil {
-		return response.Resp(c, response.Response{
-			Code:     fiber.StatusBadRequest,
-			Messages: response.Messages{"invalid request body"},
-		})
+		return response.ErrInvalidBody
 	}
 
 	// Validasi input
@@ -53,10 +48,7 @@ func (_i *workspaceController) CreateWorkspace(c *fiber.Ctx) error {
 
 	result, err := _i.workspaceService.CreateWorkspace(userID, &req)
 	if err != nil {
-		return response.Resp(c, response.Response{
-			Code:     fiber.StatusBadRequest,
-			Messages: response.Messages{err.Error()},
-		})
+		return err
 	}
 
 	return response.Resp(c, response.Response{
@@ -70,34 +62,22 @@ func (_i *workspaceController) CreateWorkspace(c *fiber.Ctx) error {
 func (_i *workspaceController) GetWorkspace(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 	if userID == 0 {
-		return response.Resp(c, response.Response{
-			Code:     fiber.StatusUnauthorized,
-			Messages: response.Messages{"user not authenticated"},
-		})
+		return middleware.ErrUnauthorized
 	}
 
 	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
-		return response.Resp(c, response.Response{
-			Code:     fiber.StatusBadRequest,
-			Messages: response.Messages{"invalid workspace id"},
-		})
+		return apperr.BadRequest("invalid_workspace_id", "invalid workspace id")
 	}
 
 	result, err := _i.workspaceService.GetWorkspace(id, userID)
 	if err != nil {
-		statusCode := fiber.StatusInternalServerError
-		if err.Error() == "workspace not found" {
-			statusCode = fiber.StatusNotFound
-		} else if err.Error() == "you don't have permission to access this workspace" {
-			statusCode = fiber.StatusForbidden
-		}
-		return response.Resp(c, response.Response{
-			Code:     statusCode,
-			Messages: response.Messages{err.Error()},
-		})
+		return err
 	}
 
+	// ETag dipakai client sebagai If-Match saat update
+	response.SetETag(c, service.ETag(result.UpdatedAt))
+
 	return response.Resp(c, response.Response{
 		Code:     fiber.StatusOK,
 		Messages: response.Messages{"workspace retrieved successfully"},
@@ -109,10 +89,7 @@ func (_i *workspaceController) GetWorkspace(c *fiber.Ctx) error {
 func (_i *workspaceController) ListWorkspaces(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 	if userID == 0 {
-		return response.Resp(c, response.Response{
-			Code:     fiber.StatusUnauthorized,
-			Messages: response.Messages{"user not authenticated"},
-		})
+		return middleware.ErrUnauthorized
 	}
 
 	page := 1
@@ -132,10 +109,7 @@ func (_i *workspaceController) ListWorkspaces(c *fiber.Ctx) error {
 
 	result, err := _i.workspaceService.ListWorkspaces(userID, page, limit)
 	if err != nil {
-		return response.Resp(c, response.Response{
-			Code:     fiber.StatusInternalServerError,
-			Messages: response.Messages{err.Error()},
-		})
+		return err
 	}
 
 	return response.Resp(c, response.Response{
@@ -149,26 +123,17 @@ func (_i *workspaceController) ListWorkspaces(c *fiber.Ctx) error {
 func (_i *workspaceController) UpdateWorkspace(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 	if userID == 0 {
-		return response.Resp(c, response.Response{
-			Code:     fiber.StatusUnauthorized,
-			Messages: response.Messages{"user not authenticated"},
-		})
+		return middleware.ErrUnauthorized
 	}
 
 	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
-		return response.Resp(c, response.Response{
-			Code:     fiber.StatusBadRequest,
-			Messages: response.Messages{"invalid workspace id"},
-		})
+		return apperr.BadRequest("invalid_workspace_id", "invalid workspace id")
 	}
 
 	var req request.UpdateWorkspaceRequest
 	if err := c.BodyParser(&req); err != nil {
-		return response.Resp(c, response.Response{
-			Code:     fiber.StatusBadRequest,
-			Messages: response.Messages{"invalid request body"},
-		})
+		return response.ErrInvalidBody
 	}
 
 	// Validasi input (omitempty fields tidak di-validasi jika kosong)
@@ -176,22 +141,18 @@ func (_i *workspaceController) UpdateWorkspace(c *fiber.Ctx) error {
 		return err
 	}
 
-	result, err := _i.workspaceService.UpdateWorkspace(id, userID, &req)
+	etag, err := response.IfMatch(c)
 	if err != nil {
-		statusCode := fiber.StatusInternalServerError
-		if err.Error() == "workspace not found" {
-			statusCode = fiber.StatusNotFound
-		} else if err.Error() == "you don't have permission to update this workspace" {
-			statusCode = fiber.StatusForbidden
-		} else {
-			statusCode = fiber.StatusBadRequest
-		}
-		return response.Resp(c, response.Response{
-			Code:     statusCode,
-			Messages: response.Messages{err.Error()},
-		})
+		return err
 	}
 
+	result, err := _i.workspaceService.UpdateWorkspace(id, userID, etag, &req)
+	if err != nil {
+		return err
+	}
+
+	response.SetETag(c, service.ETag(result.UpdatedAt))
+
 	return response.Resp(c, response.Response{
 		Code:     fiber.StatusOK,
 		Messages: response.Messages{"workspace updated successfully"},
@@ -203,32 +164,17 @@ func (_i *workspaceController) UpdateWorkspace(c *fiber.Ctx) error {
 func (_i *workspaceController) DeleteWorkspace(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 	if userID == 0 {
-		return response.Resp(c, response.Response{
-			Code:     fiber.StatusUnauthorized,
-			Messages: response.Messages{"user not authenticated"},
-		})
+		return middleware.ErrUnauthorized
 	}
 
 	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
-		return response.Resp(c, response.Response{
-			Code:     fiber.StatusBadRequest,
-			Messages: response.Messages{"invalid workspace id"},
-		})
+		return apperr.BadRequest("invalid_workspace_id", "invalid workspace id")
 	}
 
 	err = _i.workspaceService.DeleteWorkspace(id, userID)
 	if err != nil {
-		statusCode := fiber.StatusInternalServerError
-		if err.Error() == "workspace not found" {
-			statusCode = fiber.StatusNotFound
-		} else if err.Error() == "you don't have permission to delete this workspace" {
-			statusCode = fiber.StatusForbidden
-		}
-		return response.Resp(c, response.Response{
-			Code:     statusCode,
-			Messages: response.Messages{err.Error()},
-		})
+		return err
 	}
 
 	return response.Resp(c, response.Response{
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
+	"gorm.io/gorm"
+)
+
+// MemberRepository
+type MemberRepository interface {
+	Create(member *schema.WorkspaceMember) (*schema.WorkspaceMember, error)
+	// CreateWithUser membuat user beserta membership-nya, dipakai untuk service account
+	CreateWithUser(user *schema.User, member *schema.WorkspaceMember) (*schema.WorkspaceMember, error)
+	FindByWorkspaceAndUser(workspaceID uint64, userID uint64) (*schema.WorkspaceMember, error)
+	FindByWorkspaceID(workspaceID uint64) ([]schema.WorkspaceMember, error)
+	FindRolesByUserID(userID uint64, workspaceIDs []uint64) (map[uint64]schema.WorkspaceRole, error)
+	Update(member *schema.WorkspaceMember) error
+	Delete(id uint64) error
+}
+
+type memberRepository struct {
+	db *database.Database
+}
+
+func NewMemberRepository(db *database.Database) MemberRepository {
+	return &memberRepository{
+		db: db,
+	}
+}
+
+func (_i *memberRepository) Create(member *schema.WorkspaceMember) (*schema.WorkspaceMember, error) {
+	if err := _i.db.DB.Create(member).Error; err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// CreateWithUser membuat user dan membership dalam satu transaksi agar tidak ada
+// user tanpa membership jika salah satu gagal
+func (_i *memberRepository) CreateWithUser(user *schema.User, member *schema.WorkspaceMember) (*schema.WorkspaceMember, error) {
+	err := _i.db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		member.UserID = user.ID
+		return tx.Create(member).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+func (_i *memberRepository) FindByWorkspaceAndUser(workspaceID uint64, userID uint64) (*schema.WorkspaceMember, error) {
+	var member schema.WorkspaceMember
+	if err := _i.db.DB.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		First(&member).Error; err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+func (_i *memberRepository) FindByWorkspaceID(workspaceID uint64) ([]schema.WorkspaceMember, error) {
+	var members []schema.WorkspaceMember
+	if err := _i.db.DB.Preload("User").
+		Where("workspace_id = ?", workspaceID).
+		Order("created_at ASC").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// FindRolesByUserID role user pada beberapa workspace sekaligus, key berupa workspace id
+func (_i *memberRepository) FindRolesByUserID(userID uint64, workspaceIDs []uint64) (map[uint64]schema.WorkspaceRole, error) {
+	roles := map[uint64]schema.WorkspaceRole{}
+	if len(workspaceIDs) == 0 {
+		return roles, nil
+	}
+
+	var members []schema.WorkspaceMember
+	if err := _i.db.DB.Where("user_id = ? AND workspace_id IN ?", userID, workspaceIDs).
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+
+	for _, member := range members {
+		roles[member.WorkspaceID] = member.Role
+	}
+
+	return roles, nil
+}
+
+func (_i *memberRepository) Update(member *schema.WorkspaceMember) error {
+	return _i.db.DB.Model(member).
+		Update("role", member.Role).Error
+}
+
+func (_i *memberRepository) Delete(id uint64) error {
+	return _i.db.DB.Where("id = ?", id).
+		Delete(&schema.WorkspaceMember{}).Error
+}
@@ -1,17 +1,23 @@
 package repository
 
 import (
+	"time"
+
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
 	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
+	"gorm.io/gorm"
 )
 
 // WorkspaceRepository
 type WorkspaceRepository interface {
 	Create(workspace *schema.Workspace) (*schema.Workspace, error)
 	FindByID(id uint64) (*schema.Workspace, error)
-	FindByOwnerID(ownerID uint64, limit, offset int) ([]schema.Workspace, error)
-	CountByOwnerID(ownerID uint64) (int64, error)
+	FindByUserID(userID uint64, limit, offset int) ([]schema.Workspace, error)
+	CountByUserID(userID uint64) (int64, error)
+	FindByOwnerID(ownerID uint64, name string, limit, offset int) ([]schema.Workspace, error)
+	CountByOwnerID(ownerID uint64, name string) (int64, error)
 	Update(workspace *schema.Workspace) error
+	UpdateIfUnmodified(workspace *schema.Workspace, unmodifiedSince time.Time) (bool, error)
 	Delete(id uint64) error
 	CheckNameExists(name string, ownerID uint64, excludeID uint64) bool
 }
@@ -42,9 +48,10 @@ func (_i *workspaceRepository) FindByID(id uint64) (*schema.Workspace, error) {
 	return &workspace, nil
 }
 
-func (_i *workspaceRepository) FindByOwnerID(ownerID uint64, limit, offset int) ([]schema.Workspace, error) {
+// FindByUserID list workspace milik user atau yang user menjadi member-nya
+func (_i *workspaceRepository) FindByUserID(userID uint64, limit, offset int) ([]schema.Workspace, error) {
 	var workspaces []schema.Workspace
-	if err := _i.db.DB.Where("owner_id = ?", ownerID).
+	if err := _i.accessibleQuery(userID).
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -55,10 +62,9 @@ func (_i *workspaceRepository) FindByOwnerID(ownerID uint64, limit, offset int)
 	return workspaces, nil
 }
 
-func (_i *workspaceRepository) CountByOwnerID(ownerID uint64) (int64, error) {
+func (_i *workspaceRepository) CountByUserID(userID uint64) (int64, error) {
 	var count int64
-	if err := _i.db.DB.Model(&schema.Workspace{}).
-		Where("owner_id = ?", ownerID).
+	if err := _i.accessibleQuery(userID).
 		Count(&count).Error; err != nil {
 		return 0, err
 	}
@@ -66,9 +72,65 @@ func (_i *workspaceRepository) CountByOwnerID(ownerID uint64) (int64, error) {
 	return count, nil
 }
 
+// FindByOwnerID list workspace milik user, name kosong berarti semua
+func (_i *workspaceRepository) FindByOwnerID(ownerID uint64, name string, limit, offset int) ([]schema.Workspace, error) {
+	var workspaces []schema.Workspace
+	if err := _i.ownedQuery(ownerID, name).
+		Order("id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&workspaces).Error; err != nil {
+		return nil, err
+	}
+
+	return workspaces, nil
+}
+
+func (_i *workspaceRepository) CountByOwnerID(ownerID uint64, name string) (int64, error) {
+	var count int64
+	if err := _i.ownedQuery(ownerID, name).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (_i *workspaceRepository) ownedQuery(ownerID uint64, name string) *gorm.DB {
+	query := _i.db.DB.Model(&schema.Workspace{}).Where("owner_id = ?", ownerID)
+	if name != "" {
+		query = query.Where("name = ?", name)
+	}
+
+	return query
+}
+
+func (_i *workspaceRepository) accessibleQuery(userID uint64) *gorm.DB {
+	members := _i.db.DB.Model(&schema.WorkspaceMember{}).
+		Select("workspace_id").
+		Where("user_id = ?", userID)
+
+	return _i.db.DB.Model(&schema.Workspace{}).
+		Where("owner_id = ? OR id IN (?)", userID, members)
+}
+
+// Update menyimpan semua field, termasuk zero value seperti is_public = false
 func (_i *workspaceRepository) Update(workspace *schema.Workspace) error {
-	return _i.db.DB.Model(workspace).
-		Updates(workspace).Error
+	return _i.db.DB.Save(workspace).Error
+}
+
+// UpdateIfUnmodified update workspace hanya jika updated_at di database masih
+// unmodifiedSince, false jika workspace sudah diubah request lain
+func (_i *workspaceRepository) UpdateIfUnmodified(workspace *schema.Workspace, unmodifiedSince time.Time) (bool, error) {
+	result := _i.db.DB.Model(workspace).
+		Where("updated_at = ?", unmodifiedSince).
+		Select("name", "description", "is_public", "mermaid_validation", "updated_at").
+		Updates(workspace)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
 }
 
 func (_i *workspaceRepository) Delete(id uint64) error {
@@ -1,12 +1,34 @@
 package request
 
 type CreateWorkspaceRequest struct {
-	Name        string  `json:"name" validate:"required,min=1,max=255"`
-	Description *string `json:"description" validate:"omitempty,max=1000"`
+	Name              string  `json:"name" validate:"required,min=1,max=255"`
+	Description       *string `json:"description" validate:"omitempty,max=1000"`
+	MermaidValidation *string `json:"mermaid_validation" validate:"omitempty,oneof=strict warn off"`
 }
 
 type UpdateWorkspaceRequest struct {
-	Name        *string `json:"name" validate:"omitempty,min=1,max=255"`
-	Description *string `json:"description" validate:"omitempty,max=1000"`
-	IsPublic    *bool   `json:"is_public" validate:"omitempty"`
+	Name              *string `json:"name" validate:"omitempty,min=1,max=255"`
+	Description       *string `json:"description" validate:"omitempty,max=1000"`
+	IsPublic          *bool   `json:"is_public" validate:"omitempty"`
+	MermaidValidation *string `json:"mermaid_validation" validate:"omitempty,oneof=strict warn off"`
+}
+
+type AddMemberRequest struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+	Role  string `json:"role" validate:"required,oneof=admin editor viewer"`
+}
+
+type UpdateMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=admin editor viewer"`
+}
+
+type CreateServiceAccountRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+	Role string `json:"role" validate:"required,oneof=admin editor viewer"`
+}
+
+type UpdateServiceAccountRequest struct {
+	Name     *string `json:"name" validate:"omitempty,min=1,max=100"`
+	Role     *string `json:"role" validate:"omitempty,oneof=admin editor viewer"`
+	Disabled *bool   `json:"disabled" validate:"omitempty"`
 }
@@ -1,15 +1,26 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+)
 
 type WorkspaceResponse struct {
-	ID          uint64    `json:"id"`
-	Name        string    `json:"name"`
-	Description *string   `json:"description"`
-	OwnerID     uint64    `json:"owner_id"`
-	IsPublic    bool      `json:"is_public"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                uint64                   `json:"id"`
+	Name              string                   `json:"name"`
+	Description       *string                  `json:"description"`
+	OwnerID           uint64                   `json:"owner_id"`
+	Role              schema.WorkspaceRole     `json:"role,omitempty"` // role user yang request, kosong jika hanya akses public
+	IsPublic          bool                     `json:"is_public"`
+	MermaidValidation schema.MermaidValidation `json:"mermaid_validation"`
+	CreatedAt         time.Time                `json:"created_at"`
+	UpdatedAt         time.Time                `json:"updated_at"`
+}
+
+// ConflictResponse dikirim bersama 409 jika If-Match sudah bukan versi terakhir
+type ConflictResponse struct {
+	Current *WorkspaceResponse `json:"current"`
 }
 
 type WorkspaceListResponse struct {
@@ -18,3 +29,23 @@ type WorkspaceListResponse struct {
 	Page  int                 `json:"page"`
 	Limit int                 `json:"limit"`
 }
+
+type MemberResponse struct {
+	UserID    uint64               `json:"user_id"`
+	Email     string               `json:"email"`
+	Name      string               `json:"name"`
+	Kind      schema.UserKind      `json:"kind"`
+	Role      schema.WorkspaceRole `json:"role"`
+	InvitedBy *uint64              `json:"invited_by"`
+	JoinedAt  time.Time            `json:"joined_at"`
+}
+
+type ServiceAccountResponse struct {
+	ID          uint64               `json:"id"`
+	WorkspaceID uint64               `json:"workspace_id"`
+	Name        string               `json:"name"`
+	Role        schema.WorkspaceRole `json:"role"`
+	Disabled    bool                 `json:"disabled"`
+	CreatedBy   *uint64              `json:"created_by"`
+	CreatedAt   time.Time            `json:"created_at"`
+}
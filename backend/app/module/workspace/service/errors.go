@@ -0,0 +1,20 @@
+package service
+
+import "git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+
+// Error workspace dan member service
+var (
+	ErrWorkspaceNameRequired = apperr.Validation("workspace_name_required", "workspace name is required")
+	ErrUserNotFound          = apperr.NotFound("user_not_found", "user not found")
+	ErrMemberNotFound        = apperr.NotFound("member_not_found", "member not found")
+	ErrAlreadyMember         = apperr.Conflict("already_member", "user is already a member of this workspace")
+	ErrOwnerImmutable        = apperr.Forbidden("owner_immutable", "workspace owner cannot be changed or removed")
+	ErrOwnerOnlyAdmins       = apperr.Forbidden("owner_only_admins", "only the workspace owner can manage admins")
+	ErrWorkspaceConflict     = apperr.Conflict("workspace_conflict", "workspace has been changed since it was retrieved")
+
+	ErrServiceAccountNotFound   = apperr.NotFound("service_account_not_found", "service account not found")
+	ErrServiceAccountExists     = apperr.Conflict("service_account_exists", "service account with this name already exists in this workspace")
+	ErrServiceAccountMember     = apperr.Conflict("service_account_member", "service accounts can only be members of their own workspace")
+	ErrServiceAccountManaged    = apperr.Conflict("service_account_managed", "service account membership is managed via the service accounts endpoint")
+	ErrServiceAccountNotAllowed = apperr.Forbidden("service_account_not_allowed", "service accounts cannot perform this action")
+)
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"errors"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	user_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/user/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/response"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/helpers"
+	"gorm.io/gorm"
+)
+
+// MemberService adalah interface untuk business logic member workspace
+type MemberService interface {
+	ListMembers(workspaceID uint64, userID uint64) ([]response.MemberResponse, error)
+	AddMember(workspaceID uint64, userID uint64, req *request.AddMemberRequest) (*response.MemberResponse, error)
+	UpdateMember(workspaceID uint64, userID uint64, memberUserID uint64, req *request.UpdateMemberRequest) (*response.MemberResponse, error)
+	RemoveMember(workspaceID uint64, userID uint64, memberUserID uint64) error
+}
+
+type memberService struct {
+	workspaceRepo repository.WorkspaceRepository
+	memberRepo    repository.MemberRepository
+	userRepo      user_repo.UserRepository
+	policy        policy.Policy
+}
+
+// NewMemberService instance
+func NewMemberService(
+	workspaceRepo repository.WorkspaceRepository,
+	memberRepo repository.MemberRepository,
+	userRepo user_repo.UserRepository,
+	policy policy.Policy,
+) MemberService {
+	return &memberService{
+		workspaceRepo: workspaceRepo,
+		memberRepo:    memberRepo,
+		userRepo:      userRepo,
+		policy:        policy,
+	}
+}
+
+func (_i *memberService) ListMembers(workspaceID uint64, userID uint64) ([]response.MemberResponse, error) {
+	workspace, err := _i.findWorkspace(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Daftar member hanya untuk member workspace, tidak untuk akses public
+	allowed, err := _i.policy.Can(policy.User(userID), policy.WorkspaceMembersView, policy.WorkspaceResource(workspace))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, policy.Denied(policy.WorkspaceMembersView)
+	}
+
+	members, err := _i.memberRepo.FindByWorkspaceID(workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]response.MemberResponse, 0, len(members)+1)
+
+	// Owner selalu ditampilkan pertama
+	owner, err := _i.userRepo.FindUserByID(workspace.OwnerID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if owner != nil {
+		responses = append(responses, response.MemberResponse{
+			UserID:   owner.ID,
+			Email:    owner.Email,
+			Name:     owner.Name,
+			Kind:     owner.Kind,
+			Role:     schema.WorkspaceRoleOwner,
+			JoinedAt: workspace.CreatedAt,
+		})
+	}
+
+	for _, member := range members {
+		responses = append(responses, *toMemberResponse(&member))
+	}
+
+	return responses, nil
+}
+
+func (_i *memberService) AddMember(workspaceID uint64, userID uint64, req *request.AddMemberRequest) (*response.MemberResponse, error) {
+	workspace, role, err := _i.authorizeManage(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	newRole := schema.WorkspaceRole(req.Role)
+	if newRole == schema.WorkspaceRoleAdmin && role != schema.WorkspaceRoleOwner {
+		return nil, ErrOwnerOnlyAdmins
+	}
+
+	user, err := _i.userRepo.FindUserByEmail(helpers.NormalizeEmail(req.Email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if user.ID == workspace.OwnerID {
+		return nil, ErrAlreadyMember
+	}
+
+	// Service account hanya menjadi member workspace pemiliknya, role diatur lewat endpoint service account
+	if user.IsServiceAccount() {
+		return nil, ErrServiceAccountMember
+	}
+
+	if _, err := _i.memberRepo.FindByWorkspaceAndUser(workspace.ID, user.ID); err == nil {
+		return nil, ErrAlreadyMember
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	member := &schema.WorkspaceMember{
+		WorkspaceID: workspace.ID,
+		UserID:      user.ID,
+		Role:        newRole,
+		InvitedBy:   &userID,
+	}
+
+	created, err := _i.memberRepo.Create(member)
+	if err != nil {
+		return nil, err
+	}
+	created.User = user
+
+	return toMemberResponse(created), nil
+}
+
+func (_i *memberService) UpdateMember(workspaceID uint64, userID uint64, memberUserID uint64, req *request.UpdateMemberRequest) (*response.MemberResponse, error) {
+	workspace, role, err := _i.authorizeManage(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := _i.findMember(workspace, memberUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Admin hanya boleh diangkat atau diubah oleh owner
+	newRole := schema.WorkspaceRole(req.Role)
+	if (newRole == schema.WorkspaceRoleAdmin || member.Role == schema.WorkspaceRoleAdmin) && role != schema.WorkspaceRoleOwner {
+		return nil, ErrOwnerOnlyAdmins
+	}
+
+	member.Role = newRole
+	if err := _i.memberRepo.Update(member); err != nil {
+		return nil, err
+	}
+
+	user, err := _i.userRepo.FindUserByID(member.UserID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	member.User = user
+
+	return toMemberResponse(member), nil
+}
+
+func (_i *memberService) RemoveMember(workspaceID uint64, userID uint64, memberUserID uint64) error {
+	workspace, err := _i.findWorkspace(workspaceID)
+	if err != nil {
+		return err
+	}
+
+	member, err := _i.findMember(workspace, memberUserID)
+	if err != nil {
+		return err
+	}
+
+	// Membership service account dikelola lewat endpoint service account
+	memberUser, err := _i.userRepo.FindUserByID(member.UserID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if memberUser != nil && memberUser.IsServiceAccount() {
+		return ErrServiceAccountManaged
+	}
+
+	// Member boleh keluar sendiri dari workspace
+	if member.UserID != userID {
+		_, role, err := _i.authorizeManage(workspace.ID, userID)
+		if err != nil {
+			return err
+		}
+		if member.Role == schema.WorkspaceRoleAdmin && role != schema.WorkspaceRoleOwner {
+			return ErrOwnerOnlyAdmins
+		}
+	}
+
+	return _i.memberRepo.Delete(member.ID)
+}
+
+// Helper: ambil workspace dan pastikan user boleh mengelola member
+func (_i *memberService) authorizeManage(workspaceID uint64, userID uint64) (*schema.Workspace, schema.WorkspaceRole, error) {
+	workspace, err := _i.findWorkspace(workspaceID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	allowed, err := _i.policy.Can(policy.User(userID), policy.WorkspaceMembersManage, policy.WorkspaceResource(workspace))
+	if err != nil {
+		return nil, "", err
+	}
+	if !allowed {
+		return nil, "", policy.Denied(policy.WorkspaceMembersManage)
+	}
+
+	role, err := _i.policy.Role(policy.User(userID), workspace)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return workspace, role, nil
+}
+
+func (_i *memberService) findWorkspace(id uint64) (*schema.Workspace, error) {
+	workspace, err := _i.workspaceRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, policy.ErrWorkspaceNotFound
+		}
+		return nil, err
+	}
+
+	return workspace, nil
+}
+
+// Helper: owner tidak punya baris member sehingga tidak bisa diubah atau dihapus
+func (_i *memberService) findMember(workspace *schema.Workspace, memberUserID uint64) (*schema.WorkspaceMember, error) {
+	if memberUserID == workspace.OwnerID {
+		return nil, ErrOwnerImmutable
+	}
+
+	member, err := _i.memberRepo.FindByWorkspaceAndUser(workspace.ID, memberUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// Helper: convert schema to response
+func toMemberResponse(member *schema.WorkspaceMember) *response.MemberResponse {
+	res := &response.MemberResponse{
+		UserID:    member.UserID,
+		Role:      member.Role,
+		InvitedBy: member.InvitedBy,
+		JoinedAt:  member.CreatedAt,
+	}
+
+	if member.User != nil {
+		res.Email = member.User.Email
+		res.Name = member.User.Name
+		res.Kind = member.User.Kind
+	}
+
+	return res
+}
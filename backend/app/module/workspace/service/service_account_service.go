@@ -0,0 +1,371 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	token_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/token/repository"
+	token_request "git.dev.siap.id/kukuhkkh/app-diagram/app/module/token/request"
+	token_response "git.dev.siap.id/kukuhkkh/app-diagram/app/module/token/response"
+	token_service "git.dev.siap.id/kukuhkkh/app-diagram/app/module/token/service"
+	user_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/user/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/response"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/helpers"
+	"gorm.io/gorm"
+)
+
+// serviceAccountEmailDomain domain email sintetis service account, .invalid tidak pernah bisa menerima email
+const serviceAccountEmailDomain = "service-accounts.invalid"
+
+// ServiceAccountService adalah interface untuk business logic service account workspace
+type ServiceAccountService interface {
+	ListServiceAccounts(workspaceID uint64, userID uint64) ([]response.ServiceAccountResponse, error)
+	CreateServiceAccount(workspaceID uint64, userID uint64, req *request.CreateServiceAccountRequest) (*response.ServiceAccountResponse, error)
+	UpdateServiceAccount(workspaceID uint64, userID uint64, accountID uint64, req *request.UpdateServiceAccountRequest) (*response.ServiceAccountResponse, error)
+	DeleteServiceAccount(workspaceID uint64, userID uint64, accountID uint64) error
+
+	// Token service account
+	CreateToken(workspaceID uint64, userID uint64, accountID uint64, req *token_request.CreateTokenRequest) (*token_response.CreatedTokenResponse, error)
+	ListTokens(workspaceID uint64, userID uint64, accountID uint64) ([]token_response.TokenResponse, error)
+	RevokeToken(workspaceID uint64, userID uint64, accountID uint64, tokenID uint64) error
+}
+
+type serviceAccountService struct {
+	workspaceRepo repository.WorkspaceRepository
+	memberRepo    repository.MemberRepository
+	userRepo      user_repo.UserRepository
+	tokenRepo     token_repo.TokenRepository
+	tokenService  token_service.TokenService
+	policy        policy.Policy
+}
+
+// NewServiceAccountService instance
+func NewServiceAccountService(
+	workspaceRepo repository.WorkspaceRepository,
+	memberRepo repository.MemberRepository,
+	userRepo user_repo.UserRepository,
+	tokenRepo token_repo.TokenRepository,
+	tokenService token_service.TokenService,
+	policy policy.Policy,
+) ServiceAccountService {
+	return &serviceAccountService{
+		workspaceRepo: workspaceRepo,
+		memberRepo:    memberRepo,
+		userRepo:      userRepo,
+		tokenRepo:     tokenRepo,
+		tokenService:  tokenService,
+		policy:        policy,
+	}
+}
+
+func (_i *serviceAccountService) ListServiceAccounts(workspaceID uint64, userID uint64) ([]response.ServiceAccountResponse, error) {
+	workspace, err := _i.authorize(workspaceID, userID, policy.WorkspaceMembersView)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := _i.userRepo.FindServiceAccounts(workspace.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]response.ServiceAccountResponse, 0, len(accounts))
+	for i := range accounts {
+		member, err := _i.findMembership(workspace.ID, accounts[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *toServiceAccountResponse(&accounts[i], member))
+	}
+
+	return responses, nil
+}
+
+func (_i *serviceAccountService) CreateServiceAccount(workspaceID uint64, userID uint64, req *request.CreateServiceAccountRequest) (*response.ServiceAccountResponse, error) {
+	workspace, err := _i.authorize(workspaceID, userID, policy.WorkspaceMembersManage)
+	if err != nil {
+		return nil, err
+	}
+
+	// Service account tidak boleh membuat service account lain
+	if err := _i.ensureHuman(userID); err != nil {
+		return nil, err
+	}
+
+	role := schema.WorkspaceRole(req.Role)
+	if err := _i.ensureCanGrant(workspace, userID, role); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if err := _i.ensureNameAvailable(workspace.ID, name, 0); err != nil {
+		return nil, err
+	}
+
+	email, err := serviceAccountEmail(workspace.ID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &schema.User{
+		Name:        name,
+		Email:       email,
+		Kind:        schema.UserKindService,
+		WorkspaceID: &workspace.ID,
+	}
+
+	member, err := _i.memberRepo.CreateWithUser(account, &schema.WorkspaceMember{
+		WorkspaceID: workspace.ID,
+		Role:        role,
+		InvitedBy:   &userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toServiceAccountResponse(account, member), nil
+}
+
+func (_i *serviceAccountService) UpdateServiceAccount(workspaceID uint64, userID uint64, accountID uint64, req *request.UpdateServiceAccountRequest) (*response.ServiceAccountResponse, error) {
+	workspace, account, member, err := _i.authorizeAccount(workspaceID, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Service account admin hanya boleh diubah owner, termasuk nama dan disabled
+	if err := _i.ensureCanGrant(workspace, userID, member.Role); err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if err := _i.ensureNameAvailable(workspace.ID, name, account.ID); err != nil {
+			return nil, err
+		}
+		account.Name = name
+	}
+
+	if req.Disabled != nil {
+		account.Disabled = *req.Disabled
+	}
+
+	if req.Role != nil {
+		role := schema.WorkspaceRole(*req.Role)
+		if err := _i.ensureCanGrant(workspace, userID, role); err != nil {
+			return nil, err
+		}
+
+		member.Role = role
+		if err := _i.memberRepo.Update(member); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := _i.userRepo.UpdateUser(account); err != nil {
+		return nil, err
+	}
+
+	return toServiceAccountResponse(account, member), nil
+}
+
+func (_i *serviceAccountService) DeleteServiceAccount(workspaceID uint64, userID uint64, accountID uint64) error {
+	workspace, account, member, err := _i.authorizeAccount(workspaceID, userID, accountID)
+	if err != nil {
+		return err
+	}
+
+	if err := _i.ensureCanGrant(workspace, userID, member.Role); err != nil {
+		return err
+	}
+
+	// Token dicabut lebih dulu agar service account langsung tidak bisa dipakai
+	if err := _i.tokenRepo.DeleteByUserID(account.ID); err != nil {
+		return err
+	}
+
+	if err := _i.memberRepo.Delete(member.ID); err != nil {
+		return err
+	}
+
+	// Soft delete, version yang pernah dibuat service account tetap punya author
+	return _i.userRepo.DeleteUser(account.ID)
+}
+
+func (_i *serviceAccountService) CreateToken(workspaceID uint64, userID uint64, accountID uint64, req *token_request.CreateTokenRequest) (*token_response.CreatedTokenResponse, error) {
+	_, account, _, err := _i.authorizeAccount(workspaceID, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return _i.tokenService.CreateToken(account.ID, req)
+}
+
+func (_i *serviceAccountService) ListTokens(workspaceID uint64, userID uint64, accountID uint64) ([]token_response.TokenResponse, error) {
+	_, account, _, err := _i.authorizeAccount(workspaceID, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return _i.tokenService.ListTokens(account.ID)
+}
+
+func (_i *serviceAccountService) RevokeToken(workspaceID uint64, userID uint64, accountID uint64, tokenID uint64) error {
+	_, account, _, err := _i.authorizeAccount(workspaceID, userID, accountID)
+	if err != nil {
+		return err
+	}
+
+	return _i.tokenService.RevokeToken(account.ID, tokenID)
+}
+
+// Helper: ambil workspace dan pastikan user boleh melakukan action
+func (_i *serviceAccountService) authorize(workspaceID uint64, userID uint64, action policy.Action) (*schema.Workspace, error) {
+	workspace, err := _i.workspaceRepo.FindByID(workspaceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, policy.ErrWorkspaceNotFound
+		}
+		return nil, err
+	}
+
+	allowed, err := _i.policy.Can(policy.User(userID), action, policy.WorkspaceResource(workspace))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, policy.Denied(action)
+	}
+
+	return workspace, nil
+}
+
+// Helper: ambil service account milik workspace beserta membershipnya untuk dikelola
+func (_i *serviceAccountService) authorizeAccount(workspaceID uint64, userID uint64, accountID uint64) (*schema.Workspace, *schema.User, *schema.WorkspaceMember, error) {
+	workspace, err := _i.authorize(workspaceID, userID, policy.WorkspaceMembersManage)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Service account tidak boleh mengelola service account termasuk dirinya sendiri
+	if err := _i.ensureHuman(userID); err != nil {
+		return nil, nil, nil, err
+	}
+
+	account, err := _i.userRepo.FindServiceAccount(workspace.ID, accountID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil, ErrServiceAccountNotFound
+		}
+		return nil, nil, nil, err
+	}
+
+	member, err := _i.findMembership(workspace.ID, account.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if member == nil {
+		return nil, nil, nil, ErrServiceAccountNotFound
+	}
+
+	return workspace, account, member, nil
+}
+
+// Helper: membership service account, nil jika tidak ada
+func (_i *serviceAccountService) findMembership(workspaceID uint64, accountID uint64) (*schema.WorkspaceMember, error) {
+	member, err := _i.memberRepo.FindByWorkspaceAndUser(workspaceID, accountID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return member, nil
+}
+
+func (_i *serviceAccountService) ensureHuman(userID uint64) error {
+	user, err := _i.userRepo.FindUserByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.IsServiceAccount() {
+		return ErrServiceAccountNotAllowed
+	}
+
+	return nil
+}
+
+// Helper: role admin hanya boleh diberikan atau diubah oleh owner, sama seperti member biasa
+func (_i *serviceAccountService) ensureCanGrant(workspace *schema.Workspace, userID uint64, role schema.WorkspaceRole) error {
+	if role != schema.WorkspaceRoleAdmin {
+		return nil
+	}
+
+	current, err := _i.policy.Role(policy.User(userID), workspace)
+	if err != nil {
+		return err
+	}
+	if current != schema.WorkspaceRoleOwner {
+		return ErrOwnerOnlyAdmins
+	}
+
+	return nil
+}
+
+// Helper: nama service account unik per workspace
+func (_i *serviceAccountService) ensureNameAvailable(workspaceID uint64, name string, exceptID uint64) error {
+	accounts, err := _i.userRepo.FindServiceAccounts(workspaceID)
+	if err != nil {
+		return err
+	}
+
+	for _, account := range accounts {
+		if account.ID != exceptID && strings.EqualFold(account.Name, name) {
+			return ErrServiceAccountExists
+		}
+	}
+
+	return nil
+}
+
+// serviceAccountEmail email sintetis agar constraint unique email tetap terpenuhi,
+// suffix acak karena email service account yang sudah di-soft delete tetap tersimpan
+func serviceAccountEmail(workspaceID uint64, name string) (string, error) {
+	slug := helpers.Slug(name)
+	if slug == "" {
+		slug = "bot"
+	}
+
+	suffix, err := helpers.GenerateSecureToken(6)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s-%d-%s@%s", slug, workspaceID, strings.ToLower(suffix), serviceAccountEmailDomain), nil
+}
+
+// Helper: convert schema to response
+func toServiceAccountResponse(account *schema.User, member *schema.WorkspaceMember) *response.ServiceAccountResponse {
+	res := &response.ServiceAccountResponse{
+		ID:        account.ID,
+		Name:      account.Name,
+		Disabled:  account.Disabled,
+		CreatedAt: account.CreatedAt,
+	}
+
+	if account.WorkspaceID != nil {
+		res.WorkspaceID = *account.WorkspaceID
+	}
+
+	if member != nil {
+		res.Role = member.Role
+		res.CreatedBy = member.InvitedBy
+	}
+
+	return res
+}
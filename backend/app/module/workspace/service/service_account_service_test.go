@@ -0,0 +1,127 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	user_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/user/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/repository"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/request"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+)
+
+const (
+	testWorkspaceID = 1
+	testAdminID     = 10
+	testAccountID   = 20
+)
+
+type fakeWorkspaceRepo struct {
+	repository.WorkspaceRepository
+}
+
+func (fakeWorkspaceRepo) FindByID(id uint64) (*schema.Workspace, error) {
+	return &schema.Workspace{ID: id}, nil
+}
+
+type fakeMemberRepo struct {
+	repository.MemberRepository
+	role    schema.WorkspaceRole
+	updated bool
+}
+
+func (_i *fakeMemberRepo) FindByWorkspaceAndUser(workspaceID uint64, userID uint64) (*schema.WorkspaceMember, error) {
+	return &schema.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID, Role: _i.role}, nil
+}
+
+func (_i *fakeMemberRepo) Update(*schema.WorkspaceMember) error {
+	_i.updated = true
+	return nil
+}
+
+type fakeUserRepo struct {
+	user_repo.UserRepository
+	updated bool
+}
+
+func (fakeUserRepo) FindUserByID(id uint64) (*schema.User, error) {
+	return &schema.User{ID: id, Kind: schema.UserKindHuman}, nil
+}
+
+func (fakeUserRepo) FindServiceAccount(workspaceID uint64, id uint64) (*schema.User, error) {
+	return &schema.User{ID: id, Name: "ci", Kind: schema.UserKindService, WorkspaceID: &workspaceID}, nil
+}
+
+func (fakeUserRepo) FindServiceAccounts(uint64) ([]schema.User, error) {
+	return nil, nil
+}
+
+func (_i *fakeUserRepo) UpdateUser(*schema.User) error {
+	_i.updated = true
+	return nil
+}
+
+// fakePolicy user boleh semua action dengan role tetap
+type fakePolicy struct {
+	role schema.WorkspaceRole
+}
+
+func (fakePolicy) Can(policy.Subject, policy.Action, policy.Resource) (bool, error) {
+	return true, nil
+}
+
+func (_i fakePolicy) Role(policy.Subject, *schema.Workspace) (schema.WorkspaceRole, error) {
+	return _i.role, nil
+}
+
+func TestUpdateServiceAccountRequiresOwnerForAdminAccount(t *testing.T) {
+	name, disabled := "renamed", true
+	requests := map[string]*request.UpdateServiceAccountRequest{
+		"name":     {Name: &name},
+		"disabled": {Disabled: &disabled},
+	}
+
+	for field, req := range requests {
+		t.Run(field, func(t *testing.T) {
+			members, users := &fakeMemberRepo{role: schema.WorkspaceRoleAdmin}, &fakeUserRepo{}
+			svc := NewServiceAccountService(fakeWorkspaceRepo{}, members, users, nil, nil, fakePolicy{role: schema.WorkspaceRoleAdmin})
+
+			_, err := svc.UpdateServiceAccount(testWorkspaceID, testAdminID, testAccountID, req)
+			if !errors.Is(err, ErrOwnerOnlyAdmins) {
+				t.Fatalf("UpdateServiceAccount error = %v, want ErrOwnerOnlyAdmins", err)
+			}
+			if users.updated || members.updated {
+				t.Fatalf("admin service account was changed by a non-owner")
+			}
+		})
+	}
+}
+
+func TestUpdateServiceAccount(t *testing.T) {
+	name := "renamed"
+
+	cases := []struct {
+		name    string
+		account schema.WorkspaceRole
+		caller  schema.WorkspaceRole
+	}{
+		{"admin updates editor account", schema.WorkspaceRoleEditor, schema.WorkspaceRoleAdmin},
+		{"owner updates admin account", schema.WorkspaceRoleAdmin, schema.WorkspaceRoleOwner},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			members, users := &fakeMemberRepo{role: tc.account}, &fakeUserRepo{}
+			svc := NewServiceAccountService(fakeWorkspaceRepo{}, members, users, nil, nil, fakePolicy{role: tc.caller})
+
+			res, err := svc.UpdateServiceAccount(testWorkspaceID, testAdminID, testAccountID, &request.UpdateServiceAccountRequest{Name: &name})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !users.updated || res.Name != name {
+				t.Fatalf("account = {updated: %v, name: %q}, want {updated: true, name: %q}", users.updated, res.Name, name)
+			}
+		})
+	}
+}
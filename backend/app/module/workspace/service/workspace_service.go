@@ -3,12 +3,16 @@ package service
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	user_repo "git.dev.siap.id/kukuhkkh/app-diagram/app/module/user/repository"
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/repository"
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/request"
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/response"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
 	"gorm.io/gorm"
 )
 
@@ -17,39 +21,65 @@ type WorkspaceService interface {
 	CreateWorkspace(userID uint64, req *request.CreateWorkspaceRequest) (*response.WorkspaceResponse, error)
 	GetWorkspace(id uint64, userID uint64) (*response.WorkspaceResponse, error)
 	ListWorkspaces(userID uint64, page, limit int) (*response.WorkspaceListResponse, error)
-	UpdateWorkspace(id uint64, userID uint64, req *request.UpdateWorkspaceRequest) (*response.WorkspaceResponse, error)
+	UpdateWorkspace(id uint64, userID uint64, etag string, req *request.UpdateWorkspaceRequest) (*response.WorkspaceResponse, error)
 	DeleteWorkspace(id uint64, userID uint64) error
 }
 
 type workspaceService struct {
 	workspaceRepo repository.WorkspaceRepository
+	memberRepo    repository.MemberRepository
+	userRepo      user_repo.UserRepository
+	policy        policy.Policy
 }
 
 // NewWorkspaceService instance
-func NewWorkspaceService(workspaceRepo repository.WorkspaceRepository) WorkspaceService {
+func NewWorkspaceService(
+	workspaceRepo repository.WorkspaceRepository,
+	memberRepo repository.MemberRepository,
+	userRepo user_repo.UserRepository,
+	policy policy.Policy,
+) WorkspaceService {
 	return &workspaceService{
 		workspaceRepo: workspaceRepo,
+		memberRepo:    memberRepo,
+		userRepo:      userRepo,
+		policy:        policy,
 	}
 }
 
 func (_i *workspaceService) CreateWorkspace(userID uint64, req *request.CreateWorkspaceRequest) (*response.WorkspaceResponse, error) {
 	// Validasi input
 	if req.Name == "" {
-		return nil, errors.New("workspace name is required")
+		return nil, ErrWorkspaceNameRequired
+	}
+
+	// Service account terikat ke satu workspace dan tidak boleh membuat workspace baru
+	user, err := _i.userRepo.FindUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsServiceAccount() {
+		return nil, ErrServiceAccountNotAllowed
 	}
 
 	// Check if nama workspace sudah ada untuk user
 	if _i.workspaceRepo.CheckNameExists(req.Name, userID, 0) {
-		return nil, fmt.Errorf("workspace with name '%s' already exists", req.Name)
+		return nil, apperr.Conflict("workspace_name_taken", fmt.Sprintf("workspace with name '%s' already exists", req.Name))
+	}
+
+	validation := schema.MermaidValidationStrict
+	if req.MermaidValidation != nil {
+		validation = schema.MermaidValidation(*req.MermaidValidation)
 	}
 
 	workspace := &schema.Workspace{
-		OwnerID:     userID,
-		Name:        req.Name,
-		Description: req.Description,
-		IsPublic:    false,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		OwnerID:           userID,
+		Name:              req.Name,
+		Description:       req.Description,
+		IsPublic:          false,
+		MermaidValidation: validation,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
 	}
 
 	created, err := _i.workspaceRepo.Create(workspace)
@@ -57,24 +87,33 @@ func (_i *workspaceService) CreateWorkspace(userID uint64, req *request.CreateWo
 		return nil, err
 	}
 
-	return _i.toResponse(created), nil
+	return _i.toResponse(created, schema.WorkspaceRoleOwner), nil
 }
 
 func (_i *workspaceService) GetWorkspace(id uint64, userID uint64) (*response.WorkspaceResponse, error) {
 	workspace, err := _i.workspaceRepo.FindByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("workspace not found")
+			return nil, policy.ErrWorkspaceNotFound
 		}
 		return nil, err
 	}
 
-	// Validasi ownership: user hanya bisa akses workspace miliknya
-	if workspace.OwnerID != userID && !workspace.IsPublic {
-		return nil, errors.New("you don't have permission to access this workspace")
+	// Validasi akses: member workspace atau workspace public
+	allowed, err := _i.policy.Can(policy.User(userID), policy.WorkspaceView, policy.WorkspaceResource(workspace))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, policy.Denied(policy.WorkspaceView)
+	}
+
+	role, err := _i.policy.Role(policy.User(userID), workspace)
+	if err != nil {
+		return nil, err
 	}
 
-	return _i.toResponse(workspace), nil
+	return _i.toResponse(workspace, role), nil
 }
 
 func (_i *workspaceService) ListWorkspaces(userID uint64, page, limit int) (*response.WorkspaceListResponse, error) {
@@ -88,19 +127,33 @@ func (_i *workspaceService) ListWorkspaces(userID uint64, page, limit int) (*res
 
 	offset := (page - 1) * limit
 
-	workspaces, err := _i.workspaceRepo.FindByOwnerID(userID, limit, offset)
+	workspaces, err := _i.workspaceRepo.FindByUserID(userID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
 
-	total, err := _i.workspaceRepo.CountByOwnerID(userID)
+	total, err := _i.workspaceRepo.CountByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint64, 0, len(workspaces))
+	for _, ws := range workspaces {
+		ids = append(ids, ws.ID)
+	}
+
+	roles, err := _i.memberRepo.FindRolesByUserID(userID, ids)
 	if err != nil {
 		return nil, err
 	}
 
 	responses := make([]response.WorkspaceResponse, 0, len(workspaces))
 	for _, ws := range workspaces {
-		responses = append(responses, *_i.toResponse(&ws))
+		role := roles[ws.ID]
+		if ws.OwnerID == userID {
+			role = schema.WorkspaceRoleOwner
+		}
+		responses = append(responses, *_i.toResponse(&ws, role))
 	}
 
 	return &response.WorkspaceListResponse{
@@ -111,25 +164,40 @@ func (_i *workspaceService) ListWorkspaces(userID uint64, page, limit int) (*res
 	}, nil
 }
 
-func (_i *workspaceService) UpdateWorkspace(id uint64, userID uint64, req *request.UpdateWorkspaceRequest) (*response.WorkspaceResponse, error) {
+// UpdateWorkspace etag dari header If-Match, kosong jika client tidak mengirim kondisi
+func (_i *workspaceService) UpdateWorkspace(id uint64, userID uint64, etag string, req *request.UpdateWorkspaceRequest) (*response.WorkspaceResponse, error) {
 	workspace, err := _i.workspaceRepo.FindByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("workspace not found")
+			return nil, policy.ErrWorkspaceNotFound
 		}
 		return nil, err
 	}
 
-	// Validasi ownership
-	if workspace.OwnerID != userID {
-		return nil, errors.New("you don't have permission to update this workspace")
+	// Validasi akses: minimal admin
+	allowed, err := _i.policy.Can(policy.User(userID), policy.WorkspaceUpdate, policy.WorkspaceResource(workspace))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, policy.Denied(policy.WorkspaceUpdate)
+	}
+
+	role, err := _i.policy.Role(policy.User(userID), workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	unmodifiedSince := workspace.UpdatedAt
+	if etag != "" && etag != ETag(unmodifiedSince) {
+		return nil, _i.conflict(id, role)
 	}
 
 	// Update fields jika ada
 	if req.Name != nil && *req.Name != "" {
-		// Check if new name sudah ada
-		if _i.workspaceRepo.CheckNameExists(*req.Name, userID, id) {
-			return nil, fmt.Errorf("workspace with name '%s' already exists", *req.Name)
+		// Check if new name sudah ada, nama unik per owner
+		if _i.workspaceRepo.CheckNameExists(*req.Name, workspace.OwnerID, id) {
+			return nil, apperr.Conflict("workspace_name_taken", fmt.Sprintf("workspace with name '%s' already exists", *req.Name))
 		}
 		workspace.Name = *req.Name
 	}
@@ -142,27 +210,52 @@ func (_i *workspaceService) UpdateWorkspace(id uint64, userID uint64, req *reque
 		workspace.IsPublic = *req.IsPublic
 	}
 
+	if req.MermaidValidation != nil {
+		workspace.MermaidValidation = schema.MermaidValidation(*req.MermaidValidation)
+	}
+
 	workspace.UpdatedAt = time.Now()
 
-	if err := _i.workspaceRepo.Update(workspace); err != nil {
+	if etag == "" {
+		if err := _i.workspaceRepo.Update(workspace); err != nil {
+			return nil, err
+		}
+	} else {
+		// Cek ulang saat update untuk request lain yang lolos pengecekan di atas
+		updated, err := _i.workspaceRepo.UpdateIfUnmodified(workspace, unmodifiedSince)
+		if err != nil {
+			return nil, err
+		}
+		if !updated {
+			return nil, _i.conflict(id, role)
+		}
+	}
+
+	// Dibaca ulang agar updated_at, dan ETag, sesuai presisi timestamp di database
+	workspace, err = _i.workspaceRepo.FindByID(id)
+	if err != nil {
 		return nil, err
 	}
 
-	return _i.toResponse(workspace), nil
+	return _i.toResponse(workspace, role), nil
 }
 
 func (_i *workspaceService) DeleteWorkspace(id uint64, userID uint64) error {
 	workspace, err := _i.workspaceRepo.FindByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("workspace not found")
+			return policy.ErrWorkspaceNotFound
 		}
 		return err
 	}
 
-	// Validasi ownership
-	if workspace.OwnerID != userID {
-		return errors.New("you don't have permission to delete this workspace")
+	// Validasi akses: hanya owner yang bisa menghapus workspace
+	allowed, err := _i.policy.Can(policy.User(userID), policy.WorkspaceDelete, policy.WorkspaceResource(workspace))
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return policy.Denied(policy.WorkspaceDelete)
 	}
 
 	if err := _i.workspaceRepo.Delete(id); err != nil {
@@ -172,15 +265,34 @@ func (_i *workspaceService) DeleteWorkspace(id uint64, userID uint64) error {
 	return nil
 }
 
+// Helper: error 409 berisi workspace terbaru
+func (_i *workspaceService) conflict(id uint64, role schema.WorkspaceRole) error {
+	workspace, err := _i.workspaceRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	return ErrWorkspaceConflict.WithData(&response.ConflictResponse{
+		Current: _i.toResponse(workspace, role),
+	})
+}
+
+// ETag penanda versi workspace dari updated_at dalam mikrodetik
+func ETag(updatedAt time.Time) string {
+	return strconv.FormatInt(updatedAt.UnixMicro(), 10)
+}
+
 // Helper: convert schema to response
-func (_i *workspaceService) toResponse(workspace *schema.Workspace) *response.WorkspaceResponse {
+func (_i *workspaceService) toResponse(workspace *schema.Workspace, role schema.WorkspaceRole) *response.WorkspaceResponse {
 	return &response.WorkspaceResponse{
-		ID:          workspace.ID,
-		Name:        workspace.Name,
-		Description: workspace.Description,
-		OwnerID:     workspace.OwnerID,
-		IsPublic:    workspace.IsPublic,
-		CreatedAt:   workspace.CreatedAt,
-		UpdatedAt:   workspace.UpdatedAt,
+		ID:                workspace.ID,
+		Name:              workspace.Name,
+		Description:       workspace.Description,
+		OwnerID:           workspace.OwnerID,
+		Role:              role,
+		IsPublic:          workspace.IsPublic,
+		MermaidValidation: workspace.MermaidValidation,
+		CreatedAt:         workspace.CreatedAt,
+		UpdatedAt:         workspace.UpdatedAt,
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/controller"
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/repository"
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace/service"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/fx"
 )
@@ -14,15 +15,19 @@ type WorkspaceRouter struct {
 	App        fiber.Router
 	Controller *controller.Controller
 	AuthMW     *middleware.AuthMiddleware
+	PolicyMW   *middleware.PolicyMiddleware
 }
 
 // Module adalah FX module untuk workspace
 var NewWorkspaceModule = fx.Options(
 	// register repository
 	fx.Provide(repository.NewWorkspaceRepository),
+	fx.Provide(repository.NewMemberRepository),
 
 	// register service
 	fx.Provide(service.NewWorkspaceService),
+	fx.Provide(service.NewMemberService),
+	fx.Provide(service.NewServiceAccountService),
 
 	// register controller
 	controller.Module,
@@ -36,11 +41,13 @@ func NewWorkspaceRouter(
 	app *fiber.App,
 	ctrl *controller.Controller,
 	authMW *middleware.AuthMiddleware,
+	policyMW *middleware.PolicyMiddleware,
 ) *WorkspaceRouter {
 	return &WorkspaceRouter{
 		App:        app,
 		Controller: ctrl,
 		AuthMW:     authMW,
+		PolicyMW:   policyMW,
 	}
 }
 
@@ -48,14 +55,30 @@ func NewWorkspaceRouter(
 func (_i *WorkspaceRouter) RegisterWorkspaceRoutes() {
 	// define controllers
 	workspaceController := _i.Controller.Workspace
+	memberController := _i.Controller.Member
+	serviceAccountController := _i.Controller.ServiceAccount
 
 	_i.App.Route("/api/v1", func(router fiber.Router) {
 		workspaceRoutes := router.Group("/workspaces", _i.AuthMW.RequireAuth())
 
 		workspaceRoutes.Post("", workspaceController.CreateWorkspace)
 		workspaceRoutes.Get("", workspaceController.ListWorkspaces)
-		workspaceRoutes.Get("/:id", workspaceController.GetWorkspace)
-		workspaceRoutes.Put("/:id", workspaceController.UpdateWorkspace)
-		workspaceRoutes.Delete("/:id", workspaceController.DeleteWorkspace)
+		workspaceRoutes.Get("/:id", _i.PolicyMW.Workspace(policy.WorkspaceView, "id"), workspaceController.GetWorkspace)
+		workspaceRoutes.Put("/:id", _i.PolicyMW.Workspace(policy.WorkspaceUpdate, "id"), workspaceController.UpdateWorkspace)
+		workspaceRoutes.Delete("/:id", _i.PolicyMW.Workspace(policy.WorkspaceDelete, "id"), workspaceController.DeleteWorkspace)
+
+		workspaceRoutes.Get("/:id/members", _i.PolicyMW.Workspace(policy.WorkspaceMembersView, "id"), memberController.ListMembers)
+		workspaceRoutes.Post("/:id/members", _i.PolicyMW.Workspace(policy.WorkspaceMembersManage, "id"), memberController.AddMember)
+		workspaceRoutes.Put("/:id/members/:userId", _i.PolicyMW.Workspace(policy.WorkspaceMembersManage, "id"), memberController.UpdateMember)
+		// member boleh keluar sendiri, aturan lengkapnya di MemberService
+		workspaceRoutes.Delete("/:id/members/:userId", _i.PolicyMW.Workspace(policy.WorkspaceMembersView, "id"), memberController.RemoveMember)
+
+		workspaceRoutes.Get("/:id/service-accounts", _i.PolicyMW.Workspace(policy.WorkspaceMembersView, "id"), serviceAccountController.ListServiceAccounts)
+		workspaceRoutes.Post("/:id/service-accounts", _i.PolicyMW.Workspace(policy.WorkspaceMembersManage, "id"), serviceAccountController.CreateServiceAccount)
+		workspaceRoutes.Put("/:id/service-accounts/:accountId", _i.PolicyMW.Workspace(policy.WorkspaceMembersManage, "id"), serviceAccountController.UpdateServiceAccount)
+		workspaceRoutes.Delete("/:id/service-accounts/:accountId", _i.PolicyMW.Workspace(policy.WorkspaceMembersManage, "id"), serviceAccountController.DeleteServiceAccount)
+		workspaceRoutes.Post("/:id/service-accounts/:accountId/tokens", _i.PolicyMW.Workspace(policy.WorkspaceMembersManage, "id"), serviceAccountController.CreateToken)
+		workspaceRoutes.Get("/:id/service-accounts/:accountId/tokens", _i.PolicyMW.Workspace(policy.WorkspaceMembersManage, "id"), serviceAccountController.ListTokens)
+		workspaceRoutes.Delete("/:id/service-accounts/:accountId/tokens/:tokenId", _i.PolicyMW.Workspace(policy.WorkspaceMembersManage, "id"), serviceAccountController.RevokeToken)
 	})
 }
@@ -0,0 +1,47 @@
+package policy
+
+import "git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+
+// Error untuk resource yang dimuat dan diotorisasi oleh policy, dipakai bersama
+// oleh PolicyMiddleware dan service
+var (
+	ErrWorkspaceNotFound = apperr.NotFound("workspace_not_found", "workspace not found")
+	ErrDocumentNotFound  = apperr.NotFound("document_not_found", "document not found")
+	ErrShareLinkNotFound = apperr.NotFound("share_link_not_found", "share link not found")
+	ErrShareLinkExpired  = apperr.Gone("share_link_expired", "share link has expired")
+)
+
+// deniedMessages pesan error per aksi saat policy menolak
+var deniedMessages = map[Action]string{
+	WorkspaceView:          "you don't have permission to access this workspace",
+	WorkspaceUpdate:        "you don't have permission to update this workspace",
+	WorkspaceDelete:        "you don't have permission to delete this workspace",
+	WorkspaceMembersView:   "you don't have permission to access this workspace",
+	WorkspaceMembersManage: "you don't have permission to manage members of this workspace",
+	DocumentCreate:         "you don't have permission to create documents in this workspace",
+	DocumentView:           "you don't have permission to access this document",
+	DocumentEdit:           "you don't have permission to update this document",
+	DocumentDelete:         "you don't have permission to delete this document",
+	DocumentShare:          "you don't have permission to share this document",
+	VersionView:            "you don't have permission to access this document",
+	VersionRestore:         "you don't have permission to update this document",
+	ShareEdit:              "you don't have permission to edit this document",
+	CommentView:            "you don't have permission to access this document",
+	CommentCreate:          "you don't have permission to comment on this document",
+	CommentResolve:         "you don't have permission to resolve this thread",
+	CommentModerate:        "you don't have permission to delete this comment",
+}
+
+// Denied error saat policy menolak aksi. Share link yang tidak boleh dilihat
+// dianggap tidak ada agar keberadaannya tidak bocor.
+func Denied(action Action) error {
+	if action == ShareView {
+		return ErrShareLinkNotFound
+	}
+
+	message, ok := deniedMessages[action]
+	if !ok {
+		message = "you don't have permission to perform this action"
+	}
+	return apperr.Forbidden("permission_denied", message)
+}
@@ -0,0 +1,232 @@
+package policy
+
+import (
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"go.uber.org/fx"
+)
+
+// Action aksi yang diotorisasi terhadap sebuah resource
+type Action string
+
+const (
+	WorkspaceView          Action = "workspace:view"
+	WorkspaceUpdate        Action = "workspace:update"
+	WorkspaceDelete        Action = "workspace:delete"
+	WorkspaceMembersView   Action = "workspace:members:view"
+	WorkspaceMembersManage Action = "workspace:members:manage"
+
+	DocumentCreate Action = "document:create"
+	DocumentView   Action = "document:view"
+	DocumentEdit   Action = "document:edit"
+	DocumentDelete Action = "document:delete"
+	DocumentShare  Action = "document:share" // kelola share dan visibility document
+
+	VersionView    Action = "version:view"
+	VersionRestore Action = "version:restore"
+
+	CommentView     Action = "comment:view"
+	CommentCreate   Action = "comment:create"
+	CommentResolve  Action = "comment:resolve"  // resolve/reopen thread milik user lain
+	CommentModerate Action = "comment:moderate" // hapus komentar milik user lain
+
+	// Akses anonim via token share link
+	ShareView Action = "share:view"
+	ShareEdit Action = "share:edit"
+)
+
+// workspaceMinimumRole role workspace terendah untuk setiap aksi
+var workspaceMinimumRole = map[Action]schema.WorkspaceRole{
+	WorkspaceView:          schema.WorkspaceRoleViewer,
+	WorkspaceMembersView:   schema.WorkspaceRoleViewer,
+	WorkspaceUpdate:        schema.WorkspaceRoleAdmin,
+	WorkspaceMembersManage: schema.WorkspaceRoleAdmin,
+	WorkspaceDelete:        schema.WorkspaceRoleOwner,
+
+	DocumentCreate: schema.WorkspaceRoleEditor,
+	DocumentView:   schema.WorkspaceRoleViewer,
+	DocumentEdit:   schema.WorkspaceRoleEditor,
+	DocumentDelete: schema.WorkspaceRoleAdmin,
+	DocumentShare:  schema.WorkspaceRoleAdmin,
+
+	VersionView:    schema.WorkspaceRoleViewer,
+	VersionRestore: schema.WorkspaceRoleEditor,
+
+	CommentView:     schema.WorkspaceRoleViewer,
+	CommentCreate:   schema.WorkspaceRoleViewer,
+	CommentResolve:  schema.WorkspaceRoleEditor,
+	CommentModerate: schema.WorkspaceRoleAdmin,
+}
+
+// Subject pihak yang melakukan aksi. UserID 0 berarti anonim.
+type Subject struct {
+	UserID uint64
+}
+
+// User subject untuk user yang login
+func User(userID uint64) Subject {
+	return Subject{UserID: userID}
+}
+
+// Resource target aksi. Workspace selalu diisi, Document untuk aksi document/version,
+// Share untuk akses via token share link.
+type Resource struct {
+	Workspace *schema.Workspace
+	Document  *schema.Document
+	Share     *schema.SharedAccess
+}
+
+// WorkspaceResource resource untuk aksi pada workspace
+func WorkspaceResource(workspace *schema.Workspace) Resource {
+	return Resource{Workspace: workspace}
+}
+
+// DocumentResource resource untuk aksi pada document dan versinya
+func DocumentResource(workspace *schema.Workspace, document *schema.Document) Resource {
+	return Resource{Workspace: workspace, Document: document}
+}
+
+// ShareResource resource untuk akses document via share link
+func ShareResource(workspace *schema.Workspace, document *schema.Document, share *schema.SharedAccess) Resource {
+	return Resource{Workspace: workspace, Document: document, Share: share}
+}
+
+// Policy menjawab apakah subject boleh melakukan aksi terhadap resource dengan
+// menggabungkan ownership, membership, IsPublic dan SharedAccess
+type Policy interface {
+	Can(subject Subject, action Action, resource Resource) (bool, error)
+	// Role mengembalikan role subject di workspace, kosong jika bukan member
+	Role(subject Subject, workspace *schema.Workspace) (schema.WorkspaceRole, error)
+}
+
+type policy struct {
+	store Store
+}
+
+// New membuat Policy dengan Store sebagai sumber membership dan share
+func New(store Store) Policy {
+	return &policy{
+		store: store,
+	}
+}
+
+// Module adalah FX module untuk policy
+var Module = fx.Options(
+	fx.Provide(NewStore),
+	fx.Provide(New),
+)
+
+func (_i *policy) Role(subject Subject, workspace *schema.Workspace) (schema.WorkspaceRole, error) {
+	if subject.UserID == 0 || workspace == nil {
+		return "", nil
+	}
+
+	if workspace.OwnerID == subject.UserID {
+		return schema.WorkspaceRoleOwner, nil
+	}
+
+	return _i.store.MemberRole(workspace.ID, subject.UserID)
+}
+
+func (_i *policy) Can(subject Subject, action Action, resource Resource) (bool, error) {
+	if resource.Workspace == nil {
+		return false, nil
+	}
+
+	// Document harus berada di workspace yang diberikan
+	if resource.Document != nil && resource.Document.WorkspaceID != resource.Workspace.ID {
+		return false, nil
+	}
+
+	switch action {
+	case ShareView, ShareEdit:
+		return canUseShare(action, resource), nil
+	}
+
+	minimum, ok := workspaceMinimumRole[action]
+	if !ok {
+		return false, nil
+	}
+
+	role, err := _i.Role(subject, resource.Workspace)
+	if err != nil {
+		return false, err
+	}
+	if role.AtLeast(minimum) {
+		return true, nil
+	}
+
+	switch action {
+	case WorkspaceView:
+		return resource.Workspace.IsPublic, nil
+
+	case DocumentView, VersionView, CommentView:
+		if resource.Document == nil {
+			return false, nil
+		}
+		if resource.Workspace.IsPublic || resource.Document.IsPublic {
+			return true, nil
+		}
+		grant, err := _i.grant(subject, resource.Document)
+		return grant != nil, err
+
+	case CommentCreate:
+		// Document publik hanya bisa dibaca, komentar butuh share
+		if resource.Document == nil {
+			return false, nil
+		}
+		grant, err := _i.grant(subject, resource.Document)
+		return grant != nil, err
+
+	case DocumentEdit, VersionRestore, CommentResolve:
+		if resource.Document == nil {
+			return false, nil
+		}
+		grant, err := _i.grant(subject, resource.Document)
+		return grant != nil && grant.Permission == schema.PermissionEdit, err
+	}
+
+	return false, nil
+}
+
+// grant share aktif document untuk subject, nil jika tidak ada atau sudah expired
+func (_i *policy) grant(subject Subject, document *schema.Document) (*schema.SharedAccess, error) {
+	if subject.UserID == 0 {
+		return nil, nil
+	}
+
+	grant, err := _i.store.DocumentGrant(document.ID, subject.UserID)
+	if err != nil || grant == nil {
+		return nil, err
+	}
+
+	if Expired(grant) {
+		return nil, nil
+	}
+
+	return grant, nil
+}
+
+// canUseShare validasi share link: milik document, belum expired, dan permission cukup
+func canUseShare(action Action, resource Resource) bool {
+	share := resource.Share
+	if share == nil || resource.Document == nil || share.DocumentID != resource.Document.ID {
+		return false
+	}
+
+	// share ke user tertentu tidak bisa dipakai sebagai link anonim
+	if share.UserID != nil || share.InviteEmail != nil || Expired(share) {
+		return false
+	}
+
+	if action == ShareEdit {
+		return share.Permission == schema.PermissionEdit
+	}
+	return true
+}
+
+// Expired true jika share punya expiry yang sudah lewat
+func Expired(share *schema.SharedAccess) bool {
+	return share.ExpiresAt != nil && !share.ExpiresAt.After(time.Now())
+}
@@ -0,0 +1,255 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"gorm.io/gorm"
+)
+
+// fakeStore Store in-memory, membership dan share per user
+type fakeStore struct {
+	roles  map[uint64]schema.WorkspaceRole
+	grants map[uint64]*schema.SharedAccess
+}
+
+func (s *fakeStore) FindWorkspace(uint64) (*schema.Workspace, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *fakeStore) FindDocument(uint64) (*schema.Document, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *fakeStore) FindShareLink(string) (*schema.SharedAccess, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *fakeStore) MemberRole(_ uint64, userID uint64) (schema.WorkspaceRole, error) {
+	return s.roles[userID], nil
+}
+
+func (s *fakeStore) DocumentGrant(_ uint64, userID uint64) (*schema.SharedAccess, error) {
+	return s.grants[userID], nil
+}
+
+const (
+	ownerID uint64 = iota + 1
+	adminID
+	editorID
+	viewerID
+	outsiderID
+	shareViewID
+	shareEditID
+	shareExpiredID
+)
+
+func newTestPolicy() Policy {
+	userID := func(id uint64) *uint64 { return &id }
+	expired := time.Now().Add(-time.Hour)
+
+	return New(&fakeStore{
+		roles: map[uint64]schema.WorkspaceRole{
+			adminID:  schema.WorkspaceRoleAdmin,
+			editorID: schema.WorkspaceRoleEditor,
+			viewerID: schema.WorkspaceRoleViewer,
+		},
+		grants: map[uint64]*schema.SharedAccess{
+			shareViewID:    {DocumentID: 1, UserID: userID(shareViewID), Permission: schema.PermissionView},
+			shareEditID:    {DocumentID: 1, UserID: userID(shareEditID), Permission: schema.PermissionEdit},
+			shareExpiredID: {DocumentID: 1, UserID: userID(shareExpiredID), Permission: schema.PermissionEdit, ExpiresAt: &expired},
+		},
+	})
+}
+
+// Subject di kolom matrix, urutannya sama dengan kolom want
+var matrixSubjects = []struct {
+	name string
+	id   uint64
+}{
+	{"owner", ownerID},
+	{"admin", adminID},
+	{"editor", editorID},
+	{"viewer", viewerID},
+	{"outsider", outsiderID},
+	{"anonymous", 0},
+	{"share view", shareViewID},
+	{"share edit", shareEditID},
+	{"share expired", shareExpiredID},
+}
+
+func TestCanRoleMatrix(t *testing.T) {
+	p := newTestPolicy()
+	workspace := &schema.Workspace{ID: 1, OwnerID: ownerID}
+	document := &schema.Document{ID: 1, WorkspaceID: 1}
+
+	//                                     owner  admin  editor viewer outsdr anon   shareV shareE shareX
+	cases := []struct {
+		action   Action
+		document bool
+		want     [9]bool
+	}{
+		{WorkspaceView, false, [9]bool{true, true, true, true, false, false, false, false, false}},
+		{WorkspaceMembersView, false, [9]bool{true, true, true, true, false, false, false, false, false}},
+		{WorkspaceUpdate, false, [9]bool{true, true, false, false, false, false, false, false, false}},
+		{WorkspaceMembersManage, false, [9]bool{true, true, false, false, false, false, false, false, false}},
+		{WorkspaceDelete, false, [9]bool{true, false, false, false, false, false, false, false, false}},
+		{DocumentCreate, false, [9]bool{true, true, true, false, false, false, false, false, false}},
+
+		{DocumentView, true, [9]bool{true, true, true, true, false, false, true, true, false}},
+		{DocumentEdit, true, [9]bool{true, true, true, false, false, false, false, true, false}},
+		{DocumentDelete, true, [9]bool{true, true, false, false, false, false, false, false, false}},
+		{DocumentShare, true, [9]bool{true, true, false, false, false, false, false, false, false}},
+		{VersionView, true, [9]bool{true, true, true, true, false, false, true, true, false}},
+		{VersionRestore, true, [9]bool{true, true, true, false, false, false, false, true, false}},
+		{CommentView, true, [9]bool{true, true, true, true, false, false, true, true, false}},
+		{CommentCreate, true, [9]bool{true, true, true, true, false, false, true, true, false}},
+		{CommentResolve, true, [9]bool{true, true, true, false, false, false, false, true, false}},
+		{CommentModerate, true, [9]bool{true, true, false, false, false, false, false, false, false}},
+	}
+
+	for _, tc := range cases {
+		resource := WorkspaceResource(workspace)
+		if tc.document {
+			resource = DocumentResource(workspace, document)
+		}
+
+		for i, subject := range matrixSubjects {
+			got, err := p.Can(User(subject.id), tc.action, resource)
+			if err != nil {
+				t.Fatalf("Can(%s, %s): %v", subject.name, tc.action, err)
+			}
+			if got != tc.want[i] {
+				t.Errorf("Can(%s, %s) = %v, want %v", subject.name, tc.action, got, tc.want[i])
+			}
+		}
+	}
+}
+
+func TestCanPublicResources(t *testing.T) {
+	p := newTestPolicy()
+
+	cases := []struct {
+		name             string
+		workspacePublic  bool
+		documentPublic   bool
+		action           Action
+		subject          uint64
+		documentResource bool
+		want             bool
+	}{
+		{"public workspace view", true, false, WorkspaceView, 0, false, true},
+		{"public workspace document", true, false, DocumentView, outsiderID, true, true},
+		{"public document anonymous", false, true, DocumentView, 0, true, true},
+		{"public document versions", false, true, VersionView, 0, true, true},
+		{"public document comments", false, true, CommentView, 0, true, true},
+		{"public document is read only", false, true, DocumentEdit, outsiderID, true, false},
+		{"public document cannot comment", false, true, CommentCreate, outsiderID, true, false},
+		{"public document keeps workspace private", false, true, WorkspaceView, 0, false, false},
+		{"public workspace members stay private", true, false, WorkspaceMembersView, 0, false, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			workspace := &schema.Workspace{ID: 1, OwnerID: ownerID, IsPublic: tc.workspacePublic}
+			document := &schema.Document{ID: 1, WorkspaceID: 1, IsPublic: tc.documentPublic}
+
+			resource := WorkspaceResource(workspace)
+			if tc.documentResource {
+				resource = DocumentResource(workspace, document)
+			}
+
+			got, err := p.Can(User(tc.subject), tc.action, resource)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Fatalf("Can = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCanDocumentFromOtherWorkspace(t *testing.T) {
+	p := newTestPolicy()
+	workspace := &schema.Workspace{ID: 1, OwnerID: ownerID}
+	document := &schema.Document{ID: 1, WorkspaceID: 2, IsPublic: true}
+
+	for _, action := range []Action{DocumentView, DocumentEdit, DocumentDelete} {
+		got, err := p.Can(User(ownerID), action, DocumentResource(workspace, document))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got {
+			t.Errorf("Can(owner, %s) on document of another workspace = true", action)
+		}
+	}
+}
+
+func TestCanShareLink(t *testing.T) {
+	p := newTestPolicy()
+	workspace := &schema.Workspace{ID: 1, OwnerID: ownerID}
+	document := &schema.Document{ID: 1, WorkspaceID: 1}
+	expired := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	userID := shareViewID
+	email := "invitee@example.com"
+
+	cases := []struct {
+		name   string
+		share  *schema.SharedAccess
+		action Action
+		want   bool
+	}{
+		{"view link view", &schema.SharedAccess{DocumentID: 1, Permission: schema.PermissionView}, ShareView, true},
+		{"view link edit", &schema.SharedAccess{DocumentID: 1, Permission: schema.PermissionView}, ShareEdit, false},
+		{"edit link edit", &schema.SharedAccess{DocumentID: 1, Permission: schema.PermissionEdit}, ShareEdit, true},
+		{"edit link view", &schema.SharedAccess{DocumentID: 1, Permission: schema.PermissionEdit}, ShareView, true},
+		{"not expired", &schema.SharedAccess{DocumentID: 1, Permission: schema.PermissionView, ExpiresAt: &future}, ShareView, true},
+		{"expired", &schema.SharedAccess{DocumentID: 1, Permission: schema.PermissionEdit, ExpiresAt: &expired}, ShareView, false},
+		{"other document", &schema.SharedAccess{DocumentID: 2, Permission: schema.PermissionEdit}, ShareView, false},
+		{"user share", &schema.SharedAccess{DocumentID: 1, UserID: &userID, Permission: schema.PermissionView}, ShareView, false},
+		{"pending invite", &schema.SharedAccess{DocumentID: 1, InviteEmail: &email, Permission: schema.PermissionView}, ShareView, false},
+		{"no share", nil, ShareView, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := p.Can(Subject{}, tc.action, ShareResource(workspace, document, tc.share))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Fatalf("Can = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRole(t *testing.T) {
+	p := newTestPolicy()
+	workspace := &schema.Workspace{ID: 1, OwnerID: ownerID}
+
+	cases := []struct {
+		subject uint64
+		want    schema.WorkspaceRole
+	}{
+		{ownerID, schema.WorkspaceRoleOwner},
+		{adminID, schema.WorkspaceRoleAdmin},
+		{editorID, schema.WorkspaceRoleEditor},
+		{viewerID, schema.WorkspaceRoleViewer},
+		{outsiderID, ""},
+		{0, ""},
+	}
+
+	for _, tc := range cases {
+		got, err := p.Role(User(tc.subject), workspace)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("Role(%d) = %q, want %q", tc.subject, got, tc.want)
+		}
+	}
+}
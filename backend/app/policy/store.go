@@ -0,0 +1,88 @@
+package policy
+
+import (
+	"errors"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/database/schema"
+	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
+	"gorm.io/gorm"
+)
+
+// Store sumber data untuk policy dan middleware otorisasi.
+// Method Find* mengembalikan gorm.ErrRecordNotFound jika data tidak ada.
+type Store interface {
+	FindWorkspace(id uint64) (*schema.Workspace, error)
+	FindDocument(id uint64) (*schema.Document, error)
+	FindShareLink(token string) (*schema.SharedAccess, error)
+
+	// MemberRole role user di workspace, kosong jika bukan member
+	MemberRole(workspaceID uint64, userID uint64) (schema.WorkspaceRole, error)
+	// DocumentGrant share document ke user, nil jika tidak ada
+	DocumentGrant(documentID uint64, userID uint64) (*schema.SharedAccess, error)
+}
+
+type store struct {
+	db *database.Database
+}
+
+// NewStore Store berbasis gorm
+func NewStore(db *database.Database) Store {
+	return &store{
+		db: db,
+	}
+}
+
+func (_i *store) FindWorkspace(id uint64) (*schema.Workspace, error) {
+	var workspace schema.Workspace
+	if err := _i.db.DB.Where("id = ?", id).First(&workspace).Error; err != nil {
+		return nil, err
+	}
+
+	return &workspace, nil
+}
+
+func (_i *store) FindDocument(id uint64) (*schema.Document, error) {
+	var document schema.Document
+	if err := _i.db.DB.Where("id = ?", id).First(&document).Error; err != nil {
+		return nil, err
+	}
+
+	return &document, nil
+}
+
+func (_i *store) FindShareLink(token string) (*schema.SharedAccess, error) {
+	var share schema.SharedAccess
+	if err := _i.db.DB.Where("access_token = ?", token).
+		Where("user_id IS NULL AND invite_email IS NULL").
+		First(&share).Error; err != nil {
+		return nil, err
+	}
+
+	return &share, nil
+}
+
+func (_i *store) MemberRole(workspaceID uint64, userID uint64) (schema.WorkspaceRole, error) {
+	var member schema.WorkspaceMember
+	err := _i.db.DB.Where("workspace_id = ? AND user_id = ?", workspaceID, userID).First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return member.Role, nil
+}
+
+func (_i *store) DocumentGrant(documentID uint64, userID uint64) (*schema.SharedAccess, error) {
+	var share schema.SharedAccess
+	err := _i.db.DB.Where("document_id = ? AND user_id = ?", documentID, userID).First(&share).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &share, nil
+}
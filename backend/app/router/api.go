@@ -2,6 +2,16 @@ package router
 
 import (
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/auth"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/comment"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/export"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/lint"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/render"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/scim"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/share"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/token"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/usersession"
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace"
 	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
 	"github.com/gofiber/fiber/v2"
@@ -12,6 +22,16 @@ type Router struct {
 	Cfg             *config.Config
 	AuthRouter      *auth.AuthRouter
 	WorkspaceRouter *workspace.WorkspaceRouter
+	DocumentRouter  *document.DocumentRouter
+	ShareRouter     *share.ShareRouter
+	TokenRouter     *token.TokenRouter
+	SessionRouter   *usersession.UserSessionRouter
+	ScimRouter      *scim.ScimRouter
+	CollabRouter    *collab.CollabRouter
+	CommentRouter   *comment.CommentRouter
+	LintRouter      *lint.LintRouter
+	RenderRouter    *render.RenderRouter
+	ExportRouter    *export.ExportRouter
 }
 
 func NewRouter(
@@ -19,12 +39,32 @@ func NewRouter(
 	cfg *config.Config,
 	authRouter *auth.AuthRouter,
 	workspaceRouter *workspace.WorkspaceRouter,
+	documentRouter *document.DocumentRouter,
+	shareRouter *share.ShareRouter,
+	tokenRouter *token.TokenRouter,
+	sessionRouter *usersession.UserSessionRouter,
+	scimRouter *scim.ScimRouter,
+	collabRouter *collab.CollabRouter,
+	commentRouter *comment.CommentRouter,
+	lintRouter *lint.LintRouter,
+	renderRouter *render.RenderRouter,
+	exportRouter *export.ExportRouter,
 ) *Router {
 	return &Router{
 		App:             fiber,
 		Cfg:             cfg,
 		AuthRouter:      authRouter,
 		WorkspaceRouter: workspaceRouter,
+		DocumentRouter:  documentRouter,
+		ShareRouter:     shareRouter,
+		TokenRouter:     tokenRouter,
+		SessionRouter:   sessionRouter,
+		ScimRouter:      scimRouter,
+		CollabRouter:    collabRouter,
+		CommentRouter:   commentRouter,
+		LintRouter:      lintRouter,
+		RenderRouter:    renderRouter,
+		ExportRouter:    exportRouter,
 	}
 }
 
@@ -37,4 +77,14 @@ func (r *Router) Register() {
 	// routes of modules
 	r.AuthRouter.RegisterAuthRoutes()
 	r.WorkspaceRouter.RegisterWorkspaceRoutes()
+	r.DocumentRouter.RegisterDocumentRoutes()
+	r.ShareRouter.RegisterShareRoutes()
+	r.TokenRouter.RegisterTokenRoutes()
+	r.SessionRouter.RegisterUserSessionRoutes()
+	r.ScimRouter.RegisterScimRoutes()
+	r.CollabRouter.RegisterCollabRoutes()
+	r.CommentRouter.RegisterCommentRoutes()
+	r.LintRouter.RegisterLintRoutes()
+	r.RenderRouter.RegisterRenderRoutes()
+	r.ExportRouter.RegisterExportRoutes()
 }
@@ -5,12 +5,26 @@ import (
 
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/middleware"
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/auth"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/collab"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/comment"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/document"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/export"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/lint"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/render"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/scim"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/share"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/token"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/usersession"
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/module/workspace"
+	"git.dev.siap.id/kukuhkkh/app-diagram/app/policy"
 	"git.dev.siap.id/kukuhkkh/app-diagram/app/router"
 	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap"
 	"git.dev.siap.id/kukuhkkh/app-diagram/internal/bootstrap/database"
 	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/pubsub"
 	"git.dev.siap.id/kukuhkkh/app-diagram/utils/session"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/sso"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/storage"
 	fxzerolog "github.com/efectn/fx-zerolog"
 	_ "go.uber.org/automaxprocs"
 )
@@ -38,15 +52,36 @@ func main() {
 		fx.Provide(database.NewDatabase),
 		// session
 		fx.Provide(session.NewStore),
+		// pub/sub antar instance
+		fx.Provide(pubsub.NewBus),
+		fx.Provide(pubsub.NewRegistry),
+		// storage file
+		fx.Provide(storage.NewStorage),
+		// sso provider registry
+		fx.Provide(sso.NewRegistry),
+		fx.Provide(sso.NewTokenCipher),
 		// middleware
 		fx.Provide(middleware.NewMiddleware),
 		fx.Provide(middleware.NewAuthMiddleware),
+		fx.Provide(middleware.NewPolicyMiddleware),
+		// authorization policy
+		policy.Module,
 		// router
 		fx.Provide(router.NewRouter),
 
 		// provide modules
 		auth.NewAuthModule,
 		workspace.NewWorkspaceModule,
+		document.NewDocumentModule,
+		share.NewShareModule,
+		token.NewTokenModule,
+		usersession.NewUserSessionModule,
+		scim.NewScimModule,
+		collab.NewCollabModule,
+		comment.NewCommentModule,
+		lint.NewLintModule,
+		render.NewRenderModule,
+		export.NewExportModule,
 
 		// start aplication
 		fx.Invoke(bootstrap.Start),
@@ -79,7 +79,52 @@ func (_db *Database) MigrateModels() {
 		Models()...,
 	); err != nil {
 		_db.Log.Error().Err(err).Msg("An unknown error occurred when to migrate the database!")
+		return
+	}
+
+	if err := _db.migrateLogtoSub(); err != nil {
+		_db.Log.Error().Err(err).Msg("An unknown error occurred when to migrate users.logto_sub to external_identities!")
+	}
+}
+
+// migrateLogtoSub pindahkan kolom lama users.logto_sub ke external_identities
+// dengan provider "logto", lalu hapus kolomnya
+func (_db *Database) migrateLogtoSub() error {
+	migrator := _db.DB.Migrator()
+	if !migrator.HasColumn(&schema.User{}, "logto_sub") {
+		return nil
 	}
+
+	return _db.DB.Transaction(func(tx *gorm.DB) error {
+		var rows []struct {
+			ID       uint64
+			Email    string
+			LogtoSub string
+		}
+		if err := tx.Table("users").
+			Select("id, email, logto_sub").
+			Where("logto_sub IS NOT NULL AND logto_sub <> ''").
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			identity := schema.ExternalIdentity{
+				UserID:   row.ID,
+				Provider: "logto",
+				Subject:  row.LogtoSub,
+				Email:    row.Email,
+			}
+			if err := tx.Where("provider = ? AND subject = ?", identity.Provider, identity.Subject).
+				FirstOrCreate(&identity).Error; err != nil {
+				return err
+			}
+		}
+
+		_db.Log.Info().Msgf("Migrated %d logto_sub values to external_identities", len(rows))
+
+		return tx.Migrator().DropColumn(&schema.User{}, "logto_sub")
+	})
 }
 
 // Models list of models for migration
@@ -90,6 +135,16 @@ func Models() []interface{} {
 		schema.Document{},
 		schema.DocumentVersion{},
 		schema.SharedAccess{},
+		schema.WorkspaceMember{},
+		schema.PersonalAccessToken{},
+		schema.ExternalIdentity{},
+		schema.UserSession{},
+		schema.LogoutToken{},
+		schema.RecoveryCode{},
+		schema.CommentThread{},
+		schema.Comment{},
+		schema.CommentMention{},
+		schema.ExportJob{},
 	}
 }
 
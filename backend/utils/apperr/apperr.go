@@ -0,0 +1,166 @@
+package apperr
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind kategori error domain, menentukan HTTP status di response.ErrorHandler
+type Kind string
+
+const (
+	KindBadRequest   Kind = "bad_request"
+	KindUnauthorized Kind = "unauthorized"
+	KindForbidden    Kind = "forbidden"
+	KindNotFound     Kind = "not_found"
+	KindConflict     Kind = "conflict"
+	KindGone         Kind = "gone"
+	KindValidation   Kind = "validation"
+	KindInternal     Kind = "internal"
+
+	KindPreconditionRequired Kind = "precondition_required"
+)
+
+var kindStatus = map[Kind]int{
+	KindBadRequest:   http.StatusBadRequest,
+	KindUnauthorized: http.StatusUnauthorized,
+	KindForbidden:    http.StatusForbidden,
+	KindNotFound:     http.StatusNotFound,
+	KindConflict:     http.StatusConflict,
+	KindGone:         http.StatusGone,
+	KindValidation:   http.StatusUnprocessableEntity,
+	KindInternal:     http.StatusInternalServerError,
+
+	KindPreconditionRequired: http.StatusPreconditionRequired,
+}
+
+// Sentinel per kind, dipakai dengan errors.Is, contoh: errors.Is(err, apperr.ErrNotFound)
+var (
+	ErrBadRequest   = &Error{Kind: KindBadRequest}
+	ErrUnauthorized = &Error{Kind: KindUnauthorized}
+	ErrForbidden    = &Error{Kind: KindForbidden}
+	ErrNotFound     = &Error{Kind: KindNotFound}
+	ErrConflict     = &Error{Kind: KindConflict}
+	ErrGone         = &Error{Kind: KindGone}
+	ErrValidation   = &Error{Kind: KindValidation}
+
+	ErrPreconditionRequired = &Error{Kind: KindPreconditionRequired}
+)
+
+// Error error domain dengan kind dan code yang stabil untuk client.
+// Code bersifat machine-readable dan tidak boleh berubah walaupun Message berubah.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	// Data payload tambahan di response, contoh: state terbaru saat conflict
+	Data any
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return string(e.Kind)
+	}
+	return e.Message
+}
+
+// Is membandingkan berdasarkan kind, dan juga code jika target memiliki code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	if t.Kind != e.Kind {
+		return false
+	}
+	return t.Code == "" || t.Code == e.Code
+}
+
+// Status HTTP status untuk error
+func (e *Error) Status() int {
+	if status, ok := kindStatus[e.Kind]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// ErrorCode code error, fallback ke kind jika code kosong
+func (e *Error) ErrorCode() string {
+	if e.Code != "" {
+		return e.Code
+	}
+	return string(e.Kind)
+}
+
+// WithData salinan error dengan payload tambahan, error sentinel tidak ikut berubah
+func (e *Error) WithData(data any) *Error {
+	c := *e
+	c.Data = data
+	return &c
+}
+
+func newError(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// BadRequest request tidak bisa diproses, contoh: path param atau body tidak valid
+func BadRequest(code, message string) *Error {
+	return newError(KindBadRequest, code, message)
+}
+
+// Unauthorized user belum login atau kredensial salah
+func Unauthorized(code, message string) *Error {
+	return newError(KindUnauthorized, code, message)
+}
+
+// Forbidden user tidak punya akses ke resource
+func Forbidden(code, message string) *Error {
+	return newError(KindForbidden, code, message)
+}
+
+// NotFound resource tidak ditemukan
+func NotFound(code, message string) *Error {
+	return newError(KindNotFound, code, message)
+}
+
+// Conflict resource bentrok dengan data yang sudah ada
+func Conflict(code, message string) *Error {
+	return newError(KindConflict, code, message)
+}
+
+// Gone resource pernah ada tetapi sudah tidak berlaku
+func Gone(code, message string) *Error {
+	return newError(KindGone, code, message)
+}
+
+// PreconditionRequired request harus menyertakan kondisi, contoh: header If-Match
+func PreconditionRequired(code, message string) *Error {
+	return newError(KindPreconditionRequired, code, message)
+}
+
+// Validation input valid secara format tetapi melanggar aturan bisnis
+func Validation(code, message string) *Error {
+	return newError(KindValidation, code, message)
+}
+
+// As ambil *Error dari chain error
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+// CodeForStatus code generik untuk error yang tidak bertipe, contoh *fiber.Error
+func CodeForStatus(status int) string {
+	for kind, s := range kindStatus {
+		if s == status {
+			return string(kind)
+		}
+	}
+	if status >= http.StatusInternalServerError {
+		return string(KindInternal)
+	}
+	return string(KindBadRequest)
+}
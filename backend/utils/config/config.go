@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -141,7 +142,43 @@ type storage = struct {
 	} `toml:"s3"`
 }
 
+type auth = struct {
+	Local struct {
+		Enable            bool `toml:"enable"`
+		AllowRegistration bool `toml:"allow_registration"`
+	} `toml:"local"`
+}
+
+// scim provisioning user dan group dari identity provider lewat /scim/v2
+type scim = struct {
+	Enable     bool   `toml:"enable"`
+	Token      string `toml:"token"`       // bearer secret yang dikonfigurasi di IdP
+	OwnerEmail string `toml:"owner_email"` // pemilik workspace yang dikelola sebagai SCIM Group
+	MemberRole string `toml:"member_role"` // role member dari SCIM Group, default editor
+}
+
+// collab edit realtime lewat WebSocket, fan-out antar instance memakai Redis session
+type collab = struct {
+	SnapshotInterval time.Duration `toml:"snapshot_interval_seconds"` // default 30 detik
+}
+
+// SsoProvider satu identity provider OIDC, endpoint diambil dari discovery issuer
+type SsoProvider struct {
+	Name                  string   `toml:"name"` // dipakai di /auth/login/:provider
+	DisplayName           string   `toml:"display_name"`
+	Issuer                string   `toml:"issuer"`
+	ClientId              string   `toml:"client_id"`
+	ClientSecret          string   `toml:"client_secret"`
+	CallbackUrl           string   `toml:"callback_url"`
+	PostLogoutRedirectUri string   `toml:"post_logout_redirect_uri"`
+	Scopes                []string `toml:"scopes"` // default: openid, offline_access, profile, email
+}
+
 type Sso struct {
+	Providers []SsoProvider `toml:"providers"`
+	TokenKey  string        `toml:"token_key"` // kunci enkripsi token IdP di user_sessions, minimal 32 karakter
+
+	// Deprecated: gunakan [[sso.providers]]. Tetap dibaca sebagai provider "logto"
 	Logto struct {
 		Endpoint              string `toml:"endpoint"`
 		ClientId              string `toml:"client_id"`
@@ -151,6 +188,8 @@ type Sso struct {
 	} `toml:"logto"`
 }
 
+var ssoProviderName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
 type Config struct {
 	App        app
 	DB         db
@@ -158,7 +197,10 @@ type Config struct {
 	Middleware middleware
 	Cookie     cookie
 	Storage    storage
+	Auth       auth
 	Sso        Sso
+	Scim       scim
+	Collab     collab
 }
 
 // Validate validates critical configuration fields
@@ -236,6 +278,53 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate SSO providers
+	providerNames := make(map[string]bool)
+	for i, p := range c.Sso.Providers {
+		if !ssoProviderName.MatchString(p.Name) {
+			errs = append(errs, fmt.Sprintf("sso.providers[%d].name '%s' is not valid (lowercase letters, digits and '-')", i, p.Name))
+		} else if providerNames[p.Name] {
+			errs = append(errs, fmt.Sprintf("sso.providers[%d].name '%s' is duplicated", i, p.Name))
+		}
+		providerNames[p.Name] = true
+
+		if p.Issuer == "" {
+			errs = append(errs, fmt.Sprintf("sso.providers[%d].issuer is required", i))
+		}
+		if p.ClientId == "" {
+			errs = append(errs, fmt.Sprintf("sso.providers[%d].client_id is required", i))
+		}
+		if p.CallbackUrl == "" {
+			errs = append(errs, fmt.Sprintf("sso.providers[%d].callback_url is required", i))
+		}
+	}
+	if c.Sso.Logto.Endpoint != "" && providerNames["logto"] {
+		errs = append(errs, "sso.logto cannot be combined with a provider named 'logto' in sso.providers")
+	}
+	if (len(c.Sso.Providers) > 0 || c.Sso.Logto.Endpoint != "") && len(c.Sso.TokenKey) < 32 {
+		errs = append(errs, "sso.token_key should be at least 32 characters when sso providers are configured")
+	}
+
+	// Validate SCIM
+	if c.Scim.Enable {
+		if len(c.Scim.Token) < 32 {
+			errs = append(errs, "scim.token should be at least 32 characters when scim is enabled")
+		}
+		switch c.Scim.MemberRole {
+		case "", "viewer", "editor", "admin":
+		default:
+			errs = append(errs, fmt.Sprintf("scim.member_role '%s' is not valid (must be: viewer, editor, or admin)", c.Scim.MemberRole))
+		}
+	}
+
+	// Validate collab
+	if c.Collab.SnapshotInterval < 0 {
+		errs = append(errs, "collab.snapshot_interval_seconds must not be negative")
+	}
+	if c.App.Prefork && c.Middleware.Session.Driver != "redis" {
+		log.Warn().Msg("⚠️  Prefork without Redis: realtime collaboration only fans out within a single process")
+	}
+
 	// Validate CORS
 	if c.Middleware.Cors.Enable && c.App.Production {
 		if c.Middleware.Cors.AllowOrigins == "" || c.Middleware.Cors.AllowOrigins == "*" {
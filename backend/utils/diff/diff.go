@@ -0,0 +1,296 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OpKind jenis operasi pada edit script
+type OpKind int
+
+const (
+	OpEqual OpKind = iota
+	OpInsert
+	OpDelete
+)
+
+// Op satu baris pada edit script. ALine/BLine adalah index (0-based) baris
+// di teks lama/baru, bernilai -1 jika baris tidak ada di sisi tersebut.
+type Op struct {
+	Kind  OpKind
+	Text  string
+	ALine int
+	BLine int
+}
+
+// SplitLines memecah teks menjadi baris tanpa newline di akhir tiap baris
+func SplitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.TrimSuffix(s, "\n")
+	return strings.Split(s, "\n")
+}
+
+// Lines menghitung edit script antara dua teks per baris menggunakan algoritma Myers
+func Lines(a, b string) []Op {
+	return Compute(SplitLines(a), SplitLines(b))
+}
+
+// LineMap posisi (0-based) setiap baris a di b, -1 jika baris dihapus atau diubah
+func LineMap(a, b string) []int {
+	return matchLines(Lines(a, b), len(SplitLines(a)))
+}
+
+const (
+	// maxEdits batas jumlah edit (D) yang dicari Myers. Trace butuh memori O(D²),
+	// di atas batas ini bagian yang berubah dianggap satu blok replace.
+	maxEdits = 1000
+
+	// maxCost batas kerja Myers O((N+M)·D), document besar mendapat batas D lebih kecil
+	maxCost = 20_000_000
+)
+
+// Compute menghitung edit script antara dua slice baris (Myers, O(ND)).
+// Hasilnya minimal selama jumlah edit tidak melebihi batas, selain itu baris
+// di antara prefix dan suffix yang sama diganti utuh.
+func Compute(a, b []string) []Op {
+	// Prefix dan suffix yang sama tidak perlu masuk pencarian Myers
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	if len(a)+len(b) == 0 {
+		return nil
+	}
+
+	ops := make([]Op, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, Op{Kind: OpEqual, Text: a[i], ALine: i, BLine: i})
+	}
+
+	aMid, bMid := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	middle, ok := myers(aMid, bMid)
+	if !ok {
+		middle = replace(aMid, bMid)
+	}
+	for _, op := range middle {
+		if op.ALine >= 0 {
+			op.ALine += prefix
+		}
+		if op.BLine >= 0 {
+			op.BLine += prefix
+		}
+		ops = append(ops, op)
+	}
+
+	for i := 0; i < suffix; i++ {
+		x, y := len(a)-suffix+i, len(b)-suffix+i
+		ops = append(ops, Op{Kind: OpEqual, Text: a[x], ALine: x, BLine: y})
+	}
+
+	return ops
+}
+
+// myers edit script minimal, false jika jumlah edit melebihi batas.
+// trace[d] hanya menyimpan window v[-d-1..d+1] yang dibaca saat backtrack.
+func myers(a, b []string) ([]Op, bool) {
+	n, m := len(a), len(b)
+	total := n + m
+	if total == 0 {
+		return nil, true
+	}
+
+	limit := min(total, maxEdits, max(maxCost/total, 1))
+
+	offset := total + 1
+	v := make([]int, 2*total+3)
+	trace := make([][]int, 0, limit+1)
+
+	for d := 0; d <= limit; d++ {
+		snapshot := make([]int, 2*d+3)
+		copy(snapshot, v[offset-d-1:offset+d+2])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace, x, y), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// backtrack menyusun ulang edit script dari trace Myers
+func backtrack(a, b []string, trace [][]int, x, y int) []Op {
+	ops := make([]Op, 0, len(a)+len(b))
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		// window trace[d] dimulai dari k = -d-1
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, Op{Kind: OpEqual, Text: a[x], ALine: x, BLine: y})
+		}
+
+		if d > 0 {
+			if x == prevX {
+				y--
+				ops = append(ops, Op{Kind: OpInsert, Text: b[y], ALine: -1, BLine: y})
+			} else {
+				x--
+				ops = append(ops, Op{Kind: OpDelete, Text: a[x], ALine: x, BLine: -1})
+			}
+		}
+	}
+
+	// dibalik karena backtrack berjalan dari akhir
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+
+	return ops
+}
+
+// replace edit script tanpa baris yang sama: hapus seluruh a lalu sisipkan seluruh b
+func replace(a, b []string) []Op {
+	ops := make([]Op, 0, len(a)+len(b))
+	for i, line := range a {
+		ops = append(ops, Op{Kind: OpDelete, Text: line, ALine: i, BLine: -1})
+	}
+	for i, line := range b {
+		ops = append(ops, Op{Kind: OpInsert, Text: line, ALine: -1, BLine: i})
+	}
+	return ops
+}
+
+// Unified menghasilkan unified diff (format `diff -u`) dengan context baris di sekitar perubahan
+func Unified(a, b, fromName, toName string, context int) string {
+	ops := Lines(a, b)
+
+	hunks := groupHunks(ops, context)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	// aBefore[i]/bBefore[i]: jumlah baris lama/baru sebelum ops[i]
+	aBefore := make([]int, len(ops)+1)
+	bBefore := make([]int, len(ops)+1)
+	for i, op := range ops {
+		aBefore[i+1], bBefore[i+1] = aBefore[i], bBefore[i]
+		if op.Kind != OpInsert {
+			aBefore[i+1]++
+		}
+		if op.Kind != OpDelete {
+			bBefore[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	for _, h := range hunks {
+		aLen := aBefore[h[1]] - aBefore[h[0]]
+		bLen := bBefore[h[1]] - bBefore[h[0]]
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			formatRange(aBefore[h[0]], aLen),
+			formatRange(bBefore[h[0]], bLen),
+		)
+
+		for _, op := range ops[h[0]:h[1]] {
+			switch op.Kind {
+			case OpEqual:
+				sb.WriteString(" ")
+			case OpInsert:
+				sb.WriteString("+")
+			case OpDelete:
+				sb.WriteString("-")
+			}
+			sb.WriteString(op.Text)
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// groupHunks mengelompokkan perubahan yang berdekatan beserta context-nya,
+// hasilnya berupa range index [start, end) pada ops
+func groupHunks(ops []Op, context int) [][2]int {
+	var hunks [][2]int
+	start, end := -1, -1
+
+	for i, op := range ops {
+		if op.Kind == OpEqual {
+			continue
+		}
+
+		lo := max(i-context, 0)
+		hi := min(i+context+1, len(ops))
+
+		if start >= 0 && lo <= end {
+			end = max(end, hi)
+			continue
+		}
+
+		if start >= 0 {
+			hunks = append(hunks, [2]int{start, end})
+		}
+		start, end = lo, hi
+	}
+
+	if start >= 0 {
+		hunks = append(hunks, [2]int{start, end})
+	}
+
+	return hunks
+}
+
+// formatRange format posisi hunk; before adalah jumlah baris sebelum hunk.
+// Sesuai konvensi diff -u, range kosong ditulis dengan posisi baris sebelumnya.
+func formatRange(before, length int) string {
+	switch length {
+	case 0:
+		return fmt.Sprintf("%d,0", before)
+	case 1:
+		return fmt.Sprintf("%d", before+1)
+	default:
+		return fmt.Sprintf("%d,%d", before+1, length)
+	}
+}
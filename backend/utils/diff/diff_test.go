@@ -0,0 +1,177 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	cases := []struct {
+		name  string
+		a, b  string
+		edits int
+	}{
+		{"empty", "", "", 0},
+		{"insert into empty", "", "a\nb\n", 2},
+		{"delete all", "a\nb\n", "", 2},
+		{"equal", "a\nb\nc\n", "a\nb\nc\n", 0},
+		{"change middle", "a\nb\nc\n", "a\nx\nc\n", 2},
+		{"classic myers", "a\nb\nc\na\nb\nb\na\n", "c\nb\na\nb\na\nc\n", 5},
+		{"crlf", "a\r\nb\r\n", "a\nb\n", 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ops := Lines(tc.a, tc.b)
+			checkScript(t, SplitLines(tc.a), SplitLines(tc.b), ops)
+			if got := countEdits(ops); got != tc.edits {
+				t.Fatalf("edits = %d, want %d", got, tc.edits)
+			}
+		})
+	}
+}
+
+func TestComputeRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 300; i++ {
+		a, b := randomLines(rng, rng.Intn(30)), randomLines(rng, rng.Intn(30))
+		checkScript(t, a, b, Compute(a, b))
+	}
+}
+
+// Perubahan besar tidak boleh membuat trace O((N+M)·D), hasilnya tetap valid
+func TestComputeLargeChange(t *testing.T) {
+	a, b := make([]string, 6000), make([]string, 6000)
+	for i := range a {
+		a[i] = fmt.Sprintf("old %d", i)
+		b[i] = fmt.Sprintf("new %d", i)
+	}
+	a = append([]string{"head"}, append(a, "tail")...)
+	b = append([]string{"head"}, append(b, "tail")...)
+
+	ops := Compute(a, b)
+	checkScript(t, a, b, ops)
+	if ops[0].Kind != OpEqual || ops[len(ops)-1].Kind != OpEqual {
+		t.Fatalf("common prefix and suffix must stay equal")
+	}
+	if got := countEdits(ops); got != 12000 {
+		t.Fatalf("edits = %d, want 12000", got)
+	}
+}
+
+func TestLineMap(t *testing.T) {
+	got := LineMap("a\nb\nc\nd\n", "x\na\nc\nd\n")
+	want := []int{1, -1, 2, 3}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("LineMap = %v, want %v", got, want)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	got := Unified("a\nb\nc\n", "a\nx\nc\n", "v1", "v2", 1)
+	want := "--- v1\n+++ v2\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n"
+	if got != want {
+		t.Fatalf("Unified =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := Unified("a\n", "a\n", "v1", "v2", 3); got != "" {
+		t.Fatalf("Unified of equal text = %q, want empty", got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	cases := []struct {
+		name               string
+		base, ours, theirs string
+		content            string
+		conflicts          int
+	}{
+		{
+			name:    "both sides unchanged",
+			base:    "a\nb\n",
+			ours:    "a\nb\n",
+			theirs:  "a\nb\n",
+			content: "a\nb\n",
+		},
+		{
+			name:    "separate lines",
+			base:    "a\nb\nc\n",
+			ours:    "A\nb\nc\n",
+			theirs:  "a\nb\nC\n",
+			content: "A\nb\nC\n",
+		},
+		{
+			name:    "same change on both sides",
+			base:    "a\nb\n",
+			ours:    "a\nx\n",
+			theirs:  "a\nx\n",
+			content: "a\nx\n",
+		},
+		{
+			name:      "conflicting change",
+			base:      "a\nb\nc\n",
+			ours:      "a\nours\nc\n",
+			theirs:    "a\ntheirs\nc\n",
+			content:   "a\n<<<<<<< yours\nours\n=======\ntheirs\n>>>>>>> latest\nc\n",
+			conflicts: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Merge(tc.base, tc.ours, tc.theirs, "yours", "latest")
+			if got.Content != tc.content || got.Conflicts != tc.conflicts {
+				t.Fatalf("Merge = %q (%d conflicts), want %q (%d conflicts)", got.Content, got.Conflicts, tc.content, tc.conflicts)
+			}
+		})
+	}
+}
+
+// checkScript edit script harus menyusun ulang a dan b dengan index baris yang benar
+func checkScript(t *testing.T, a, b []string, ops []Op) {
+	t.Helper()
+
+	var gotA, gotB []string
+	for _, op := range ops {
+		if op.Kind != OpInsert {
+			if op.ALine != len(gotA) || a[op.ALine] != op.Text {
+				t.Fatalf("op %+v: wrong ALine", op)
+			}
+			gotA = append(gotA, op.Text)
+		}
+		if op.Kind != OpDelete {
+			if op.BLine != len(gotB) || b[op.BLine] != op.Text {
+				t.Fatalf("op %+v: wrong BLine", op)
+			}
+			gotB = append(gotB, op.Text)
+		}
+	}
+
+	if strings.Join(gotA, "\n") != strings.Join(a, "\n") || len(gotA) != len(a) {
+		t.Fatalf("script does not reproduce a")
+	}
+	if strings.Join(gotB, "\n") != strings.Join(b, "\n") || len(gotB) != len(b) {
+		t.Fatalf("script does not reproduce b")
+	}
+}
+
+func countEdits(ops []Op) int {
+	n := 0
+	for _, op := range ops {
+		if op.Kind != OpEqual {
+			n++
+		}
+	}
+	return n
+}
+
+func randomLines(rng *rand.Rand, n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = string(rune('a' + rng.Intn(4)))
+	}
+	return lines
+}
@@ -0,0 +1,91 @@
+package diff
+
+import (
+	"slices"
+	"strings"
+)
+
+// MergeResult hasil three-way merge. Bagian yang bentrok ditandai dengan
+// conflict marker seperti git, Conflicts jumlah bagian tersebut.
+type MergeResult struct {
+	Content   string `json:"content"`
+	Conflicts int    `json:"conflicts"`
+}
+
+// Merge gabungkan perubahan ours dan theirs yang sama-sama berasal dari base
+// per baris (diff3). Baris yang hanya diubah satu sisi diambil otomatis,
+// baris yang diubah berbeda di kedua sisi ditandai sebagai conflict.
+func Merge(base, ours, theirs, oursName, theirsName string) MergeResult {
+	baseLines, oursLines, theirsLines := SplitLines(base), SplitLines(ours), SplitLines(theirs)
+
+	// matchOurs[i]/matchTheirs[i]: index baris base ke-i di ours/theirs, -1 jika diubah
+	matchOurs := matchLines(Compute(baseLines, oursLines), len(baseLines))
+	matchTheirs := matchLines(Compute(baseLines, theirsLines), len(baseLines))
+
+	var out []string
+	result := MergeResult{}
+	i, o, t := 0, 0, 0
+
+	for {
+		// Baris yang tidak berubah di kedua sisi
+		for i < len(baseLines) && matchOurs[i] == o && matchTheirs[i] == t {
+			out = append(out, baseLines[i])
+			i, o, t = i+1, o+1, t+1
+		}
+
+		// Baris base berikutnya yang masih ada di kedua sisi menjadi batas chunk
+		next := i
+		for next < len(baseLines) && (matchOurs[next] < 0 || matchTheirs[next] < 0) {
+			next++
+		}
+
+		oEnd, tEnd := len(oursLines), len(theirsLines)
+		if next < len(baseLines) {
+			oEnd, tEnd = matchOurs[next], matchTheirs[next]
+		}
+
+		baseChunk, oursChunk, theirsChunk := baseLines[i:next], oursLines[o:oEnd], theirsLines[t:tEnd]
+
+		switch {
+		case slices.Equal(oursChunk, baseChunk):
+			out = append(out, theirsChunk...)
+		case slices.Equal(theirsChunk, baseChunk), slices.Equal(oursChunk, theirsChunk):
+			out = append(out, oursChunk...)
+		default:
+			result.Conflicts++
+			out = append(out, "<<<<<<< "+oursName)
+			out = append(out, oursChunk...)
+			out = append(out, "=======")
+			out = append(out, theirsChunk...)
+			out = append(out, ">>>>>>> "+theirsName)
+		}
+
+		if next >= len(baseLines) {
+			break
+		}
+		i, o, t = next, oEnd, tEnd
+	}
+
+	result.Content = strings.Join(out, "\n")
+	if len(out) > 0 && (strings.HasSuffix(ours, "\n") || strings.HasSuffix(theirs, "\n")) {
+		result.Content += "\n"
+	}
+
+	return result
+}
+
+// matchLines pasangan baris base ke baris di sisi lain dari edit script
+func matchLines(ops []Op, n int) []int {
+	match := make([]int, n)
+	for i := range match {
+		match[i] = -1
+	}
+
+	for _, op := range ops {
+		if op.Kind == OpEqual {
+			match[op.ALine] = op.BLine
+		}
+	}
+
+	return match
+}
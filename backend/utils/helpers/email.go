@@ -0,0 +1,8 @@
+package helpers
+
+import "strings"
+
+// NormalizeEmail menyamakan format email sebelum disimpan atau dibandingkan
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
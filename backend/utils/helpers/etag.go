@@ -0,0 +1,25 @@
+package helpers
+
+import "strings"
+
+// ETag strong ETag dari penanda versi resource
+func ETag(version string) string {
+	return `"` + version + `"`
+}
+
+// ParseIfMatch ambil penanda versi dari header If-Match. Hanya satu strong
+// ETag yang diterima, weak ETag dan "*" tidak bisa dipakai untuk mendeteksi
+// perubahan dari client lain.
+func ParseIfMatch(header string) (string, bool) {
+	header = strings.TrimSpace(header)
+	if len(header) < 2 || !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) {
+		return "", false
+	}
+
+	version := header[1 : len(header)-1]
+	if version == "" || strings.ContainsAny(version, `",`) {
+		return "", false
+	}
+
+	return version, true
+}
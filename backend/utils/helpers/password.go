@@ -0,0 +1,87 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Parameter argon2id untuk hash password baru, mengikuti rekomendasi OWASP
+const (
+	argon2Memory  uint32 = 64 * 1024
+	argon2Time    uint32 = 3
+	argon2Threads uint8  = 2
+	argon2KeyLen  uint32 = 32
+	argon2SaltLen        = 16
+)
+
+// HashPassword hash password dengan argon2id dan salt acak, format PHC:
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, argon2Memory, argon2Time, argon2Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// VerifyPassword cek password terhadap hash yang tersimpan. needsRehash true jika
+// hash cocok tetapi memakai format lama (SHA-256 tanpa salt) atau parameter argon2
+// yang sudah tidak dipakai, sehingga perlu di-hash ulang dengan HashPassword.
+func VerifyPassword(password, encoded string) (match bool, needsRehash bool) {
+	if !strings.HasPrefix(encoded, "$argon2id$") {
+		return verifyLegacyHash(password, encoded), true
+	}
+
+	var version int
+	var memory, time uint32
+	var threads uint8
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return false, false
+	}
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, false
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false, false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, false
+	}
+
+	other := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return false, false
+	}
+
+	needsRehash = memory != argon2Memory || time != argon2Time || threads != argon2Threads || uint32(len(key)) != argon2KeyLen
+	return true, needsRehash
+}
+
+// verifyLegacyHash cek hash SHA-256 hex dari versi lama (lihat Hash)
+func verifyLegacyHash(password, encoded string) bool {
+	if _, err := hex.DecodeString(encoded); err != nil || len(encoded) != 64 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(Hash([]byte(password))), []byte(encoded)) == 1
+}
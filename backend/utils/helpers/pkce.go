@@ -3,23 +3,9 @@ package helpers
 import (
 	"crypto/sha256"
 	"encoding/base64"
-	"math/rand"
-	"time"
 )
 
-func GenerateRandomString(length int) string {
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-._~"
-	seed := rand.NewSource(time.Now().UnixNano())
-	random := rand.New(seed)
-
-	result := make([]byte, length)
-	for i := range result {
-		result[i] = charset[random.Intn(len(charset))]
-	}
-
-	return string(result)
-}
-
+// GenerateCodeChallenge code challenge PKCE metode S256 dari verifier
 func GenerateCodeChallenge(verifier string) string {
 	hash := sha256.Sum256([]byte(verifier))
 	return base64.RawURLEncoding.EncodeToString(hash[:])
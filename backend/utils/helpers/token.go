@@ -0,0 +1,17 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+)
+
+// GenerateSecureToken generate token URL-safe dari crypto/rand, dipakai untuk
+// semua token yang memberi akses termasuk state dan code verifier OIDC.
+func GenerateSecureToken(byteLength int) (string, error) {
+	b := make([]byte, byteLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
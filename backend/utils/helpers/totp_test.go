@@ -0,0 +1,111 @@
+package helpers
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret secret SHA1 dari RFC 6238 Appendix B ("12345678901234567890")
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+// Vektor RFC 6238 Appendix B (SHA1). RFC memakai 8 digit, code 6 digit adalah
+// 6 digit terakhirnya karena keduanya modulo dari nilai truncation yang sama.
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},          // 94287082
+	{1111111109, "081804"},  // 07081804
+	{1111111111, "050471"},  // 14050471
+	{1234567890, "005924"},  // 89005924
+	{2000000000, "279037"},  // 69279037
+	{20000000000, "353130"}, // 65353130
+}
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	for _, tc := range rfc6238Vectors {
+		counter := TOTPCounter(time.Unix(tc.unix, 0))
+
+		code, err := TOTPCode(rfc6238Secret, counter)
+		if err != nil {
+			t.Fatalf("TOTPCode(t=%d): %v", tc.unix, err)
+		}
+		if code != tc.code {
+			t.Errorf("TOTPCode(t=%d) = %s, want %s", tc.unix, code, tc.code)
+		}
+	}
+}
+
+func TestTOTPCodeLowercaseSecret(t *testing.T) {
+	upper, err := TOTPCode(rfc6238Secret, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lower, err := TOTPCode("gezdgnbvgy3tqojqgezdgnbvgy3tqojq", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lower != upper {
+		t.Fatalf("lowercase secret code = %s, want %s", lower, upper)
+	}
+}
+
+func TestTOTPCodeInvalidSecret(t *testing.T) {
+	if _, err := TOTPCode("not base32!", 1); err == nil {
+		t.Fatal("TOTPCode with invalid secret: want error")
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	counter := TOTPCounter(now)
+
+	codeAt := func(c int64) string {
+		code, err := TOTPCode(rfc6238Secret, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	cases := []struct {
+		name        string
+		code        string
+		skew        int64
+		wantCounter int64
+		wantOK      bool
+	}{
+		{"current step", codeAt(counter), 1, counter, true},
+		{"previous step within skew", codeAt(counter - 1), 1, counter - 1, true},
+		{"next step within skew", codeAt(counter + 1), 1, counter + 1, true},
+		{"previous step without skew", codeAt(counter - 1), 0, 0, false},
+		{"outside skew", codeAt(counter - 2), 1, 0, false},
+		{"surrounding whitespace", " " + codeAt(counter) + " ", 1, counter, true},
+		{"wrong length", codeAt(counter)[:5], 1, 0, false},
+		{"wrong code", "000000", 1, 0, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := VerifyTOTP(rfc6238Secret, tc.code, now, tc.skew)
+			if ok != tc.wantOK || got != tc.wantCounter {
+				t.Fatalf("VerifyTOTP(%q) = (%d, %v), want (%d, %v)", tc.code, got, ok, tc.wantCounter, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret) != 32 {
+		t.Fatalf("secret length = %d, want 32", len(secret))
+	}
+	if _, err := TOTPCode(secret, 0); err != nil {
+		t.Fatalf("generated secret is not usable: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var (
+	KindDiagramBlock = ast.NewNodeKind("DiagramBlock")
+	KindDiagramEmbed = ast.NewNodeKind("DiagramEmbed")
+
+	// embed document lain: ![[slug]], slug mengikuti helpers.Slug
+	regexpEmbed = regexp.MustCompile(`^!\[\[\s*([a-z0-9-]+)\s*\]\]`)
+)
+
+// DiagramBlock pengganti fenced code block ```mermaid
+type DiagramBlock struct {
+	ast.BaseBlock
+	Content string
+}
+
+func (n *DiagramBlock) Kind() ast.NodeKind { return KindDiagramBlock }
+
+func (n *DiagramBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Content": n.Content}, nil)
+}
+
+// DiagramEmbed embed ![[slug]] di dalam paragraf
+type DiagramEmbed struct {
+	ast.BaseInline
+	Slug string
+}
+
+func (n *DiagramEmbed) Kind() ast.NodeKind { return KindDiagramEmbed }
+
+func (n *DiagramEmbed) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Slug": n.Slug}, nil)
+}
+
+// diagramTransformer ganti fenced code block berbahasa mermaid menjadi DiagramBlock
+type diagramTransformer struct{}
+
+func (_i *diagramTransformer) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+
+	var blocks []*ast.FencedCodeBlock
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if block, ok := n.(*ast.FencedCodeBlock); ok && entering {
+			if strings.EqualFold(string(block.Language(source)), "mermaid") {
+				blocks = append(blocks, block)
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, block := range blocks {
+		var content bytes.Buffer
+		lines := block.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			segment := lines.At(i)
+			content.Write(segment.Value(source))
+		}
+		block.Parent().ReplaceChild(block.Parent(), block, &DiagramBlock{Content: content.String()})
+	}
+}
+
+// embedParser parse ![[slug]], dijalankan sebelum parser link/image bawaan
+type embedParser struct{}
+
+func (_i *embedParser) Trigger() []byte {
+	return []byte{'!'}
+}
+
+func (_i *embedParser) Parse(_ ast.Node, block text.Reader, _ parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	m := regexpEmbed.FindSubmatch(line)
+	if m == nil {
+		return nil
+	}
+
+	block.Advance(len(m[0]))
+	return &DiagramEmbed{Slug: string(m[1])}
+}
+
+// RegisterFuncs render DiagramBlock dan DiagramEmbed menjadi placeholder SVG
+func (_i *diagramRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindDiagramBlock, _i.renderBlock)
+	reg.Register(KindDiagramEmbed, _i.renderEmbed)
+}
+
+func (_i *diagramRenderer) renderBlock(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	placeholder, msg := _i.diagram(node.(*DiagramBlock).Content)
+	_, _ = w.WriteString(`<figure class="diagram">`)
+	if msg != "" {
+		_, _ = w.WriteString(errorHTML(msg))
+	} else {
+		_, _ = w.WriteString(placeholder)
+	}
+	_, _ = w.WriteString("</figure>\n")
+
+	return ast.WalkContinue, nil
+}
+
+func (_i *diagramRenderer) renderEmbed(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	placeholder, msg := _i.embed(node.(*DiagramEmbed).Slug)
+	_, _ = w.WriteString(`<span class="diagram">`)
+	if msg != "" {
+		_, _ = w.WriteString(errorHTML(msg))
+	} else {
+		_, _ = w.WriteString(placeholder)
+	}
+	_, _ = w.WriteString("</span>")
+
+	return ast.WalkContinue, nil
+}
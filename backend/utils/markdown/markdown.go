@@ -0,0 +1,155 @@
+// Package markdown render document Markdown (CommonMark + GFM) menjadi HTML yang
+// sudah disanitasi. Blok ```mermaid dan embed ![[slug]] digambar inline sebagai SVG
+// sehingga content bisa ditampilkan tanpa JavaScript frontend.
+package markdown
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/mermaid"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/render"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// maxDiagrams batas diagram per document agar satu render tidak menghabiskan CPU
+const maxDiagrams = 50
+
+// EmbedResolver cari content Mermaid document lain berdasarkan slug. ok false jika
+// document tidak ada, tidak boleh dilihat pembaca, atau bukan diagram.
+type EmbedResolver func(slug string) (content string, ok bool, err error)
+
+// Render Markdown menjadi HTML. Resolver nil berarti semua embed ditampilkan sebagai
+// tidak tersedia.
+func Render(src string, resolve EmbedResolver) (string, error) {
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	r := &diagramRenderer{
+		marker:  "diagram-" + hex.EncodeToString(nonce),
+		resolve: resolve,
+		embeds:  map[string]embedResult{},
+	}
+
+	md := newGoldmark(goldmark.WithRendererOptions(
+		renderer.WithNodeRenderers(util.Prioritized(r, 100)),
+	))
+
+	var b bytes.Buffer
+	if err := md.Convert([]byte(src), &b); err != nil {
+		return "", err
+	}
+	if r.err != nil {
+		return "", r.err
+	}
+
+	// SVG dibuat server sendiri sehingga disisipkan setelah sanitasi, lewat marker
+	// dengan nonce yang tidak bisa ditulis di content
+	out := sanitizer.Sanitize(b.String())
+	for i, svg := range r.svgs {
+		out = strings.ReplaceAll(out, r.placeholder(i), svg)
+	}
+
+	return out, nil
+}
+
+// Parse Markdown menjadi AST goldmark untuk output selain HTML, contoh: PDF. Blok
+// mermaid menjadi *DiagramBlock dan embed menjadi *DiagramEmbed.
+func Parse(src []byte) ast.Node {
+	return newGoldmark().Parser().Parse(text.NewReader(src))
+}
+
+// newGoldmark CommonMark + GFM dengan parser diagram
+func newGoldmark(opts ...goldmark.Option) goldmark.Markdown {
+	opts = append(opts,
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(
+			parser.WithInlineParsers(util.Prioritized(&embedParser{}, 100)),
+			parser.WithASTTransformers(util.Prioritized(&diagramTransformer{}, 100)),
+		),
+	)
+	return goldmark.New(opts...)
+}
+
+type embedResult struct {
+	svg string
+	msg string
+}
+
+// diagramRenderer menyimpan hasil render SVG selama satu konversi
+type diagramRenderer struct {
+	marker  string
+	resolve EmbedResolver
+	embeds  map[string]embedResult
+	svgs    []string
+	err     error
+}
+
+func (_i *diagramRenderer) placeholder(i int) string {
+	return fmt.Sprintf("[%s:%d]", _i.marker, i)
+}
+
+// diagram render content mermaid, mengembalikan placeholder SVG atau pesan error
+func (_i *diagramRenderer) diagram(content string) (string, string) {
+	if len(_i.svgs) >= maxDiagrams {
+		return "", fmt.Sprintf("diagram limit of %d per document reached", maxDiagrams)
+	}
+
+	graph, diags := mermaid.Parse(content)
+	if mermaid.HasErrors(diags) {
+		for _, d := range diags {
+			if d.Severity == mermaid.SeverityError {
+				return "", fmt.Sprintf("line %d: %s", d.Line, d.Message)
+			}
+		}
+	}
+
+	svg, err := render.SVG(graph)
+	if err != nil {
+		if errors.Is(err, render.ErrUnsupported) {
+			return "", fmt.Sprintf("%s diagrams cannot be rendered yet", graph.Type)
+		}
+		return "", err.Error()
+	}
+
+	_i.svgs = append(_i.svgs, string(svg))
+	return _i.placeholder(len(_i.svgs) - 1), ""
+}
+
+// embed render document lain, hasil per slug dipakai ulang dalam satu document
+func (_i *diagramRenderer) embed(slug string) (string, string) {
+	if res, ok := _i.embeds[slug]; ok {
+		return res.svg, res.msg
+	}
+
+	res := embedResult{msg: fmt.Sprintf("embedded diagram %q is not available", slug)}
+	if _i.resolve != nil && _i.err == nil {
+		content, ok, err := _i.resolve(slug)
+		if err != nil {
+			_i.err = err
+		} else if ok {
+			res.svg, res.msg = _i.diagram(content)
+		}
+	}
+
+	_i.embeds[slug] = res
+	return res.svg, res.msg
+}
+
+// errorHTML pesan error diagram yang tetap lolos sanitasi
+func errorHTML(msg string) string {
+	return `<span class="diagram-error">` + html.EscapeString(msg) + `</span>`
+}
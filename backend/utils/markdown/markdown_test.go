@@ -0,0 +1,126 @@
+package markdown
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// regexpPlaceholder placeholder yang tersisa di output berarti SVG tidak disisipkan
+var regexpPlaceholder = regexp.MustCompile(`\[diagram-[0-9a-f]{16}:\d+\]`)
+
+func TestRenderStripsRawMarkup(t *testing.T) {
+	src := "# Title\n\n" +
+		"<script>alert(1)</script>\n\n" +
+		"<svg onload=\"alert(1)\"><circle r=\"1\"/></svg>\n\n" +
+		"text <img src=x onerror=\"alert(1)\"> and [link](javascript:alert(1))\n\n" +
+		"<figure class=\"diagram\" onclick=\"alert(1)\">fake</figure>\n"
+
+	out, err := Render(src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, forbidden := range []string{"<script", "<svg", "onload", "onerror", "onclick", "javascript:"} {
+		if strings.Contains(out, forbidden) {
+			t.Fatalf("output contains %q:\n%s", forbidden, out)
+		}
+	}
+	if !strings.Contains(out, "<h1") {
+		t.Fatalf("output lost regular markup:\n%s", out)
+	}
+}
+
+func TestRenderPlaceholderInContentStaysText(t *testing.T) {
+	// Placeholder tulisan user, baik di teks maupun di dalam diagram, tidak boleh
+	// diganti SVG karena nonce-nya tidak sama dengan marker render
+	src := "before [diagram-0123456789abcdef:0] after\n\n" +
+		"```mermaid\ngraph TD\nA[\"[diagram-0123456789abcdef:0]\"] --> B\n```\n"
+
+	out, err := Render(src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := strings.Count(out, "<svg"); n != 1 {
+		t.Fatalf("svg count = %d, want 1:\n%s", n, out)
+	}
+	if !strings.Contains(out, "before [diagram-0123456789abcdef:0] after") {
+		t.Fatalf("placeholder-like text was replaced:\n%s", out)
+	}
+
+	// Placeholder milik render ini semuanya sudah diganti
+	for _, m := range regexpPlaceholder.FindAllString(out, -1) {
+		if m != "[diagram-0123456789abcdef:0]" {
+			t.Fatalf("placeholder %q left in output:\n%s", m, out)
+		}
+	}
+}
+
+func TestRenderDiagramEscapesLabels(t *testing.T) {
+	src := "```mermaid\ngraph TD\nA[\"<script>alert(1)</script>\"] --> B[\"<svg onload=alert(1)>\"] --> C[\"x < y & z\"]\n```\n"
+
+	out, err := Render(src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(out, "<script") || strings.Count(out, "<svg") != 1 {
+		t.Fatalf("diagram label injected markup:\n%s", out)
+	}
+	if !strings.Contains(out, ">x &lt; y &amp; z</text>") {
+		t.Fatalf("escaped label missing:\n%s", out)
+	}
+}
+
+func TestRenderDiagramErrors(t *testing.T) {
+	src := "```mermaid\ngraph TD\nA -> <b>B</b>\n```\n"
+
+	out, err := Render(src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(out, `<span class="diagram-error">line 2: `) {
+		t.Fatalf("diagram error missing:\n%s", out)
+	}
+	if strings.Contains(out, "<b>") || strings.Contains(out, "<svg") {
+		t.Fatalf("invalid diagram rendered markup:\n%s", out)
+	}
+}
+
+func TestRenderEmbeds(t *testing.T) {
+	calls := 0
+	resolve := func(slug string) (string, bool, error) {
+		calls++
+		if slug == "flow" {
+			return "graph TD\nA --> B", true, nil
+		}
+		return "", false, nil
+	}
+
+	out, err := Render("![[flow]] ![[flow]] ![[missing]]", resolve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := strings.Count(out, "<svg"); n != 2 {
+		t.Fatalf("svg count = %d, want 2:\n%s", n, out)
+	}
+	if calls != 2 {
+		t.Fatalf("resolver calls = %d, want 2", calls)
+	}
+	if !strings.Contains(out, "embedded diagram &#34;missing&#34; is not available") {
+		t.Fatalf("missing embed message not found:\n%s", out)
+	}
+	if regexpPlaceholder.MatchString(out) {
+		t.Fatalf("placeholder left in output:\n%s", out)
+	}
+
+	// Error resolver menggagalkan seluruh render
+	failed := errors.New("database down")
+	if _, err := Render("![[flow]]", func(string) (string, bool, error) { return "", false, failed }); !errors.Is(err, failed) {
+		t.Fatalf("Render error = %v, want %v", err, failed)
+	}
+}
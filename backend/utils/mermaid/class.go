@@ -0,0 +1,147 @@
+package mermaid
+
+import (
+	"regexp"
+	"strings"
+)
+
+// classID nama class: kata dengan generic opsional (Animal~T~) atau nama dalam backtick
+const classID = "(`[^`]+`|[\\w-]+?(?:~[^~]+~)?)"
+
+var (
+	// relasi antar class, contoh: A <|-- B, A "1" *-- "many" B : has
+	regexpClassRelation = regexp.MustCompile(`^` + classID + `\s*(?:"([^"]*)"\s*)?(<\||\*|o|<|\(\)|\})?(--|\.\.)(\|>|\*|o|>|\(\)|\{)?\s*(?:"([^"]*)"\s*)?` + classID + `(?:\s*:::\s*\w+)?\s*(?::\s*(.*))?$`)
+
+	regexpClassDef        = regexp.MustCompile(`^class\s+` + classID + `(?:\s*\[\s*"([^"]*)"\s*\])?(?:\s*:::\s*\w+)?\s*(\{)?\s*(\})?$`)
+	regexpClassMember     = regexp.MustCompile(`^` + classID + `\s*:\s*(.+)$`)
+	regexpClassAnnotation = regexp.MustCompile(`^<<[^>]+>>\s*` + classID + `$`)
+	regexpClassNote       = regexp.MustCompile(`^note\s+(?:for\s+` + classID + `\s+)?"[^"]*"$`)
+	regexpClassNamespace  = regexp.MustCompile(`^namespace\s+[\w.-]+\s*\{$`)
+	regexpClassName       = regexp.MustCompile(`^` + classID + `$`)
+
+	classIgnoredKeywords = map[string]bool{
+		"classDef": true, "cssClass": true, "style": true, "click": true,
+		"callback": true, "link": true, "title": true,
+	}
+)
+
+// parseClass mem-parsing classDiagram. Class menjadi node dan relasi menjadi edge.
+func parseClass(lines []sourceLine) (*Graph, []Diagnostic) {
+	g := newGraph(DiagramClass)
+	var diags []Diagnostic
+
+	// body class yang sedang dibuka dan namespace yang belum ditutup
+	var body *sourceLine
+	var namespaces []sourceLine
+
+	// id class tanpa generic, Animal~T~ menjadi Animal. Label hanya diganti
+	// oleh deklarasi class dengan label.
+	addClass := func(name, label string, line sourceLine) string {
+		name = strings.Trim(name, "`")
+		if i := strings.IndexByte(name, '~'); i > 0 {
+			name = name[:i]
+		}
+		shape := ""
+		if label != "" {
+			shape = "class"
+		}
+		g.addNode(name, label, shape, line.Number)
+		return name
+	}
+
+	for _, line := range lines {
+		text, col := statementText(line)
+		if text == "" {
+			continue
+		}
+
+		// member di dalam body class bebas formatnya sampai '}'
+		if body != nil {
+			if text == "}" {
+				body = nil
+			}
+			continue
+		}
+
+		keyword, rest := splitKeyword(text)
+
+		switch {
+		case text == "}":
+			if len(namespaces) == 0 {
+				diags = append(diags, errorAt(line, col, "unexpected '}'"))
+			} else {
+				namespaces = namespaces[:len(namespaces)-1]
+			}
+			continue
+
+		case keyword == "direction":
+			if !flowchartDirections[rest] {
+				diags = append(diags, errorAt(line, col, "invalid direction %q, expected one of TB, TD, BT, RL, LR", rest))
+			} else {
+				g.Direction = rest
+			}
+			continue
+
+		case keyword == "namespace":
+			if !regexpClassNamespace.MatchString(text) {
+				diags = append(diags, errorAt(line, col, "invalid namespace, expected 'namespace <name> {'"))
+				continue
+			}
+			namespaces = append(namespaces, line)
+			continue
+
+		case keyword == "class":
+			m := regexpClassDef.FindStringSubmatch(text)
+			if m == nil {
+				diags = append(diags, errorAt(line, col, "invalid class declaration %q", text))
+				continue
+			}
+			addClass(m[1], m[2], line)
+			if m[3] != "" && m[4] == "" {
+				body = &line
+			}
+			continue
+
+		case strings.EqualFold(keyword, "note"):
+			if !regexpClassNote.MatchString(text) {
+				diags = append(diags, errorAt(line, col, "invalid note, expected 'note \"text\"' or 'note for <class> \"text\"'"))
+			}
+			continue
+
+		case classIgnoredKeywords[keyword]:
+			continue
+		}
+
+		if m := regexpClassRelation.FindStringSubmatch(text); m != nil {
+			from, to := addClass(m[1], "", line), addClass(m[7], "", line)
+			g.addEdge(from, to, m[3]+m[4]+m[5], strings.TrimSpace(m[8]), line.Number)
+			continue
+		}
+
+		if m := regexpClassMember.FindStringSubmatch(text); m != nil {
+			addClass(m[1], "", line)
+			continue
+		}
+
+		if m := regexpClassAnnotation.FindStringSubmatch(text); m != nil {
+			addClass(m[1], "", line)
+			continue
+		}
+
+		if m := regexpClassName.FindStringSubmatch(text); m != nil {
+			addClass(m[1], "", line)
+			continue
+		}
+
+		diags = append(diags, errorAt(line, col, "invalid statement %q", text))
+	}
+
+	if body != nil {
+		diags = append(diags, errorAt(*body, strings.Index(body.Text, "{")+1, "class body not closed with '}'"))
+	}
+	for _, ns := range namespaces {
+		diags = append(diags, errorAt(ns, strings.Index(ns.Text, "namespace")+1, "namespace not closed with '}'"))
+	}
+
+	return g, diags
+}
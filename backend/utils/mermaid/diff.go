@@ -0,0 +1,158 @@
+package mermaid
+
+import "fmt"
+
+// NodeChange node yang ditambahkan atau dihapus
+type NodeChange struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+// NodeRelabel node yang labelnya berubah
+type NodeRelabel struct {
+	ID   string `json:"id"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// EdgeChange edge yang ditambahkan atau dihapus
+type EdgeChange struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Arrow string `json:"arrow"`
+	Label string `json:"label,omitempty"`
+}
+
+// EdgeRelabel edge antara dua node yang sama tetapi label atau jenis link-nya berubah
+type EdgeRelabel struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	FromArrow string `json:"from_arrow"`
+	ToArrow   string `json:"to_arrow"`
+	FromLabel string `json:"from_label"`
+	ToLabel   string `json:"to_label"`
+}
+
+// GraphDiff perbedaan struktural antara dua diagram
+type GraphDiff struct {
+	NodesAdded      []NodeChange  `json:"nodes_added"`
+	NodesRemoved    []NodeChange  `json:"nodes_removed"`
+	NodesRelabelled []NodeRelabel `json:"nodes_relabelled"`
+	EdgesAdded      []EdgeChange  `json:"edges_added"`
+	EdgesRemoved    []EdgeChange  `json:"edges_removed"`
+	EdgesRelabelled []EdgeRelabel `json:"edges_relabelled"`
+	Summary         []string      `json:"summary"`
+}
+
+// Empty true jika tidak ada perubahan struktural
+func (d *GraphDiff) Empty() bool {
+	return len(d.Summary) == 0
+}
+
+// DiffGraphs membandingkan dua graph berdasarkan id node dan pasangan from/to edge
+func DiffGraphs(old, new *Graph) *GraphDiff {
+	d := &GraphDiff{
+		NodesAdded:      []NodeChange{},
+		NodesRemoved:    []NodeChange{},
+		NodesRelabelled: []NodeRelabel{},
+		EdgesAdded:      []EdgeChange{},
+		EdgesRemoved:    []EdgeChange{},
+		EdgesRelabelled: []EdgeRelabel{},
+		Summary:         []string{},
+	}
+
+	for _, n := range old.Nodes {
+		other := new.Node(n.ID)
+		if other == nil {
+			d.NodesRemoved = append(d.NodesRemoved, NodeChange{ID: n.ID, Label: n.Label})
+			d.Summary = append(d.Summary, fmt.Sprintf("node %s removed", n.ID))
+			continue
+		}
+		if other.Label != n.Label {
+			d.NodesRelabelled = append(d.NodesRelabelled, NodeRelabel{ID: n.ID, From: n.Label, To: other.Label})
+			d.Summary = append(d.Summary, fmt.Sprintf("node %s relabelled %q -> %q", n.ID, n.Label, other.Label))
+		}
+	}
+
+	for _, n := range new.Nodes {
+		if old.Node(n.ID) == nil {
+			d.NodesAdded = append(d.NodesAdded, NodeChange{ID: n.ID, Label: n.Label})
+			d.Summary = append(d.Summary, fmt.Sprintf("node %s added", n.ID))
+		}
+	}
+
+	diffEdges(d, old.Edges, new.Edges)
+
+	return d
+}
+
+// diffEdges mencocokkan edge per pasangan from/to. Edge yang identik dipasangkan
+// lebih dulu, sisanya dipasangkan berurutan sebagai relabel, lalu sisa
+// terakhir dianggap added/removed.
+func diffEdges(d *GraphDiff, oldEdges, newEdges []*Edge) {
+	type key struct{ from, to string }
+
+	newByKey := map[key][]*Edge{}
+	for _, e := range newEdges {
+		k := key{e.From, e.To}
+		newByKey[k] = append(newByKey[k], e)
+	}
+
+	var unmatchedOld []*Edge
+	for _, e := range oldEdges {
+		k := key{e.From, e.To}
+		candidates := newByKey[k]
+
+		matched := -1
+		for i, c := range candidates {
+			if c.Arrow == e.Arrow && c.Label == e.Label {
+				matched = i
+				break
+			}
+		}
+
+		if matched >= 0 {
+			newByKey[k] = append(candidates[:matched:matched], candidates[matched+1:]...)
+			continue
+		}
+		unmatchedOld = append(unmatchedOld, e)
+	}
+
+	for _, e := range unmatchedOld {
+		k := key{e.From, e.To}
+		if candidates := newByKey[k]; len(candidates) > 0 {
+			c := candidates[0]
+			newByKey[k] = candidates[1:]
+
+			d.EdgesRelabelled = append(d.EdgesRelabelled, EdgeRelabel{
+				From: e.From, To: e.To,
+				FromArrow: e.Arrow, ToArrow: c.Arrow,
+				FromLabel: e.Label, ToLabel: c.Label,
+			})
+			if c.Label != e.Label {
+				d.Summary = append(d.Summary, fmt.Sprintf("edge %s%s%s relabelled %q -> %q", e.From, e.Arrow, e.To, e.Label, c.Label))
+			} else {
+				d.Summary = append(d.Summary, fmt.Sprintf("edge %s%s%s changed to %s%s%s", e.From, e.Arrow, e.To, c.From, c.Arrow, c.To))
+			}
+			continue
+		}
+
+		d.EdgesRemoved = append(d.EdgesRemoved, EdgeChange{From: e.From, To: e.To, Arrow: e.Arrow, Label: e.Label})
+		d.Summary = append(d.Summary, fmt.Sprintf("edge %s%s%s removed", e.From, e.Arrow, e.To))
+	}
+
+	// sisa edge baru yang belum terpasang, urut sesuai posisi di source
+	for _, e := range newEdges {
+		k := key{e.From, e.To}
+		for i, c := range newByKey[k] {
+			if c != e {
+				continue
+			}
+			newByKey[k] = append(newByKey[k][:i:i], newByKey[k][i+1:]...)
+
+			d.EdgesAdded = append(d.EdgesAdded, EdgeChange{From: e.From, To: e.To, Arrow: e.Arrow, Label: e.Label})
+			d.Summary = append(d.Summary, fmt.Sprintf("edge %s%s%s added", e.From, e.Arrow, e.To))
+			break
+		}
+	}
+}
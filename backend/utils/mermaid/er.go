@@ -0,0 +1,126 @@
+package mermaid
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// erEntity nama entity, boleh dengan alias: CUSTOMER, "Line Item", p[Person]
+	erEntity = `("[^"]+"|[\w-]+)(?:\[[^\]]*\])?`
+
+	// kardinalitas dalam bentuk simbol atau kata
+	erCardinalityWords = `one or zero|zero or one|one or more|one or many|many\(1\)|1\+|zero or more|zero or many|many\(0\)|0\+|only one|1`
+	erLeftCardinality  = `(\|o|\|\||\}o|\}\||` + erCardinalityWords + `)`
+	erRightCardinality = `(o\||\|\||o\{|\|\{|` + erCardinalityWords + `)`
+)
+
+var (
+	// relasi dengan simbol (A ||--o{ B) atau kata (A only one to zero or more B)
+	regexpERSymbolRelation = regexp.MustCompile(`^` + erEntity + `\s*(\|o|\|\||\}o|\}\|)(--|\.\.)(o\||\|\||o\{|\|\{)\s*` + erEntity + `\s*:\s*(.*)$`)
+	regexpERWordRelation   = regexp.MustCompile(`^` + erEntity + `\s+` + erLeftCardinality + `\s+(to|optionally to)\s+` + erRightCardinality + `\s+` + erEntity + `\s*:\s*(.*)$`)
+
+	regexpEREntityBlock = regexp.MustCompile(`^` + erEntity + `\s*\{\s*(\})?$`)
+	regexpEREntityName  = regexp.MustCompile(`^` + erEntity + `$`)
+
+	// atribut: type name [PK, FK, UK] ["comment"]
+	regexpERAttribute = regexp.MustCompile(`^[\w\-\[\]\(\),.]+\s+\*?[\w\-\[\]()]+(?:\s+(?:PK|FK|UK)(?:\s*,\s*(?:PK|FK|UK))*)?(?:\s+"[^"]*")?$`)
+
+	erIgnoredKeywords = map[string]bool{
+		"classDef": true, "class": true, "style": true, "title": true,
+	}
+)
+
+// parseER mem-parsing erDiagram. Entity menjadi node dan relationship menjadi edge.
+func parseER(lines []sourceLine) (*Graph, []Diagnostic) {
+	g := newGraph(DiagramER)
+	var diags []Diagnostic
+
+	// entity yang blok atributnya sedang dibuka
+	var block *sourceLine
+
+	addEntity := func(name string, line sourceLine) string {
+		id := strings.Trim(name, `"`)
+		g.addNode(id, "", "", line.Number)
+		return id
+	}
+
+	for _, line := range lines {
+		text, col := statementText(line)
+		if text == "" {
+			continue
+		}
+
+		if block != nil {
+			switch {
+			case text == "}":
+				block = nil
+			case !regexpERAttribute.MatchString(text):
+				diags = append(diags, errorAt(line, col, "invalid attribute %q, expected '<type> <name> [PK|FK|UK] [\"comment\"]'", text))
+			}
+			continue
+		}
+
+		keyword, rest := splitKeyword(text)
+
+		switch {
+		case keyword == "direction":
+			if !flowchartDirections[rest] {
+				diags = append(diags, errorAt(line, col, "invalid direction %q, expected one of TB, TD, BT, RL, LR", rest))
+			} else {
+				g.Direction = rest
+			}
+			continue
+
+		case erIgnoredKeywords[keyword]:
+			continue
+		}
+
+		if m := regexpERSymbolRelation.FindStringSubmatch(text); m != nil {
+			from, to := addEntity(m[1], line), addEntity(m[5], line)
+			g.addEdge(from, to, m[2]+m[3]+m[4], erLabel(m[6]), line.Number)
+			if m[6] == "" {
+				diags = append(diags, errorAt(line, col+len(text), "relationship requires a label after ':'"))
+			}
+			continue
+		}
+
+		if m := regexpERWordRelation.FindStringSubmatch(text); m != nil {
+			from, to := addEntity(m[1], line), addEntity(m[5], line)
+			g.addEdge(from, to, m[2]+" "+m[3]+" "+m[4], erLabel(m[6]), line.Number)
+			if m[6] == "" {
+				diags = append(diags, errorAt(line, col+len(text), "relationship requires a label after ':'"))
+			}
+			continue
+		}
+
+		if m := regexpEREntityBlock.FindStringSubmatch(text); m != nil {
+			addEntity(m[1], line)
+			if m[2] == "" {
+				block = &line
+			}
+			continue
+		}
+
+		if m := regexpEREntityName.FindStringSubmatch(text); m != nil {
+			addEntity(m[1], line)
+			continue
+		}
+
+		if strings.ContainsAny(text, "|}{") || strings.Contains(text, "--") || strings.Contains(text, "..") {
+			diags = append(diags, errorAt(line, col, "invalid relationship %q, expected '<entity> <cardinality>--<cardinality> <entity> : <label>'", text))
+			continue
+		}
+		diags = append(diags, errorAt(line, col, "invalid statement %q", text))
+	}
+
+	if block != nil {
+		diags = append(diags, errorAt(*block, strings.Index(block.Text, "{")+1, "entity block not closed with '}'"))
+	}
+
+	return g, diags
+}
+
+func erLabel(label string) string {
+	return unquote(strings.TrimSpace(label))
+}
@@ -0,0 +1,358 @@
+package mermaid
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	flowchartDirections = map[string]bool{"TB": true, "TD": true, "BT": true, "RL": true, "LR": true}
+
+	// bentuk link yang valid, contoh: -->, ---, -.->, ==>, <-->, --x, ~~~
+	regexpLink = regexp.MustCompile(`^(?:[<xo]?(?:-{2,}>|-{2,}[xo]|={2,}>|={2,}[xo]|-\.+->|-\.+-[xo])|-{3,}|={3,}|-\.+-|~{3,})$`)
+
+	// penutup link dengan teks inline, contoh: A -- text --> B
+	regexpLinkTextEnd = regexp.MustCompile(`^(.*?)\s*(-{2,}>|-{2,}[xo]|-{3,}|={2,}>|={2,}[xo]|={3,}|\.-+>|\.-+[xo]|\.-+)`)
+
+	// pasangan pembuka dan penutup shape node, urut dari yang terpanjang
+	nodeShapes = []struct {
+		open, close, name string
+	}{
+		{"(((", ")))", "double-circle"},
+		{"((", "))", "circle"},
+		{"([", "])", "stadium"},
+		{"[[", "]]", "subroutine"},
+		{"[(", ")]", "cylinder"},
+		{"[/", "/]", "parallelogram"},
+		{"[/", `\]`, "trapezoid"},
+		{`[\`, `\]`, "parallelogram-alt"},
+		{`[\`, "/]", "trapezoid-alt"},
+		{"{{", "}}", "hexagon"},
+		{"(", ")", "round"},
+		{"[", "]", "rect"},
+		{"{", "}", "rhombus"},
+		{">", "]", "asymmetric"},
+	}
+
+	// keyword yang bukan bagian dari graph dan cukup dilewati
+	flowchartIgnoredKeywords = map[string]bool{
+		"classDef": true, "class": true, "style": true, "linkStyle": true,
+		"click": true, "direction": true, "accTitle": true, "accDescr": true,
+	}
+)
+
+// parseFlowchart mem-parsing diagram flowchart/graph
+func parseFlowchart(header sourceLine, rest string, lines []sourceLine) (*Graph, []Diagnostic) {
+	g := newGraph(DiagramFlowchart)
+	var diags []Diagnostic
+
+	if rest != "" {
+		dir := strings.TrimSuffix(strings.Fields(rest)[0], ";")
+		if !flowchartDirections[dir] {
+			diags = append(diags, Diagnostic{
+				Line:     header.Number,
+				Column:   strings.Index(header.Text, dir) + 1,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("invalid direction %q, expected one of TB, TD, BT, RL, LR", dir),
+			})
+		}
+		g.Direction = dir
+	}
+
+	subgraphDepth := 0
+	for _, line := range lines {
+		for _, stmt := range splitStatements(line.Text) {
+			text := strings.TrimSpace(stmt.text)
+			if text == "" {
+				continue
+			}
+
+			col := stmt.col + strings.Index(stmt.text, text)
+			keyword, _ := splitKeyword(text)
+
+			switch {
+			case keyword == "subgraph":
+				subgraphDepth++
+				continue
+			case text == "end":
+				if subgraphDepth == 0 {
+					diags = append(diags, Diagnostic{
+						Line: line.Number, Column: col, Severity: SeverityError,
+						Message: "unexpected 'end' without matching 'subgraph'",
+					})
+				} else {
+					subgraphDepth--
+				}
+				continue
+			case flowchartIgnoredKeywords[keyword]:
+				continue
+			}
+
+			p := &flowchartParser{graph: g, src: text, line: line.Number, col: col}
+			if d := p.parseChain(); d != nil {
+				diags = append(diags, *d)
+			}
+		}
+	}
+
+	if subgraphDepth > 0 {
+		last := lines[len(lines)-1]
+		diags = append(diags, Diagnostic{
+			Line: last.Number, Column: 1, Severity: SeverityError,
+			Message: fmt.Sprintf("%d subgraph(s) not closed with 'end'", subgraphDepth),
+		})
+	}
+
+	return g, diags
+}
+
+type statement struct {
+	text string
+	col  int
+}
+
+// splitStatements memecah satu baris berdasarkan ';' di luar label/kutip
+func splitStatements(line string) []statement {
+	var out []statement
+	depth, start := 0, 0
+	inQuote := false
+
+	for i, r := range line {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case strings.ContainsRune("[({", r):
+			depth++
+		case strings.ContainsRune("])}", r) && depth > 0:
+			depth--
+		case r == ';' && depth == 0:
+			out = append(out, statement{text: line[start:i], col: start + 1})
+			start = i + 1
+		}
+	}
+
+	return append(out, statement{text: line[start:], col: start + 1})
+}
+
+// flowchartParser parser untuk satu statement flowchart
+type flowchartParser struct {
+	graph *Graph
+	src   string
+	pos   int
+	line  int
+	col   int
+}
+
+func (p *flowchartParser) errorf(format string, args ...any) *Diagnostic {
+	return &Diagnostic{
+		Line:     p.line,
+		Column:   p.col + p.pos,
+		Severity: SeverityError,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *flowchartParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *flowchartParser) skipSpaces() {
+	for !p.eof() && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// parseChain: nodeGroup (link nodeGroup)*
+func (p *flowchartParser) parseChain() *Diagnostic {
+	from, d := p.parseNodeGroup()
+	if d != nil {
+		return d
+	}
+
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			return nil
+		}
+
+		arrow, label, d := p.parseLink()
+		if d != nil {
+			return d
+		}
+
+		to, d := p.parseNodeGroup()
+		if d != nil {
+			return d
+		}
+
+		for _, f := range from {
+			for _, t := range to {
+				p.graph.addEdge(f, t, arrow, label, p.line)
+			}
+		}
+		from = to
+	}
+}
+
+// parseNodeGroup: node ('&' node)*
+func (p *flowchartParser) parseNodeGroup() ([]string, *Diagnostic) {
+	var ids []string
+
+	for {
+		id, d := p.parseNode()
+		if d != nil {
+			return nil, d
+		}
+		ids = append(ids, id)
+
+		p.skipSpaces()
+		if p.eof() || p.src[p.pos] != '&' {
+			return ids, nil
+		}
+		p.pos++
+	}
+}
+
+// parseNode: id shape? (':::' class)?
+func (p *flowchartParser) parseNode() (string, *Diagnostic) {
+	p.skipSpaces()
+
+	start := p.pos
+	for !p.eof() {
+		r := rune(p.src[p.pos])
+		if r >= 0x80 || unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			p.pos++
+			continue
+		}
+		// '-' boleh di tengah id selama bukan awal link, contoh: my-node
+		if r == '-' && p.pos > start && p.pos+1 < len(p.src) && isIDChar(p.src[p.pos+1]) {
+			p.pos++
+			continue
+		}
+		break
+	}
+
+	if p.pos == start {
+		if p.eof() {
+			return "", p.errorf("expected node id")
+		}
+		return "", p.errorf("expected node id, found %q", p.src[p.pos])
+	}
+
+	id := p.src[start:p.pos]
+	label, shape := "", ""
+
+	for _, s := range nodeShapes {
+		if !strings.HasPrefix(p.src[p.pos:], s.open) {
+			continue
+		}
+
+		end := findClose(p.src, p.pos+len(s.open), s.close)
+		if end < 0 {
+			continue
+		}
+
+		label = unquote(p.src[p.pos+len(s.open) : end])
+		shape = s.name
+		p.pos = end + len(s.close)
+		break
+	}
+
+	if shape == "" && !p.eof() && strings.ContainsRune("[({>", rune(p.src[p.pos])) {
+		return "", p.errorf("unclosed shape for node %q", id)
+	}
+
+	// class shorthand, contoh: A:::highlight
+	if strings.HasPrefix(p.src[p.pos:], ":::") {
+		p.pos += 3
+		for !p.eof() && isIDChar(p.src[p.pos]) {
+			p.pos++
+		}
+	}
+
+	p.graph.addNode(id, label, shape, p.line)
+	return id, nil
+}
+
+// parseLink membaca link beserta label opsional: -->, -- text -->, -->|text|
+func (p *flowchartParser) parseLink() (arrow, label string, d *Diagnostic) {
+	start := p.pos
+
+	// head bidirectional x/o di awal, contoh: x--x
+	if !p.eof() && (p.src[p.pos] == 'x' || p.src[p.pos] == 'o') &&
+		p.pos+1 < len(p.src) && (p.src[p.pos+1] == '-' || p.src[p.pos+1] == '=') {
+		p.pos++
+	}
+
+	for !p.eof() && strings.ContainsRune("-=.<>~", rune(p.src[p.pos])) {
+		p.pos++
+	}
+
+	// head x/o di akhir langsung setelah '-' atau '=', sama seperti lexer Mermaid
+	// boleh tanpa spasi sebelum node berikutnya, contoh: A--oB, A==xB
+	if !p.eof() && (p.src[p.pos] == 'x' || p.src[p.pos] == 'o') &&
+		p.pos-start >= 2 && (p.src[p.pos-1] == '-' || p.src[p.pos-1] == '=') {
+		p.pos++
+	}
+
+	run := p.src[start:p.pos]
+	if run == "" {
+		return "", "", p.errorf("expected link or end of statement, found %q", p.src[p.pos])
+	}
+
+	switch {
+	case regexpLink.MatchString(run):
+		arrow = run
+	case run == "--" || run == "==" || run == "-.":
+		// link dengan teks inline
+		m := regexpLinkTextEnd.FindStringSubmatchIndex(p.src[p.pos:])
+		if m == nil {
+			p.pos = start
+			return "", "", p.errorf("unterminated link text after %q", run)
+		}
+		label = unquote(p.src[p.pos+m[2] : p.pos+m[3]])
+		arrow = p.src[p.pos+m[4] : p.pos+m[5]]
+		if run == "-." {
+			arrow = "-" + arrow
+		}
+		p.pos += m[5]
+	default:
+		p.pos = start
+		return "", "", p.errorf("invalid link %q", run)
+	}
+
+	// label dengan format pipe, contoh: -->|yes|
+	p.skipSpaces()
+	if !p.eof() && p.src[p.pos] == '|' {
+		end := strings.IndexByte(p.src[p.pos+1:], '|')
+		if end < 0 {
+			return "", "", p.errorf("unterminated link label")
+		}
+		label = unquote(p.src[p.pos+1 : p.pos+1+end])
+		p.pos += end + 2
+	}
+
+	return arrow, label, nil
+}
+
+// findClose mencari penutup shape, mengabaikan isi di dalam tanda kutip
+func findClose(s string, from int, closer string) int {
+	inQuote := false
+	for i := from; i < len(s); i++ {
+		if s[i] == '"' {
+			inQuote = !inQuote
+			continue
+		}
+		if !inQuote && strings.HasPrefix(s[i:], closer) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isIDChar(c byte) bool {
+	return c >= 0x80 || c == '_' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
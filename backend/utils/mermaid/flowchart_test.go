@@ -0,0 +1,51 @@
+package mermaid
+
+import "testing"
+
+func TestFlowchartLinks(t *testing.T) {
+	cases := []struct {
+		src   string
+		arrow string
+		label string
+	}{
+		{"A-->B", "-->", ""},
+		{"A --> B", "-->", ""},
+		{"A---B", "---", ""},
+		{"A-.->B", "-.->", ""},
+		{"A==>B", "==>", ""},
+		{"A<-->B", "<-->", ""},
+		{"A~~~B", "~~~", ""},
+		{"A --o B", "--o", ""},
+		{"A --x B", "--x", ""},
+		{"A--oB", "--o", ""},
+		{"A--xB", "--x", ""},
+		{"A---oB", "---o", ""},
+		{"A==oB", "==o", ""},
+		{"A-.-xB", "-.-x", ""},
+		{"A o--o B", "o--o", ""},
+		{"A x--x B", "x--x", ""},
+		{"A-->oB", "-->", ""},
+		{"A -- text --> B", "-->", "text"},
+		{"A -. text .-> B", "-.->", "text"},
+		{"A == text ==> B", "==>", "text"},
+		{"A -->|yes| B", "-->", "yes"},
+		{`A -- "quoted text" --> B`, "-->", "quoted text"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.src, func(t *testing.T) {
+			g, diags := Parse("graph TD\n" + tc.src)
+			if HasErrors(diags) {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if len(g.Edges) != 1 {
+				t.Fatalf("edges = %d, want 1", len(g.Edges))
+			}
+
+			e := g.Edges[0]
+			if e.Arrow != tc.arrow || e.Label != tc.label {
+				t.Fatalf("edge = {arrow: %q, label: %q}, want {arrow: %q, label: %q}", e.Arrow, e.Label, tc.arrow, tc.label)
+			}
+		})
+	}
+}
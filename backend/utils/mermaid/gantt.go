@@ -0,0 +1,154 @@
+package mermaid
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	regexpGanttDuration = regexp.MustCompile(`^\d+(?:\.\d+)?(?:ms|s|m|h|d|w|M|y)$`)
+	regexpGanttID       = regexp.MustCompile(`^[\w-]+$`)
+
+	ganttTags = map[string]bool{"done": true, "active": true, "crit": true, "milestone": true}
+
+	ganttSettings = map[string]bool{
+		"title": true, "dateFormat": true, "axisFormat": true, "tickInterval": true,
+		"excludes": true, "includes": true, "todayMarker": true, "weekday": true,
+		"weekend": true, "inclusiveEndDates": true, "topAxis": true, "displayMode": true,
+		"click": true,
+	}
+)
+
+// ganttDependency referensi 'after' ke task lain, divalidasi setelah semua task terbaca
+type ganttDependency struct {
+	task string
+	id   string
+	line sourceLine
+	col  int
+}
+
+// parseGantt mem-parsing gantt. Task menjadi node dan dependency 'after'
+// menjadi edge dari task yang ditunggu ke task yang menunggu.
+func parseGantt(lines []sourceLine) (*Graph, []Diagnostic) {
+	g := newGraph(DiagramGantt)
+	var diags []Diagnostic
+	var deps []ganttDependency
+
+	// tanggal hanya divalidasi untuk format default
+	dateFormat := "YYYY-MM-DD"
+	tasks := 0
+
+	for _, line := range lines {
+		text, col := statementText(line)
+		if text == "" {
+			continue
+		}
+
+		keyword, rest := splitKeyword(text)
+
+		switch {
+		case keyword == "section":
+			if rest == "" {
+				diags = append(diags, errorAt(line, col, "section requires a name"))
+			}
+			continue
+
+		case keyword == "dateFormat":
+			if rest == "" {
+				diags = append(diags, errorAt(line, col, "dateFormat requires a format"))
+			}
+			dateFormat = rest
+			continue
+
+		case ganttSettings[keyword] && !strings.Contains(text, ":"):
+			continue
+		}
+
+		colon := strings.IndexByte(text, ':')
+		if colon < 0 {
+			diags = append(diags, errorAt(line, col, "invalid statement %q, tasks must be '<name> : <metadata>'", text))
+			continue
+		}
+
+		name := strings.TrimSpace(text[:colon])
+		metaCol := col + colon + 1
+		if name == "" {
+			diags = append(diags, errorAt(line, col, "task requires a name"))
+			continue
+		}
+
+		var items []string
+		for _, item := range strings.Split(text[colon+1:], ",") {
+			items = append(items, strings.TrimSpace(item))
+		}
+		for len(items) > 0 && ganttTags[items[0]] {
+			items = items[1:]
+		}
+
+		tasks++
+		id := fmt.Sprintf("task%d", tasks)
+		var start, end string
+
+		switch len(items) {
+		case 1:
+			end = items[0]
+		case 2:
+			start, end = items[0], items[1]
+		case 3:
+			if !regexpGanttID.MatchString(items[0]) {
+				diags = append(diags, errorAt(line, metaCol, "invalid task id %q", items[0]))
+				continue
+			}
+			id, start, end = items[0], items[1], items[2]
+		default:
+			diags = append(diags, errorAt(line, metaCol, "task metadata must be [tags,] [id,] [start,] end"))
+			continue
+		}
+
+		if g.Node(id) != nil && g.Node(id).Shape == "task" {
+			diags = append(diags, errorAt(line, metaCol, "duplicate task id %q", id))
+			continue
+		}
+		g.addNode(id, name, "task", line.Number)
+
+		if strings.HasPrefix(start, "after ") {
+			for _, dep := range strings.Fields(start)[1:] {
+				deps = append(deps, ganttDependency{task: id, id: dep, line: line, col: metaCol})
+			}
+		} else if start != "" && !validGanttDate(start, dateFormat) {
+			diags = append(diags, errorAt(line, metaCol, "invalid start %q, expected a date or 'after <task id>'", start))
+		}
+
+		switch {
+		case end == "":
+			diags = append(diags, errorAt(line, metaCol, "task requires an end date or duration"))
+		case strings.HasPrefix(end, "until "):
+			for _, dep := range strings.Fields(end)[1:] {
+				deps = append(deps, ganttDependency{task: id, id: dep, line: line, col: metaCol})
+			}
+		case !regexpGanttDuration.MatchString(end) && !validGanttDate(end, dateFormat):
+			diags = append(diags, errorAt(line, metaCol, "invalid end %q, expected a date, a duration like 3d or 'until <task id>'", end))
+		}
+	}
+
+	for _, dep := range deps {
+		if node := g.Node(dep.id); node == nil || node.Shape != "task" {
+			diags = append(diags, errorAt(dep.line, dep.col, "unknown task id %q", dep.id))
+			continue
+		}
+		g.addEdge(dep.id, dep.task, "after", "", dep.line.Number)
+	}
+
+	return g, diags
+}
+
+// validGanttDate cek tanggal sesuai dateFormat, format selain default tidak divalidasi
+func validGanttDate(s, dateFormat string) bool {
+	if dateFormat != "YYYY-MM-DD" {
+		return s != ""
+	}
+	_, err := time.Parse("2006-1-2", s)
+	return err == nil
+}
@@ -0,0 +1,292 @@
+package mermaid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiagramType jenis diagram Mermaid berdasarkan keyword header
+type DiagramType string
+
+const (
+	DiagramUnknown   DiagramType = "unknown"
+	DiagramFlowchart DiagramType = "flowchart"
+	DiagramSequence  DiagramType = "sequence"
+	DiagramClass     DiagramType = "class"
+	DiagramState     DiagramType = "state"
+	DiagramER        DiagramType = "er"
+	DiagramGantt     DiagramType = "gantt"
+)
+
+// unvalidatedDiagrams jenis diagram Mermaid yang dikenal tetapi belum di-parse,
+// isinya tidak divalidasi
+var unvalidatedDiagrams = map[string]bool{
+	"pie": true, "journey": true, "gitGraph": true, "mindmap": true, "timeline": true,
+	"quadrantChart": true, "requirementDiagram": true, "sankey-beta": true,
+	"xychart-beta": true, "block-beta": true, "packet-beta": true, "kanban": true,
+	"architecture-beta": true, "radar-beta": true, "zenuml": true, "C4Context": true,
+	"C4Container": true, "C4Component": true, "C4Dynamic": true, "C4Deployment": true,
+}
+
+// Severity tingkat diagnostic hasil parsing
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic pesan parser dengan posisi baris dan kolom (1-based)
+type Diagnostic struct {
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s", d.Line, d.Column, d.Severity, d.Message)
+}
+
+// HasErrors true jika ada diagnostic dengan severity error
+func HasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Node satu node/vertex pada diagram
+type Node struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Shape string `json:"shape,omitempty"`
+	Line  int    `json:"line"`
+}
+
+// Edge satu relasi antar node
+type Edge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Arrow string `json:"arrow"`
+	Label string `json:"label,omitempty"`
+	Line  int    `json:"line"`
+}
+
+// Graph representasi struktural dari sebuah diagram
+type Graph struct {
+	Type      DiagramType `json:"type"`
+	Direction string      `json:"direction,omitempty"`
+	Nodes     []*Node     `json:"nodes"`
+	Edges     []*Edge     `json:"edges"`
+
+	nodeIndex map[string]*Node
+}
+
+func newGraph(t DiagramType) *Graph {
+	return &Graph{
+		Type:      t,
+		Nodes:     []*Node{},
+		Edges:     []*Edge{},
+		nodeIndex: map[string]*Node{},
+	}
+}
+
+// Node mencari node berdasarkan id
+func (g *Graph) Node(id string) *Node {
+	return g.nodeIndex[id]
+}
+
+// addNode menambahkan node baru atau memperbarui label node yang sudah ada.
+// Definisi dengan shape/label terakhir yang berlaku, sama seperti Mermaid.
+func (g *Graph) addNode(id, label, shape string, line int) *Node {
+	if n, ok := g.nodeIndex[id]; ok {
+		if shape != "" {
+			n.Label = label
+			n.Shape = shape
+		}
+		return n
+	}
+
+	if label == "" {
+		label = id
+	}
+
+	n := &Node{ID: id, Label: label, Shape: shape, Line: line}
+	g.Nodes = append(g.Nodes, n)
+	g.nodeIndex[id] = n
+	return n
+}
+
+func (g *Graph) addEdge(from, to, arrow, label string, line int) {
+	g.Edges = append(g.Edges, &Edge{From: from, To: to, Arrow: arrow, Label: label, Line: line})
+}
+
+// sourceLine satu baris source beserta nomor barisnya (1-based)
+type sourceLine struct {
+	Text   string
+	Number int
+}
+
+// Parse mem-parsing source Mermaid menjadi Graph beserta diagnostic.
+// Graph selalu dikembalikan (best effort) walaupun ada error.
+func Parse(src string) (*Graph, []Diagnostic) {
+	lines := splitSource(src)
+	if len(lines) == 0 {
+		return newGraph(DiagramUnknown), []Diagnostic{{
+			Line: 1, Column: 1, Severity: SeverityError, Message: "diagram is empty",
+		}}
+	}
+
+	header := lines[0]
+	keyword, rest := splitKeyword(strings.TrimSpace(header.Text))
+	body, diags := skipAccessibility(lines[1:])
+
+	var g *Graph
+	var parsed []Diagnostic
+
+	switch keyword {
+	case "graph", "flowchart", "flowchart-elk":
+		g, parsed = parseFlowchart(header, rest, body)
+	case "sequenceDiagram":
+		g, parsed = parseSequence(body)
+	case "classDiagram", "classDiagram-v2":
+		g, parsed = parseClass(body)
+	case "stateDiagram", "stateDiagram-v2":
+		g, parsed = parseState(body)
+	case "erDiagram":
+		g, parsed = parseER(body)
+	case "gantt":
+		g, parsed = parseGantt(body)
+	}
+
+	if g != nil {
+		return g, append(diags, parsed...)
+	}
+
+	if unvalidatedDiagrams[keyword] {
+		return newGraph(DiagramUnknown), []Diagnostic{{
+			Line:     header.Number,
+			Column:   1,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("%s diagrams are not validated", keyword),
+		}}
+	}
+
+	return newGraph(DiagramUnknown), []Diagnostic{{
+		Line:     header.Number,
+		Column:   1,
+		Severity: SeverityError,
+		Message:  fmt.Sprintf("unknown diagram type %q", keyword),
+	}}
+}
+
+// splitSource memecah source per baris, membuang komentar (%%), directive
+// dan front matter YAML, serta baris kosong
+func splitSource(src string) []sourceLine {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	raw := strings.Split(src, "\n")
+
+	var lines []sourceLine
+	inFrontMatter := false
+
+	for i, text := range raw {
+		trimmed := strings.TrimSpace(text)
+
+		// front matter hanya valid di awal dokumen
+		if trimmed == "---" && (inFrontMatter || len(lines) == 0) {
+			inFrontMatter = !inFrontMatter
+			continue
+		}
+		if inFrontMatter || trimmed == "" || strings.HasPrefix(trimmed, "%%") {
+			continue
+		}
+
+		lines = append(lines, sourceLine{Text: text, Number: i + 1})
+	}
+
+	return lines
+}
+
+// skipAccessibility buang accTitle/accDescr yang berlaku untuk semua jenis
+// diagram, termasuk accDescr multi-baris dengan kurung kurawal
+func skipAccessibility(lines []sourceLine) ([]sourceLine, []Diagnostic) {
+	var out []sourceLine
+	var block *sourceLine
+
+	for i, line := range lines {
+		text := strings.TrimSpace(line.Text)
+
+		if block != nil {
+			if strings.HasSuffix(text, "}") {
+				block = nil
+			}
+			continue
+		}
+
+		keyword, rest := splitKeyword(text)
+		if strings.HasPrefix(text, "accTitle") || strings.HasPrefix(text, "accDescr") {
+			if sep := strings.IndexAny(text, ":{"); sep >= 0 {
+				keyword, rest = strings.TrimSpace(text[:sep]), text[sep:]
+			}
+		}
+
+		switch {
+		case (keyword == "accTitle" || keyword == "accDescr") && strings.HasPrefix(rest, ":"):
+			continue
+		case keyword == "accDescr" && strings.HasPrefix(rest, "{"):
+			if !strings.HasSuffix(rest, "}") {
+				block = &lines[i]
+			}
+			continue
+		}
+
+		out = append(out, line)
+	}
+
+	if block != nil {
+		return out, []Diagnostic{{
+			Line:     block.Number,
+			Column:   strings.Index(block.Text, "accDescr") + 1,
+			Severity: SeverityError,
+			Message:  "accDescr block not closed with '}'",
+		}}
+	}
+
+	return out, nil
+}
+
+// statementText teks statement tanpa spasi di tepi dan ';' di akhir, beserta
+// kolom awalnya (1-based)
+func statementText(line sourceLine) (string, int) {
+	text := strings.TrimSpace(line.Text)
+	col := strings.Index(line.Text, text) + 1
+	return strings.TrimSpace(strings.TrimSuffix(text, ";")), col
+}
+
+func errorAt(line sourceLine, col int, format string, args ...any) Diagnostic {
+	return Diagnostic{Line: line.Number, Column: col, Severity: SeverityError, Message: fmt.Sprintf(format, args...)}
+}
+
+func warningAt(line sourceLine, col int, format string, args ...any) Diagnostic {
+	return Diagnostic{Line: line.Number, Column: col, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)}
+}
+
+// splitKeyword memisahkan kata pertama dari sisa baris
+func splitKeyword(s string) (keyword, rest string) {
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		return s[:i], strings.TrimSpace(s[i+1:])
+	}
+	return s, ""
+}
+
+// unquote membuang tanda kutip ganda di awal dan akhir label
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,206 @@
+package mermaid
+
+import (
+	"strings"
+	"testing"
+)
+
+// wantDiag diagnostic yang diharapkan, message cukup dicocokkan sebagian
+type wantDiag struct {
+	line, column int
+	severity     Severity
+	message      string
+}
+
+func checkDiagnostics(t *testing.T, got []Diagnostic, want []wantDiag) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("diagnostics = %v, want %d", got, len(want))
+	}
+	for i, w := range want {
+		d := got[i]
+		if d.Line != w.line || d.Column != w.column || d.Severity != w.severity || !strings.Contains(d.Message, w.message) {
+			t.Fatalf("diagnostic[%d] = %v, want %d:%d: %s: ...%s...", i, d, w.line, w.column, w.severity, w.message)
+		}
+	}
+}
+
+func TestParseDiagnostics(t *testing.T) {
+	cases := []struct {
+		name  string
+		src   string
+		want  []wantDiag
+		nodes int
+		edges int
+	}{
+		// header
+		{"empty", "  \n%% comment\n", []wantDiag{{1, 1, SeverityError, "diagram is empty"}}, 0, 0},
+		{"unknown type", "\nfoo\nA-->B", []wantDiag{{2, 1, SeverityError, `unknown diagram type "foo"`}}, 0, 0},
+		{"unvalidated type", "pie\n\"a\": 1", []wantDiag{{1, 1, SeverityWarning, "pie diagrams are not validated"}}, 0, 0},
+		{"front matter", "---\ntitle: x\n---\ngraph TD\nA-->B", nil, 2, 1},
+		{"accDescr not closed", "graph TD\n  accDescr {\nA-->B", []wantDiag{{2, 3, SeverityError, "accDescr block not closed"}}, 0, 0},
+
+		// flowchart
+		{"flowchart valid", "flowchart LR\n  A[Start] --> B{Ok?}\n  B -->|yes| C & D\n  subgraph s\n    C --> E((End))\n  end\n  classDef x fill:#fff", nil, 5, 4},
+		{"flowchart statements", "graph TD\nA-->B; B-->C", nil, 3, 2},
+		{"flowchart invalid direction", "graph XY\nA-->B", []wantDiag{{1, 7, SeverityError, `invalid direction "XY"`}}, 2, 1},
+		{"flowchart invalid link", "graph TD\n  A -> B", []wantDiag{{2, 5, SeverityError, `invalid link "->"`}}, 1, 0},
+		{"flowchart unclosed shape", "graph TD\nA --> B[open", []wantDiag{{2, 8, SeverityError, `unclosed shape for node "B"`}}, 1, 0},
+		{"flowchart missing node", "graph TD\nA -->", []wantDiag{{2, 6, SeverityError, "expected node id"}}, 1, 0},
+		{"flowchart second statement", "graph TD\nA-->B;  C -> D", []wantDiag{{2, 11, SeverityError, `invalid link "->"`}}, 3, 1},
+		{"flowchart unterminated text", "graph TD\nA -- text B", []wantDiag{{2, 3, SeverityError, "unterminated link text"}}, 1, 0},
+		{"flowchart unterminated label", "graph TD\nA -->|yes B", []wantDiag{{2, 6, SeverityError, "unterminated link label"}}, 1, 0},
+		{"flowchart stray end", "graph TD\nA-->B\n  end", []wantDiag{{3, 3, SeverityError, "unexpected 'end'"}}, 2, 1},
+		{"flowchart subgraph not closed", "graph TD\nsubgraph s\nA-->B", []wantDiag{{3, 1, SeverityError, "1 subgraph(s) not closed"}}, 2, 1},
+
+		// sequence
+		{"sequence valid", "sequenceDiagram\n  participant A as Alice\n  actor B\n  A->>+B: hello\n  alt ok\n    B-->>-A: hi\n  else\n    B-xA: no\n  end\n  Note over A,B: done", nil, 2, 3},
+		{"sequence missing colon", "sequenceDiagram\n  A->>B hi", []wantDiag{{2, 11, SeverityError, "expected ':'"}}, 0, 0},
+		{"sequence missing receiver", "sequenceDiagram\nA->>: hi", []wantDiag{{2, 5, SeverityError, "message requires a receiver"}}, 0, 0},
+		{"sequence invalid statement", "sequenceDiagram\n  hello", []wantDiag{{2, 3, SeverityError, `invalid statement "hello"`}}, 0, 0},
+		{"sequence else outside alt", "sequenceDiagram\nloop x\n  else\nend", []wantDiag{{3, 3, SeverityError, "'else' is only valid inside alt"}}, 0, 0},
+		{"sequence block not closed", "sequenceDiagram\n  loop every minute\nA->>B: ping", []wantDiag{{2, 3, SeverityError, "'loop' block not closed"}}, 2, 1},
+		{"sequence inactive participant", "sequenceDiagram\nA->>-B: x", []wantDiag{{2, 2, SeverityError, `participant "A" is not active`}}, 2, 1},
+		{"sequence invalid note", "sequenceDiagram\nNote above A: x", []wantDiag{{2, 1, SeverityError, "invalid note"}}, 0, 0},
+		{"sequence note single participant", "sequenceDiagram\nNote left of A,B: x", []wantDiag{{2, 1, SeverityError, "note left of accepts a single participant"}}, 0, 0},
+		{"sequence invalid autonumber", "sequenceDiagram\nautonumber x", []wantDiag{{2, 1, SeverityError, "invalid autonumber"}}, 0, 0},
+
+		// class
+		{"class valid", "classDiagram\n  direction LR\n  class Animal~T~ {\n    +String name\n  }\n  Animal <|-- Duck : is\n  Duck \"1\" *-- \"many\" Egg\n  Duck : +swim()\n  <<interface>> Animal\n  note for Duck \"quack\"\n  namespace zoo {\n    class Cage\n  }", nil, 4, 2},
+		{"class invalid statement", "classDiagram\n  A -> B", []wantDiag{{2, 3, SeverityError, `invalid statement "A -> B"`}}, 0, 0},
+		{"class invalid direction", "classDiagram\ndirection up", []wantDiag{{2, 1, SeverityError, `invalid direction "up"`}}, 0, 0},
+		{"class invalid declaration", "classDiagram\nclass A B", []wantDiag{{2, 1, SeverityError, "invalid class declaration"}}, 0, 0},
+		{"class body not closed", "classDiagram\n  class A {\n    +int x", []wantDiag{{2, 11, SeverityError, "class body not closed"}}, 1, 0},
+		{"class namespace not closed", "classDiagram\n  namespace zoo {\n  class A", []wantDiag{{2, 3, SeverityError, "namespace not closed"}}, 1, 0},
+		{"class stray brace", "classDiagram\n}", []wantDiag{{2, 1, SeverityError, "unexpected '}'"}}, 0, 0},
+		{"class invalid note", "classDiagram\nnote for A", []wantDiag{{2, 1, SeverityError, "invalid note"}}, 0, 0},
+
+		// state
+		{"state valid", "stateDiagram-v2\n  [*] --> Idle\n  Idle --> Busy : start\n  state \"Working hard\" as Busy {\n    direction LR\n    [*] --> Step\n    --\n    Step --> [*]\n  }\n  state fork <<fork>>\n  note right of Idle\n    waiting\n  end note\n  Idle : ready", nil, 5, 4},
+		{"state invalid statement", "stateDiagram\n  A -> B", []wantDiag{{2, 3, SeverityError, `invalid statement "A -> B"`}}, 0, 0},
+		{"state invalid declaration", "stateDiagram\nstate \"x\"", []wantDiag{{2, 1, SeverityError, "invalid state declaration"}}, 0, 0},
+		{"state separator outside composite", "stateDiagram\n  --", []wantDiag{{2, 3, SeverityError, "concurrency separator"}}, 0, 0},
+		{"state composite not closed", "stateDiagram\n  state A {\n  B --> C", []wantDiag{{2, 3, SeverityError, "composite state not closed"}}, 3, 1},
+		{"state note not closed", "stateDiagram\nA --> B\n  note left of A\n  text", []wantDiag{{3, 3, SeverityError, "note not closed"}}, 2, 1},
+		{"state invalid note", "stateDiagram\nnote over A", []wantDiag{{2, 1, SeverityError, "invalid note"}}, 0, 0},
+
+		// er
+		{"er valid", "erDiagram\n  CUSTOMER ||--o{ ORDER : places\n  ORDER }|..|{ \"Line Item\" : contains\n  p[Person] only one to zero or more CUSTOMER : is\n  CUSTOMER {\n    string name PK \"full name\"\n    int age\n  }", nil, 4, 3},
+		{"er missing label", "erDiagram\n  A ||--o{ B :", []wantDiag{{2, 15, SeverityError, "relationship requires a label"}}, 2, 1},
+		{"er invalid relationship", "erDiagram\n  A ||--> B : x", []wantDiag{{2, 3, SeverityError, "invalid relationship"}}, 0, 0},
+		{"er invalid statement", "erDiagram\nA B", []wantDiag{{2, 1, SeverityError, `invalid statement "A B"`}}, 0, 0},
+		{"er invalid attribute", "erDiagram\nA {\n    string\n}", []wantDiag{{3, 5, SeverityError, `invalid attribute "string"`}}, 1, 0},
+		{"er block not closed", "erDiagram\n  A {\n  int x", []wantDiag{{2, 5, SeverityError, "entity block not closed"}}, 1, 0},
+
+		// gantt
+		{"gantt valid", "gantt\n  title Plan\n  dateFormat YYYY-MM-DD\n  section Build\n  Design : done, des, 2024-01-06, 3d\n  Code : active, code, after des, 5d\n  Ship : milestone, 1d\n  Test : until ship2\n  Ship 2 : ship2, 2024-02-01, 2024-02-03", nil, 5, 2},
+		{"gantt missing colon", "gantt\n  Design 3d", []wantDiag{{2, 3, SeverityError, "tasks must be '<name> : <metadata>'"}}, 0, 0},
+		{"gantt invalid end", "gantt\n  Design : 2024-01-01, soon", []wantDiag{{2, 11, SeverityError, `invalid end "soon"`}}, 1, 0},
+		{"gantt invalid start", "gantt\nDesign : 2024-13-01, 3d", []wantDiag{{2, 9, SeverityError, `invalid start "2024-13-01"`}}, 1, 0},
+		{"gantt unknown dependency", "gantt\nA : a1, 2024-01-01, 1d\nB : after a2, 2d", []wantDiag{{3, 4, SeverityError, `unknown task id "a2"`}}, 2, 0},
+		{"gantt duplicate id", "gantt\nA : a1, 2024-01-01, 1d\nB : a1, 2024-01-02, 1d", []wantDiag{{3, 4, SeverityError, `duplicate task id "a1"`}}, 1, 0},
+		{"gantt too much metadata", "gantt\nA : a, b, c, d", []wantDiag{{2, 4, SeverityError, "task metadata must be"}}, 0, 0},
+		{"gantt custom date format", "gantt\ndateFormat DD-MM-YYYY\nA : 01-02-2024, 1d", nil, 1, 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g, diags := Parse(tc.src)
+			checkDiagnostics(t, diags, tc.want)
+
+			if len(g.Nodes) != tc.nodes || len(g.Edges) != tc.edges {
+				t.Fatalf("graph = {nodes: %d, edges: %d}, want {nodes: %d, edges: %d}", len(g.Nodes), len(g.Edges), tc.nodes, tc.edges)
+			}
+		})
+	}
+}
+
+func TestParseGraph(t *testing.T) {
+	cases := []struct {
+		name      string
+		src       string
+		typ       DiagramType
+		direction string
+		nodes     map[string]string
+		edges     []string
+	}{
+		{
+			name:      "flowchart",
+			src:       "graph LR\nA[\"Start here\"] --> B\nB -- go --> C:::hot",
+			typ:       DiagramFlowchart,
+			direction: "LR",
+			nodes:     map[string]string{"A": "Start here", "B": "B", "C": "C"},
+			edges:     []string{"A-->B", "B-->C:go"},
+		},
+		{
+			name:  "sequence",
+			src:   "sequenceDiagram\nparticipant A as Alice\nA->>B: hi\nB--)A: bye",
+			typ:   DiagramSequence,
+			nodes: map[string]string{"A": "Alice", "B": "B"},
+			edges: []string{"A->>B:hi", "B--)A:bye"},
+		},
+		{
+			name:  "class",
+			src:   "classDiagram\nclass Animal[\"Hewan\"]\nAnimal~T~ <|-- Duck : is\n`My Class` ..> Duck",
+			typ:   DiagramClass,
+			nodes: map[string]string{"Animal": "Hewan", "Duck": "Duck", "My Class": "My Class"},
+			edges: []string{"Animal<|--Duck:is", "My Class..>Duck"},
+		},
+		{
+			name:  "state",
+			src:   "stateDiagram-v2\n[*] --> Idle\nstate \"Busy now\" as Busy\nIdle --> Busy : go",
+			typ:   DiagramState,
+			nodes: map[string]string{"[*]": "[*]", "Idle": "Idle", "Busy": "Busy now"},
+			edges: []string{"[*]-->Idle", "Idle-->Busy:go"},
+		},
+		{
+			name:  "er",
+			src:   "erDiagram\nCUSTOMER ||--o{ ORDER : \"places order\"",
+			typ:   DiagramER,
+			nodes: map[string]string{"CUSTOMER": "CUSTOMER", "ORDER": "ORDER"},
+			edges: []string{"CUSTOMER||--o{ORDER:places order"},
+		},
+		{
+			name:  "gantt",
+			src:   "gantt\nDesign : des, 2024-01-01, 2d\nCode : after des, 3d",
+			typ:   DiagramGantt,
+			nodes: map[string]string{"des": "Design", "task2": "Code"},
+			edges: []string{"desaftertask2"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g, diags := Parse(tc.src)
+			if HasErrors(diags) {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if g.Type != tc.typ || g.Direction != tc.direction {
+				t.Fatalf("graph = {type: %q, direction: %q}, want {type: %q, direction: %q}", g.Type, g.Direction, tc.typ, tc.direction)
+			}
+
+			if len(g.Nodes) != len(tc.nodes) {
+				t.Fatalf("nodes = %d, want %d", len(g.Nodes), len(tc.nodes))
+			}
+			for id, label := range tc.nodes {
+				n := g.Node(id)
+				if n == nil || n.Label != label {
+					t.Fatalf("node %q = %+v, want label %q", id, n, label)
+				}
+			}
+
+			var edges []string
+			for _, e := range g.Edges {
+				s := e.From + e.Arrow + e.To
+				if e.Label != "" {
+					s += ":" + e.Label
+				}
+				edges = append(edges, s)
+			}
+			if strings.Join(edges, "\n") != strings.Join(tc.edges, "\n") {
+				t.Fatalf("edges = %q, want %q", edges, tc.edges)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package mermaid
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// arrow message sequence, urut dari yang terpanjang
+	sequenceArrows = []string{"<<-->>", "<<->>", "-->>", "->>", "--x", "--)", "-->", "-x", "-)", "->"}
+
+	regexpSequenceNote   = regexp.MustCompile(`(?i)^note\s+(left of|right of|over)\s+([^:]+?)\s*:(.*)$`)
+	regexpAutonumber     = regexp.MustCompile(`^autonumber(?:\s+off|\s+\d+(?:\s+\d+)?)?$`)
+	regexpParticipantDef = regexp.MustCompile(`^(?:create\s+)?(participant|actor)\s+(.+?)(?:\s*@\{.*\})?(?:\s+as\s+(.+))?$`)
+
+	// blok yang ditutup dengan 'end'
+	sequenceBlocks = map[string]bool{
+		"loop": true, "alt": true, "opt": true, "par": true, "par_over": true,
+		"critical": true, "break": true, "rect": true, "box": true,
+	}
+
+	// keyword pemisah di dalam blok beserta blok induk yang valid
+	sequenceBranches = map[string][]string{
+		"else":   {"alt"},
+		"and":    {"par", "par_over"},
+		"option": {"critical"},
+	}
+
+	sequenceIgnoredKeywords = map[string]bool{
+		"title": true, "link": true, "links": true, "properties": true, "details": true,
+	}
+)
+
+type sequenceBlock struct {
+	keyword string
+	line    sourceLine
+	col     int
+}
+
+// parseSequence mem-parsing sequenceDiagram. Participant menjadi node dan
+// message menjadi edge.
+func parseSequence(lines []sourceLine) (*Graph, []Diagnostic) {
+	g := newGraph(DiagramSequence)
+	var diags []Diagnostic
+	var blocks []sequenceBlock
+	active := map[string]int{}
+
+	participant := func(name string, line sourceLine) string {
+		id := strings.TrimSpace(name)
+		g.addNode(id, "", "", line.Number)
+		return id
+	}
+
+	for _, line := range lines {
+		text, col := statementText(line)
+		if text == "" {
+			continue
+		}
+		keyword, rest := splitKeyword(text)
+
+		switch {
+		case sequenceBlocks[keyword]:
+			if keyword == "box" && hasBlock(blocks, "box") {
+				diags = append(diags, errorAt(line, col, "box cannot be nested"))
+			}
+			blocks = append(blocks, sequenceBlock{keyword: keyword, line: line, col: col})
+			continue
+
+		case sequenceBranches[keyword] != nil:
+			if len(blocks) == 0 || !slices.Contains(sequenceBranches[keyword], blocks[len(blocks)-1].keyword) {
+				diags = append(diags, errorAt(line, col, "'%s' is only valid inside %s", keyword, strings.Join(sequenceBranches[keyword], "/")))
+			}
+			continue
+
+		case text == "end":
+			if len(blocks) == 0 {
+				diags = append(diags, errorAt(line, col, "unexpected 'end' without matching block"))
+			} else {
+				blocks = blocks[:len(blocks)-1]
+			}
+			continue
+
+		case sequenceIgnoredKeywords[keyword]:
+			continue
+
+		case strings.HasPrefix(keyword, "autonumber"):
+			if !regexpAutonumber.MatchString(text) {
+				diags = append(diags, errorAt(line, col, "invalid autonumber, expected 'autonumber [start [step]]' or 'autonumber off'"))
+			}
+			continue
+
+		case keyword == "activate" || keyword == "deactivate":
+			if rest == "" {
+				diags = append(diags, errorAt(line, col, "%s requires a participant", keyword))
+				continue
+			}
+			id := participant(rest, line)
+			if keyword == "activate" {
+				active[id]++
+			} else if active[id] == 0 {
+				diags = append(diags, errorAt(line, col, "participant %q is not active", id))
+			} else {
+				active[id]--
+			}
+			continue
+
+		case keyword == "destroy":
+			if rest == "" {
+				diags = append(diags, errorAt(line, col, "destroy requires a participant"))
+			}
+			continue
+
+		case strings.EqualFold(keyword, "note"):
+			m := regexpSequenceNote.FindStringSubmatch(text)
+			if m == nil {
+				diags = append(diags, errorAt(line, col, "invalid note, expected 'Note left of|right of|over <participant>: text'"))
+				continue
+			}
+			names := strings.Split(m[2], ",")
+			if len(names) > 2 || (len(names) == 2 && !strings.EqualFold(m[1], "over")) {
+				diags = append(diags, errorAt(line, col, "note %s accepts a single participant", strings.ToLower(m[1])))
+				continue
+			}
+			for _, name := range names {
+				participant(name, line)
+			}
+			continue
+		}
+
+		if m := regexpParticipantDef.FindStringSubmatch(text); m != nil {
+			id := strings.TrimSpace(m[2])
+			label := strings.TrimSpace(m[3])
+			if label == "" {
+				label = id
+			}
+			g.addNode(id, label, m[1], line.Number)
+			continue
+		}
+
+		if d := parseSequenceMessage(g, line, col, text, active); d != nil {
+			diags = append(diags, *d)
+		}
+	}
+
+	for _, b := range blocks {
+		diags = append(diags, errorAt(b.line, b.col, "'%s' block not closed with 'end'", b.keyword))
+	}
+
+	return g, diags
+}
+
+// parseSequenceMessage: actor arrow [+|-] actor ':' text
+func parseSequenceMessage(g *Graph, line sourceLine, col int, text string, active map[string]int) *Diagnostic {
+	at, arrow := findSequenceArrow(text)
+	if at < 0 {
+		d := errorAt(line, col, "invalid statement %q", text)
+		return &d
+	}
+
+	from := strings.TrimSpace(text[:at])
+	rest := text[at+len(arrow):]
+
+	activation := ""
+	if trimmed := strings.TrimLeft(rest, " \t"); trimmed != "" && (trimmed[0] == '+' || trimmed[0] == '-') {
+		activation = trimmed[:1]
+		rest = trimmed[1:]
+	}
+
+	colon := strings.IndexByte(rest, ':')
+	if colon < 0 {
+		d := errorAt(line, col+len(text), "expected ':' and message text")
+		return &d
+	}
+	to := strings.TrimSpace(rest[:colon])
+
+	if from == "" {
+		d := errorAt(line, col, "message requires a sender")
+		return &d
+	}
+	if to == "" {
+		d := errorAt(line, col+at+len(arrow), "message requires a receiver")
+		return &d
+	}
+
+	g.addNode(from, "", "", line.Number)
+	g.addNode(to, "", "", line.Number)
+	g.addEdge(from, to, arrow, strings.TrimSpace(rest[colon+1:]), line.Number)
+
+	switch activation {
+	case "+":
+		active[to]++
+	case "-":
+		if active[from] == 0 {
+			d := errorAt(line, col+at, "participant %q is not active", from)
+			return &d
+		}
+		active[from]--
+	}
+
+	return nil
+}
+
+// findSequenceArrow posisi arrow pertama di statement
+func findSequenceArrow(s string) (int, string) {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '-' && s[i] != '<' {
+			continue
+		}
+		for _, arrow := range sequenceArrows {
+			if strings.HasPrefix(s[i:], arrow) {
+				return i, arrow
+			}
+		}
+	}
+	return -1, ""
+}
+
+func hasBlock(blocks []sequenceBlock, keyword string) bool {
+	for _, b := range blocks {
+		if b.keyword == keyword {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package mermaid
+
+import (
+	"regexp"
+	"strings"
+)
+
+// stateID id state, [*] untuk start/end
+const stateID = `(\[\*\]|[\w-]+)(?:\s*:::\s*\w+)?`
+
+var (
+	regexpStateTransition  = regexp.MustCompile(`^` + stateID + `\s*-->\s*` + stateID + `\s*(?::\s*(.*))?$`)
+	regexpStateAlias       = regexp.MustCompile(`^state\s+"([^"]*)"\s+as\s+([\w-]+)\s*(\{)?$`)
+	regexpStateDef         = regexp.MustCompile(`^state\s+([\w-]+)(?:\s+<<(fork|join|choice)>>)?\s*(\{)?$`)
+	regexpStateDescription = regexp.MustCompile(`^([\w-]+)\s*:\s*(.*)$`)
+	regexpStateNote        = regexp.MustCompile(`(?i)^note\s+(?:left|right)\s+of\s+([\w-]+)\s*(:.*)?$`)
+	regexpStateName        = regexp.MustCompile(`^([\w-]+)(?:\s*:::\s*\w+)?$`)
+
+	stateIgnoredKeywords = map[string]bool{
+		"classDef": true, "class": true, "style": true, "click": true,
+		"hide": true, "title": true,
+	}
+)
+
+// parseState mem-parsing stateDiagram. State menjadi node dan transisi menjadi edge.
+func parseState(lines []sourceLine) (*Graph, []Diagnostic) {
+	g := newGraph(DiagramState)
+	var diags []Diagnostic
+
+	// composite state yang belum ditutup dan note multi-baris yang sedang dibuka
+	var composites []sourceLine
+	var note *sourceLine
+
+	addState := func(id, label string, line sourceLine) {
+		shape := ""
+		if label != "" {
+			shape = "state"
+		}
+		g.addNode(id, label, shape, line.Number)
+	}
+
+	for _, line := range lines {
+		text, col := statementText(line)
+		if text == "" {
+			continue
+		}
+
+		if note != nil {
+			if text == "end note" {
+				note = nil
+			}
+			continue
+		}
+
+		keyword, rest := splitKeyword(text)
+
+		switch {
+		case text == "}":
+			if len(composites) == 0 {
+				diags = append(diags, errorAt(line, col, "unexpected '}'"))
+			} else {
+				composites = composites[:len(composites)-1]
+			}
+			continue
+
+		case text == "--":
+			if len(composites) == 0 {
+				diags = append(diags, errorAt(line, col, "concurrency separator '--' is only valid inside a composite state"))
+			}
+			continue
+
+		case keyword == "direction":
+			if !flowchartDirections[rest] {
+				diags = append(diags, errorAt(line, col, "invalid direction %q, expected one of TB, TD, BT, RL, LR", rest))
+			} else if len(composites) == 0 {
+				// direction di dalam composite state hanya berlaku untuk composite tersebut
+				g.Direction = rest
+			}
+			continue
+
+		case keyword == "state":
+			if m := regexpStateAlias.FindStringSubmatch(text); m != nil {
+				addState(m[2], m[1], line)
+				if m[3] != "" {
+					composites = append(composites, line)
+				}
+				continue
+			}
+			if m := regexpStateDef.FindStringSubmatch(text); m != nil {
+				addState(m[1], "", line)
+				if m[3] != "" {
+					composites = append(composites, line)
+				}
+				continue
+			}
+			diags = append(diags, errorAt(line, col, "invalid state declaration %q", text))
+			continue
+
+		case strings.EqualFold(keyword, "note"):
+			m := regexpStateNote.FindStringSubmatch(text)
+			if m == nil {
+				diags = append(diags, errorAt(line, col, "invalid note, expected 'note left of|right of <state>'"))
+				continue
+			}
+			addState(m[1], "", line)
+			if m[2] == "" {
+				note = &line
+			}
+			continue
+
+		case stateIgnoredKeywords[keyword]:
+			continue
+		}
+
+		if m := regexpStateTransition.FindStringSubmatch(text); m != nil {
+			addState(m[1], "", line)
+			addState(m[2], "", line)
+			g.addEdge(m[1], m[2], "-->", strings.TrimSpace(m[3]), line.Number)
+			continue
+		}
+
+		if m := regexpStateDescription.FindStringSubmatch(text); m != nil {
+			addState(m[1], strings.TrimSpace(m[2]), line)
+			continue
+		}
+
+		if m := regexpStateName.FindStringSubmatch(text); m != nil {
+			addState(m[1], "", line)
+			continue
+		}
+
+		diags = append(diags, errorAt(line, col, "invalid statement %q", text))
+	}
+
+	if note != nil {
+		diags = append(diags, errorAt(*note, strings.Index(note.Text, "note")+1, "note not closed with 'end note'"))
+	}
+	for _, c := range composites {
+		diags = append(diags, errorAt(c, strings.Index(c.Text, "state")+1, "composite state not closed with '}'"))
+	}
+
+	return g, diags
+}
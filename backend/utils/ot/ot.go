@@ -0,0 +1,319 @@
+package ot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Panjang dan posisi dihitung dalam rune (code point), bukan byte
+
+// MaxLength panjang maksimal dokumen dan komponen operasi dalam rune. Operasi
+// dari client yang melebihi batas ini ditolak saat decode agar penjumlahan
+// panjang tidak overflow.
+const MaxLength = 1 << 20
+
+var (
+	ErrBaseLength = errors.New("ot: operation base length does not match document length")
+	ErrConcurrent = errors.New("ot: concurrent operations have different base lengths")
+	ErrMalformed  = errors.New("ot: malformed operation")
+)
+
+// Component satu langkah operasi. Tepat satu field yang terisi.
+type Component struct {
+	Retain int
+	Insert string
+	Delete int
+}
+
+// Operation urutan retain/insert/delete yang menelusuri seluruh dokumen,
+// sama seperti model ot.js. BaseLen panjang dokumen sebelum operasi
+// diterapkan, TargetLen panjang sesudahnya.
+type Operation struct {
+	Components []Component
+	BaseLen    int
+	TargetLen  int
+}
+
+// Retain lewati n rune tanpa perubahan
+func (o *Operation) Retain(n int) *Operation {
+	if n <= 0 {
+		return o
+	}
+
+	o.BaseLen += n
+	o.TargetLen += n
+
+	if last := o.last(); last != nil && last.Retain > 0 {
+		last.Retain += n
+		return o
+	}
+
+	o.Components = append(o.Components, Component{Retain: n})
+	return o
+}
+
+// Insert sisipkan teks di posisi saat ini
+func (o *Operation) Insert(s string) *Operation {
+	if s == "" {
+		return o
+	}
+
+	o.TargetLen += utf8.RuneCountInString(s)
+
+	last := o.last()
+	switch {
+	case last != nil && last.Insert != "":
+		last.Insert += s
+	case last != nil && last.Delete > 0:
+		// Insert selalu diletakkan sebelum delete agar bentuk operasi kanonis
+		n := len(o.Components)
+		if n > 1 && o.Components[n-2].Insert != "" {
+			o.Components[n-2].Insert += s
+		} else {
+			o.Components = append(o.Components, *last)
+			o.Components[n-1] = Component{Insert: s}
+		}
+	default:
+		o.Components = append(o.Components, Component{Insert: s})
+	}
+
+	return o
+}
+
+// Delete hapus n rune mulai posisi saat ini
+func (o *Operation) Delete(n int) *Operation {
+	if n <= 0 {
+		return o
+	}
+
+	o.BaseLen += n
+
+	if last := o.last(); last != nil && last.Delete > 0 {
+		last.Delete += n
+		return o
+	}
+
+	o.Components = append(o.Components, Component{Delete: n})
+	return o
+}
+
+// IsNoop true jika operasi tidak mengubah dokumen
+func (o *Operation) IsNoop() bool {
+	return len(o.Components) == 0 || (len(o.Components) == 1 && o.Components[0].Retain > 0)
+}
+
+// Apply terapkan operasi ke dokumen
+func (o *Operation) Apply(doc string) (string, error) {
+	runes := []rune(doc)
+	if len(runes) != o.BaseLen {
+		return "", ErrBaseLength
+	}
+
+	var b strings.Builder
+	b.Grow(len(doc))
+
+	pos := 0
+	for _, c := range o.Components {
+		if c.Retain+c.Delete > len(runes)-pos {
+			return "", ErrBaseLength
+		}
+
+		switch {
+		case c.Retain > 0:
+			b.WriteString(string(runes[pos : pos+c.Retain]))
+			pos += c.Retain
+		case c.Insert != "":
+			b.WriteString(c.Insert)
+		case c.Delete > 0:
+			pos += c.Delete
+		}
+	}
+
+	return b.String(), nil
+}
+
+// Transform hitung a' dan b' untuk dua operasi konkuren a dan b sehingga
+// apply(apply(doc, a), b') == apply(apply(doc, b), a'). Jika keduanya insert
+// di posisi yang sama, insert milik a diletakkan lebih dulu.
+func Transform(a, b *Operation) (*Operation, *Operation, error) {
+	if a.BaseLen != b.BaseLen {
+		return nil, nil, ErrConcurrent
+	}
+
+	aPrime, bPrime := &Operation{}, &Operation{}
+	ops1, ops2 := a.Components, b.Components
+	i1, i2 := 0, 0
+
+	var op1, op2 *Component
+	next := func(ops []Component, i *int) *Component {
+		if *i >= len(ops) {
+			return nil
+		}
+		c := ops[*i]
+		*i++
+		return &c
+	}
+	op1, op2 = next(ops1, &i1), next(ops2, &i2)
+
+	for op1 != nil || op2 != nil {
+		if op1 != nil && op1.Insert != "" {
+			aPrime.Insert(op1.Insert)
+			bPrime.Retain(utf8.RuneCountInString(op1.Insert))
+			op1 = next(ops1, &i1)
+			continue
+		}
+		if op2 != nil && op2.Insert != "" {
+			aPrime.Retain(utf8.RuneCountInString(op2.Insert))
+			bPrime.Insert(op2.Insert)
+			op2 = next(ops2, &i2)
+			continue
+		}
+		if op1 == nil || op2 == nil {
+			return nil, nil, ErrConcurrent
+		}
+
+		len1, len2 := op1.Retain+op1.Delete, op2.Retain+op2.Delete
+		n := min(len1, len2)
+
+		switch {
+		case op1.Retain > 0 && op2.Retain > 0:
+			aPrime.Retain(n)
+			bPrime.Retain(n)
+		case op1.Delete > 0 && op2.Retain > 0:
+			aPrime.Delete(n)
+		case op1.Retain > 0 && op2.Delete > 0:
+			bPrime.Delete(n)
+		}
+		// delete/delete: bagian yang sama sudah dihapus kedua sisi
+
+		op1 = consume(op1, n, ops1, &i1, next)
+		op2 = consume(op2, n, ops2, &i2, next)
+	}
+
+	return aPrime, bPrime, nil
+}
+
+// consume kurangi komponen retain/delete sebanyak n, lanjut ke komponen
+// berikutnya jika sudah habis
+func consume(c *Component, n int, ops []Component, i *int, next func([]Component, *int) *Component) *Component {
+	if c.Retain > 0 {
+		c.Retain -= n
+		if c.Retain > 0 {
+			return c
+		}
+	} else {
+		c.Delete -= n
+		if c.Delete > 0 {
+			return c
+		}
+	}
+	return next(ops, i)
+}
+
+// TransformIndex posisi index setelah operasi diterapkan, dipakai untuk
+// menggeser cursor editor lain. Insert tepat di posisi index menggeser index.
+func (o *Operation) TransformIndex(index int) int {
+	newIndex, pos := index, 0
+
+	for _, c := range o.Components {
+		switch {
+		case c.Retain > 0:
+			pos += c.Retain
+		case c.Insert != "":
+			newIndex += utf8.RuneCountInString(c.Insert)
+		case c.Delete > 0:
+			newIndex -= min(index-pos, c.Delete)
+			pos += c.Delete
+		}
+
+		if pos > index {
+			break
+		}
+	}
+
+	return newIndex
+}
+
+// Range selection editor, Anchor == Head berarti cursor tanpa selection
+type Range struct {
+	Anchor int `json:"anchor"`
+	Head   int `json:"head"`
+}
+
+// Transform geser range mengikuti operasi
+func (r Range) Transform(o *Operation) Range {
+	return Range{
+		Anchor: o.TransformIndex(r.Anchor),
+		Head:   o.TransformIndex(r.Head),
+	}
+}
+
+func (o *Operation) last() *Component {
+	if len(o.Components) == 0 {
+		return nil
+	}
+	return &o.Components[len(o.Components)-1]
+}
+
+// MarshalJSON format ot.js: angka positif retain, angka negatif delete, string insert
+func (o Operation) MarshalJSON() ([]byte, error) {
+	out := make([]any, 0, len(o.Components))
+	for _, c := range o.Components {
+		switch {
+		case c.Retain > 0:
+			out = append(out, c.Retain)
+		case c.Insert != "":
+			out = append(out, c.Insert)
+		case c.Delete > 0:
+			out = append(out, -c.Delete)
+		}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON kebalikan MarshalJSON, BaseLen dan TargetLen dihitung ulang.
+// Komponen dan total panjang dibatasi MaxLength.
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return ErrMalformed
+	}
+
+	op := Operation{}
+	for _, item := range raw {
+		var n int
+		if err := json.Unmarshal(item, &n); err == nil {
+			switch {
+			case n > MaxLength || n < -MaxLength:
+				return fmt.Errorf("%w: component exceeds maximum length", ErrMalformed)
+			case n > 0:
+				op.Retain(n)
+			case n < 0:
+				op.Delete(-n)
+			default:
+				return fmt.Errorf("%w: zero-length component", ErrMalformed)
+			}
+		} else {
+			var s string
+			if err := json.Unmarshal(item, &s); err != nil || s == "" {
+				return fmt.Errorf("%w: component must be a non-zero integer or a non-empty string", ErrMalformed)
+			}
+			if len(s) > 4*MaxLength || utf8.RuneCountInString(s) > MaxLength {
+				return fmt.Errorf("%w: component exceeds maximum length", ErrMalformed)
+			}
+			op.Insert(s)
+		}
+
+		// Setiap komponen maksimal MaxLength sehingga total tidak bisa overflow
+		// selama dicek setelah setiap komponen
+		if op.BaseLen > MaxLength || op.TargetLen > MaxLength {
+			return fmt.Errorf("%w: operation exceeds maximum length", ErrMalformed)
+		}
+	}
+
+	*o = op
+	return nil
+}
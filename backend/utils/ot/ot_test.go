@@ -0,0 +1,193 @@
+package ot
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestUnmarshalMalformed(t *testing.T) {
+	cases := []struct {
+		name string
+		json string
+	}{
+		{"not an array", `{"retain":1}`},
+		{"zero component", `[0]`},
+		{"empty insert", `[""]`},
+		{"boolean component", `[true]`},
+		{"retain over limit", `[1048577]`},
+		{"delete over limit", `[-1048577]`},
+		{"overflowing retains", `[9223372036854775807,-1,9223372036854775807,-1]`},
+		{"min int delete", `[-9223372036854775808]`},
+		{"total over limit", `[1048576,1]`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var op Operation
+			if err := json.Unmarshal([]byte(tc.json), &op); !errors.Is(err, ErrMalformed) {
+				t.Fatalf("Unmarshal(%s) error = %v, want ErrMalformed", tc.json, err)
+			}
+		})
+	}
+}
+
+func TestUnmarshalRoundTrip(t *testing.T) {
+	var op Operation
+	if err := json.Unmarshal([]byte(`[2,"héllo",-3,1]`), &op); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if op.BaseLen != 6 || op.TargetLen != 8 {
+		t.Fatalf("BaseLen, TargetLen = %d, %d, want 6, 8", op.BaseLen, op.TargetLen)
+	}
+
+	got, err := op.Apply("abcdef")
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if got != "abhéllof" {
+		t.Fatalf("Apply = %q, want %q", got, "abhéllof")
+	}
+
+	data, err := json.Marshal(op)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(data) != `[2,"héllo",-3,1]` {
+		t.Fatalf("Marshal = %s", data)
+	}
+}
+
+func TestApplyBaseLength(t *testing.T) {
+	op := (&Operation{}).Retain(3)
+	if _, err := op.Apply("ab"); !errors.Is(err, ErrBaseLength) {
+		t.Fatalf("Apply error = %v, want ErrBaseLength", err)
+	}
+
+	// Komponen yang tidak konsisten dengan BaseLen tidak boleh panic
+	bad := &Operation{Components: []Component{{Retain: 5}}, BaseLen: 2}
+	if _, err := bad.Apply("ab"); !errors.Is(err, ErrBaseLength) {
+		t.Fatalf("Apply error = %v, want ErrBaseLength", err)
+	}
+}
+
+func TestTransformConvergence(t *testing.T) {
+	cases := []struct {
+		name string
+		doc  string
+		a, b *Operation
+		want string
+	}{
+		{
+			name: "insert at same position, a first",
+			doc:  "abc",
+			a:    (&Operation{}).Retain(1).Insert("X").Retain(2),
+			b:    (&Operation{}).Retain(1).Insert("Y").Retain(2),
+			want: "aXYbc",
+		},
+		{
+			name: "overlapping deletes",
+			doc:  "abcdef",
+			a:    (&Operation{}).Retain(1).Delete(3).Retain(2),
+			b:    (&Operation{}).Retain(2).Delete(3).Retain(1),
+			want: "af",
+		},
+		{
+			name: "insert inside deleted range",
+			doc:  "abcdef",
+			a:    (&Operation{}).Retain(1).Delete(4).Retain(1),
+			b:    (&Operation{}).Retain(3).Insert("Z").Retain(3),
+			want: "aZf",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := converge(t, tc.doc, tc.a, tc.b)
+			if got != tc.want {
+				t.Fatalf("converged = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTransformConvergenceRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 500; i++ {
+		doc := randomString(rng, rng.Intn(20))
+		converge(t, doc, randomOperation(rng, doc), randomOperation(rng, doc))
+	}
+}
+
+func TestTransformConcurrentBaseLength(t *testing.T) {
+	a := (&Operation{}).Retain(2)
+	b := (&Operation{}).Retain(3)
+	if _, _, err := Transform(a, b); !errors.Is(err, ErrConcurrent) {
+		t.Fatalf("Transform error = %v, want ErrConcurrent", err)
+	}
+}
+
+// converge cek apply(apply(doc, a), b') == apply(apply(doc, b), a')
+func converge(t *testing.T, doc string, a, b *Operation) string {
+	t.Helper()
+
+	aPrime, bPrime, err := Transform(a, b)
+	if err != nil {
+		t.Fatalf("Transform: %v", err)
+	}
+
+	left := mustApply(t, mustApply(t, doc, a), bPrime)
+	right := mustApply(t, mustApply(t, doc, b), aPrime)
+	if left != right {
+		t.Fatalf("doc %q: a then b' = %q, b then a' = %q", doc, left, right)
+	}
+	return left
+}
+
+func mustApply(t *testing.T, doc string, op *Operation) string {
+	t.Helper()
+
+	out, err := op.Apply(doc)
+	if err != nil {
+		t.Fatalf("Apply(%q): %v", doc, err)
+	}
+	return out
+}
+
+func randomString(rng *rand.Rand, n int) string {
+	const alphabet = "abcdé\n"
+	runes := []rune(alphabet)
+
+	out := make([]rune, n)
+	for i := range out {
+		out[i] = runes[rng.Intn(len(runes))]
+	}
+	return string(out)
+}
+
+func randomOperation(rng *rand.Rand, doc string) *Operation {
+	op := &Operation{}
+	remaining := utf8.RuneCountInString(doc)
+
+	for remaining > 0 {
+		n := 1 + rng.Intn(remaining)
+		switch rng.Intn(3) {
+		case 0:
+			op.Retain(n)
+			remaining -= n
+		case 1:
+			op.Delete(n)
+			remaining -= n
+		default:
+			op.Insert(randomString(rng, 1+rng.Intn(3)))
+		}
+	}
+	if rng.Intn(2) == 0 {
+		op.Insert(randomString(rng, 1+rng.Intn(3)))
+	}
+
+	return op
+}
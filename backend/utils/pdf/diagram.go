@@ -0,0 +1,128 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/png"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/mermaid"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/render"
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	diagramScale = 2.0       // resolusi raster diagram terhadap ukuran natural
+	pxToMM       = 25.4 / 96 // ukuran natural diagram dihitung pada 96 dpi
+	maxUpscale   = 1.5       // diagram kecil tidak diperbesar berlebihan
+)
+
+// diagramImage hasil raster diagram. msg berisi pesan error jika diagram tidak
+// bisa digambar, ditampilkan di PDF menggantikan gambar.
+type diagramImage struct {
+	name          string
+	data          []byte
+	width, height float64 // ukuran natural dalam mm
+	msg           string
+}
+
+// diagram render content mermaid menjadi PNG, hasil per content dipakai ulang
+func (w *writer) diagram(content string) *diagramImage {
+	if img, ok := w.cache.diagrams[content]; ok {
+		return img
+	}
+
+	img := &diagramImage{name: fmt.Sprintf("diagram-%d", len(w.cache.diagrams))}
+	w.cache.diagrams[content] = img
+
+	graph, diags := mermaid.Parse(content)
+	for _, d := range diags {
+		if d.Severity == mermaid.SeverityError {
+			img.msg = fmt.Sprintf("line %d: %s", d.Line, d.Message)
+			return img
+		}
+	}
+
+	data, err := render.PNG(graph, diagramScale)
+	if err != nil {
+		if errors.Is(err, render.ErrUnsupported) {
+			img.msg = fmt.Sprintf("%s diagrams cannot be rendered yet", graph.Type)
+		} else {
+			img.msg = err.Error()
+		}
+		return img
+	}
+
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		img.msg = err.Error()
+		return img
+	}
+
+	img.data = data
+	img.width = float64(cfg.Width) / diagramScale * pxToMM
+	img.height = float64(cfg.Height) / diagramScale * pxToMM
+	return img
+}
+
+// embed diagram dari document lain, hasil resolve per slug dipakai ulang
+func (w *writer) embed(slug string) *diagramImage {
+	res, ok := w.cache.embeds[slug]
+	if !ok && w.opts.Embed != nil && w.err == nil {
+		content, found, err := w.opts.Embed(slug)
+		if err != nil {
+			w.err = err
+		}
+		res = embedResult{content: content, ok: found}
+		w.cache.embeds[slug] = res
+	}
+
+	if !res.ok {
+		return &diagramImage{msg: fmt.Sprintf("embedded diagram %q is not available", slug)}
+	}
+	return w.diagram(res.content)
+}
+
+// place gambar diagram di posisi saat ini, selebar maksimal area teks. fill true
+// untuk chapter diagram: diperbesar sampai memenuhi sisa halaman.
+func (w *writer) place(img *diagramImage, fill bool) {
+	f := w.f
+
+	if img.msg != "" {
+		f.SetFont(fontFamily, "I", w.size)
+		f.SetTextColor(180, 30, 30)
+		f.MultiCell(0, lineHeight, img.msg, "", "L", false)
+		f.SetTextColor(0, 0, 0)
+		f.SetFont(fontFamily, w.style, w.size)
+		f.Ln(2)
+		return
+	}
+
+	if !w.registered[img.name] {
+		f.RegisterImageOptionsReader(img.name, fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(img.data))
+		w.registered[img.name] = true
+	}
+
+	pageW, pageH := f.GetPageSize()
+	left, top, right, bottom := f.GetMargins()
+	maxW := pageW - left - right
+	available := pageH - bottom - f.GetY()
+
+	scale := min(1, maxW/img.width)
+	if fill {
+		scale = min(maxW/img.width, available/img.height, maxUpscale)
+	} else if img.height*scale > available {
+		// Pindah ke halaman baru jika muat di satu halaman penuh, selain itu diperkecil
+		full := pageH - top - bottom
+		if img.height*scale <= full || available < full/2 {
+			f.AddPage()
+			available = full
+		}
+		scale = min(scale, available/img.height)
+	}
+
+	width, height := img.width*scale, img.height*scale
+	x := left + (maxW-width)/2
+	f.ImageOptions(img.name, x, f.GetY(), width, height, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	f.SetY(f.GetY() + height + 3)
+}
@@ -0,0 +1,327 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/markdown"
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+)
+
+const listIndent = 6.0 // mm
+
+var headingSizes = map[int]float64{1: 18, 2: 15, 3: 13, 4: 12, 5: 11, 6: 11}
+
+// markdown tulis content Markdown dari AST, HTML mentah diabaikan seperti output HTML
+func (w *writer) markdown(content string) {
+	w.source = []byte(content)
+	w.blocks(markdown.Parse(w.source))
+}
+
+func (w *writer) blocks(parent ast.Node) {
+	for n := parent.FirstChild(); n != nil; n = n.NextSibling() {
+		w.block(n)
+	}
+}
+
+func (w *writer) block(node ast.Node) {
+	f := w.f
+
+	switch n := node.(type) {
+	case *ast.Heading:
+		size := headingSizes[n.Level]
+		height := size * 0.5
+		f.Ln(2)
+		w.font("B", size)
+		w.inlines(n, height)
+		f.Ln(height + 1)
+		w.font("", fontSize)
+
+	case *ast.Paragraph:
+		w.inlines(n, lineHeight)
+		f.Ln(lineHeight + 2)
+
+	case *ast.TextBlock:
+		w.inlines(n, lineHeight)
+		f.Ln(lineHeight)
+
+	case *ast.List:
+		w.list(n)
+
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		w.code(n)
+
+	case *ast.Blockquote:
+		w.blockquote(n)
+
+	case *ast.ThematicBreak:
+		pageW, _ := f.GetPageSize()
+		left, _, right, _ := f.GetMargins()
+		y := f.GetY() + 2
+		f.SetDrawColor(200, 200, 200)
+		f.Line(left, y, pageW-right, y)
+		f.SetY(y + 4)
+
+	case *ast.HTMLBlock:
+		// HTML mentah tidak dirender
+
+	case *markdown.DiagramBlock:
+		w.place(w.diagram(n.Content), false)
+
+	case *extast.Table:
+		w.table(n)
+
+	default:
+		w.blocks(n)
+	}
+}
+
+func (w *writer) inlines(parent ast.Node, height float64) {
+	for n := parent.FirstChild(); n != nil; n = n.NextSibling() {
+		w.inline(n, height)
+	}
+}
+
+func (w *writer) inline(node ast.Node, height float64) {
+	f := w.f
+
+	switch n := node.(type) {
+	case *ast.Text:
+		f.Write(height, string(n.Segment.Value(w.source)))
+		if n.HardLineBreak() {
+			f.Ln(height)
+		} else if n.SoftLineBreak() {
+			f.Write(height, " ")
+		}
+
+	case *ast.String:
+		f.Write(height, string(n.Value))
+
+	case *ast.Emphasis:
+		style := "I"
+		if n.Level > 1 {
+			style = "B"
+		}
+		w.styled(style, func() { w.inlines(n, height) })
+
+	case *ast.CodeSpan:
+		f.SetFont(monoFamily, "", w.size*0.9)
+		w.inlines(n, height)
+		f.SetFont(fontFamily, w.style, w.size)
+
+	case *ast.Link:
+		w.link(w.text(n), string(n.Destination), height)
+
+	case *ast.AutoLink:
+		url := string(n.URL(w.source))
+		if n.AutoLinkType == ast.AutoLinkEmail && !strings.HasPrefix(url, "mailto:") {
+			url = "mailto:" + url
+		}
+		w.link(string(n.Label(w.source)), url, height)
+
+	case *ast.Image:
+		// Gambar eksternal tidak diunduh server, cukup alt text
+		w.styled("I", func() { f.Write(height, fmt.Sprintf("[image: %s]", w.text(n))) })
+
+	case *ast.RawHTML:
+		// HTML mentah tidak dirender
+
+	case *extast.TaskCheckBox:
+		if n.IsChecked {
+			f.Write(height, "[x] ")
+		} else {
+			f.Write(height, "[ ] ")
+		}
+
+	case *markdown.DiagramEmbed:
+		f.Ln(height)
+		w.place(w.embed(n.Slug), false)
+
+	default:
+		w.inlines(n, height)
+	}
+}
+
+// styled tulis inline dengan tambahan style bold/italic di atas style saat ini
+func (w *writer) styled(style string, fn func()) {
+	previous := w.style
+	combined := previous
+	if !strings.Contains(combined, style) {
+		combined = map[string]string{"": style, "B": "BI", "I": "BI"}[combined]
+		if combined == "" {
+			combined = previous
+		}
+	}
+
+	w.style = combined
+	w.f.SetFont(fontFamily, combined, w.size)
+	fn()
+	w.style = previous
+	w.f.SetFont(fontFamily, previous, w.size)
+}
+
+// link hanya untuk URL http, https dan mailto, selain itu ditulis sebagai text biasa
+func (w *writer) link(label, url string, height float64) {
+	f := w.f
+
+	lower := strings.ToLower(url)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") && !strings.HasPrefix(lower, "mailto:") {
+		f.Write(height, label)
+		return
+	}
+
+	f.SetTextColor(26, 95, 180)
+	f.WriteLinkString(height, label, url)
+	f.SetTextColor(0, 0, 0)
+}
+
+func (w *writer) list(n *ast.List) {
+	f := w.f
+	left, _, _, _ := f.GetMargins()
+
+	number := n.Start
+	for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+		marker := "•"
+		if n.IsOrdered() {
+			marker = fmt.Sprintf("%d.", number)
+			number++
+		}
+
+		f.SetX(left)
+		f.CellFormat(listIndent, lineHeight, marker, "", 0, "L", false, 0, "")
+		f.SetLeftMargin(left + listIndent)
+		w.blocks(item)
+		f.SetLeftMargin(left)
+		f.SetX(left)
+	}
+	f.Ln(1)
+}
+
+func (w *writer) code(n ast.Node) {
+	f := w.f
+
+	var b strings.Builder
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		b.Write(segment.Value(w.source))
+	}
+	text := strings.ReplaceAll(strings.TrimRight(b.String(), "\n"), "\t", "    ")
+
+	f.SetFont(monoFamily, "", 9)
+	f.SetFillColor(245, 245, 245)
+	f.MultiCell(0, 4.5, text, "", "L", true)
+	f.SetFont(fontFamily, w.style, w.size)
+	f.Ln(2)
+}
+
+func (w *writer) blockquote(n *ast.Blockquote) {
+	f := w.f
+	left, _, _, _ := f.GetMargins()
+	page, y := f.PageNo(), f.GetY()
+
+	f.SetLeftMargin(left + listIndent)
+	f.SetX(left + listIndent)
+	f.SetTextColor(100, 100, 100)
+	w.blocks(n)
+	f.SetTextColor(0, 0, 0)
+	f.SetLeftMargin(left)
+	f.SetX(left)
+
+	// Garis kiri hanya digambar jika quote tidak terpotong halaman
+	if f.PageNo() == page {
+		f.SetDrawColor(200, 200, 200)
+		f.SetLineWidth(0.8)
+		f.Line(left+2, y, left+2, f.GetY()-2)
+		f.SetLineWidth(0.2)
+	}
+}
+
+// table kolom dibagi rata, text setiap cell di-wrap dan tinggi baris mengikuti cell tertinggi
+func (w *writer) table(n *extast.Table) {
+	f := w.f
+	pageW, pageH := f.GetPageSize()
+	left, _, right, bottom := f.GetMargins()
+
+	var rows [][]string
+	columns := 0
+	for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+		var cells []string
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			cells = append(cells, w.text(cell))
+		}
+		rows = append(rows, cells)
+		columns = max(columns, len(cells))
+	}
+	if columns == 0 {
+		return
+	}
+
+	const cellHeight = 5.0
+	colW := (pageW - left - right) / float64(columns)
+	f.SetDrawColor(200, 200, 200)
+	f.SetFillColor(240, 240, 240)
+
+	for i, cells := range rows {
+		// baris pertama adalah header
+		style := ""
+		if i == 0 {
+			style = "B"
+		}
+		f.SetFont(fontFamily, style, fontSize-1)
+
+		lines := make([][]string, columns)
+		count := 1
+		for c := range columns {
+			if c < len(cells) && cells[c] != "" {
+				lines[c] = f.SplitText(cells[c], colW-2)
+			}
+			count = max(count, len(lines[c]))
+		}
+
+		rowH := float64(count)*cellHeight + 2
+		if f.GetY()+rowH > pageH-bottom {
+			f.AddPage()
+		}
+
+		y := f.GetY()
+		for c := range columns {
+			x := left + float64(c)*colW
+			border := "D"
+			if i == 0 {
+				border = "FD"
+			}
+			f.Rect(x, y, colW, rowH, border)
+			for l, line := range lines[c] {
+				f.SetXY(x+1, y+1+float64(l)*cellHeight)
+				f.CellFormat(colW-2, cellHeight, line, "", 0, "L", false, 0, "")
+			}
+		}
+		f.SetY(y + rowH)
+	}
+
+	f.SetFont(fontFamily, w.style, w.size)
+	f.Ln(3)
+}
+
+// text gabungan text inline di bawah node, untuk label link dan isi cell tabel
+func (w *writer) text(n ast.Node) string {
+	var b strings.Builder
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch c := child.(type) {
+		case *ast.Text:
+			b.Write(c.Segment.Value(w.source))
+			if c.SoftLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(c.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return b.String()
+}
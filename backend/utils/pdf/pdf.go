@@ -0,0 +1,226 @@
+// Package pdf menulis document Markdown dan diagram Mermaid menjadi PDF tanpa
+// browser. Diagram dirasterisasi dengan package render, Markdown ditata langsung
+// dari AST goldmark sehingga hasilnya sederhana tetapi tidak butuh JavaScript.
+package pdf
+
+import (
+	"io"
+	"strconv"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/markdown"
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const (
+	fontFamily = "go"
+	monoFamily = "gomono"
+
+	pageSize   = "A4"
+	pageMargin = 20.0 // mm
+	fontSize   = 10.5 // pt
+	lineHeight = 5.5  // mm
+)
+
+// Chapter satu document di dalam PDF. Markdown false berarti content diagram Mermaid.
+type Chapter struct {
+	Title    string
+	Markdown bool
+	Content  string
+}
+
+// Options pengaturan PDF
+type Options struct {
+	Title string
+	// TOC tambahkan halaman judul dan daftar isi di awal, dipakai untuk export workspace
+	TOC bool
+	// Embed resolver untuk ![[slug]] di Markdown, nil berarti embed tidak tersedia
+	Embed markdown.EmbedResolver
+}
+
+// Write tulis chapters menjadi satu PDF, setiap chapter dimulai di halaman baru
+func Write(w io.Writer, chapters []Chapter, opts Options) error {
+	c := &cache{
+		diagrams: map[string]*diagramImage{},
+		embeds:   map[string]embedResult{},
+	}
+
+	doc, err := build(chapters, opts, c, nil)
+	if err != nil {
+		return err
+	}
+
+	// Halaman awal setiap chapter baru diketahui setelah layout, daftar isi ditulis
+	// ulang dengan nomor dari pass pertama. Setiap entry satu baris sehingga jumlah
+	// halaman daftar isi sama di kedua pass.
+	if opts.TOC {
+		doc, err = build(chapters, opts, c, doc.starts)
+		if err != nil {
+			return err
+		}
+	}
+
+	return doc.f.Output(w)
+}
+
+// cache hasil render diagram dan resolve embed, dipakai ulang antar pass
+type cache struct {
+	diagrams map[string]*diagramImage
+	embeds   map[string]embedResult
+}
+
+type embedResult struct {
+	content string
+	ok      bool
+}
+
+// writer state satu pass layout
+type writer struct {
+	f          *fpdf.Fpdf
+	opts       Options
+	cache      *cache
+	registered map[string]bool // image yang sudah didaftarkan ke f
+	source     []byte          // source Markdown chapter yang sedang ditulis
+	style      string
+	size       float64
+	starts     []int // halaman awal setiap chapter
+	links      []int // link internal daftar isi ke setiap chapter
+	err        error
+}
+
+func build(chapters []Chapter, opts Options, c *cache, pages []int) (*writer, error) {
+	f := fpdf.New("P", "mm", pageSize, "")
+	f.SetMargins(pageMargin, pageMargin, pageMargin)
+	f.SetAutoPageBreak(true, pageMargin)
+	f.SetTitle(opts.Title, true)
+
+	f.AddUTF8FontFromBytes(fontFamily, "", goregular.TTF)
+	f.AddUTF8FontFromBytes(fontFamily, "B", gobold.TTF)
+	f.AddUTF8FontFromBytes(fontFamily, "I", goitalic.TTF)
+	f.AddUTF8FontFromBytes(fontFamily, "BI", gobolditalic.TTF)
+	f.AddUTF8FontFromBytes(monoFamily, "", gomono.TTF)
+
+	f.SetFooterFunc(func() {
+		f.SetY(-pageMargin + 5)
+		f.SetFont(fontFamily, "", 8)
+		f.SetTextColor(128, 128, 128)
+		f.CellFormat(0, 5, strconv.Itoa(f.PageNo()), "", 0, "C", false, 0, "")
+		f.SetTextColor(0, 0, 0)
+	})
+
+	w := &writer{
+		f:          f,
+		opts:       opts,
+		cache:      c,
+		registered: map[string]bool{},
+	}
+
+	if opts.TOC {
+		w.toc(chapters, pages)
+	}
+	for i, chapter := range chapters {
+		w.chapter(i, chapter)
+	}
+
+	if w.err != nil {
+		return nil, w.err
+	}
+	if f.Err() {
+		return nil, f.Error()
+	}
+
+	return w, nil
+}
+
+// toc halaman judul dan daftar isi, pages nil di pass pertama
+func (w *writer) toc(chapters []Chapter, pages []int) {
+	f := w.f
+	f.AddPage()
+
+	w.font("B", 22)
+	f.MultiCell(0, 10, w.opts.Title, "", "L", false)
+	f.Ln(4)
+
+	w.font("B", 14)
+	f.CellFormat(0, 8, "Table of contents", "", 1, "L", false, 0, "")
+	f.Ln(2)
+
+	w.font("", fontSize)
+	pageW, _ := f.GetPageSize()
+	numberW := 15.0
+	titleW := pageW - 2*pageMargin - numberW
+
+	w.links = make([]int, len(chapters))
+	for i, chapter := range chapters {
+		w.links[i] = f.AddLink()
+
+		number := ""
+		if pages != nil {
+			number = strconv.Itoa(pages[i])
+		}
+		f.CellFormat(titleW, 7, truncate(f, chapter.Title, titleW), "", 0, "L", false, w.links[i], "")
+		f.CellFormat(numberW, 7, number, "", 1, "R", false, w.links[i], "")
+	}
+}
+
+// chapter tulis satu document. Diagram Mermaid mendapat halaman sendiri, landscape
+// jika diagram lebar.
+func (w *writer) chapter(i int, chapter Chapter) {
+	f := w.f
+
+	if chapter.Markdown {
+		f.AddPageFormat("P", f.GetPageSizeStr(pageSize))
+		w.start(i, chapter)
+		w.markdown(chapter.Content)
+		return
+	}
+
+	img := w.diagram(chapter.Content)
+	orientation := "P"
+	if img.msg == "" && img.width > img.height*1.2 {
+		orientation = "L"
+	}
+
+	f.AddPageFormat(orientation, f.GetPageSizeStr(pageSize))
+	w.start(i, chapter)
+	w.place(img, true)
+}
+
+// start catat halaman awal chapter, tujuan link daftar isi dan bookmark PDF
+func (w *writer) start(i int, chapter Chapter) {
+	f := w.f
+
+	w.starts = append(w.starts, f.PageNo())
+	if w.links != nil {
+		f.SetLink(w.links[i], 0, -1)
+	}
+	f.Bookmark(chapter.Title, 0, -1)
+
+	w.font("B", 18)
+	f.MultiCell(0, 9, chapter.Title, "", "L", false)
+	f.Ln(3)
+	w.font("", fontSize)
+}
+
+// font ganti font utama dan simpan state untuk dikembalikan setelah inline style
+func (w *writer) font(style string, size float64) {
+	w.style, w.size = style, size
+	w.f.SetFont(fontFamily, style, size)
+}
+
+// truncate potong text dengan elipsis agar muat dalam satu baris
+func truncate(f *fpdf.Fpdf, text string, width float64) string {
+	if f.GetStringWidth(text) <= width {
+		return text
+	}
+
+	runes := []rune(text)
+	for len(runes) > 0 && f.GetStringWidth(string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
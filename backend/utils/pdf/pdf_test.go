@@ -0,0 +1,71 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"testing"
+)
+
+// regexpPage object halaman di output fpdf, bukan /Type /Pages
+var regexpPage = regexp.MustCompile(`/Type /Page\b[^s]`)
+
+const markdownChapter = "# Notes\n\n" +
+	"Some **bold**, _italic_ and `code` with a [link](https://example.com).\n\n" +
+	"- one\n- two\n  1. nested\n\n" +
+	"> quote\n\n" +
+	"| a | b |\n|---|---|\n| 1 | 2 |\n\n" +
+	"```go\nfmt.Println(\"hi\")\n```\n\n" +
+	"```mermaid\ngraph TD\nA --> B\n```\n\n" +
+	"![[flow]] ![[flow]] ![[missing]]\n"
+
+func TestWrite(t *testing.T) {
+	calls := 0
+	opts := Options{
+		Title: "Workspace",
+		TOC:   true,
+		Embed: func(slug string) (string, bool, error) {
+			calls++
+			if slug == "flow" {
+				return "graph LR\nA --> B --> C", true, nil
+			}
+			return "", false, nil
+		},
+	}
+	chapters := []Chapter{
+		{Title: "Flow", Content: "graph LR\nA --> B"},
+		{Title: "Notes", Markdown: true, Content: markdownChapter},
+		{Title: "Broken", Content: "graph TD\nA -> B"},
+		{Title: "Pie", Content: "pie\n\"a\": 1"},
+	}
+
+	var out bytes.Buffer
+	if err := Write(&out, chapters, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.HasPrefix(out.Bytes(), []byte("%PDF-")) {
+		t.Fatalf("output is not a PDF: %q", out.Bytes()[:min(out.Len(), 16)])
+	}
+	// Daftar isi dan setiap chapter di halaman sendiri
+	if pages := len(regexpPage.FindAll(out.Bytes(), -1)); pages < len(chapters)+1 {
+		t.Fatalf("pages = %d, want at least %d", pages, len(chapters)+1)
+	}
+	// Resolve embed per slug sekali untuk kedua pass layout
+	if calls != 2 {
+		t.Fatalf("embed calls = %d, want 2", calls)
+	}
+}
+
+func TestWriteEmbedError(t *testing.T) {
+	failed := errors.New("database down")
+	opts := Options{
+		Embed: func(string) (string, bool, error) { return "", false, failed },
+	}
+
+	var out bytes.Buffer
+	err := Write(&out, []Chapter{{Title: "Notes", Markdown: true, Content: "![[flow]]"}}, opts)
+	if !errors.Is(err, failed) {
+		t.Fatalf("Write error = %v, want %v", err, failed)
+	}
+}
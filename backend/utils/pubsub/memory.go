@@ -0,0 +1,139 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// memoryBus bus in-process untuk deployment single node
+type memoryBus struct {
+	mu       sync.Mutex
+	channels map[string]map[*memorySubscription]struct{}
+}
+
+func NewMemoryBus() Bus {
+	return &memoryBus{
+		channels: make(map[string]map[*memorySubscription]struct{}),
+	}
+}
+
+// Publish tidak pernah block, pesan diantrikan ke setiap subscriber
+func (_i *memoryBus) Publish(_ context.Context, channel string, payload []byte) error {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	for sub := range _i.channels[channel] {
+		sub.push(payload)
+	}
+
+	return nil
+}
+
+func (_i *memoryBus) Subscribe(_ context.Context, channel string, handler Handler) (Subscription, error) {
+	sub := &memorySubscription{
+		bus:     _i,
+		channel: channel,
+		handler: handler,
+		signal:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+
+	_i.mu.Lock()
+	if _i.channels[channel] == nil {
+		_i.channels[channel] = make(map[*memorySubscription]struct{})
+	}
+	_i.channels[channel][sub] = struct{}{}
+	_i.mu.Unlock()
+
+	go sub.run()
+
+	return sub, nil
+}
+
+func (_i *memoryBus) Shared() bool {
+	return false
+}
+
+func (_i *memoryBus) Close() error {
+	_i.mu.Lock()
+	channels := _i.channels
+	_i.channels = make(map[string]map[*memorySubscription]struct{})
+	_i.mu.Unlock()
+
+	for _, subs := range channels {
+		for sub := range subs {
+			sub.stop()
+		}
+	}
+
+	return nil
+}
+
+// memorySubscription antrian tanpa batas per subscriber agar handler yang
+// lambat tidak menahan publisher
+type memorySubscription struct {
+	bus     *memoryBus
+	channel string
+	handler Handler
+
+	mu      sync.Mutex
+	queue   [][]byte
+	signal  chan struct{}
+	done    chan struct{}
+	stopped sync.Once
+}
+
+func (_i *memorySubscription) push(payload []byte) {
+	_i.mu.Lock()
+	_i.queue = append(_i.queue, payload)
+	_i.mu.Unlock()
+
+	select {
+	case _i.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (_i *memorySubscription) run() {
+	for {
+		select {
+		case <-_i.done:
+			return
+		case <-_i.signal:
+		}
+
+		_i.mu.Lock()
+		queue := _i.queue
+		_i.queue = nil
+		_i.mu.Unlock()
+
+		for _, payload := range queue {
+			select {
+			case <-_i.done:
+				return
+			default:
+			}
+			_i.handler(payload)
+		}
+	}
+}
+
+func (_i *memorySubscription) Close() error {
+	_i.bus.mu.Lock()
+	if subs, ok := _i.bus.channels[_i.channel]; ok {
+		delete(subs, _i)
+		if len(subs) == 0 {
+			delete(_i.bus.channels, _i.channel)
+		}
+	}
+	_i.bus.mu.Unlock()
+
+	_i.stop()
+	return nil
+}
+
+func (_i *memorySubscription) stop() {
+	_i.stopped.Do(func() {
+		close(_i.done)
+	})
+}
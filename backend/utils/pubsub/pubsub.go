@@ -0,0 +1,64 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
+	"github.com/rs/zerolog/log"
+)
+
+// Handler dipanggil berurutan sesuai urutan publish di channel. Payload nil
+// berarti koneksi ke bus sempat terputus dan sebagian pesan mungkin hilang.
+type Handler func(payload []byte)
+
+// Bus fan-out pesan antar instance. Semua subscriber sebuah channel menerima
+// pesan dengan urutan yang sama.
+type Bus interface {
+	Publish(ctx context.Context, channel string, payload []byte) error
+	Subscribe(ctx context.Context, channel string, handler Handler) (Subscription, error)
+	// Shared true jika pesan juga sampai ke instance lain
+	Shared() bool
+	Close() error
+}
+
+// Subscription berhenti menerima pesan setelah Close
+type Subscription interface {
+	Close() error
+}
+
+// Registry kumpulan member berumur pendek per key yang bisa dibaca semua
+// instance, contoh daftar editor yang sedang membuka document. Member yang
+// tidak diperbarui sampai ttl habis dianggap hilang.
+type Registry interface {
+	Put(ctx context.Context, key string, member string, value []byte, ttl time.Duration) error
+	Remove(ctx context.Context, key string, member string) error
+	Members(ctx context.Context, key string) (map[string][]byte, error)
+}
+
+// NewBus memakai Redis yang sama dengan session store, in-process untuk single node
+func NewBus(cfg *config.Config) Bus {
+	session := cfg.Middleware.Session
+
+	if session.Driver == "redis" && session.RedisHost != "" {
+		addr := fmt.Sprintf("%s:%d", session.RedisHost, session.RedisPort)
+		log.Info().Msgf("Pub/sub bus: Redis at %s", addr)
+		return NewRedisBus(addr, session.RedisPasswd, session.RedisDB)
+	}
+
+	log.Info().Msg("Pub/sub bus: In-process (single node only)")
+	return NewMemoryBus()
+}
+
+// NewRegistry memakai Redis yang sama dengan bus, in-process untuk single node
+func NewRegistry(cfg *config.Config) Registry {
+	session := cfg.Middleware.Session
+
+	if session.Driver == "redis" && session.RedisHost != "" {
+		addr := fmt.Sprintf("%s:%d", session.RedisHost, session.RedisPort)
+		return NewRedisRegistry(addr, session.RedisPasswd, session.RedisDB)
+	}
+
+	return NewMemoryRegistry()
+}
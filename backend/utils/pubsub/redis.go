@@ -0,0 +1,61 @@
+package pubsub
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisBus bus lintas instance memakai Redis pub/sub. Redis mengirim pesan
+// satu channel ke semua subscriber dengan urutan yang sama.
+type redisBus struct {
+	client *redis.Client
+}
+
+func NewRedisBus(addr string, password string, db int) Bus {
+	return &redisBus{
+		client: redis.NewClient(&redis.Options{
+			Addr:     addr,
+			Password: password,
+			DB:       db,
+		}),
+	}
+}
+
+func (_i *redisBus) Publish(ctx context.Context, channel string, payload []byte) error {
+	return _i.client.Publish(ctx, channel, payload).Err()
+}
+
+// Subscribe menunggu konfirmasi subscribe dari Redis sehingga pesan yang
+// di-publish setelah Subscribe selesai pasti diterima
+func (_i *redisBus) Subscribe(ctx context.Context, channel string, handler Handler) (Subscription, error) {
+	ps := _i.client.Subscribe(ctx, channel)
+	if _, err := ps.Receive(ctx); err != nil {
+		_ = ps.Close()
+		return nil, err
+	}
+
+	go func() {
+		for msg := range ps.ChannelWithSubscriptions() {
+			switch m := msg.(type) {
+			case *redis.Message:
+				handler([]byte(m.Payload))
+			case *redis.Subscription:
+				// Subscribe ulang setelah reconnect, pesan selama terputus hilang
+				if m.Kind == "subscribe" {
+					handler(nil)
+				}
+			}
+		}
+	}()
+
+	return ps, nil
+}
+
+func (_i *redisBus) Shared() bool {
+	return true
+}
+
+func (_i *redisBus) Close() error {
+	return _i.client.Close()
+}
@@ -0,0 +1,140 @@
+package pubsub
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// memoryRegistry registry in-process untuk deployment single node
+type memoryRegistry struct {
+	mu   sync.Mutex
+	keys map[string]map[string]registryEntry
+}
+
+type registryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func NewMemoryRegistry() Registry {
+	return &memoryRegistry{
+		keys: make(map[string]map[string]registryEntry),
+	}
+}
+
+func (_i *memoryRegistry) Put(_ context.Context, key string, member string, value []byte, ttl time.Duration) error {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	if _i.keys[key] == nil {
+		_i.keys[key] = make(map[string]registryEntry)
+	}
+	_i.keys[key][member] = registryEntry{value: value, expiresAt: time.Now().Add(ttl)}
+
+	return nil
+}
+
+func (_i *memoryRegistry) Remove(_ context.Context, key string, member string) error {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	delete(_i.keys[key], member)
+	if len(_i.keys[key]) == 0 {
+		delete(_i.keys, key)
+	}
+
+	return nil
+}
+
+func (_i *memoryRegistry) Members(_ context.Context, key string) (map[string][]byte, error) {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	now := time.Now()
+	members := make(map[string][]byte, len(_i.keys[key]))
+	for member, entry := range _i.keys[key] {
+		if now.After(entry.expiresAt) {
+			delete(_i.keys[key], member)
+			continue
+		}
+		members[member] = entry.value
+	}
+	if len(_i.keys[key]) == 0 {
+		delete(_i.keys, key)
+	}
+
+	return members, nil
+}
+
+// redisRegistry satu hash per key. Redis tidak mendukung TTL per field,
+// sehingga waktu kedaluwarsa disimpan di depan value dan field yang sudah
+// lewat dihapus saat dibaca.
+type redisRegistry struct {
+	client *redis.Client
+}
+
+func NewRedisRegistry(addr string, password string, db int) Registry {
+	return &redisRegistry{
+		client: redis.NewClient(&redis.Options{
+			Addr:     addr,
+			Password: password,
+			DB:       db,
+		}),
+	}
+}
+
+func (_i *redisRegistry) Put(ctx context.Context, key string, member string, value []byte, ttl time.Duration) error {
+	expiresAt := strconv.FormatInt(time.Now().Add(ttl).UnixMilli(), 10)
+	encoded := append([]byte(expiresAt+"|"), value...)
+
+	_, err := _i.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, member, encoded)
+		// Hash ikut hilang jika tidak ada member yang diperbarui lagi
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+
+	return err
+}
+
+func (_i *redisRegistry) Remove(ctx context.Context, key string, member string) error {
+	return _i.client.HDel(ctx, key, member).Err()
+}
+
+func (_i *redisRegistry) Members(ctx context.Context, key string) (map[string][]byte, error) {
+	raw, err := _i.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	members := make(map[string][]byte, len(raw))
+	var expired []string
+
+	for member, encoded := range raw {
+		sep := bytes.IndexByte([]byte(encoded), '|')
+		if sep < 0 {
+			expired = append(expired, member)
+			continue
+		}
+
+		expiresAt, err := strconv.ParseInt(encoded[:sep], 10, 64)
+		if err != nil || expiresAt < now {
+			expired = append(expired, member)
+			continue
+		}
+
+		members[member] = []byte(encoded[sep+1:])
+	}
+
+	if len(expired) > 0 {
+		_ = _i.client.HDel(ctx, key, expired...).Err()
+	}
+
+	return members, nil
+}
@@ -0,0 +1,217 @@
+package render
+
+import (
+	"math"
+	"strings"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/mermaid"
+)
+
+// penanda di ujung edge
+const (
+	markerNone     = ""
+	markerArrow    = "arrow"
+	markerTriangle = "triangle" // inheritance/realization, segitiga kosong
+	markerDiamond  = "diamond"  // composition
+	markerODiamond = "odiamond" // aggregation
+	markerCross    = "cross"
+	markerCircle   = "circle"
+)
+
+// kardinalitas ER dalam bentuk simbol atau kata menjadi notasi singkat
+var erCardinality = map[string]string{
+	"|o": "0..1", "o|": "0..1", "||": "1", "}o": "0..*", "o{": "0..*", "}|": "1..*", "|{": "1..*",
+	"only one": "1", "1": "1", "zero or one": "0..1", "one or zero": "0..1",
+	"one or more": "1..*", "one or many": "1..*", "many(1)": "1..*", "1+": "1..*",
+	"zero or more": "0..*", "zero or many": "0..*", "many(0)": "0..*", "0+": "0..*",
+}
+
+func drawNode(s *scene, n *layoutNode) {
+	if n.dummy {
+		return
+	}
+
+	x, y, w, h := n.x-n.w/2, n.y-n.h/2, n.w, n.h
+	switch n.shape {
+	case "start":
+		s.ellipse(n.x, n.y, w/2, h/2, colorLine, colorLine)
+		return
+	case "end":
+		s.ellipse(n.x, n.y, w/2, h/2, colorBackground, colorLine)
+		s.ellipse(n.x, n.y, w/2-4, h/2-4, colorLine, colorLine)
+		return
+	case "round":
+		s.rect(x, y, w, h, 8, colorNodeFill, colorNodeStroke)
+	case "stadium":
+		s.rect(x, y, w, h, h/2, colorNodeFill, colorNodeStroke)
+	case "circle":
+		s.ellipse(n.x, n.y, w/2, h/2, colorNodeFill, colorNodeStroke)
+	case "double-circle":
+		s.ellipse(n.x, n.y, w/2, h/2, colorNodeFill, colorNodeStroke)
+		s.ellipse(n.x, n.y, w/2-4, h/2-4, colorNodeFill, colorNodeStroke)
+	case "rhombus":
+		s.polygon([]point{{n.x, y}, {x + w, n.y}, {n.x, y + h}, {x, n.y}}, colorNodeFill, colorNodeStroke)
+	case "hexagon":
+		s.polygon([]point{{x + 15, y}, {x + w - 15, y}, {x + w, n.y}, {x + w - 15, y + h}, {x + 15, y + h}, {x, n.y}}, colorNodeFill, colorNodeStroke)
+	case "parallelogram":
+		s.polygon([]point{{x + 15, y}, {x + w, y}, {x + w - 15, y + h}, {x, y + h}}, colorNodeFill, colorNodeStroke)
+	case "parallelogram-alt":
+		s.polygon([]point{{x, y}, {x + w - 15, y}, {x + w, y + h}, {x + 15, y + h}}, colorNodeFill, colorNodeStroke)
+	case "trapezoid":
+		s.polygon([]point{{x + 15, y}, {x + w - 15, y}, {x + w, y + h}, {x, y + h}}, colorNodeFill, colorNodeStroke)
+	case "trapezoid-alt":
+		s.polygon([]point{{x, y}, {x + w, y}, {x + w - 15, y + h}, {x + 15, y + h}}, colorNodeFill, colorNodeStroke)
+	case "asymmetric":
+		s.polygon([]point{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}, {x + 12, n.y}}, colorNodeFill, colorNodeStroke)
+	case "cylinder":
+		s.rect(x, y, w, h, 0, colorNodeFill, colorNodeStroke)
+		s.ellipse(n.x, y, w/2, 5, colorNodeFill, colorNodeStroke)
+	case "subroutine":
+		s.rect(x, y, w, h, 0, colorNodeFill, colorNodeStroke)
+		s.polyline([]point{{x + 6, y}, {x + 6, y + h}}, colorNodeStroke, strokeWidth, false)
+		s.polyline([]point{{x + w - 6, y}, {x + w - 6, y + h}}, colorNodeStroke, strokeWidth, false)
+	default:
+		s.rect(x, y, w, h, 0, colorNodeFill, colorNodeStroke)
+	}
+
+	s.label(n.x, n.y, n.lines, colorText)
+}
+
+func drawEdge(s *scene, t mermaid.DiagramType, e *mermaid.Edge, pts []point) {
+	if len(pts) < 2 {
+		return
+	}
+
+	var head, tail string
+	width := strokeWidth
+	dashed := false
+
+	switch t {
+	case mermaid.DiagramFlowchart:
+		dashed = strings.Contains(e.Arrow, ".")
+		if strings.Contains(e.Arrow, "=") {
+			width = 3.5
+		}
+		head = flowchartMarker(e.Arrow[len(e.Arrow)-1])
+		if e.Arrow[0] == '<' || (len(e.Arrow) > 1 && e.Arrow[0] == e.Arrow[len(e.Arrow)-1]) {
+			tail = flowchartMarker(e.Arrow[len(e.Arrow)-1])
+		}
+
+	case mermaid.DiagramClass:
+		left, line, right := splitRelation(e.Arrow)
+		dashed = line == ".."
+		tail, head = classMarker(left), classMarker(right)
+
+	case mermaid.DiagramState:
+		head = markerArrow
+
+	case mermaid.DiagramER:
+		left, right, optional := splitERRelation(e.Arrow)
+		dashed = optional
+		s.polyline(pts, colorLine, width, dashed)
+		cardinality(s, pts[0], pts[1], erCardinality[left])
+		cardinality(s, pts[len(pts)-1], pts[len(pts)-2], erCardinality[right])
+		return
+	}
+
+	s.polyline(pts, colorLine, width, dashed)
+	s.marker(head, pts[len(pts)-1], pts[len(pts)-1].sub(pts[len(pts)-2]).unit())
+	s.marker(tail, pts[0], pts[0].sub(pts[1]).unit())
+}
+
+func flowchartMarker(c byte) string {
+	switch c {
+	case '>', '<':
+		return markerArrow
+	case 'x':
+		return markerCross
+	case 'o':
+		return markerCircle
+	}
+	return markerNone
+}
+
+func classMarker(end string) string {
+	switch end {
+	case "<|", "|>":
+		return markerTriangle
+	case "*":
+		return markerDiamond
+	case "o":
+		return markerODiamond
+	case "<", ">":
+		return markerArrow
+	case "()":
+		return markerCircle
+	}
+	return markerNone
+}
+
+// splitRelation pecah arrow class seperti "<|--" menjadi ujung kiri, garis dan ujung kanan
+func splitRelation(arrow string) (string, string, string) {
+	for _, line := range []string{"--", ".."} {
+		if i := strings.Index(arrow, line); i >= 0 {
+			return arrow[:i], line, arrow[i+len(line):]
+		}
+	}
+	return "", arrow, ""
+}
+
+// splitERRelation kardinalitas kiri dan kanan dari relasi simbol (||--o{) atau kata
+// (only one to zero or more), optional true untuk garis putus-putus
+func splitERRelation(arrow string) (string, string, bool) {
+	for _, sep := range []string{" optionally to ", " to "} {
+		if left, right, ok := strings.Cut(arrow, sep); ok {
+			return left, right, sep == " optionally to "
+		}
+	}
+	if len(arrow) == 6 {
+		return arrow[:2], arrow[4:], arrow[2:4] == ".."
+	}
+	return "", "", false
+}
+
+// cardinality notasi kardinalitas ER di dekat ujung edge
+func cardinality(s *scene, end, next point, text string) {
+	if text == "" {
+		return
+	}
+	dir := next.sub(end).unit()
+	at := end.add(dir.scale(16)).add(dir.perp().scale(10))
+	s.label(at.X, at.Y, []string{text}, colorText)
+}
+
+// marker gambar penanda di ujung edge, dir arah garis menuju tip
+func (s *scene) marker(kind string, tip, dir point) {
+	n := dir.perp()
+	back := func(d, side float64) point {
+		return tip.sub(dir.scale(d)).add(n.scale(side))
+	}
+
+	switch kind {
+	case markerArrow:
+		s.polygon([]point{tip, back(10, 5), back(10, -5)}, colorLine, colorLine)
+	case markerTriangle:
+		s.polygon([]point{tip, back(14, 7), back(14, -7)}, colorBackground, colorLine)
+	case markerDiamond:
+		s.polygon([]point{tip, back(8, 5), back(16, 0), back(8, -5)}, colorLine, colorLine)
+	case markerODiamond:
+		s.polygon([]point{tip, back(8, 5), back(16, 0), back(8, -5)}, colorBackground, colorLine)
+	case markerCross:
+		s.polyline([]point{back(10, 4), back(2, -4)}, colorLine, 2, false)
+		s.polyline([]point{back(10, -4), back(2, 4)}, colorLine, 2, false)
+	case markerCircle:
+		c := back(5, 0)
+		s.ellipse(c.X, c.Y, 5, 5, colorBackground, colorLine)
+	}
+}
+
+// arc titik-titik busur lingkaran, dipakai untuk rounded rect dan ellipse di PNG
+func arc(c point, rx, ry, from, to float64, steps int) []point {
+	pts := make([]point, 0, steps+1)
+	for i := 0; i <= steps; i++ {
+		a := from + (to-from)*float64(i)/float64(steps)
+		pts = append(pts, point{c.X + rx*math.Cos(a), c.Y + ry*math.Sin(a)})
+	}
+	return pts
+}
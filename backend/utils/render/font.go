@@ -0,0 +1,69 @@
+package render
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+var (
+	regexpLineBreak = regexp.MustCompile(`(?i)<br\s*/?>`)
+	regexpHTMLTag   = regexp.MustCompile(`<[^>]+>`)
+
+	fontOnce sync.Once
+	fontErr  error
+	fontData *opentype.Font
+	// measureFace face ukuran fontSize untuk mengukur text saat layout
+	measureFace font.Face
+)
+
+// loadFont parsing font Go Regular sekali, dipakai untuk ukuran layout dan PNG
+func loadFont() error {
+	fontOnce.Do(func() {
+		fontData, fontErr = opentype.Parse(goregular.TTF)
+		if fontErr != nil {
+			return
+		}
+		measureFace, fontErr = newFace(1)
+	})
+	return fontErr
+}
+
+func newFace(scale float64) (font.Face, error) {
+	return opentype.NewFace(fontData, &opentype.FaceOptions{
+		Size:    fontSize * scale,
+		DPI:     72,
+		Hinting: font.HintingNone,
+	})
+}
+
+// textWidth lebar text dalam pixel pada fontSize
+func textWidth(s string) float64 {
+	return float64(font.MeasureString(measureFace, s)) / 64
+}
+
+// textSize ukuran blok text multi-baris
+func textSize(lines []string) (float64, float64) {
+	w := 0.0
+	for _, line := range lines {
+		w = max(w, textWidth(line))
+	}
+	return w, float64(len(lines)) * lineHeight
+}
+
+// labelLines pecah label menjadi baris dari <br> atau \n dan buang tag HTML lain
+func labelLines(label string) []string {
+	label = regexpLineBreak.ReplaceAllString(label, "\n")
+	label = regexpHTMLTag.ReplaceAllString(label, "")
+	label = strings.ReplaceAll(label, `\n`, "\n")
+
+	lines := strings.Split(label, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	return lines
+}
@@ -0,0 +1,81 @@
+package render
+
+import (
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/mermaid"
+)
+
+const (
+	ganttRowHeight = 28.0
+	ganttBarHeight = 20.0
+	ganttUnit      = 80.0
+)
+
+// layoutGantt gambar skematik gantt: satu baris per task, posisi bar mengikuti
+// urutan dependency 'after', bukan tanggal sebenarnya
+func layoutGantt(g *mermaid.Graph) *scene {
+	s := &scene{}
+
+	deps := map[string][]string{}
+	for _, e := range g.Edges {
+		deps[e.To] = append(deps[e.To], e.From)
+	}
+
+	// kolom task = panjang rantai dependency terpanjang sebelum task
+	column := map[string]int{}
+	visiting := map[string]bool{}
+	var depth func(id string) int
+	depth = func(id string) int {
+		if c, ok := column[id]; ok {
+			return c
+		}
+		if visiting[id] {
+			return 0
+		}
+		visiting[id] = true
+		c := 0
+		for _, dep := range deps[id] {
+			c = max(c, depth(dep)+1)
+		}
+		visiting[id] = false
+		column[id] = c
+		return c
+	}
+
+	labelW := 0.0
+	columns := 0
+	for _, n := range g.Nodes {
+		labelW = max(labelW, textWidth(n.Label))
+		columns = max(columns, depth(n.ID)+1)
+	}
+	left := margin + labelW + 30
+
+	row := map[string]int{}
+	for i, n := range g.Nodes {
+		row[n.ID] = i
+		y := margin + float64(i)*ganttRowHeight
+
+		if i%2 == 0 {
+			s.rect(margin, y, labelW+30+float64(columns)*ganttUnit, ganttRowHeight, 0, "#F4F4FF", "")
+		}
+		s.label(margin+10+labelW/2, y+ganttRowHeight/2, []string{n.Label}, colorText)
+
+		x := left + float64(column[n.ID])*ganttUnit
+		s.rect(x, y+(ganttRowHeight-ganttBarHeight)/2, ganttUnit-10, ganttBarHeight, 3, colorBarFill, colorBarStroke)
+	}
+
+	for _, e := range g.Edges {
+		from, to := row[e.From], row[e.To]
+		startX := left + float64(column[e.From])*ganttUnit + ganttUnit - 10
+		endX := left + float64(column[e.To])*ganttUnit
+		startY := margin + float64(from)*ganttRowHeight + ganttRowHeight/2
+		endY := margin + float64(to)*ganttRowHeight + ganttRowHeight/2
+
+		pts := []point{{startX, startY}, {startX + 5, startY}, {startX + 5, endY}, {endX, endY}}
+		s.polyline(pts, colorLine, 1, false)
+		s.marker(markerArrow, pts[3], point{1, 0})
+	}
+
+	s.width = left + float64(columns)*ganttUnit + margin
+	s.height = margin*2 + float64(len(g.Nodes))*ganttRowHeight
+	return s
+}
@@ -0,0 +1,584 @@
+package render
+
+import (
+	"math"
+	"slices"
+	"sort"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/mermaid"
+)
+
+const (
+	rankSep     = 50.0
+	nodeSep     = 40.0
+	dummySep    = 20.0
+	parallelSep = 14.0
+	nodePadX    = 15.0
+	nodePadY    = 10.0
+	sweepCount  = 8
+)
+
+// layoutNode node pada layered layout, dummy dipakai untuk edge yang melewati
+// lebih dari satu rank agar edge tidak menabrak node lain
+type layoutNode struct {
+	id    string
+	lines []string
+	shape string
+	dummy bool
+
+	w, h  float64
+	rank  int
+	order int
+	cross float64 // posisi sejajar rank sebelum ditransformasi sesuai direction
+	x, y  float64 // titik tengah final
+
+	up, down []*layoutNode
+}
+
+// layoutEdge rantai node dari source ke target searah edge asli
+type layoutEdge struct {
+	edge  *mermaid.Edge
+	chain []*layoutNode
+	self  bool
+}
+
+type graphLayout struct {
+	vertical bool
+	flip     bool
+	nodes    []*layoutNode
+	index    map[string]*layoutNode
+	edges    []*layoutEdge
+	layers   [][]*layoutNode
+}
+
+// layoutGraph layered layout (Sugiyama sederhana) untuk flowchart, class, state dan ER:
+// pecah cycle, ranking longest path, dummy node, barycenter untuk urutan dalam rank
+func layoutGraph(g *mermaid.Graph) *scene {
+	l := &graphLayout{index: map[string]*layoutNode{}}
+
+	switch g.Direction {
+	case "LR", "RL":
+		l.vertical = false
+	default:
+		l.vertical = true
+	}
+	l.flip = g.Direction == "BT" || g.Direction == "RL"
+
+	l.build(g)
+	l.rank()
+	l.order()
+	l.position(g)
+
+	return l.draw(g)
+}
+
+// build node dan edge. Pada state diagram [*] dipecah menjadi node start dan end.
+func (l *graphLayout) build(g *mermaid.Graph) {
+	add := func(id string, lines []string, shape string) *layoutNode {
+		if n, ok := l.index[id]; ok {
+			return n
+		}
+		n := &layoutNode{id: id, lines: lines, shape: shape}
+		n.w, n.h = nodeSize(lines, shape)
+		l.nodes = append(l.nodes, n)
+		l.index[id] = n
+		return n
+	}
+
+	resolve := func(id string, source bool) *layoutNode {
+		if g.Type == mermaid.DiagramState && id == "[*]" {
+			if source {
+				return add("[*]start", nil, "start")
+			}
+			return add("[*]end", nil, "end")
+		}
+		n := g.Node(id)
+		if n == nil {
+			return add(id, []string{id}, "")
+		}
+		return add(id, labelLines(n.Label), nodeShape(g.Type, n))
+	}
+
+	for _, n := range g.Nodes {
+		if g.Type == mermaid.DiagramState && n.ID == "[*]" {
+			continue
+		}
+		resolve(n.ID, true)
+	}
+
+	for _, e := range g.Edges {
+		from, to := resolve(e.From, true), resolve(e.To, false)
+		l.edges = append(l.edges, &layoutEdge{edge: e, chain: []*layoutNode{from, to}, self: from == to})
+	}
+}
+
+// rank pecah cycle dengan DFS lalu ranking longest path, edge yang melewati
+// beberapa rank diisi dummy node
+func (l *graphLayout) rank() {
+	out := map[*layoutNode][]*layoutEdge{}
+	for _, e := range l.edges {
+		if !e.self {
+			out[e.chain[0]] = append(out[e.chain[0]], e)
+		}
+	}
+
+	// back edge dibalik arahnya hanya untuk ranking
+	reversed := map[*layoutEdge]bool{}
+	state := map[*layoutNode]int{} // 1 sedang dikunjungi, 2 selesai
+	var visit func(n *layoutNode)
+	visit = func(n *layoutNode) {
+		state[n] = 1
+		for _, e := range out[n] {
+			next := e.chain[1]
+			switch state[next] {
+			case 0:
+				visit(next)
+			case 1:
+				reversed[e] = true
+			}
+		}
+		state[n] = 2
+	}
+	for _, n := range l.nodes {
+		if state[n] == 0 {
+			visit(n)
+		}
+	}
+
+	// longest path dengan Kahn
+	succ := map[*layoutNode][]*layoutNode{}
+	indegree := map[*layoutNode]int{}
+	for _, e := range l.edges {
+		if e.self {
+			continue
+		}
+		from, to := e.chain[0], e.chain[1]
+		if reversed[e] {
+			from, to = to, from
+		}
+		succ[from] = append(succ[from], to)
+		indegree[to]++
+	}
+
+	var queue []*layoutNode
+	for _, n := range l.nodes {
+		if indegree[n] == 0 {
+			queue = append(queue, n)
+		}
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, next := range succ[n] {
+			next.rank = max(next.rank, n.rank+1)
+			indegree[next]--
+			if indegree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	maxRank := 0
+	for _, n := range l.nodes {
+		maxRank = max(maxRank, n.rank)
+	}
+	l.layers = make([][]*layoutNode, maxRank+1)
+	for _, n := range l.nodes {
+		l.layers[n.rank] = append(l.layers[n.rank], n)
+	}
+
+	// dummy node untuk edge panjang, chain tetap searah edge asli
+	for _, e := range l.edges {
+		if e.self {
+			continue
+		}
+		top, bottom := e.chain[0], e.chain[1]
+		if reversed[e] {
+			top, bottom = bottom, top
+		}
+
+		chain := []*layoutNode{top}
+		for r := top.rank + 1; r < bottom.rank; r++ {
+			d := &layoutNode{dummy: true, rank: r}
+			l.layers[r] = append(l.layers[r], d)
+			chain = append(chain, d)
+		}
+		chain = append(chain, bottom)
+
+		for i := 1; i < len(chain); i++ {
+			chain[i-1].down = append(chain[i-1].down, chain[i])
+			chain[i].up = append(chain[i].up, chain[i-1])
+		}
+
+		if reversed[e] {
+			slices.Reverse(chain)
+		}
+		e.chain = chain
+	}
+}
+
+// order kurangi edge yang bersilangan dengan heuristik barycenter bolak-balik
+func (l *graphLayout) order() {
+	for _, layer := range l.layers {
+		for i, n := range layer {
+			n.order = i
+		}
+	}
+
+	for sweep := 0; sweep < sweepCount; sweep++ {
+		if sweep%2 == 0 {
+			for r := 1; r < len(l.layers); r++ {
+				sortByBarycenter(l.layers[r], func(n *layoutNode) []*layoutNode { return n.up })
+			}
+		} else {
+			for r := len(l.layers) - 2; r >= 0; r-- {
+				sortByBarycenter(l.layers[r], func(n *layoutNode) []*layoutNode { return n.down })
+			}
+		}
+	}
+}
+
+func sortByBarycenter(layer []*layoutNode, neighbours func(*layoutNode) []*layoutNode) {
+	center := map[*layoutNode]float64{}
+	for _, n := range layer {
+		ns := neighbours(n)
+		if len(ns) == 0 {
+			center[n] = float64(n.order)
+			continue
+		}
+		sum := 0.0
+		for _, m := range ns {
+			sum += float64(m.order)
+		}
+		center[n] = sum / float64(len(ns))
+	}
+
+	sort.SliceStable(layer, func(i, j int) bool { return center[layer[i]] < center[layer[j]] })
+	for i, n := range layer {
+		n.order = i
+	}
+}
+
+// crossSize ukuran node sejajar rank, mainSize ukuran searah rank
+func (l *graphLayout) crossSize(n *layoutNode) float64 {
+	if l.vertical {
+		return n.w
+	}
+	return n.h
+}
+
+func (l *graphLayout) mainSize(n *layoutNode) float64 {
+	if l.vertical {
+		return n.h
+	}
+	return n.w
+}
+
+// gap jarak minimal titik tengah dua node bersebelahan dalam satu rank
+func (l *graphLayout) gap(a, b *layoutNode) float64 {
+	sep := nodeSep
+	if a.dummy || b.dummy {
+		sep = dummySep
+	}
+	return (l.crossSize(a)+l.crossSize(b))/2 + sep
+}
+
+// position hitung koordinat node dari rank dan urutan
+func (l *graphLayout) position(g *mermaid.Graph) {
+	for _, layer := range l.layers {
+		for i, n := range layer {
+			if i > 0 {
+				n.cross = layer[i-1].cross + l.gap(layer[i-1], n)
+			}
+		}
+	}
+
+	// tarik node ke rata-rata posisi tetangga dengan tetap menjaga urutan
+	for sweep := 0; sweep < sweepCount; sweep++ {
+		for r := range l.layers {
+			layer := l.layers[r]
+			if sweep%2 == 1 {
+				layer = l.layers[len(l.layers)-1-r]
+			}
+			l.align(layer, sweep%2 == 0)
+		}
+	}
+
+	// label edge butuh ruang di antara rank, ER juga untuk notasi kardinalitas
+	sep := rankSep
+	if g.Type == mermaid.DiagramER {
+		sep += 2 * lineHeight
+	}
+	for _, e := range l.edges {
+		if e.edge.Label == "" || e.self {
+			continue
+		}
+		w, h := textSize(labelLines(e.edge.Label))
+		if l.vertical {
+			sep = max(sep, h+rankSep)
+		} else {
+			sep = max(sep, w+rankSep)
+		}
+		if g.Type == mermaid.DiagramER {
+			sep = max(sep, h+rankSep+2*lineHeight)
+		}
+	}
+
+	main := 0.0
+	for _, layer := range l.layers {
+		size := 0.0
+		for _, n := range layer {
+			size = max(size, l.mainSize(n))
+		}
+		for _, n := range layer {
+			m := main + size/2
+			if l.flip {
+				m = -m
+			}
+			if l.vertical {
+				n.x, n.y = n.cross, m
+			} else {
+				n.x, n.y = m, n.cross
+			}
+		}
+		main += size + sep
+	}
+}
+
+func (l *graphLayout) align(layer []*layoutNode, useUp bool) {
+	if len(layer) == 0 {
+		return
+	}
+
+	desired := make([]float64, len(layer))
+	for i, n := range layer {
+		ns := n.down
+		if useUp {
+			ns = n.up
+		}
+		if len(ns) == 0 {
+			ns = slices.Concat(n.up, n.down)
+		}
+		if len(ns) == 0 {
+			desired[i] = n.cross
+			continue
+		}
+		sum := 0.0
+		for _, m := range ns {
+			sum += m.cross
+		}
+		desired[i] = sum / float64(len(ns))
+	}
+
+	pos := slices.Clone(desired)
+	for i := 1; i < len(layer); i++ {
+		pos[i] = max(pos[i], pos[i-1]+l.gap(layer[i-1], layer[i]))
+	}
+
+	// geser seluruh rank agar rata-rata perpindahan dari posisi ideal nol
+	shift := 0.0
+	for i := range pos {
+		shift += pos[i] - desired[i]
+	}
+	shift /= float64(len(pos))
+	for i, n := range layer {
+		n.cross = pos[i] - shift
+	}
+}
+
+func (l *graphLayout) draw(g *mermaid.Graph) *scene {
+	s := &scene{}
+
+	type placedLabel struct {
+		at    point
+		lines []string
+	}
+	var labels []placedLabel
+	var paths [][]point
+
+	// edge langsung antar pasangan node yang sama digeser sejajar agar tidak bertumpuk
+	type pair [2]*layoutNode
+	parallel := map[pair][]*layoutEdge{}
+	pairOf := func(e *layoutEdge) pair {
+		a, b := e.chain[0], e.chain[len(e.chain)-1]
+		if a.id > b.id {
+			a, b = b, a
+		}
+		return pair{a, b}
+	}
+	for _, e := range l.edges {
+		if !e.self && len(e.chain) == 2 {
+			parallel[pairOf(e)] = append(parallel[pairOf(e)], e)
+		}
+	}
+
+	for _, e := range l.edges {
+		var pts []point
+		if e.self {
+			pts = selfLoop(e.chain[0])
+		} else {
+			for _, n := range e.chain {
+				pts = append(pts, point{n.x, n.y})
+			}
+			pts[0] = clip(e.chain[0], pts[1])
+			pts[len(pts)-1] = clip(e.chain[len(e.chain)-1], pts[len(pts)-2])
+		}
+
+		if group := parallel[pairOf(e)]; len(pts) == 2 && len(group) > 1 {
+			p := pairOf(e)
+			n := point{p[1].x, p[1].y}.sub(point{p[0].x, p[0].y}).unit().perp()
+			i := slices.Index(group, e)
+			shift := n.scale((float64(i) - float64(len(group)-1)/2) * parallelSep)
+			pts[0], pts[1] = pts[0].add(shift), pts[1].add(shift)
+		}
+		paths = append(paths, pts)
+
+		if e.edge.Label != "" {
+			labels = append(labels, placedLabel{at: midpoint(pts), lines: labelLines(e.edge.Label)})
+		}
+	}
+
+	// normalisasi koordinat agar semua isi berada di dalam margin
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	extend := func(x, y float64) {
+		minX, minY = min(minX, x), min(minY, y)
+		maxX, maxY = max(maxX, x), max(maxY, y)
+	}
+	for _, n := range l.nodes {
+		extend(n.x-n.w/2, n.y-n.h/2)
+		extend(n.x+n.w/2, n.y+n.h/2)
+	}
+	for _, pts := range paths {
+		for _, p := range pts {
+			extend(p.X, p.Y)
+		}
+	}
+	for _, lb := range labels {
+		w, h := textSize(lb.lines)
+		extend(lb.at.X-w/2-2, lb.at.Y-h/2)
+		extend(lb.at.X+w/2+2, lb.at.Y+h/2)
+	}
+	if len(l.nodes) == 0 {
+		minX, minY, maxX, maxY = 0, 0, 0, 0
+	}
+
+	offset := point{margin - minX, margin - minY}
+	s.width = maxX - minX + 2*margin
+	s.height = maxY - minY + 2*margin
+
+	for _, n := range l.nodes {
+		n.x += offset.X
+		n.y += offset.Y
+		drawNode(s, n)
+	}
+
+	for i, e := range l.edges {
+		pts := paths[i]
+		for j := range pts {
+			pts[j] = pts[j].add(offset)
+		}
+		drawEdge(s, g.Type, e.edge, pts)
+	}
+
+	for _, lb := range labels {
+		s.boxedLabel(lb.at.X+offset.X, lb.at.Y+offset.Y, lb.lines)
+	}
+
+	return s
+}
+
+// nodeShape shape render untuk node sesuai jenis diagram
+func nodeShape(t mermaid.DiagramType, n *mermaid.Node) string {
+	switch t {
+	case mermaid.DiagramFlowchart:
+		if n.Shape == "" {
+			return "rect"
+		}
+		return n.Shape
+	case mermaid.DiagramState:
+		return "round"
+	}
+	return "rect"
+}
+
+func nodeSize(lines []string, shape string) (float64, float64) {
+	switch shape {
+	case "start":
+		return 16, 16
+	case "end":
+		return 20, 20
+	}
+
+	tw, th := textSize(lines)
+	switch shape {
+	case "circle", "double-circle":
+		d := max(tw, th) + 2*nodePadX
+		return d, d
+	case "rhombus":
+		return tw*1.6 + 40, th*2 + 24
+	case "hexagon", "parallelogram", "parallelogram-alt", "trapezoid", "trapezoid-alt":
+		return tw + 2*nodePadX + 30, th + 2*nodePadY
+	}
+	return max(tw+2*nodePadX, 40), th + 2*nodePadY
+}
+
+// clip titik pada tepi node di garis dari titik tengah node ke p
+func clip(n *layoutNode, p point) point {
+	c := point{n.x, n.y}
+	d := p.sub(c)
+	if d.X == 0 && d.Y == 0 {
+		return c
+	}
+
+	hw, hh := n.w/2, n.h/2
+	var t float64
+	switch n.shape {
+	case "circle", "double-circle", "start", "end":
+		t = 1 / math.Sqrt((d.X/hw)*(d.X/hw)+(d.Y/hh)*(d.Y/hh))
+	case "rhombus":
+		t = 1 / (math.Abs(d.X)/hw + math.Abs(d.Y)/hh)
+	default:
+		t = math.Inf(1)
+		if d.X != 0 {
+			t = hw / math.Abs(d.X)
+		}
+		if d.Y != 0 {
+			t = min(t, hh/math.Abs(d.Y))
+		}
+	}
+
+	if t >= 1 {
+		return p
+	}
+	return c.add(d.scale(t))
+}
+
+// selfLoop edge dari node ke dirinya sendiri di sisi kanan node
+func selfLoop(n *layoutNode) []point {
+	right := n.x + n.w/2
+	return []point{
+		{right, n.y - n.h/4},
+		{right + 25, n.y - n.h/4},
+		{right + 25, n.y + n.h/4},
+		{right, n.y + n.h/4},
+	}
+}
+
+// midpoint titik di tengah panjang polyline
+func midpoint(pts []point) point {
+	total := 0.0
+	for i := 1; i < len(pts); i++ {
+		total += pts[i-1].distance(pts[i])
+	}
+
+	half := total / 2
+	for i := 1; i < len(pts); i++ {
+		seg := pts[i-1].distance(pts[i])
+		if seg >= half && seg > 0 {
+			return pts[i-1].add(pts[i].sub(pts[i-1]).scale(half / seg))
+		}
+		half -= seg
+	}
+	return pts[0]
+}
@@ -0,0 +1,204 @@
+package render
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// rasterizer menggambar scene ke image RGBA. Setiap path dirasterisasi hanya
+// seluas bounding box-nya agar diagram besar tetap cepat.
+type rasterizer struct {
+	img   *image.RGBA
+	scale float64
+	face  font.Face
+	r     vector.Rasterizer
+}
+
+// writePNG rasterisasi scene dengan skala tertentu
+func writePNG(s *scene, scale float64) ([]byte, error) {
+	face, err := newFace(scale)
+	if err != nil {
+		return nil, err
+	}
+	defer face.Close()
+
+	w := int(math.Ceil(s.width * scale))
+	h := int(math.Ceil(s.height * scale))
+	ra := &rasterizer{
+		img:   image.NewRGBA(image.Rect(0, 0, w, h)),
+		scale: scale,
+		face:  face,
+	}
+	draw.Draw(ra.img, ra.img.Bounds(), image.NewUniform(parseColor(colorBackground)), image.Point{}, draw.Src)
+
+	for _, sh := range s.shapes {
+		ra.shape(sh)
+	}
+
+	var b bytes.Buffer
+	if err := png.Encode(&b, ra.img); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (ra *rasterizer) shape(sh shape) {
+	switch sh.kind {
+	case shapeRect:
+		pts := rectOutline(sh.x, sh.y, sh.w, sh.h, sh.radius)
+		ra.fill(pts, sh.fill)
+		ra.stroke(append(pts, pts[0]), sh.stroke, sh.width, sh.dashed)
+
+	case shapeEllipse:
+		pts := arc(point{sh.x, sh.y}, sh.w, sh.h, 0, 2*math.Pi, 48)
+		ra.fill(pts, sh.fill)
+		ra.stroke(pts, sh.stroke, sh.width, sh.dashed)
+
+	case shapePolygon:
+		ra.fill(sh.points, sh.fill)
+		ra.stroke(append(sh.points, sh.points[0]), sh.stroke, sh.width, sh.dashed)
+
+	case shapePolyline:
+		ra.stroke(sh.points, sh.stroke, sh.width, sh.dashed)
+
+	case shapeText:
+		ra.text(sh)
+	}
+}
+
+// rectOutline titik keliling rect, sudut dibulatkan jika radius > 0
+func rectOutline(x, y, w, h, radius float64) []point {
+	radius = min(radius, w/2, h/2)
+	if radius <= 0 {
+		return []point{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}
+	}
+
+	var pts []point
+	pts = append(pts, arc(point{x + w - radius, y + radius}, radius, radius, -math.Pi/2, 0, 8)...)
+	pts = append(pts, arc(point{x + w - radius, y + h - radius}, radius, radius, 0, math.Pi/2, 8)...)
+	pts = append(pts, arc(point{x + radius, y + h - radius}, radius, radius, math.Pi/2, math.Pi, 8)...)
+	pts = append(pts, arc(point{x + radius, y + radius}, radius, radius, math.Pi, 3*math.Pi/2, 8)...)
+	return pts
+}
+
+// fill isi polygon tertutup
+func (ra *rasterizer) fill(pts []point, c string) {
+	if c == "" || len(pts) < 3 {
+		return
+	}
+	ra.path([][]point{pts}, c)
+}
+
+// stroke garis sepanjang polyline, setiap segmen menjadi quad selebar width
+func (ra *rasterizer) stroke(pts []point, c string, width float64, dashed bool) {
+	if c == "" || len(pts) < 2 {
+		return
+	}
+
+	segments := [][2]point{}
+	for i := 1; i < len(pts); i++ {
+		if dashed {
+			segments = append(segments, dashes(pts[i-1], pts[i], 5, 4)...)
+		} else {
+			segments = append(segments, [2]point{pts[i-1], pts[i]})
+		}
+	}
+
+	half := width / 2
+	var quads [][]point
+	for _, seg := range segments {
+		d := seg[1].sub(seg[0]).unit()
+		if d.X == 0 && d.Y == 0 {
+			continue
+		}
+		// diperpanjang setengah lebar agar sambungan antar segmen tidak berlubang
+		a, b := seg[0].sub(d.scale(half)), seg[1].add(d.scale(half))
+		n := d.perp().scale(half)
+		quads = append(quads, []point{a.add(n), b.add(n), b.sub(n), a.sub(n)})
+	}
+	ra.path(quads, c)
+}
+
+// dashes pecah segmen menjadi potongan dash sepanjang on dengan jarak off
+func dashes(a, b point, on, off float64) [][2]point {
+	length := a.distance(b)
+	d := b.sub(a).unit()
+
+	var out [][2]point
+	for t := 0.0; t < length; t += on + off {
+		end := min(t+on, length)
+		out = append(out, [2]point{a.add(d.scale(t)), a.add(d.scale(end))})
+	}
+	return out
+}
+
+// path rasterisasi kumpulan polygon dalam satu bounding box
+func (ra *rasterizer) path(polys [][]point, c string) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, poly := range polys {
+		for _, p := range poly {
+			minX, minY = min(minX, p.X*ra.scale), min(minY, p.Y*ra.scale)
+			maxX, maxY = max(maxX, p.X*ra.scale), max(maxY, p.Y*ra.scale)
+		}
+	}
+
+	box := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX))+1, int(math.Ceil(maxY))+1).
+		Intersect(ra.img.Bounds())
+	if box.Empty() {
+		return
+	}
+
+	ra.r.Reset(box.Dx(), box.Dy())
+	ra.r.DrawOp = draw.Over
+	ox, oy := float32(box.Min.X), float32(box.Min.Y)
+	for _, poly := range polys {
+		for i, p := range poly {
+			x, y := float32(p.X*ra.scale)-ox, float32(p.Y*ra.scale)-oy
+			if i == 0 {
+				ra.r.MoveTo(x, y)
+			} else {
+				ra.r.LineTo(x, y)
+			}
+		}
+		ra.r.ClosePath()
+	}
+	ra.r.Draw(ra.img, box, image.NewUniform(parseColor(c)), image.Point{})
+}
+
+func (ra *rasterizer) text(sh shape) {
+	d := &font.Drawer{
+		Dst:  ra.img,
+		Src:  image.NewUniform(parseColor(sh.fill)),
+		Face: ra.face,
+	}
+
+	width := d.MeasureString(sh.text)
+	metrics := ra.face.Metrics()
+	// baseline agar titik tengah text berada di y, sama dengan dominant-baseline central
+	baseline := sh.y*ra.scale + float64(metrics.Ascent-metrics.Descent)/64/2
+
+	d.Dot = fixed.Point26_6{
+		X: fixed.Int26_6(sh.x*ra.scale*64) - width/2,
+		Y: fixed.Int26_6(baseline * 64),
+	}
+	d.DrawString(sh.text)
+}
+
+// parseColor warna hex #RRGGBB
+func parseColor(hex string) color.RGBA {
+	v, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return color.RGBA{A: 0xff}
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}
+}
@@ -0,0 +1,65 @@
+// Package render menggambar diagram Mermaid menjadi SVG dan PNG tanpa browser.
+// Layout dihitung sendiri dari Graph hasil mermaid.Parse sehingga hasilnya
+// mendekati, tetapi tidak identik dengan, render Mermaid di browser.
+package render
+
+import (
+	"errors"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/mermaid"
+)
+
+// Version versi renderer, ikut di cache key agar output lama tidak dipakai lagi
+// setelah layout atau style berubah
+const Version = "1"
+
+// Batas skala dan ukuran PNG agar satu render tidak menghabiskan memory
+const (
+	MaxScale   = 4.0
+	maxPNGSide = 8192.0
+)
+
+// ErrUnsupported jenis diagram belum bisa digambar
+var ErrUnsupported = errors.New("diagram type is not supported by the renderer")
+
+// SVG gambar graph menjadi SVG
+func SVG(g *mermaid.Graph) ([]byte, error) {
+	s, err := build(g)
+	if err != nil {
+		return nil, err
+	}
+	return writeSVG(s), nil
+}
+
+// PNG gambar graph menjadi PNG. Scale dibatasi 1 sampai MaxScale dan diturunkan
+// otomatis jika sisi gambar melebihi batas.
+func PNG(g *mermaid.Graph, scale float64) ([]byte, error) {
+	s, err := build(g)
+	if err != nil {
+		return nil, err
+	}
+
+	scale = min(max(scale, 1), MaxScale)
+	if side := max(s.width, s.height) * scale; side > maxPNGSide {
+		scale *= maxPNGSide / side
+	}
+
+	return writePNG(s, scale)
+}
+
+func build(g *mermaid.Graph) (*scene, error) {
+	if err := loadFont(); err != nil {
+		return nil, err
+	}
+
+	switch g.Type {
+	case mermaid.DiagramFlowchart, mermaid.DiagramClass, mermaid.DiagramState, mermaid.DiagramER:
+		return layoutGraph(g), nil
+	case mermaid.DiagramSequence:
+		return layoutSequence(g), nil
+	case mermaid.DiagramGantt:
+		return layoutGantt(g), nil
+	}
+
+	return nil, ErrUnsupported
+}
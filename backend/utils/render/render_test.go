@@ -0,0 +1,104 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/mermaid"
+)
+
+var diagrams = map[string]string{
+	"flowchart": "graph LR\nA[\"a < b & c\"] -->|go| B((End))",
+	"sequence":  "sequenceDiagram\nA->>B: hi\nB-->>A: bye",
+	"class":     "classDiagram\nAnimal <|-- Duck",
+	"state":     "stateDiagram-v2\n[*] --> Idle\nIdle --> [*]",
+	"er":        "erDiagram\nCUSTOMER ||--o{ ORDER : places",
+	"gantt":     "gantt\nDesign : des, 2024-01-01, 2d\nCode : after des, 3d",
+}
+
+func parse(t *testing.T, src string) *mermaid.Graph {
+	t.Helper()
+
+	g, diags := mermaid.Parse(src)
+	if mermaid.HasErrors(diags) {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	return g
+}
+
+func TestSVG(t *testing.T) {
+	for name, src := range diagrams {
+		t.Run(name, func(t *testing.T) {
+			svg, err := SVG(parse(t, src))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Output harus XML valid dengan root <svg>, label tidak boleh merusak markup
+			dec := xml.NewDecoder(bytes.NewReader(svg))
+			root := ""
+			for {
+				tok, err := dec.Token()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("invalid svg: %v\n%s", err, svg)
+				}
+				if el, ok := tok.(xml.StartElement); ok && root == "" {
+					root = el.Name.Local
+				}
+			}
+			if root != "svg" {
+				t.Fatalf("root element = %q, want svg", root)
+			}
+		})
+	}
+
+	svg, err := SVG(parse(t, diagrams["flowchart"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(svg), ">a &lt; b &amp; c</text>") {
+		t.Fatalf("escaped label missing:\n%s", svg)
+	}
+}
+
+func TestSVGUnsupported(t *testing.T) {
+	g, _ := mermaid.Parse("pie\n\"a\": 1")
+	if _, err := SVG(g); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("SVG(pie) error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestPNGScale(t *testing.T) {
+	g := parse(t, diagrams["flowchart"])
+
+	base, err := PNG(g, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	small, err := png.DecodeConfig(bytes.NewReader(base))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Scale di atas MaxScale dibatasi
+	large, err := PNG(g, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	big, err := png.DecodeConfig(bytes.NewReader(large))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := small.Width * int(MaxScale); big.Width < want-int(MaxScale) || big.Width > want+int(MaxScale) {
+		t.Fatalf("width at scale 100 = %d, want about %d", big.Width, want)
+	}
+}
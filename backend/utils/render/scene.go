@@ -0,0 +1,105 @@
+package render
+
+import "math"
+
+// warna default mengikuti theme default Mermaid
+const (
+	colorBackground = "#ffffff"
+	colorNodeFill   = "#ECECFF"
+	colorNodeStroke = "#9370DB"
+	colorLine       = "#333333"
+	colorText       = "#333333"
+	colorLabelFill  = "#E8E8E8"
+	colorNoteFill   = "#FFF5AD"
+	colorBarFill    = "#8A90DD"
+	colorBarStroke  = "#534FBC"
+)
+
+const (
+	fontSize    = 14.0
+	lineHeight  = 18.0
+	strokeWidth = 1.5
+	margin      = 20.0
+)
+
+type point struct {
+	X, Y float64
+}
+
+func (p point) add(q point) point        { return point{p.X + q.X, p.Y + q.Y} }
+func (p point) sub(q point) point        { return point{p.X - q.X, p.Y - q.Y} }
+func (p point) scale(f float64) point    { return point{p.X * f, p.Y * f} }
+func (p point) perp() point              { return point{-p.Y, p.X} }
+func (p point) length() float64          { return math.Hypot(p.X, p.Y) }
+func (p point) distance(q point) float64 { return q.sub(p).length() }
+
+// unit vektor satuan, nol jika panjang nol
+func (p point) unit() point {
+	l := p.length()
+	if l == 0 {
+		return point{}
+	}
+	return p.scale(1 / l)
+}
+
+type shapeKind int
+
+const (
+	shapeRect shapeKind = iota
+	shapeEllipse
+	shapePolygon
+	shapePolyline
+	shapeText
+)
+
+// shape satu primitive gambar. Koordinat text adalah titik tengah baris.
+type shape struct {
+	kind   shapeKind
+	x, y   float64
+	w, h   float64
+	radius float64
+	points []point
+	text   string
+
+	fill   string // kosong berarti tanpa fill
+	stroke string // kosong berarti tanpa stroke
+	width  float64
+	dashed bool
+}
+
+// scene hasil layout yang siap digambar ke SVG maupun PNG
+type scene struct {
+	width, height float64
+	shapes        []shape
+}
+
+func (s *scene) rect(x, y, w, h, radius float64, fill, stroke string) {
+	s.shapes = append(s.shapes, shape{kind: shapeRect, x: x, y: y, w: w, h: h, radius: radius, fill: fill, stroke: stroke, width: strokeWidth})
+}
+
+func (s *scene) ellipse(cx, cy, rx, ry float64, fill, stroke string) {
+	s.shapes = append(s.shapes, shape{kind: shapeEllipse, x: cx, y: cy, w: rx, h: ry, fill: fill, stroke: stroke, width: strokeWidth})
+}
+
+func (s *scene) polygon(points []point, fill, stroke string) {
+	s.shapes = append(s.shapes, shape{kind: shapePolygon, points: points, fill: fill, stroke: stroke, width: strokeWidth})
+}
+
+func (s *scene) polyline(points []point, stroke string, width float64, dashed bool) {
+	s.shapes = append(s.shapes, shape{kind: shapePolyline, points: points, stroke: stroke, width: width, dashed: dashed})
+}
+
+// label text multi-baris dengan titik tengah (cx, cy)
+func (s *scene) label(cx, cy float64, lines []string, color string) {
+	top := cy - float64(len(lines)-1)*lineHeight/2
+	for i, line := range lines {
+		s.shapes = append(s.shapes, shape{kind: shapeText, x: cx, y: top + float64(i)*lineHeight, text: line, fill: color})
+	}
+}
+
+// boxedLabel label dengan background, dipakai untuk label edge
+func (s *scene) boxedLabel(cx, cy float64, lines []string) {
+	w, h := textSize(lines)
+	s.rect(cx-w/2-2, cy-h/2, w+4, h, 0, colorLabelFill, "")
+	s.label(cx, cy, lines, colorText)
+}
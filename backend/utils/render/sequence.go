@@ -0,0 +1,169 @@
+package render
+
+import (
+	"strings"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/mermaid"
+)
+
+const (
+	participantGap = 40.0
+	messageGap     = 20.0
+	selfLoopWidth  = 30.0
+	actorHeight    = 36.0
+)
+
+type sequenceColumn struct {
+	lines []string
+	actor bool
+	w, h  float64
+	x     float64
+}
+
+// layoutSequence participant sebagai kolom dengan lifeline, message digambar
+// berurutan dari atas ke bawah. Note dan blok loop/alt tidak digambar.
+func layoutSequence(g *mermaid.Graph) *scene {
+	s := &scene{}
+
+	var cols []*sequenceColumn
+	index := map[string]int{}
+	boxH := 0.0
+	for _, n := range g.Nodes {
+		c := &sequenceColumn{lines: labelLines(n.Label), actor: n.Shape == "actor"}
+		tw, th := textSize(c.lines)
+		c.w, c.h = max(tw+2*nodePadX, 60), th+2*nodePadY
+		if c.actor {
+			c.w, c.h = max(tw+10, 40), actorHeight+th
+		}
+		boxH = max(boxH, c.h)
+		index[n.ID] = len(cols)
+		cols = append(cols, c)
+	}
+
+	// jarak minimal antar titik tengah kolom, diperlebar untuk label message
+	gaps := make([]float64, len(cols)+1)
+	for i := 1; i < len(cols); i++ {
+		gaps[i] = (cols[i-1].w+cols[i].w)/2 + participantGap
+	}
+	for _, e := range g.Edges {
+		a, b := index[e.From], index[e.To]
+		lw, _ := textSize(labelLines(e.Label))
+		if a == b {
+			gaps[a+1] = max(gaps[a+1], selfLoopWidth+lw+messageGap)
+			continue
+		}
+		a, b = min(a, b), max(a, b)
+		total := 0.0
+		for i := a + 1; i <= b; i++ {
+			total += gaps[i]
+		}
+		if need := lw + 2*messageGap; total < need {
+			gaps[b] += need - total
+		}
+	}
+
+	x := margin
+	if len(cols) > 0 {
+		x += cols[0].w / 2
+	}
+	for i, c := range cols {
+		if i > 0 {
+			x += gaps[i]
+		}
+		c.x = x
+	}
+
+	width := x + margin
+	if len(cols) > 0 {
+		width = max(width, x+cols[len(cols)-1].w/2+margin, x+gaps[len(cols)]+margin)
+	}
+
+	// posisi vertikal message
+	type placed struct {
+		edge *mermaid.Edge
+		y    float64
+	}
+	var messages []placed
+	y := margin + boxH + 30
+	for _, e := range g.Edges {
+		_, lh := textSize(labelLines(e.Label))
+		y += lh
+		messages = append(messages, placed{edge: e, y: y})
+		if e.From == e.To {
+			y += messageGap
+		}
+		y += messageGap
+	}
+	bottom := y + 10
+
+	for _, c := range cols {
+		s.polyline([]point{{c.x, margin + boxH}, {c.x, bottom}}, colorLine, 1, true)
+		drawParticipant(s, c, margin, boxH)
+		drawParticipant(s, c, bottom, boxH)
+	}
+
+	for _, m := range messages {
+		drawMessage(s, m.edge, cols[index[m.edge.From]], cols[index[m.edge.To]], m.y)
+	}
+
+	s.width = width
+	s.height = bottom + boxH + margin
+	return s
+}
+
+func drawParticipant(s *scene, c *sequenceColumn, top, boxH float64) {
+	if !c.actor {
+		s.rect(c.x-c.w/2, top+boxH-c.h, c.w, c.h, 3, colorNodeFill, colorNodeStroke)
+		s.label(c.x, top+boxH-c.h/2, c.lines, colorText)
+		return
+	}
+
+	// stick figure dengan nama di bawahnya
+	y := top + boxH - c.h
+	s.ellipse(c.x, y+6, 6, 6, colorNodeFill, colorNodeStroke)
+	s.polyline([]point{{c.x, y + 12}, {c.x, y + 24}}, colorNodeStroke, strokeWidth, false)
+	s.polyline([]point{{c.x - 10, y + 16}, {c.x + 10, y + 16}}, colorNodeStroke, strokeWidth, false)
+	s.polyline([]point{{c.x - 8, y + 33}, {c.x, y + 24}, {c.x + 8, y + 33}}, colorNodeStroke, strokeWidth, false)
+	_, th := textSize(c.lines)
+	s.label(c.x, y+actorHeight+th/2, c.lines, colorText)
+}
+
+func drawMessage(s *scene, e *mermaid.Edge, from, to *sequenceColumn, y float64) {
+	arrow := e.Arrow
+	dashed := strings.HasPrefix(strings.TrimPrefix(arrow, "<<"), "--")
+
+	head := markerNone
+	switch {
+	case strings.HasSuffix(arrow, ">>"), strings.HasSuffix(arrow, ")"):
+		head = markerArrow
+	case strings.HasSuffix(arrow, "x"):
+		head = markerCross
+	}
+	tail := markerNone
+	if strings.HasPrefix(arrow, "<<") {
+		tail = markerArrow
+	}
+
+	lines := labelLines(e.Label)
+	_, lh := textSize(lines)
+
+	if from == to {
+		pts := []point{{from.x, y}, {from.x + selfLoopWidth, y}, {from.x + selfLoopWidth, y + messageGap}, {from.x, y + messageGap}}
+		s.polyline(pts, colorLine, strokeWidth, dashed)
+		s.marker(head, pts[3], point{-1, 0})
+		if e.Label != "" {
+			lw, _ := textSize(lines)
+			s.label(from.x+selfLoopWidth+5+lw/2, y+messageGap/2, lines, colorText)
+		}
+		return
+	}
+
+	start, end := point{from.x, y}, point{to.x, y}
+	dir := end.sub(start).unit()
+	s.polyline([]point{start, end}, colorLine, strokeWidth, dashed)
+	s.marker(head, end, dir)
+	s.marker(tail, start, dir.scale(-1))
+	if e.Label != "" {
+		s.label((from.x+to.x)/2, y-lh/2-2, lines, colorText)
+	}
+}
@@ -0,0 +1,88 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const svgFontFamily = "Go, Arial, Helvetica, sans-serif"
+
+// writeSVG serialisasi scene menjadi SVG standalone
+func writeSVG(s *scene) []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s" font-family="%s" font-size="%s">`,
+		num(s.width), num(s.height), num(s.width), num(s.height), svgFontFamily, num(fontSize))
+	b.WriteString("\n")
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", colorBackground)
+
+	for _, sh := range s.shapes {
+		switch sh.kind {
+		case shapeRect:
+			fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="%s"`, num(sh.x), num(sh.y), num(sh.w), num(sh.h))
+			if sh.radius > 0 {
+				fmt.Fprintf(&b, ` rx="%s"`, num(sh.radius))
+			}
+			writePaint(&b, sh)
+			b.WriteString("/>\n")
+
+		case shapeEllipse:
+			fmt.Fprintf(&b, `<ellipse cx="%s" cy="%s" rx="%s" ry="%s"`, num(sh.x), num(sh.y), num(sh.w), num(sh.h))
+			writePaint(&b, sh)
+			b.WriteString("/>\n")
+
+		case shapePolygon, shapePolyline:
+			tag := "polygon"
+			if sh.kind == shapePolyline {
+				tag = "polyline"
+			}
+			fmt.Fprintf(&b, `<%s points="`, tag)
+			for i, p := range sh.points {
+				if i > 0 {
+					b.WriteByte(' ')
+				}
+				fmt.Fprintf(&b, "%s,%s", num(p.X), num(p.Y))
+			}
+			b.WriteByte('"')
+			writePaint(&b, sh)
+			b.WriteString("/>\n")
+
+		case shapeText:
+			fmt.Fprintf(&b, `<text x="%s" y="%s" text-anchor="middle" dominant-baseline="central" fill="%s">`, num(sh.x), num(sh.y), sh.fill)
+			_ = xml.EscapeText(&b, []byte(sh.text))
+			b.WriteString("</text>\n")
+		}
+	}
+
+	b.WriteString("</svg>\n")
+	return b.Bytes()
+}
+
+func writePaint(b *bytes.Buffer, sh shape) {
+	fill := sh.fill
+	if fill == "" {
+		fill = "none"
+	}
+	fmt.Fprintf(b, ` fill="%s"`, fill)
+
+	if sh.stroke == "" {
+		return
+	}
+	fmt.Fprintf(b, ` stroke="%s" stroke-width="%s"`, sh.stroke, num(sh.width))
+	if sh.dashed {
+		b.WriteString(` stroke-dasharray="5,4"`)
+	}
+	if sh.kind == shapePolyline {
+		b.WriteString(` stroke-linejoin="round"`)
+	}
+}
+
+// num format angka maksimal dua desimal agar output stabil dan ringkas
+func num(f float64) string {
+	s := strconv.FormatFloat(f, 'f', 2, 64)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,54 @@
+package response
+
+import (
+	"strconv"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/helpers"
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	ErrInvalidIfMatch      = apperr.BadRequest("invalid_if_match", "If-Match header must contain a single strong ETag")
+	ErrBaseVersionMismatch = apperr.BadRequest("base_version_mismatch", "base_version does not match the If-Match header")
+)
+
+// SetETag set header ETag dari penanda versi resource
+func SetETag(c *fiber.Ctx, version string) {
+	c.Set(fiber.HeaderETag, helpers.ETag(version))
+}
+
+// IfMatch penanda versi dari header If-Match, kosong jika header tidak dikirim
+func IfMatch(c *fiber.Ctx) (string, error) {
+	header := c.Get(fiber.HeaderIfMatch)
+	if header == "" {
+		return "", nil
+	}
+
+	version, ok := helpers.ParseIfMatch(header)
+	if !ok {
+		return "", ErrInvalidIfMatch
+	}
+
+	return version, nil
+}
+
+// BaseVersion nomor versi yang menjadi dasar perubahan client, dari header
+// If-Match atau dari body. Nil jika keduanya tidak dikirim.
+func BaseVersion(c *fiber.Ctx, body *int) (*int, error) {
+	header, err := IfMatch(c)
+	if err != nil || header == "" {
+		return body, err
+	}
+
+	version, err := strconv.Atoi(header)
+	if err != nil || version < 0 {
+		return nil, ErrInvalidIfMatch
+	}
+
+	if body != nil && *body != version {
+		return nil, ErrBaseVersionMismatch
+	}
+
+	return &version, nil
+}
@@ -1,9 +1,11 @@
 package response
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog/log"
@@ -24,10 +26,11 @@ func (e *Error) Error() string {
 
 // Response A struct to return normal response
 type Response struct {
-	Code     int      `json:"code"`
-	Messages Messages `json:"messages"`
-	Data     any      `json:"data,omitempty"`
-	Meta     any      `json:"meta,omitempty"`
+	Code      int      `json:"code"`
+	ErrorCode string   `json:"error_code,omitempty"`
+	Messages  Messages `json:"messages"`
+	Data      any      `json:"data,omitempty"`
+	Meta      any      `json:"meta,omitempty"`
 }
 
 // IsProduction nothiing to describe this fucking variable
@@ -40,20 +43,30 @@ var ErrorHandler = func(ctx *fiber.Ctx, err error) error {
 	}
 
 	// handle errors
-	if c, ok := err.(validator.ValidationErrors); ok {
+	var fiberErr *fiber.Error
+	if c, ok := apperr.As(err); ok {
+		resp.Code = c.Status()
+		resp.ErrorCode = c.ErrorCode()
+		resp.Messages = Messages{c.Message}
+		resp.Data = c.Data
+	} else if c, ok := err.(validator.ValidationErrors); ok {
 		resp.Code = fiber.StatusUnprocessableEntity
+		resp.ErrorCode = "validation_failed"
 		resp.Messages = Messages{removeTopStruct(c.Translate(trans))}
-	} else if c, ok := err.(*fiber.Error); ok {
-		resp.Code = c.Code
-		resp.Messages = Messages{c.Message}
+	} else if errors.As(err, &fiberErr) {
+		resp.Code = fiberErr.Code
+		resp.ErrorCode = apperr.CodeForStatus(fiberErr.Code)
+		resp.Messages = Messages{fiberErr.Message}
 	} else if c, ok := err.(*Error); ok {
 		resp.Code = c.Code
+		resp.ErrorCode = apperr.CodeForStatus(c.Code)
 		resp.Messages = Messages{c.Message}
 
 		if resp.Messages == nil {
 			resp.Messages = Messages{err}
 		}
 	} else {
+		resp.ErrorCode = string(apperr.KindInternal)
 		resp.Messages = Messages{err.Error()}
 	}
 
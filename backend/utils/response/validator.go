@@ -3,6 +3,7 @@ package response
 import (
 	"github.com/rs/zerolog/log"
 
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/apperr"
 	"github.com/go-playground/locales/en"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
@@ -27,13 +28,16 @@ func init() {
 	}
 }
 
+// ErrInvalidBody body request tidak bisa di-parse
+var ErrInvalidBody = apperr.BadRequest("invalid_request_body", "invalid request body")
+
 func ValidateStruct(input any) error {
 	return validate.Struct(input)
 }
 
 func ParseAndValidate(c *fiber.Ctx, body any) error {
 	if err := c.BodyParser(body); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		return apperr.BadRequest("invalid_request_body", err.Error())
 	}
 
 	return ValidateStruct(body)
@@ -46,13 +46,7 @@ func NewStore(cfg *config.Config) *session.Store {
 	}
 
 	// Session expiration
-	expiration := cfg.Middleware.Session.Expiration
-	if expiration == 0 {
-		expiration = 24 * time.Hour // default 24 hours
-	} else if expiration < 1000000 {
-		// If expiration is small (likely in seconds), convert to duration
-		expiration = expiration * time.Second
-	}
+	expiration := Expiration(cfg)
 
 	store := session.New(session.Config{
 		KeyLookup:      fmt.Sprintf("cookie:%s", cfg.Middleware.Session.Name),
@@ -70,6 +64,19 @@ func NewStore(cfg *config.Config) *session.Store {
 	return store
 }
 
+// Expiration umur session dari config, default 24 jam
+func Expiration(cfg *config.Config) time.Duration {
+	expiration := cfg.Middleware.Session.Expiration
+	if expiration == 0 {
+		expiration = 24 * time.Hour // default 24 hours
+	} else if expiration < 1000000 {
+		// If expiration is small (likely in seconds), convert to duration
+		expiration = expiration * time.Second
+	}
+
+	return expiration
+}
+
 func Get(c *fiber.Ctx, store *session.Store) (*session.Session, error) {
 	return store.Get(c)
 }
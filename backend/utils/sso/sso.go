@@ -0,0 +1,187 @@
+package sso
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// discoveryTimeout batas waktu request ke discovery document dan JWKS issuer
+const discoveryTimeout = 10 * time.Second
+
+var defaultScopes = []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess, "profile", "email"}
+
+// Provider satu identity provider OIDC. Discovery dilakukan saat pertama kali
+// dipakai sehingga aplikasi tetap bisa start walaupun issuer sedang down.
+type Provider struct {
+	Name        string
+	DisplayName string
+
+	cfg config.SsoProvider
+
+	mu            sync.Mutex
+	provider      *oidc.Provider
+	endSessionURL string
+}
+
+// Registry daftar provider yang dikonfigurasi, urutan mengikuti config
+type Registry struct {
+	providers []*Provider
+	byName    map[string]*Provider
+}
+
+// NewRegistry membuat registry dari [[sso.providers]] ditambah blok lama [sso.logto]
+func NewRegistry(cfg *config.Config) *Registry {
+	registry := &Registry{
+		byName: make(map[string]*Provider),
+	}
+
+	for _, p := range cfg.Sso.Providers {
+		registry.add(p)
+	}
+
+	// Blok [sso.logto] lama tetap didukung sebagai provider "logto"
+	if logto := cfg.Sso.Logto; logto.Endpoint != "" {
+		issuer := strings.TrimSuffix(logto.Endpoint, "/")
+		if !strings.HasSuffix(issuer, "/oidc") {
+			issuer = issuer + "/oidc"
+		}
+
+		registry.add(config.SsoProvider{
+			Name:                  "logto",
+			DisplayName:           "Logto",
+			Issuer:                issuer,
+			ClientId:              logto.ClientId,
+			ClientSecret:          logto.ClientSecret,
+			CallbackUrl:           logto.CallbackUrl,
+			PostLogoutRedirectUri: logto.PostLogoutRedirectUri,
+		})
+	}
+
+	return registry
+}
+
+func (_i *Registry) add(cfg config.SsoProvider) {
+	displayName := cfg.DisplayName
+	if displayName == "" {
+		displayName = cfg.Name
+	}
+
+	p := &Provider{
+		Name:        cfg.Name,
+		DisplayName: displayName,
+		cfg:         cfg,
+	}
+
+	_i.providers = append(_i.providers, p)
+	_i.byName[p.Name] = p
+}
+
+// Get provider berdasarkan nama
+func (_i *Registry) Get(name string) (*Provider, bool) {
+	p, ok := _i.byName[name]
+	return p, ok
+}
+
+// Default provider pertama, dipakai oleh /auth/login tanpa nama provider
+func (_i *Registry) Default() (*Provider, bool) {
+	if len(_i.providers) == 0 {
+		return nil, false
+	}
+	return _i.providers[0], true
+}
+
+// List semua provider sesuai urutan config
+func (_i *Registry) List() []*Provider {
+	return _i.providers
+}
+
+// OAuth2Config config authorization code flow dari endpoint hasil discovery
+func (_i *Provider) OAuth2Config(ctx context.Context) (*oauth2.Config, error) {
+	provider, err := _i.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := _i.cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultScopes
+	}
+
+	return &oauth2.Config{
+		ClientID:     _i.cfg.ClientId,
+		ClientSecret: _i.cfg.ClientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  _i.cfg.CallbackUrl,
+		Scopes:       scopes,
+	}, nil
+}
+
+// Verifier verifier id_token untuk client ini
+func (_i *Provider) Verifier(ctx context.Context) (*oidc.IDTokenVerifier, error) {
+	provider, err := _i.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.Verifier(&oidc.Config{ClientID: _i.cfg.ClientId}), nil
+}
+
+// LogoutURL RP-initiated logout ke end_session_endpoint issuer,
+// kosong jika issuer tidak mendukung atau discovery gagal
+func (_i *Provider) LogoutURL(ctx context.Context) string {
+	if _, err := _i.discover(ctx); err != nil || _i.endSessionURL == "" {
+		return ""
+	}
+
+	params := url.Values{}
+	params.Set("client_id", _i.cfg.ClientId)
+	if _i.cfg.PostLogoutRedirectUri != "" {
+		params.Set("post_logout_redirect_uri", _i.cfg.PostLogoutRedirectUri)
+	}
+
+	separator := "?"
+	if strings.Contains(_i.endSessionURL, "?") {
+		separator = "&"
+	}
+
+	return _i.endSessionURL + separator + params.Encode()
+}
+
+// discover ambil dan cache discovery document, gagal tidak di-cache agar dicoba lagi
+func (_i *Provider) discover(ctx context.Context) (*oidc.Provider, error) {
+	_i.mu.Lock()
+	defer _i.mu.Unlock()
+
+	if _i.provider != nil {
+		return _i.provider, nil
+	}
+
+	// Client dengan timeout juga dipakai provider untuk mengambil JWKS
+	ctx = oidc.ClientContext(ctx, &http.Client{Timeout: discoveryTimeout})
+
+	provider, err := oidc.NewProvider(ctx, _i.cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("sso provider %s: discovery failed: %w", _i.Name, err)
+	}
+
+	var claims struct {
+		EndSessionEndpoint string `json:"end_session_endpoint"`
+	}
+	if err := provider.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("sso provider %s: invalid discovery document: %w", _i.Name, err)
+	}
+
+	_i.provider = provider
+	_i.endSessionURL = claims.EndSessionEndpoint
+
+	return provider, nil
+}
@@ -0,0 +1,95 @@
+package sso
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
+)
+
+// SealedPrefix penanda token terenkripsi, value tanpa prefix adalah token lama
+// yang disimpan sebelum enkripsi
+const SealedPrefix = "enc:v1:"
+
+var (
+	ErrTokenKeyMissing = errors.New("sso.token_key is required to store identity provider tokens")
+	ErrTokenInvalid    = errors.New("stored identity provider token cannot be decrypted")
+)
+
+// TokenCipher enkripsi token IdP yang disimpan di database dengan AES-256-GCM.
+// Key diturunkan dari sso.token_key, nama kolom dipakai sebagai additional data
+// sehingga ciphertext tidak bisa dipindah ke kolom lain.
+type TokenCipher struct {
+	aead cipher.AEAD
+}
+
+// NewTokenCipher membuat cipher dari config, tanpa key hanya string kosong yang bisa disimpan
+func NewTokenCipher(cfg *config.Config) (*TokenCipher, error) {
+	if cfg.Sso.TokenKey == "" {
+		return &TokenCipher{}, nil
+	}
+
+	key := sha256.Sum256([]byte(cfg.Sso.TokenKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenCipher{aead: aead}, nil
+}
+
+// Seal enkripsi token untuk kolom column, string kosong tetap kosong
+func (_i *TokenCipher) Seal(column, token string) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+	if _i.aead == nil {
+		return "", ErrTokenKeyMissing
+	}
+
+	nonce := make([]byte, _i.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := _i.aead.Seal(nonce, nonce, []byte(token), []byte(column))
+	return SealedPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Open dekripsi token dari kolom column. Token lama tanpa prefix dikembalikan apa
+// adanya, cek dengan Sealed untuk mengenkripsi ulang.
+func (_i *TokenCipher) Open(column, value string) (string, error) {
+	if !strings.HasPrefix(value, SealedPrefix) {
+		return value, nil
+	}
+	if _i.aead == nil {
+		return "", ErrTokenKeyMissing
+	}
+
+	data, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(value, SealedPrefix))
+	if err != nil || len(data) < _i.aead.NonceSize() {
+		return "", ErrTokenInvalid
+	}
+
+	nonce, ciphertext := data[:_i.aead.NonceSize()], data[_i.aead.NonceSize():]
+	token, err := _i.aead.Open(nil, nonce, ciphertext, []byte(column))
+	if err != nil {
+		return "", ErrTokenInvalid
+	}
+
+	return string(token), nil
+}
+
+// Sealed true jika value sudah terenkripsi atau kosong
+func Sealed(value string) bool {
+	return value == "" || strings.HasPrefix(value, SealedPrefix)
+}
@@ -0,0 +1,65 @@
+package sso
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"git.dev.siap.id/kukuhkkh/app-diagram/utils/config"
+)
+
+func newTestCipher(t *testing.T, key string) *TokenCipher {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.Sso.TokenKey = key
+	cipher, err := NewTokenCipher(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cipher
+}
+
+func TestTokenCipher(t *testing.T) {
+	cipher := newTestCipher(t, strings.Repeat("k", 32))
+
+	sealed, err := cipher.Seal("refresh_token", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Sealed(sealed) || strings.Contains(sealed, "secret") {
+		t.Fatalf("Seal = %q, want encrypted value", sealed)
+	}
+
+	token, err := cipher.Open("refresh_token", sealed)
+	if err != nil || token != "secret" {
+		t.Fatalf("Open = %q, %v, want %q", token, err, "secret")
+	}
+
+	// Ciphertext terikat ke kolomnya
+	if _, err := cipher.Open("access_token", sealed); !errors.Is(err, ErrTokenInvalid) {
+		t.Fatalf("Open other column error = %v, want ErrTokenInvalid", err)
+	}
+	if _, err := newTestCipher(t, strings.Repeat("x", 32)).Open("refresh_token", sealed); !errors.Is(err, ErrTokenInvalid) {
+		t.Fatalf("Open other key error = %v, want ErrTokenInvalid", err)
+	}
+
+	// Token lama tanpa enkripsi tetap bisa dibaca
+	if token, err := cipher.Open("refresh_token", "legacy"); err != nil || token != "legacy" {
+		t.Fatalf("Open plaintext = %q, %v, want %q", token, err, "legacy")
+	}
+	if Sealed("legacy") {
+		t.Fatal("plaintext token reported as sealed")
+	}
+}
+
+func TestTokenCipherWithoutKey(t *testing.T) {
+	cipher := newTestCipher(t, "")
+
+	if sealed, err := cipher.Seal("access_token", ""); err != nil || sealed != "" {
+		t.Fatalf("Seal empty = %q, %v, want empty", sealed, err)
+	}
+	if _, err := cipher.Seal("access_token", "secret"); !errors.Is(err, ErrTokenKeyMissing) {
+		t.Fatalf("Seal error = %v, want ErrTokenKeyMissing", err)
+	}
+}
@@ -24,6 +24,10 @@ func NewLocalStorage(path string) (*LocalStorage, error) {
 
 func (s *LocalStorage) Upload(ctx context.Context, filename string, file io.Reader) (string, error) {
 	dstPath := filepath.Join(s.Path, filename)
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+		return "", err
+	}
+
 	dst, err := os.Create(dstPath)
 	if err != nil {
 		return "", err